@@ -1,10 +1,14 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
+	"time"
 
 	"gitlab.com/tozd/go/errors"
 
@@ -13,38 +17,252 @@ import (
 
 func main() {
 	// Define command-line flags
-	schemaFile := flag.String("schema", "", "Path to the JSON schema file")
-	modelFile := flag.String("model", "", "Path to the Go model file")
-	outputDir := flag.String("output", "", "Output directory for generated files")
+	schemaFile := flag.String("schema", "", "Path to the JSON schema file, or a directory or glob of them")
+	modelFile := flag.String("model", "", "Path to the Go model file; {name} stands for the schema's name")
+	outputDir := flag.String("output", "", "Output directory for generated files; {name} stands for the schema's name")
+	inPlace := flag.Bool("inplace", false, "Rewrite the model file in place once the result type-checks")
+	typeScript := flag.String("typescript", "", "Also write the schema's TypeScript types to this file; {name} stands for the schema's name")
+	proto := flag.String("proto", "", "Also write a protobuf definition of the schema to this file; {name} stands for the schema's name")
+	protoPackage := flag.String("proto-package", "", "The package of the -proto file; defaults to the model's package")
+	protoGoPackage := flag.String("proto-go-package", "", "The go_package of the -proto file, which -proto-converters imports")
+	protoConverters := flag.String("proto-converters", "", "Also write Go converters between the model and the -proto messages to this file in the model's package")
+	check := flag.Bool("check", false, "Compare the generated code with the files on disk instead of writing it, printing a diff if they differ")
+	watch := flag.Bool("watch", false, "Process again whenever the schemas, the schemas they $ref or the models change")
+	debounce := flag.Duration("debounce", repostprocess.DefaultDebounce, "How long -watch waits for changes to settle before processing")
+	generate := flag.Bool("generate", false, "Generate the model with go-jsonschema instead of reading an existing one")
+	report := flag.String("report", "", "Print analysis diagnostics as \"text\" or \"json\"")
+	strict := flag.Bool("strict", false, "Fail on warning diagnostics, not just errors")
+	force := flag.Bool("force", false, "Write the generated code even if it doesn't type-check")
+	templatesDir := flag.String("templates", "", "Directory of *.tmpl files overriding the built-in templates")
+	fromGo := flag.String("from-go", "", "Write a JSON schema of the union interfaces and structs of this Go package directory to -schema, instead of processing one")
+	root := flag.String("root", "", "With -from-go, the type the schema describes; without it every exported struct and parent is defined")
+	configFile := flag.String("config", "", "Path to a YAML or JSON config file; defaults to "+repostprocess.DefaultConfigFile+" if it exists")
 
 	flag.Parse()
 
-	// Validate required arguments
-	if *schemaFile == "" {
-		fmt.Println("Error: schema file path is required")
-		flag.Usage()
-		os.Exit(1)
+	// Without -config, a config in the working directory is picked up
+	if *configFile == "" {
+		if _, err := os.Stat(repostprocess.DefaultConfigFile); err == nil {
+			*configFile = repostprocess.DefaultConfigFile
+		}
 	}
-	if *modelFile == "" {
-		fmt.Println("Error: model file path is required")
-		flag.Usage()
-		os.Exit(1)
+
+	var config *repostprocess.Config
+	if *configFile != "" {
+		var err error
+		config, err = repostprocess.LoadConfig(*configFile)
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *fromGo != "" {
+		if *schemaFile == "" {
+			fmt.Println("Error: -from-go needs -schema to name the schema it writes")
+			flag.Usage()
+			os.Exit(1)
+		}
+		if err := writeSchemaFromGo(*fromGo, *root, *schemaFile, config); err != nil {
+			fmt.Printf("Error generating schema: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Schema of %s written to %s\n", *fromGo, *schemaFile)
+		return
 	}
-	if *outputDir == "" {
-		fmt.Println("Error: output directory is required")
+
+	if *report != "" && *report != "text" && *report != "json" {
+		fmt.Println("Error: report format must be text or json")
 		flag.Usage()
 		os.Exit(1)
 	}
 
-	// Create processor and run it
-	processor := repostprocess.NewProcessor(*schemaFile, *modelFile, *outputDir)
-	if err := processor.Process(); err != nil {
-		fmt.Printf("Error processing schema: %v\n", err)
+	// Without -schema, every target of the config is processed
+	var processors []*repostprocess.Processor
+	if *schemaFile == "" && config != nil && len(config.Targets) > 0 {
+		if *modelFile != "" || *outputDir != "" || *inPlace || *generate || *typeScript != "" || *proto != "" || *protoPackage != "" || *protoGoPackage != "" || *protoConverters != "" {
+			fmt.Println("Error: -model, -output, -inplace, -generate, -typescript and -proto flags need -schema")
+			flag.Usage()
+			os.Exit(1)
+		}
+		var err error
+		processors, err = config.Processors()
+		if err != nil {
+			fmt.Printf("Error listing schemas: %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		// Validate required arguments
+		if *schemaFile == "" {
+			fmt.Println("Error: schema file path is required")
+			flag.Usage()
+			os.Exit(1)
+		}
+		if *modelFile == "" {
+			fmt.Println("Error: model file path is required")
+			flag.Usage()
+			os.Exit(1)
+		}
+		if *outputDir == "" && !*inPlace {
+			fmt.Println("Error: output directory is required")
+			flag.Usage()
+			os.Exit(1)
+		}
+		if *outputDir != "" && *inPlace {
+			fmt.Println("Error: -output and -inplace can't be used together")
+			flag.Usage()
+			os.Exit(1)
+		}
+
+		// A directory or glob of schemas maps each one to its model and output through {name}
+		targets, err := repostprocess.ExpandTarget(repostprocess.Target{
+			Schema:     *schemaFile,
+			Model:      *modelFile,
+			Output:     *outputDir,
+			InPlace:    *inPlace,
+			Generate:   *generate,
+			TypeScript: *typeScript,
+			Proto: repostprocess.ProtoConfig{
+				File:       *proto,
+				Package:    *protoPackage,
+				GoPackage:  *protoGoPackage,
+				Converters: *protoConverters,
+			},
+		})
+		if err != nil {
+			fmt.Printf("Error listing schemas: %v\n", err)
+			os.Exit(1)
+		}
+		for _, target := range targets {
+			processor := repostprocess.NewProcessor(target.Schema, target.Model, target.Output)
+			processor.InPlace = target.InPlace
+			processor.GenerateModel = target.Generate
+			processor.TypeScriptPath = target.TypeScript
+			processor.Proto = target.Proto
+			processor.Config = config
+			processors = append(processors, processor)
+		}
+	}
+
+	for _, processor := range processors {
+		processor.Strict = *strict
+		processor.Force = *force
+		processor.TemplatesDir = *templatesDir
+		processor.Check = *check
+	}
+
+	if *watch {
+		// The diagnostics are the point of watching, so they're always shown
+		if *report == "" {
+			*report = "text"
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		err := repostprocess.Watch(ctx, processors, *debounce, func(results []repostprocess.BatchResult) {
+			fmt.Printf("\n[%s]\n", time.Now().Format(time.TimeOnly))
+			printResults(results, *report)
+		})
+		if err != nil {
+			fmt.Printf("Error watching schemas: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Every schema is processed, so one failing doesn't hide the problems of the others
+	if failed := printResults(repostprocess.ProcessBatch(processors), *report); failed > 0 {
 		os.Exit(1)
 	}
+}
 
-	fmt.Println("Schema post-processing completed successfully!")
-	fmt.Printf("Generated files written to %s\n", *outputDir)
+// writeSchemaFromGo writes the JSON schema of the Go package in dir to schemaPath
+func writeSchemaFromGo(dir, root, schemaPath string, config *repostprocess.Config) error {
+	schema, err := repostprocess.GenerateSchema(dir, root, config)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(schemaPath), 0755); err != nil {
+		return errors.Errorf("creating schema directory: %w", err)
+	}
+	if err := os.WriteFile(schemaPath, schema, 0644); err != nil {
+		return errors.Errorf("writing schema: %w", err)
+	}
+	return nil
+}
+
+// printResults prints the outcome of each schema, and a summary when there are
+// several, returning how many failed
+func printResults(results []repostprocess.BatchResult, report string) int {
+	failed := 0
+	for _, result := range results {
+		if !printResult(result, report, len(results) > 1) {
+			failed++
+		}
+	}
+
+	if len(results) > 1 {
+		fmt.Println()
+		if err := repostprocess.WriteSummary(os.Stdout, results); err != nil {
+			fmt.Printf("Error writing summary: %v\n", err)
+			return len(results)
+		}
+	}
+
+	if failed > 0 && len(results) > 1 {
+		fmt.Printf("Error: %d of %d schemas failed or are out of date\n", failed, len(results))
+	}
+	return failed
+}
+
+// printResult prints the report and outcome of processing one schema,
+// returning whether it succeeded. With several schemas, each line names its own
+func printResult(result repostprocess.BatchResult, report string, named bool) bool {
+	processor := result.Processor
+	prefix := ""
+	if named {
+		prefix = processor.SchemaPath + ": "
+	}
+
+	// Print the report even when processing failed, since it usually explains why
+	if report != "" {
+		if err := repostprocess.WriteDiagnostics(os.Stdout, processor.Diagnostics, report); err != nil {
+			fmt.Printf("%sError writing report: %v\n", prefix, err)
+			return false
+		}
+	}
+
+	// A stale model is shown as the diff regenerating it would apply
+	if processor.Diff != "" {
+		fmt.Print(processor.Diff)
+	}
+
+	if result.Err != nil {
+		fmt.Printf("%sError processing schema: %v\n", prefix, result.Err)
+		return false
+	}
+
+	if processor.Check {
+		fmt.Printf("%sGenerated code is up to date: %s\n", prefix, processor.CheckedPath())
+		return true
+	}
+
+	if processor.TypeScriptPath != "" {
+		fmt.Printf("%sTypeScript types written to %s\n", prefix, processor.TypeScriptPath)
+	}
+	if processor.Proto.File != "" {
+		fmt.Printf("%sProtobuf definition written to %s\n", prefix, processor.Proto.File)
+	}
+	if processor.Proto.Converters != "" {
+		fmt.Printf("%sProto converters written to %s\n", prefix, processor.Proto.Converters)
+	}
+
+	fmt.Printf("%sSchema post-processing completed successfully!\n", prefix)
+	if processor.InPlace {
+		fmt.Printf("%sModel rewritten in place: %s\n", prefix, processor.ModelPath)
+		return true
+	}
+	fmt.Printf("%sGenerated files written to %s\n", prefix, processor.OutputDirPath)
+	return true
 }
 
 // runWithTestData runs the processor with test data for development/testing
@@ -2,7 +2,11 @@ package repostprocess
 
 import (
 	"encoding/json"
+	"fmt"
+	"math"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 
 	"gitlab.com/tozd/go/errors"
@@ -13,6 +17,8 @@ import (
 type SchemaAnalyzer struct {
 	schemaData map[string]interface{}
 	schemaPath string
+	// config can pick the discriminator of a parent
+	config *Config
 }
 
 // SchemaResults contains the analysis results
@@ -23,6 +29,23 @@ type SchemaResults struct {
 	DirectParentCallers map[string]ParentCallerInfo
 	ArrayParentCallers  map[string]ParentCallerInfo
 	MapParentCallers    map[string]ParentCallerInfo
+	// RootProperties lists the properties of the root schema, if it has any
+	RootProperties []string
+	// Constraints maps the JSON pointer of each object schema, whether a
+	// definition, the root or an inline object, to the validation keywords on
+	// its properties
+	Constraints map[string][]Constraint
+	// Required maps the JSON pointer of each object schema to the properties
+	// it requires, in order
+	Required map[string][]string
+	// Closed maps the JSON pointer of each object schema that sets
+	// additionalProperties to false to the properties it allows
+	Closed map[string][]string
+	// Diagnostics reports schema constructs the analyzer skipped or couldn't resolve
+	Diagnostics []Diagnostic
+	// Schema is the analyzed schema, with any definitions shared from the other
+	// schemas of a batch copied in, for generators that need more than the above
+	Schema map[string]interface{}
 }
 
 // ParentInfo holds information about a parent schema
@@ -32,6 +55,55 @@ type ParentInfo struct {
 	Children       []string
 	ConstantField  string
 	ConstantValues map[string]string
+	// Discriminator records how the children of this parent are told apart
+	Discriminator DiscriminatorStrategy
+	// ConstantKind is the JSON kind of the values in ConstantValues
+	ConstantKind ConstantKind
+	// CompositeFields lists the constant fields that together identify a child
+	CompositeFields []string
+	// CompositeValues maps each child to its values for CompositeFields, in order
+	CompositeValues map[string][]Constant
+	// PresenceFields maps each child to a required field that only it has
+	PresenceFields map[string]string
+	// Overlaps lists the pairs of children that a single input can validly match
+	Overlaps [][2]string
+	// DecisionPlan orders the key checks that pick a child when there is no discriminator
+	DecisionPlan []DecisionStep
+	// NestedParents lists the children that are themselves parents
+	NestedParents []string
+	// EmbeddedIn lists the parents that have this parent as a child
+	EmbeddedIn []string
+	// LeafChildren lists the concrete children, reached through any nested parents
+	LeafChildren []string
+	// LeafRoutes maps each leaf child to the direct child that leads to it
+	LeafRoutes map[string]string
+}
+
+// IsNested reports whether the given child of this parent is itself a parent
+func (p ParentInfo) IsNested(childName string) bool {
+	for _, nested := range p.NestedParents {
+		if nested == childName {
+			return true
+		}
+	}
+	return false
+}
+
+// isRoutedThroughNested reports whether the given leaf child is reached through a nested parent
+func (p ParentInfo) isRoutedThroughNested(leafName string) bool {
+	route, ok := p.LeafRoutes[leafName]
+	return ok && route != leafName
+}
+
+// DirectChildren returns the children of this parent that are not parents themselves
+func (p ParentInfo) DirectChildren() []string {
+	direct := []string{}
+	for _, child := range p.Children {
+		if !p.IsNested(child) {
+			direct = append(direct, child)
+		}
+	}
+	return direct
 }
 
 // ParentCallerInfo holds information about schemas that reference parents
@@ -43,6 +115,27 @@ type ParentCallerInfo struct {
 	IsMap       bool
 	IsRequired  bool
 	ParentNames []string
+	// IsNestedArray is set for arrays of arrays of the parent
+	IsNestedArray bool
+	// IsNullable is set for fields that allow null, such as anyOf a parent and null
+	IsNullable bool
+	// Root is set for callers in the root schema rather than in a definition
+	Root bool
+	// ObjectPath lists the properties leading from the root schema to the inline
+	// object holding Field; it's empty for fields of the root schema itself
+	ObjectPath []string
+}
+
+// ObjectPointer returns the JSON pointer to the object holding the caller's field
+func (c ParentCallerInfo) ObjectPointer() string {
+	var tokens []string
+	if !c.Root {
+		tokens = append(tokens, "definitions", c.Name)
+	}
+	for _, prop := range c.ObjectPath {
+		tokens = append(tokens, "properties", prop)
+	}
+	return jsonPointer(tokens...)
 }
 
 // NewSchemaAnalyzer creates a new schema analyzer
@@ -63,6 +156,52 @@ func NewSchemaAnalyzer(schemaPath string) (*SchemaAnalyzer, error) {
 	}, nil
 }
 
+// SetConfig sets the project-specific settings the analyzer applies
+func (sa *SchemaAnalyzer) SetConfig(config *Config) {
+	sa.config = config
+}
+
+// SortedParentNames returns the names of all parents, sorted so that anything
+// generated from them comes out in the same order on every run
+func (r *SchemaResults) SortedParentNames() []string {
+	names := make([]string, 0, len(r.Parents))
+	for name := range r.Parents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SortedParentCallers returns the parent callers sorted by their "Struct.field" key
+func (r *SchemaResults) SortedParentCallers() []ParentCallerInfo {
+	return sortedCallers(r.ParentCallers)
+}
+
+// sortedCallers returns the callers of a caller map ordered by key
+func sortedCallers(m map[string]ParentCallerInfo) []ParentCallerInfo {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	callers := make([]ParentCallerInfo, 0, len(keys))
+	for _, key := range keys {
+		callers = append(callers, m[key])
+	}
+	return callers
+}
+
+// sortedKeys returns the keys of a decoded JSON object in sorted order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // NewSchemaResults creates a new SchemaResults with initialized maps
 func NewSchemaResults() *SchemaResults {
 	return &SchemaResults{
@@ -72,19 +211,16 @@ func NewSchemaResults() *SchemaResults {
 		DirectParentCallers: make(map[string]ParentCallerInfo),
 		ArrayParentCallers:  make(map[string]ParentCallerInfo),
 		MapParentCallers:    make(map[string]ParentCallerInfo),
+		Constraints:         make(map[string][]Constraint),
+		Required:            make(map[string][]string),
+		Closed:              make(map[string][]string),
 	}
 }
 
 // Analyze parses the schema and identifies parents, children, and constant fields
 func (sa *SchemaAnalyzer) Analyze() (*SchemaResults, error) {
-	results := &SchemaResults{
-		Parents:             make(map[string]ParentInfo),
-		ConstantFieldNames:  make(map[string]string),
-		ParentCallers:       make(map[string]ParentCallerInfo),
-		DirectParentCallers: make(map[string]ParentCallerInfo),
-		ArrayParentCallers:  make(map[string]ParentCallerInfo),
-		MapParentCallers:    make(map[string]ParentCallerInfo),
-	}
+	results := NewSchemaResults()
+	results.Schema = sa.schemaData
 
 	// Get the definitions section from the schema
 	definitions, ok := sa.schemaData["definitions"].(map[string]interface{})
@@ -97,81 +233,104 @@ func (sa *SchemaAnalyzer) Analyze() (*SchemaResults, error) {
 		return nil, err
 	}
 
+	// Step 1b: Resolve parents whose children are themselves parents
+	if err := sa.identifyNestedParents(results); err != nil {
+		return nil, err
+	}
+
 	// Step 2: Analyze children for common constant fields
 	if err := sa.identifyConstantFields(definitions, results); err != nil {
 		return nil, err
 	}
 
+	// Step 2b: Fall back to composite and presence-based discriminators
+	if err := sa.identifyCompositeDiscriminators(definitions, results); err != nil {
+		return nil, err
+	}
+	if err := sa.identifyPresenceDiscriminators(definitions, results); err != nil {
+		return nil, err
+	}
+
+	// Step 2c: Compare the children of the remaining parents by the keys they accept
+	if err := sa.identifyDecisionPlans(definitions, results); err != nil {
+		return nil, err
+	}
+
+	// Step 2d: Report parents whose children can't be told apart
+	sa.identifyAmbiguousParents(definitions, results)
+
 	// Step 3: Find all references to parents
 	if err := sa.identifyParentCallers(definitions, results); err != nil {
 		return nil, err
 	}
 
 	// Step 4: Check for parent callers at the top level
-	if topProps, ok := sa.schemaData["properties"].(map[string]interface{}); ok {
-		if err := sa.identifyTopLevelParentCallers(topProps, results); err != nil {
-			return nil, err
+	if err := sa.identifyTopLevelParentCallers(results); err != nil {
+		return nil, err
+	}
+
+	// Step 5: Keep the required fields and validation keywords of every object
+	sa.recordObject(nil, sa.schemaData, results)
+	for _, defName := range sortedKeys(definitions) {
+		if defMap, ok := definitions[defName].(map[string]interface{}); ok {
+			sa.recordObject([]string{"definitions", defName}, defMap, results)
 		}
 	}
 
+	results.sortDiagnostics()
+
 	return results, nil
 }
 
 // identifyTopLevelParentCallers looks for references to parent types in the top level properties
-func (sa *SchemaAnalyzer) identifyTopLevelParentCallers(properties map[string]interface{}, results *SchemaResults) error {
-	// Track parent callers in the top level properties
-	for propName, propVal := range properties {
-		propMap, ok := propVal.(map[string]interface{})
+func (sa *SchemaAnalyzer) identifyTopLevelParentCallers(results *SchemaResults) error {
+	properties, ok := sa.schemaData["properties"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	results.RootProperties = sortedKeys(properties)
+
+	return sa.identifyNestedParentCallers(nil, sa.schemaData, results)
+}
+
+// identifyNestedParentCallers looks for references to parent types in an object
+// inside the root schema, recursing into inline object properties; path lists the
+// properties leading from the root schema to the object
+func (sa *SchemaAnalyzer) identifyNestedParentCallers(path []string, object map[string]interface{}, results *SchemaResults) error {
+	properties, ok := object["properties"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	required := requiredSet(object)
+
+	// Callers in the root schema have no definition name; nested objects are
+	// named by the property that holds them
+	name := ""
+	if len(path) > 0 {
+		name = path[len(path)-1]
+	}
+
+	for _, propName := range sortedKeys(properties) {
+		propMap, ok := properties[propName].(map[string]interface{})
 		if !ok {
-			// Check if this is a nested object with its own properties
-			if nestedObj, ok := propVal.(map[string]interface{}); ok {
-				if props, ok := nestedObj["properties"].(map[string]interface{}); ok {
-					// This is a nested object, check its properties for parent callers
-					if err := sa.identifyNestedParentCallers(propName, props, results); err != nil {
-						return err
-					}
-				}
-			}
 			continue
 		}
 
-		// Check for direct reference
-		ref, hasRef := propMap["$ref"].(string)
-		if hasRef {
-			refName := extractRefName(ref)
-			if _, isParent := results.Parents[refName]; isParent {
-				// This is a direct parent caller at top level
-				caller := ParentCallerInfo{
-					Name:        propName, // Use property name as the struct name
-					Field:       propName, // Field is the same as name at top level
-					ParentRef:   refName,
-					IsRequired:  true, // Assume required at top level
-					ParentNames: []string{refName},
-				}
-				results.ParentCallers[propName+"."+propName] = caller
-				results.DirectParentCallers[propName+"."+propName] = caller
-			}
+		if caller, ok := parentReference(propMap, results); ok {
+			caller.Name = name
+			caller.Field = propName
+			caller.IsRequired = required[propName]
+			caller.Root = true
+			caller.ObjectPath = append([]string{}, path...)
+			results.addParentCaller(strings.Join(append(append([]string{}, path...), propName), "."), caller)
 			continue
 		}
 
-		// Check for array reference
-		items, ok := propMap["items"].(map[string]interface{})
-		if ok {
-			if itemRef, ok := items["$ref"].(string); ok {
-				refName := extractRefName(itemRef)
-				if _, isParent := results.Parents[refName]; isParent {
-					// This is an array parent caller at top level
-					caller := ParentCallerInfo{
-						Name:        "", // Empty name for top level
-						Field:       propName,
-						ParentRef:   refName,
-						IsArray:     true,
-						IsRequired:  true, // Assume required at top level
-						ParentNames: []string{refName},
-					}
-					results.ParentCallers[propName] = caller
-					results.ArrayParentCallers[propName] = caller
-				}
+		// Inline objects become their own structs, so look inside them too
+		if _, hasRef := propMap["$ref"]; !hasRef {
+			if err := sa.identifyNestedParentCallers(append(append([]string{}, path...), propName), propMap, results); err != nil {
+				return err
 			}
 		}
 	}
@@ -179,63 +338,43 @@ func (sa *SchemaAnalyzer) identifyTopLevelParentCallers(properties map[string]in
 	return nil
 }
 
-// identifyNestedParentCallers looks for references to parent types in nested object properties
-func (sa *SchemaAnalyzer) identifyNestedParentCallers(parentName string, properties map[string]interface{}, results *SchemaResults) error {
-	// Check each property in the nested object
-	for propName, propVal := range properties {
-		propMap, ok := propVal.(map[string]interface{})
-		if !ok {
-			continue
+// recordObject records the required fields and validation keywords of an
+// object schema, at the pointer made of tokens, and of the inline objects in
+// its properties, items and additionalProperties
+func (sa *SchemaAnalyzer) recordObject(tokens []string, object map[string]interface{}, results *SchemaResults) {
+	if properties, ok := object["properties"].(map[string]interface{}); ok {
+		pointer := jsonPointer(tokens...)
+		results.Constraints[pointer] = propertyConstraints(object)
+		results.Required[pointer] = requiredList(object)
+		if additional, ok := object["additionalProperties"].(bool); ok && !additional && object["patternProperties"] == nil {
+			results.Closed[pointer] = sortedKeys(properties)
 		}
 
-		// Check for direct reference
-		ref, hasRef := propMap["$ref"].(string)
-		if hasRef {
-			refName := extractRefName(ref)
-			if _, isParent := results.Parents[refName]; isParent {
-				// This is a direct parent caller in a nested object
-				caller := ParentCallerInfo{
-					Name:        parentName, // Parent object name
-					Field:       propName,   // Field within the parent
-					ParentRef:   refName,
-					IsRequired:  true, // Assume required
-					ParentNames: []string{refName},
-				}
-				results.ParentCallers[parentName+"."+propName] = caller
-				results.DirectParentCallers[parentName+"."+propName] = caller
-			}
-			continue
-		}
-
-		// Check for array reference
-		items, ok := propMap["items"].(map[string]interface{})
-		if ok {
-			if itemRef, ok := items["$ref"].(string); ok {
-				refName := extractRefName(itemRef)
-				if _, isParent := results.Parents[refName]; isParent {
-					// This is an array parent caller in a nested object
-					caller := ParentCallerInfo{
-						Name:        parentName, // Parent object name
-						Field:       propName,   // Field within the parent
-						ParentRef:   refName,
-						IsArray:     true,
-						IsRequired:  true, // Assume required
-						ParentNames: []string{refName},
-					}
-					results.ParentCallers[parentName+"."+propName] = caller
-					results.ArrayParentCallers[parentName+"."+propName] = caller
-				}
-			}
+		for _, propName := range sortedKeys(properties) {
+			sa.recordInline(append(tokens, "properties", propName), properties[propName], results)
 		}
 	}
+	sa.recordInline(append(tokens, "items"), object["items"], results)
+	sa.recordInline(append(tokens, "additionalProperties"), object["additionalProperties"], results)
+}
 
-	return nil
+// recordInline records a schema that isn't a reference, which go-jsonschema
+// turns into a type of its own
+func (sa *SchemaAnalyzer) recordInline(tokens []string, schema interface{}, results *SchemaResults) {
+	schemaMap, ok := schema.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if _, hasRef := schemaMap["$ref"]; hasRef {
+		return
+	}
+	sa.recordObject(append([]string{}, tokens...), schemaMap, results)
 }
 
 // identifyParentCallers identifies schemas that refer to parent schemas
 func (sa *SchemaAnalyzer) identifyParentCallers(definitions map[string]interface{}, results *SchemaResults) error {
-	for defName, defObj := range definitions {
-		defMap, ok := defObj.(map[string]interface{})
+	for _, defName := range sortedKeys(definitions) {
+		defMap, ok := definitions[defName].(map[string]interface{})
 		if !ok {
 			continue
 		}
@@ -246,115 +385,153 @@ func (sa *SchemaAnalyzer) identifyParentCallers(definitions map[string]interface
 		}
 
 		// Check required fields
-		required := make(map[string]bool)
-		requiredArr, ok := defMap["required"].([]interface{})
-		if ok {
-			for _, req := range requiredArr {
-				reqStr, ok := req.(string)
-				if ok {
-					required[reqStr] = true
-				}
-			}
-		}
+		required := requiredSet(defMap)
 
 		// Check each property for references to parents
-		for propName, propVal := range properties {
-			propMap, ok := propVal.(map[string]interface{})
+		for _, propName := range sortedKeys(properties) {
+			propMap, ok := properties[propName].(map[string]interface{})
 			if !ok {
 				continue
 			}
 
-			// Check for direct reference
-			ref, hasRef := propMap["$ref"].(string)
-			if hasRef {
-				refName := extractRefName(ref)
-				if _, isParent := results.Parents[refName]; isParent {
-					// This is a direct parent caller
-					caller := ParentCallerInfo{
-						Name:        defName,
-						Field:       propName,
-						ParentRef:   refName,
-						IsRequired:  required[propName],
-						ParentNames: []string{refName},
-					}
-					results.ParentCallers[defName+"."+propName] = caller
-					results.DirectParentCallers[defName+"."+propName] = caller
-				}
-				continue
+			if caller, ok := parentReference(propMap, results); ok {
+				caller.Name = defName
+				caller.Field = propName
+				caller.IsRequired = required[propName]
+				results.addParentCaller(defName+"."+propName, caller)
 			}
+		}
+	}
+	return nil
+}
 
-			// Check for array reference
-			items, ok := propMap["items"].(map[string]interface{})
-			if ok {
-				// Check for nested array
-				if nestedItems, ok := items["items"].(map[string]interface{}); ok {
-					// This is a nested array
-					if itemRef, ok := nestedItems["$ref"].(string); ok {
-						refName := extractRefName(itemRef)
-						if _, isParent := results.Parents[refName]; isParent {
-							// This is a nested array parent caller
-							caller := ParentCallerInfo{
-								Name:        defName,
-								Field:       propName,
-								ParentRef:   refName,
-								IsArray:     true,
-								IsRequired:  required[propName],
-								ParentNames: []string{refName},
-							}
-							results.ParentCallers[defName+"."+propName] = caller
-							results.ArrayParentCallers[defName+"."+propName] = caller
-						}
-					}
-				} else {
-					// Check for single array reference
-					if itemRef, ok := items["$ref"].(string); ok {
-						refName := extractRefName(itemRef)
-						if _, isParent := results.Parents[refName]; isParent {
-							// This is an array parent caller
-							caller := ParentCallerInfo{
-								Name:        defName,
-								Field:       propName,
-								ParentRef:   refName,
-								IsArray:     true,
-								IsRequired:  required[propName],
-								ParentNames: []string{refName},
-							}
-							results.ParentCallers[defName+"."+propName] = caller
-							results.ArrayParentCallers[defName+"."+propName] = caller
-						}
-					}
-				}
-			}
+// parentReference reports whether a property refers to a parent directly, as the
+// items of an array (or of an array of arrays), or as the values of a map
+func parentReference(propMap map[string]interface{}, results *SchemaResults) (ParentCallerInfo, bool) {
+	isParentRef := func(schema map[string]interface{}) (string, bool) {
+		ref, ok := schema["$ref"].(string)
+		if !ok {
+			return "", false
+		}
+		refName := extractRefName(ref)
+		_, isParent := results.Parents[refName]
+		return refName, isParent
+	}
 
-			// Check for map reference
-			additionalProperties, ok := propMap["additionalProperties"].(map[string]interface{})
-			if ok {
-				if itemRef, ok := additionalProperties["$ref"].(string); ok {
-					refName := extractRefName(itemRef)
-					if _, isParent := results.Parents[refName]; isParent {
-						// This is a map parent caller
-						caller := ParentCallerInfo{
-							Name:        defName,
-							Field:       propName,
-							ParentRef:   refName,
-							IsMap:       true,
-							IsRequired:  required[propName],
-							ParentNames: []string{refName},
-						}
-						results.ParentCallers[defName+"."+propName] = caller
-						results.MapParentCallers[defName+"."+propName] = caller
-					}
-				}
+	// Check for direct reference
+	if refName, ok := isParentRef(propMap); ok {
+		return ParentCallerInfo{ParentRef: refName, ParentNames: []string{refName}, IsNullable: allowsNull(propMap)}, true
+	}
+
+	// Check for a nullable reference, written as anyOf or oneOf the parent and null
+	if schema, ok := nullableSchema(propMap); ok {
+		if caller, ok := parentReference(schema, results); ok {
+			caller.IsNullable = true
+			return caller, true
+		}
+	}
+
+	// Check for array reference, including nested arrays
+	if items, ok := propMap["items"].(map[string]interface{}); ok {
+		nested := false
+		if nestedItems, ok := items["items"].(map[string]interface{}); ok {
+			items = nestedItems
+			nested = true
+		}
+		if refName, ok := isParentRef(items); ok {
+			return ParentCallerInfo{ParentRef: refName, IsArray: true, IsNestedArray: nested, ParentNames: []string{refName}, IsNullable: allowsNull(propMap)}, true
+		}
+	}
+
+	// Check for map reference
+	if additionalProperties, ok := propMap["additionalProperties"].(map[string]interface{}); ok {
+		if refName, ok := isParentRef(additionalProperties); ok {
+			return ParentCallerInfo{ParentRef: refName, IsMap: true, ParentNames: []string{refName}, IsNullable: allowsNull(propMap)}, true
+		}
+	}
+
+	return ParentCallerInfo{}, false
+}
+
+// nullableSchema returns the schema in an anyOf or oneOf made of one schema and
+// {"type": "null"}
+func nullableSchema(propMap map[string]interface{}) (map[string]interface{}, bool) {
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		options, ok := propMap[keyword].([]interface{})
+		if !ok || len(options) != 2 {
+			continue
+		}
+
+		var schema map[string]interface{}
+		hasNull := false
+		for _, option := range options {
+			optionMap, ok := option.(map[string]interface{})
+			if !ok {
+				continue
 			}
+			if optionMap["type"] == "null" {
+				hasNull = true
+			} else {
+				schema = optionMap
+			}
+		}
+		if hasNull && schema != nil {
+			return schema, true
 		}
 	}
-	return nil
+	return nil, false
+}
+
+// allowsNull reports whether a schema's type list includes null
+func allowsNull(propMap map[string]interface{}) bool {
+	types, _ := propMap["type"].([]interface{})
+	for _, t := range types {
+		if t == "null" {
+			return true
+		}
+	}
+	return false
+}
+
+// requiredSet returns the required property names of an object schema
+func requiredSet(schema map[string]interface{}) map[string]bool {
+	required := make(map[string]bool)
+	for _, name := range requiredList(schema) {
+		required[name] = true
+	}
+	return required
+}
+
+// requiredList returns the required property names of an object schema, in order
+func requiredList(schema map[string]interface{}) []string {
+	var required []string
+	requiredArr, _ := schema["required"].([]interface{})
+	for _, req := range requiredArr {
+		if reqStr, ok := req.(string); ok {
+			required = append(required, reqStr)
+		}
+	}
+	return required
+}
+
+// addParentCaller records a caller under its key in ParentCallers and in the
+// map for its kind
+func (r *SchemaResults) addParentCaller(key string, caller ParentCallerInfo) {
+	r.ParentCallers[key] = caller
+	switch {
+	case caller.IsArray:
+		r.ArrayParentCallers[key] = caller
+	case caller.IsMap:
+		r.MapParentCallers[key] = caller
+	default:
+		r.DirectParentCallers[key] = caller
+	}
 }
 
 // identifyParents identifies schemas with anyOf that are parent types
 func (sa *SchemaAnalyzer) identifyParents(definitions map[string]interface{}, results *SchemaResults) error {
-	for defName, defObj := range definitions {
-		defMap, ok := defObj.(map[string]interface{})
+	for _, defName := range sortedKeys(definitions) {
+		defMap, ok := definitions[defName].(map[string]interface{})
 		if !ok {
 			continue
 		}
@@ -373,20 +550,39 @@ func (sa *SchemaAnalyzer) identifyParents(definitions map[string]interface{}, re
 			ConstantValues: make(map[string]string),
 		}
 
-		// Extract references to children
-		for _, child := range anyOf {
+		// Extract references to children, ignoring repeated references
+		seen := make(map[string]bool)
+		for i, child := range anyOf {
+			pointer := jsonPointer("definitions", defName, "anyOf", strconv.Itoa(i))
+
 			childMap, ok := child.(map[string]interface{})
 			if !ok {
+				results.addDiagnostic(pointer, SeverityWarning, DiagnosticInlineChild,
+					fmt.Sprintf("anyOf entry %d of %s is not a schema object and is skipped", i, defName))
 				continue
 			}
 
 			ref, ok := childMap["$ref"].(string)
 			if !ok {
+				results.addDiagnostic(pointer, SeverityWarning, DiagnosticInlineChild,
+					fmt.Sprintf("anyOf entry %d of %s is an inline schema rather than a $ref and is skipped", i, defName))
 				continue
 			}
 
 			childName := extractRefName(ref)
+			if _, ok := definitions[childName]; !ok {
+				results.addDiagnostic(pointer, SeverityError, DiagnosticMissingChild,
+					fmt.Sprintf("%s refers to %s, which is not in definitions", defName, ref))
+				continue
+			}
+
 			parent.ChildrenRefs = append(parent.ChildrenRefs, childName)
+			if seen[childName] {
+				results.addDiagnostic(pointer, SeverityWarning, DiagnosticDuplicateChild,
+					fmt.Sprintf("%s lists %s more than once", defName, childName))
+				continue
+			}
+			seen[childName] = true
 			parent.Children = append(parent.Children, childName)
 		}
 
@@ -398,99 +594,209 @@ func (sa *SchemaAnalyzer) identifyParents(definitions map[string]interface{}, re
 	return nil
 }
 
+// identifyNestedParents links parents that appear as children of other parents
+// and resolves the concrete leaf children of every parent
+func (sa *SchemaAnalyzer) identifyNestedParents(results *SchemaResults) error {
+	// Link nested parents to the parents that embed them
+	for _, parentName := range results.SortedParentNames() {
+		parent := results.Parents[parentName]
+		for _, childName := range parent.Children {
+			nested, isParent := results.Parents[childName]
+			if !isParent {
+				continue
+			}
+			if childName == parentName {
+				return errors.Errorf("parent %s lists itself as a child", parentName)
+			}
+			parent.NestedParents = append(parent.NestedParents, childName)
+			nested.EmbeddedIn = append(nested.EmbeddedIn, parentName)
+			results.Parents[childName] = nested
+		}
+		results.Parents[parentName] = parent
+	}
+
+	// Resolve the leaf children through every layer of nesting
+	for _, parentName := range results.SortedParentNames() {
+		parent := results.Parents[parentName]
+		parent.LeafChildren = []string{}
+		parent.LeafRoutes = make(map[string]string)
+		for _, childName := range parent.Children {
+			leaves, err := resolveLeafChildren(childName, results, map[string]bool{parentName: true})
+			if err != nil {
+				return errors.Errorf("resolving children of %s: %w", parentName, err)
+			}
+			for _, leaf := range leaves {
+				if _, ok := parent.LeafRoutes[leaf]; ok {
+					continue
+				}
+				parent.LeafRoutes[leaf] = childName
+				parent.LeafChildren = append(parent.LeafChildren, leaf)
+			}
+		}
+		results.Parents[parentName] = parent
+	}
+
+	return nil
+}
+
+// resolveLeafChildren returns the concrete types reachable from the given child
+func resolveLeafChildren(childName string, results *SchemaResults, visiting map[string]bool) ([]string, error) {
+	nested, isParent := results.Parents[childName]
+	if !isParent {
+		return []string{childName}, nil
+	}
+	if visiting[childName] {
+		return nil, errors.Errorf("cycle detected through parent %s", childName)
+	}
+
+	visiting[childName] = true
+	defer delete(visiting, childName)
+
+	leaves := []string{}
+	for _, grandchild := range nested.Children {
+		found, err := resolveLeafChildren(grandchild, results, visiting)
+		if err != nil {
+			return nil, err
+		}
+		leaves = append(leaves, found...)
+	}
+	return leaves, nil
+}
+
 // identifyConstantFields analyzes children schemas to find common "type" or other constant fields
 func (sa *SchemaAnalyzer) identifyConstantFields(definitions map[string]interface{}, results *SchemaResults) error {
 	// For each parent, check its children for constant fields
-	for parentName, parent := range results.Parents {
-		if len(parent.Children) == 0 {
+	for _, parentName := range results.SortedParentNames() {
+		parent := results.Parents[parentName]
+		// Nested parents are looked through, so the leaves are what get compared
+		children := parent.LeafChildren
+		if len(children) == 0 {
 			continue
 		}
 
-		// Check first child for potential constant fields
-		firstChildName := parent.Children[0]
-		firstChild, ok := definitions[firstChildName].(map[string]interface{})
-		if !ok {
-			continue
+		// A discriminator from the config is the only candidate
+		candidates := constantFieldCandidates(definitions, children)
+		configured := sa.config.Parent(parentName).Discriminator
+		if configured != "" {
+			candidates = []string{configured}
 		}
 
-		properties, ok := firstChild["properties"].(map[string]interface{})
+		// Check each candidate field to see if it holds a unique constant in every child
+		for _, propName := range candidates {
+			potentialValues := make(map[string]string)
+			var kind ConstantKind
+			allHaveIt := true
+			seenValues := make(map[string]bool)
+
+			for _, childName := range children {
+				constantValue, valueKind, ok := childConstantValue(definitions, childName, propName)
+				if !ok || (kind != "" && valueKind != kind) || seenValues[constantValue] {
+					allHaveIt = false
+					break
+				}
+				kind = valueKind
+				seenValues[constantValue] = true
+				potentialValues[childName] = constantValue
+			}
+
+			if allHaveIt && len(potentialValues) == len(children) {
+				// This is a valid constant field, update the parent info
+				parent.Discriminator = DiscriminatorConstant
+				parent.ConstantField = propName
+				parent.ConstantKind = kind
+				parent.ConstantValues = potentialValues
+				results.Parents[parentName] = parent
+				results.ConstantFieldNames[parentName] = propName
+				break // Found a constant field, no need to check others
+			}
+		}
+
+		if configured != "" && parent.ConstantField != configured {
+			results.addDiagnostic(jsonPointer("definitions", parentName), SeverityError, DiagnosticInvalidDiscriminator,
+				fmt.Sprintf("configured discriminator %q doesn't hold a unique constant in every child", configured))
+		}
+	}
+
+	return nil
+}
+
+// constantFieldCandidates returns the sorted property names of the first child that
+// could act as a constant field
+func constantFieldCandidates(definitions map[string]interface{}, children []string) []string {
+	candidates := []string{}
+	properties := definitionProperties(definitions, children[0])
+	for _, propName := range sortedKeys(properties) {
+		propVal := properties[propName]
+		propMap, ok := propVal.(map[string]interface{})
 		if !ok {
 			continue
 		}
 
-		// Check each property to see if it's a potential constant field
-		for propName, propVal := range properties {
-			propMap, ok := propVal.(map[string]interface{})
-			if !ok {
-				continue
-			}
+		// Check if this property has enum or const, which indicates it could be a constant field
+		_, hasEnum := propMap["enum"]
+		_, hasConst := propMap["const"]
+		if hasEnum || hasConst || propName == "type" {
+			candidates = append(candidates, propName)
+		}
+	}
+	sort.Strings(candidates)
+	return candidates
+}
 
-			// Check if this property has enum or const, which indicates it could be a constant field
-			_, hasEnum := propMap["enum"]
-			_, hasConst := propMap["const"]
-
-			// If this is a potential constant field, check all children to see if they have it
-			if hasEnum || hasConst || propName == "type" {
-				allHaveIt := true
-				potentialValues := make(map[string]string)
-
-				// Check if all children have this property as a constant
-				for _, childName := range parent.Children {
-					child, ok := definitions[childName].(map[string]interface{})
-					if !ok {
-						allHaveIt = false
-						break
-					}
-
-					childProps, ok := child["properties"].(map[string]interface{})
-					if !ok {
-						allHaveIt = false
-						break
-					}
-
-					childProp, ok := childProps[propName].(map[string]interface{})
-					if !ok {
-						allHaveIt = false
-						break
-					}
-
-					// Check for enum or const value
-					var constantValue string
-					if enum, ok := childProp["enum"].([]interface{}); ok && len(enum) == 1 {
-						if strVal, ok := enum[0].(string); ok {
-							constantValue = strVal
-						}
-					} else if constVal, ok := childProp["const"]; ok {
-						if strVal, ok := constVal.(string); ok {
-							constantValue = strVal
-						}
-					} else if propName == "type" && childProp["type"] == "string" {
-						// For "type" fields, use the child name as a default value
-						constantValue = strings.ToLower(childName)
-					}
-
-					if constantValue == "" {
-						allHaveIt = false
-						break
-					}
-
-					potentialValues[childName] = constantValue
-				}
+// childConstantValue returns the constant held by a child's property along with its kind
+func childConstantValue(definitions map[string]interface{}, childName, propName string) (string, ConstantKind, bool) {
+	childProp, ok := definitionProperties(definitions, childName)[propName].(map[string]interface{})
+	if !ok {
+		return "", "", false
+	}
 
-				if allHaveIt && len(potentialValues) == len(parent.Children) {
-					// This is a valid constant field, update the parent info
-					updatedParent := parent
-					updatedParent.ConstantField = propName
-					updatedParent.ConstantValues = potentialValues
-					results.Parents[parentName] = updatedParent
-					results.ConstantFieldNames[parentName] = propName
-					break // Found a constant field, no need to check others
-				}
-			}
+	// Check for enum or const value
+	if enum, ok := childProp["enum"].([]interface{}); ok {
+		if len(enum) != 1 {
+			return "", "", false
 		}
+		return formatConstant(enum[0])
+	}
+	if constVal, ok := childProp["const"]; ok {
+		return formatConstant(constVal)
+	}
+	if propName == "type" && childProp["type"] == "string" {
+		// For "type" fields, use the child name as a default value
+		return strings.ToLower(childName), ConstantKindString, true
 	}
 
-	return nil
+	return "", "", false
+}
+
+// formatConstant converts a decoded JSON constant to its string form,
+// accepting strings, integers and booleans
+func formatConstant(value interface{}) (string, ConstantKind, bool) {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return "", "", false
+		}
+		return v, ConstantKindString, true
+	case float64:
+		if v != math.Trunc(v) {
+			return "", "", false
+		}
+		return strconv.FormatInt(int64(v), 10), ConstantKindInteger, true
+	case bool:
+		return strconv.FormatBool(v), ConstantKindBoolean, true
+	default:
+		return "", "", false
+	}
+}
+
+// definitionProperties returns the properties of a definition, or nil if it has none
+func definitionProperties(definitions map[string]interface{}, name string) map[string]interface{} {
+	definition, ok := definitions[name].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	properties, _ := definition["properties"].(map[string]interface{})
+	return properties
 }
 
 // extractRefName extracts the schema name from a reference string
@@ -121,3 +121,83 @@ func TestSchemaAnalyzer_SimpleSchema(t *testing.T) {
 		t.Error("Expected array parent caller for shapes not found")
 	}
 }
+
+func TestSchemaAnalyzer_NestedSchema(t *testing.T) {
+	// Create analyzer for nested schema
+	schemaPath := filepath.Join("testdata", "nested", "nested.schema.json")
+	analyzer, err := NewSchemaAnalyzer(schemaPath)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+
+	// Run the analysis
+	results, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Failed to analyze schema: %v", err)
+	}
+
+	fillInfo, ok := results.Parents["Fill"]
+	if !ok {
+		t.Fatal("Expected Fill parent not identified")
+	}
+	colorInfo, ok := results.Parents["Color"]
+	if !ok {
+		t.Fatal("Expected Color parent not identified")
+	}
+
+	// Check that Color is recognized as a parent nested inside Fill
+	if len(fillInfo.NestedParents) != 1 || fillInfo.NestedParents[0] != "Color" {
+		t.Errorf("Expected Fill.NestedParents to be [Color], got %v", fillInfo.NestedParents)
+	}
+	if len(colorInfo.EmbeddedIn) != 1 || colorInfo.EmbeddedIn[0] != "Fill" {
+		t.Errorf("Expected Color.EmbeddedIn to be [Fill], got %v", colorInfo.EmbeddedIn)
+	}
+
+	// Check that the leaves of Fill are resolved through Color
+	expectedRoutes := map[string]string{
+		"RGBColor": "Color",
+		"HSLColor": "Color",
+		"Gradient": "Gradient",
+	}
+	if len(fillInfo.LeafChildren) != len(expectedRoutes) {
+		t.Errorf("Expected %d leaf children for Fill, got %v", len(expectedRoutes), fillInfo.LeafChildren)
+	}
+	for leaf, expectedRoute := range expectedRoutes {
+		if route := fillInfo.LeafRoutes[leaf]; route != expectedRoute {
+			t.Errorf("Expected leaf %s to route through %s, got '%s'", leaf, expectedRoute, route)
+		}
+	}
+
+	// Check that the constant field is found across the layers
+	if fillInfo.ConstantField != "kind" {
+		t.Errorf("Expected Fill.ConstantField to be 'kind', got '%s'", fillInfo.ConstantField)
+	}
+	if colorInfo.ConstantField != "kind" {
+		t.Errorf("Expected Color.ConstantField to be 'kind', got '%s'", colorInfo.ConstantField)
+	}
+	if value := fillInfo.ConstantValues["HSLColor"]; value != "hsl" {
+		t.Errorf("Expected Fill constant value for HSLColor to be 'hsl', got '%s'", value)
+	}
+}
+
+func TestSchemaAnalyzer_DuplicateChildren(t *testing.T) {
+	// The color schema lists several children more than once
+	schemaPath := filepath.Join("testdata", "color", "color.schema.json")
+	analyzer, err := NewSchemaAnalyzer(schemaPath)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+
+	results, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Failed to analyze schema: %v", err)
+	}
+
+	colorInfo := results.Parents["Color"]
+	if len(colorInfo.Children) != 8 {
+		t.Errorf("Expected 8 unique Color children, got %d: %v", len(colorInfo.Children), colorInfo.Children)
+	}
+	if len(colorInfo.ChildrenRefs) != 15 {
+		t.Errorf("Expected 15 Color child references, got %d", len(colorInfo.ChildrenRefs))
+	}
+}
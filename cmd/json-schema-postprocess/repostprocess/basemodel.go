@@ -0,0 +1,52 @@
+package repostprocess
+
+import (
+	"log"
+	"path/filepath"
+
+	"github.com/atombender/go-jsonschema/pkg/generator"
+	"gitlab.com/tozd/go/errors"
+)
+
+// GenerateModel runs go-jsonschema on a schema, with the settings of
+// `go tool go-jsonschema <schema> -o=<model> -p=<package>`, and returns the
+// source of the model it would write to modelPath
+func GenerateModel(schemaPath, modelPath, packageName string) ([]byte, error) {
+	gen, err := generator.New(generator.Config{
+		DefaultOutputName:  modelPath,
+		DefaultPackageName: packageName,
+		Tags:               []string{"json", "yaml", "mapstructure"},
+		YAMLExtensions:     []string{".yml", ".yaml"},
+		Warner: func(message string) {
+			log.Printf("Warning: go-jsonschema: %s", message)
+		},
+	})
+	if err != nil {
+		return nil, errors.Errorf("creating go-jsonschema generator: %w", err)
+	}
+
+	if err := gen.DoFile(schemaPath); err != nil {
+		return nil, errors.Errorf("generating model with go-jsonschema: %w", err)
+	}
+
+	source, ok := gen.Sources()[modelPath]
+	if !ok {
+		return nil, errors.Errorf("go-jsonschema generated no model for %s", schemaPath)
+	}
+	return source, nil
+}
+
+// modelPackageName returns the package a generated model declares: the
+// config's package, or else the name of the model's directory, as the
+// generate-jsonschema task names them
+func modelPackageName(modelPath string, config *Config) (string, error) {
+	dir, err := filepath.Abs(filepath.Dir(modelPath))
+	if err != nil {
+		return "", errors.Errorf("resolving model directory: %w", err)
+	}
+	name := config.PackageName(filepath.Base(dir))
+	if !isIdentifier(name) {
+		return "", errors.Errorf("model directory %s isn't a valid package name, set one in the config", name)
+	}
+	return name, nil
+}
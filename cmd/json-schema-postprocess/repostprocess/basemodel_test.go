@@ -0,0 +1,71 @@
+package repostprocess
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateModel(t *testing.T) {
+	schemaPath := filepath.Join("testdata", "color", "color.schema.json")
+	modelPath := filepath.Join("testdata", "color", "model.gen.go")
+
+	source, err := GenerateModel(schemaPath, modelPath, "color")
+	if err != nil {
+		t.Fatalf("Failed to generate model: %v", err)
+	}
+
+	// The model matches the one `go tool go-jsonschema` wrote for the fixture
+	expected, err := os.ReadFile(modelPath)
+	if err != nil {
+		t.Fatalf("Failed to read model file: %v", err)
+	}
+	if !bytes.Equal(source, expected) {
+		t.Errorf("Generated model differs from %s", modelPath)
+	}
+}
+
+func TestProcessor_GenerateModel(t *testing.T) {
+	schemaPath := filepath.Join("testdata", "color", "color.schema.json")
+	modelPath := filepath.Join(t.TempDir(), "palette", "model.go")
+
+	// The model and its package don't exist until the processor generates them
+	processor := NewProcessor(schemaPath, modelPath, "")
+	processor.InPlace = true
+	processor.GenerateModel = true
+	if err := processor.Process(); err != nil {
+		t.Fatalf("Failed to process with a generated model: %v", err)
+	}
+
+	first, err := os.ReadFile(modelPath)
+	if err != nil {
+		t.Fatalf("Failed to read model file: %v", err)
+	}
+	modelStr := string(first)
+	checkForContent(t, modelStr, generatedHeader)
+	checkForContent(t, modelStr, "package palette")
+	checkForContent(t, modelStr, "type Color interface {")
+
+	// The model is generated afresh, so rerunning gives the same result
+	if err := processor.Process(); err != nil {
+		t.Fatalf("Failed to reprocess with a generated model: %v", err)
+	}
+	second, err := os.ReadFile(modelPath)
+	if err != nil {
+		t.Fatalf("Failed to read model file: %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Errorf("Reprocessing changed the model")
+	}
+
+	// A configured package replaces the directory name
+	outputDir := t.TempDir()
+	processor = NewProcessor(schemaPath, filepath.Join(outputDir, "model.gen.go"), outputDir)
+	processor.GenerateModel = true
+	processor.Config = &Config{Package: "colors"}
+	if err := processor.Process(); err != nil {
+		t.Fatalf("Failed to process with a configured package: %v", err)
+	}
+	checkForContent(t, readModel(t, outputDir), "package colors")
+}
@@ -0,0 +1,332 @@
+package repostprocess
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"gitlab.com/tozd/go/errors"
+)
+
+// NamePlaceholder stands for a schema's name, its file name without
+// .schema.json, in the model and output paths of a target
+const NamePlaceholder = "{name}"
+
+// schemaSuffix ends the name of every schema file a directory is searched for
+const schemaSuffix = ".schema.json"
+
+// BatchResult is the outcome of processing one schema of a batch
+type BatchResult struct {
+	Processor *Processor
+	// Results is the analysis of the schema, or nil if it couldn't be analyzed
+	Results *SchemaResults
+	Err     error
+}
+
+// ExpandSchemas returns the schema files a path names: every *.schema.json
+// under a directory, the matches of a glob, or the file itself
+func ExpandSchemas(path string) ([]string, error) {
+	if strings.ContainsAny(path, "*?[") {
+		matches, err := filepath.Glob(path)
+		if err != nil {
+			return nil, errors.Errorf("matching schemas %s: %w", path, err)
+		}
+		if len(matches) == 0 {
+			return nil, errors.Errorf("no schemas match %s", path)
+		}
+		return matches, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Errorf("reading schema path: %w", err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var schemas []string
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(d.Name(), schemaSuffix) {
+			schemas = append(schemas, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Errorf("searching %s for schemas: %w", path, err)
+	}
+	if len(schemas) == 0 {
+		return nil, errors.Errorf("no *%s files in %s", schemaSuffix, path)
+	}
+	return schemas, nil
+}
+
+// SchemaName returns the name a schema file stands for in NamePlaceholder
+func SchemaName(path string) string {
+	name := filepath.Base(path)
+	name = strings.TrimSuffix(name, schemaSuffix)
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// ExpandTarget returns a target for each schema the target's Schema names,
+// with NamePlaceholder in its paths and proto packages replaced by the schema's name
+func ExpandTarget(target Target) ([]Target, error) {
+	schemas, err := ExpandSchemas(target.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	// Schemas sharing a model or output would overwrite each other
+	if len(schemas) > 1 {
+		if !strings.Contains(target.Model, NamePlaceholder) {
+			return nil, errors.Errorf("model %s needs %s to tell the %d schemas of %s apart", target.Model, NamePlaceholder, len(schemas), target.Schema)
+		}
+		if target.Output != "" && !strings.Contains(target.Output, NamePlaceholder) {
+			return nil, errors.Errorf("output %s needs %s to tell the %d schemas of %s apart", target.Output, NamePlaceholder, len(schemas), target.Schema)
+		}
+		if target.TypeScript != "" && !strings.Contains(target.TypeScript, NamePlaceholder) {
+			return nil, errors.Errorf("typescript %s needs %s to tell the %d schemas of %s apart", target.TypeScript, NamePlaceholder, len(schemas), target.Schema)
+		}
+		for _, path := range []string{target.Proto.File, target.Proto.Converters} {
+			if path != "" && !strings.Contains(path, NamePlaceholder) {
+				return nil, errors.Errorf("proto %s needs %s to tell the %d schemas of %s apart", path, NamePlaceholder, len(schemas), target.Schema)
+			}
+		}
+	}
+
+	targets := make([]Target, 0, len(schemas))
+	for _, schema := range schemas {
+		name := SchemaName(schema)
+		expanded := target
+		expanded.Schema = schema
+		expanded.Model = strings.ReplaceAll(target.Model, NamePlaceholder, name)
+		expanded.Output = strings.ReplaceAll(target.Output, NamePlaceholder, name)
+		expanded.TypeScript = strings.ReplaceAll(target.TypeScript, NamePlaceholder, name)
+		for _, p := range []*string{&expanded.Proto.File, &expanded.Proto.Package, &expanded.Proto.GoPackage, &expanded.Proto.Converters} {
+			*p = strings.ReplaceAll(*p, NamePlaceholder, name)
+		}
+		targets = append(targets, expanded)
+	}
+	return targets, nil
+}
+
+// ProcessBatch processes several schemas in one run. The schemas are analyzed
+// concurrently, each with the definitions it refers to in the others copied
+// in, and then each model is generated in turn. A schema that fails doesn't
+// stop the others, so every result has to be checked
+func ProcessBatch(processors []*Processor) []BatchResult {
+	results := make([]BatchResult, len(processors))
+	analyzers := make([]*SchemaAnalyzer, len(processors))
+	schemas := make(map[string]*SchemaAnalyzer)
+	for i, p := range processors {
+		results[i].Processor = p
+		analyzer, err := p.newAnalyzer()
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		analyzers[i] = analyzer
+		schemas[analyzer.absPath()] = analyzer
+	}
+
+	// Every schema is loaded before any analysis starts, since sharing reads the others
+	for i, analyzer := range analyzers {
+		if analyzer == nil {
+			continue
+		}
+		if err := analyzer.shareDefinitions(schemas); err != nil {
+			results[i].Err = errors.Errorf("sharing definitions: %w", err)
+			analyzers[i] = nil
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i, analyzer := range analyzers {
+		if analyzer == nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			analysis, err := analyzer.Analyze()
+			if err != nil {
+				results[i].Err = errors.Errorf("analyzing schema: %w", err)
+				return
+			}
+			results[i].Results = analysis
+		}()
+	}
+	wg.Wait()
+
+	// Generating type-checks each package with the go tool, so it runs one at a time
+	for i := range results {
+		if results[i].Results != nil {
+			results[i].Err = results[i].Processor.generate(results[i].Results)
+		}
+	}
+	return results
+}
+
+// WriteSummary writes a table of the parents and callers found in each schema
+// of a batch, where its model went, and whether it was processed
+func WriteSummary(w io.Writer, results []BatchResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SCHEMA\tPARENTS\tCALLERS\tOUTPUT\tSTATUS")
+	for _, r := range results {
+		parents, callers := "-", "-"
+		if r.Results != nil {
+			parents = strconv.Itoa(len(r.Results.Parents))
+			callers = strconv.Itoa(len(r.Results.ParentCallers))
+		}
+		output := r.Processor.OutputDirPath
+		if r.Processor.InPlace {
+			output = r.Processor.ModelPath
+		}
+		status := "ok"
+		switch {
+		case errors.Is(r.Err, ErrStale):
+			status = "stale"
+		case r.Err != nil:
+			status = "failed"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.Processor.SchemaPath, parents, callers, output, status)
+	}
+	return tw.Flush()
+}
+
+// absPath returns the absolute path of the analyzer's schema, which is how the
+// other schemas of a batch refer to it
+func (sa *SchemaAnalyzer) absPath() string {
+	path, err := filepath.Abs(sa.schemaPath)
+	if err != nil {
+		return sa.schemaPath
+	}
+	return path
+}
+
+// shareDefinitions copies the definitions this schema refers to in the other
+// schemas of a batch into its own, along with everything they refer to, and
+// points the references at the copies. A reference to a schema outside the
+// batch is left alone, for the analyzer to report if it matters
+func (sa *SchemaAnalyzer) shareDefinitions(schemas map[string]*SchemaAnalyzer) error {
+	definitions, ok := sa.schemaData["definitions"].(map[string]interface{})
+	if !ok {
+		definitions = make(map[string]interface{})
+	}
+	self := sa.absPath()
+	// imported maps each copied definition to the schema it came from
+	imported := make(map[string]string)
+
+	var walk func(node interface{}, base string) error
+	walk = func(node interface{}, base string) error {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			if ref, ok := n["$ref"].(string); ok {
+				local, err := sa.importRef(ref, base, self, schemas, definitions, imported, walk)
+				if err != nil {
+					return err
+				}
+				n["$ref"] = local
+			}
+			for _, key := range sortedKeys(n) {
+				if err := walk(n[key], base); err != nil {
+					return err
+				}
+			}
+		case []interface{}:
+			for _, item := range n {
+				if err := walk(item, base); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := walk(sa.schemaData, self); err != nil {
+		return err
+	}
+	if len(imported) > 0 {
+		sa.schemaData["definitions"] = definitions
+	}
+	return nil
+}
+
+// importRef resolves a reference found in the schema at base. A definition of
+// another schema in the batch is copied into definitions, and walked for the
+// references it makes in turn; the returned reference points at the copy
+func (sa *SchemaAnalyzer) importRef(ref, base, self string, schemas map[string]*SchemaAnalyzer, definitions map[string]interface{}, imported map[string]string, walk func(interface{}, string) error) (string, error) {
+	file, fragment, _ := strings.Cut(ref, "#")
+	source := base
+	if file != "" {
+		source = filepath.Join(filepath.Dir(base), file)
+	}
+	if source == self {
+		return "#" + fragment, nil
+	}
+
+	other, ok := schemas[source]
+	defName, isDefinition := strings.CutPrefix(fragment, "/definitions/")
+	if !ok || !isDefinition {
+		return ref, nil
+	}
+	local := "#/definitions/" + defName
+
+	if from, ok := imported[defName]; ok {
+		if from != source {
+			return "", errors.Errorf("%s is defined by both %s and %s", defName, from, source)
+		}
+		return local, nil
+	}
+
+	otherDefinitions, _ := other.schemaData["definitions"].(map[string]interface{})
+	definition, ok := otherDefinitions[defName]
+	if !ok {
+		return ref, nil
+	}
+	copied, err := copyJSON(definition)
+	if err != nil {
+		return "", errors.Errorf("copying %s from %s: %w", defName, source, err)
+	}
+
+	if existing, ok := definitions[defName]; ok {
+		// The same definition in both schemas is fine, say when both were
+		// written from a shared source
+		if reflect.DeepEqual(existing, copied) {
+			return local, nil
+		}
+		return "", errors.Errorf("%s refers to %s in %s but defines a different %s itself", self, defName, source, defName)
+	}
+
+	definitions[defName] = copied
+	imported[defName] = source
+	if err := walk(copied, source); err != nil {
+		return "", err
+	}
+	return local, nil
+}
+
+// copyJSON deep copies a decoded JSON value, so a definition shared between
+// schemas can be changed in one without affecting the other
+func copyJSON(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var copied interface{}
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return nil, err
+	}
+	return copied, nil
+}
@@ -0,0 +1,161 @@
+package repostprocess
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandSchemas(t *testing.T) {
+	shared := filepath.Join("testdata", "shared")
+	drawing := filepath.Join(shared, "drawing", "drawing.schema.json")
+	shapes := filepath.Join(shared, "shapes", "shapes.schema.json")
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{shared, []string{drawing, shapes}},
+		{filepath.Join(shared, "*", "*.schema.json"), []string{drawing, shapes}},
+		{drawing, []string{drawing}},
+	}
+	for _, tt := range tests {
+		got, err := ExpandSchemas(tt.path)
+		if err != nil {
+			t.Fatalf("Failed to expand %s: %v", tt.path, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ExpandSchemas(%s) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	for _, path := range []string{filepath.Join(shared, "*.json"), filepath.Join(shared, "missing.schema.json"), t.TempDir()} {
+		if _, err := ExpandSchemas(path); err == nil {
+			t.Errorf("Expected an error expanding %s", path)
+		}
+	}
+}
+
+func TestExpandTarget(t *testing.T) {
+	targets, err := ExpandTarget(Target{
+		Schema: filepath.Join("testdata", "shared"),
+		Model:  "gen/{name}/model.go",
+		Output: "out/{name}",
+	})
+	if err != nil {
+		t.Fatalf("Failed to expand target: %v", err)
+	}
+	if len(targets) != 2 {
+		t.Fatalf("Expected 2 targets, got %+v", targets)
+	}
+	if targets[0].Model != "gen/drawing/model.go" || targets[1].Output != "out/shapes" {
+		t.Errorf("Expected each schema's name in its paths, got %+v", targets)
+	}
+
+	// The proto settings are named after each schema too
+	targets, err = ExpandTarget(Target{
+		Schema:  filepath.Join("testdata", "shared"),
+		Model:   "gen/{name}/model.go",
+		InPlace: true,
+		Proto:   ProtoConfig{File: "proto/{name}.proto", Package: "shared.{name}", GoPackage: "example.com/{name}pb", Converters: "gen/{name}/proto.gen.go"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to expand target: %v", err)
+	}
+	want := ProtoConfig{File: "proto/shapes.proto", Package: "shared.shapes", GoPackage: "example.com/shapespb", Converters: "gen/shapes/proto.gen.go"}
+	if len(targets) != 2 || targets[1].Proto != want {
+		t.Errorf("Expected each schema's name in its proto settings, got %+v", targets)
+	}
+
+	// Several schemas can't share a model or output
+	for _, target := range []Target{
+		{Schema: filepath.Join("testdata", "shared"), Model: "model.go", InPlace: true},
+		{Schema: filepath.Join("testdata", "shared"), Model: "{name}/model.go", Output: "out"},
+		{Schema: filepath.Join("testdata", "shared"), Model: "{name}/model.go", InPlace: true, Proto: ProtoConfig{File: "shared.proto"}},
+	} {
+		if _, err := ExpandTarget(target); err == nil {
+			t.Errorf("Expected an error expanding %+v", target)
+		}
+	}
+}
+
+func TestProcessBatch_SharedDefinitions(t *testing.T) {
+	targets, err := ExpandTarget(Target{
+		Schema: filepath.Join("testdata", "shared"),
+		Model:  filepath.Join("testdata", "shared", "{name}", "model.gen.go"),
+		Output: filepath.Join(t.TempDir(), "{name}"),
+	})
+	if err != nil {
+		t.Fatalf("Failed to expand target: %v", err)
+	}
+	var processors []*Processor
+	for _, target := range targets {
+		processors = append(processors, NewProcessor(target.Schema, target.Model, target.Output))
+	}
+
+	results := ProcessBatch(processors)
+	for _, r := range results {
+		if r.Err != nil {
+			t.Fatalf("Failed to process %s: %v", r.Processor.SchemaPath, r.Err)
+		}
+	}
+
+	// The drawing refers to the shapes' Shape, which becomes a parent of its own
+	drawing := results[0]
+	if _, ok := drawing.Results.Parents["Shape"]; !ok {
+		t.Fatalf("Expected Shape to be a parent of the drawing, got %v", drawing.Results.SortedParentNames())
+	}
+	if caller, ok := drawing.Results.ParentCallers["Layer.shapes"]; !ok || !caller.IsArray {
+		t.Errorf("Expected Layer.shapes to call Shape as an array, got %+v", drawing.Results.ParentCallers)
+	}
+	modelStr := readModel(t, drawing.Processor.OutputDirPath)
+	checkForContent(t, modelStr, "type Shape interface {")
+	checkForContent(t, modelStr, "Shapes ShapeSlice")
+	checkForAbsence(t, modelStr, "LayerShapesElem")
+	validateGoTypes(t, drawing.Processor.OutputDirPath)
+
+	var summary bytes.Buffer
+	if err := WriteSummary(&summary, results); err != nil {
+		t.Fatalf("Failed to write summary: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(summary.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "SCHEMA") {
+		t.Fatalf("Expected a header and a row per schema, got:\n%s", summary.String())
+	}
+	if fields := strings.Fields(lines[1]); !reflect.DeepEqual(fields[1:3], []string{"1", "1"}) || fields[len(fields)-1] != "ok" {
+		t.Errorf("Expected one parent and one caller in the drawing, got %q", lines[1])
+	}
+}
+
+func TestProcessBatch_ConflictingDefinition(t *testing.T) {
+	dir := t.TempDir()
+	shapes, err := os.ReadFile(filepath.Join("testdata", "shared", "shapes", "shapes.schema.json"))
+	if err != nil {
+		t.Fatalf("Failed to read shapes schema: %v", err)
+	}
+	// The drawing declares a Circle of its own, which the shared Shape would replace
+	drawing := `{
+	"definitions": {
+		"Circle": {"type": "object", "properties": {"diameter": {"type": "number"}}},
+		"Layer": {"type": "object", "properties": {"shapes": {"type": "array", "items": {"$ref": "shapes.schema.json#/definitions/Shape"}}}}
+	}
+}`
+	files := map[string]string{"shapes.schema.json": string(shapes), "drawing.schema.json": drawing}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	// The drawing fails while sharing, before its model would be read
+	results := ProcessBatch([]*Processor{
+		NewProcessor(filepath.Join(dir, "drawing.schema.json"), filepath.Join(dir, "drawing.go"), dir),
+		NewProcessor(filepath.Join(dir, "shapes.schema.json"), filepath.Join(dir, "shapes.go"), dir),
+	})
+	if results[0].Err == nil || !strings.Contains(results[0].Err.Error(), "different Circle") {
+		t.Errorf("Expected the conflicting Circle to fail the drawing, got %v", results[0].Err)
+	}
+}
@@ -0,0 +1,114 @@
+package repostprocess
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSchemaAnalyzer_OptionalCallers(t *testing.T) {
+	analyzer, err := NewSchemaAnalyzer(filepath.Join("testdata", "optional", "optional.schema.json"))
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+
+	results, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Failed to analyze schema: %v", err)
+	}
+
+	tests := []struct {
+		key        string
+		isRequired bool
+		isNullable bool
+		isArray    bool
+		isMap      bool
+	}{
+		{key: "Swatch.accents", isArray: true},
+		{key: "Swatch.background", isNullable: true},
+		{key: "Swatch.highlight"},
+		{key: "Swatch.named", isMap: true},
+		{key: "Swatch.primary", isRequired: true},
+	}
+
+	for _, tt := range tests {
+		caller, ok := results.ParentCallers[tt.key]
+		if !ok {
+			t.Errorf("Expected caller %s to be identified", tt.key)
+			continue
+		}
+		if caller.ParentRef != "Color" {
+			t.Errorf("Expected %s to refer to Color, got '%s'", tt.key, caller.ParentRef)
+		}
+		if caller.IsRequired != tt.isRequired || caller.IsNullable != tt.isNullable || caller.IsArray != tt.isArray || caller.IsMap != tt.isMap {
+			t.Errorf("Unexpected caller %s: %+v", tt.key, caller)
+		}
+	}
+}
+
+func TestCodeGenerator_GenerateOptional(t *testing.T) {
+	schemaPath := filepath.Join("testdata", "optional", "optional.schema.json")
+	modelPath := filepath.Join("testdata", "optional", "model.gen.go")
+
+	outputDir := filepath.Join(t.TempDir(), "output")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		t.Fatalf("Failed to create output directory: %v", err)
+	}
+
+	analyzer, err := NewSchemaAnalyzer(schemaPath)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+
+	results, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Failed to analyze schema: %v", err)
+	}
+
+	generator := NewCodeGenerator(modelPath, outputDir, results)
+	if err := generator.Generate(); err != nil {
+		t.Fatalf("Failed to generate code: %v", err)
+	}
+
+	modelStr := readModel(t, outputDir)
+
+	// Every union field is retyped, including the optional, nullable and named map ones
+	checkForContent(t, modelStr, "Accents ColorSlice `json:\"accents,omitempty\"")
+	checkForContent(t, modelStr, "Background Color `json:\"background,omitempty\"")
+	checkForContent(t, modelStr, "Highlight Color `json:\"highlight,omitempty\"")
+	checkForContent(t, modelStr, "Named ColorMap `json:\"named,omitempty\"")
+	checkForContent(t, modelStr, "Primary Color `json:\"primary\"")
+	checkForAbsence(t, modelStr, "SwatchNamed")
+
+	// One UnmarshalJSON and one MarshalJSON for the struct, covering every field
+	if count := strings.Count(modelStr, "func (j *Swatch) UnmarshalJSON("); count != 1 {
+		t.Errorf("Expected one UnmarshalJSON for Swatch, got %d", count)
+	}
+	if count := strings.Count(modelStr, "func (j Swatch) MarshalJSON("); count != 1 {
+		t.Errorf("Expected one MarshalJSON for Swatch, got %d", count)
+	}
+	for _, field := range []string{"Accents", "Background", "Highlight", "Named", "Primary"} {
+		checkForContent(t, modelStr, "plain."+field+" = ")
+	}
+
+	// Only the required, non-nullable field rejects null and nil
+	checkForContent(t, modelStr, "return fmt.Errorf(\"field primary in Swatch: must not be null\")")
+	checkForAbsence(t, modelStr, "field background in Swatch: must not be null")
+	checkForContent(t, modelStr, "if j.Primary == nil {\n\t\treturn nil, fmt.Errorf(\"field primary in Swatch: required\")")
+	checkForAbsence(t, modelStr, "if j.Background == nil")
+	checkForAbsence(t, modelStr, "if j.Highlight == nil")
+
+	// Collections can't hold nil parents
+	checkForContent(t, modelStr, "return nil, fmt.Errorf(\"field accents in Swatch: item %d is nil\", i)")
+	checkForContent(t, modelStr, "return nil, fmt.Errorf(\"field named in Swatch: entry %q is nil\", key)")
+
+	// Schema constraints are checked on plain, with optional fields only when present
+	checkForContent(t, modelStr, "if plain.Accents != nil && len(plain.Accents) < 1 {")
+	checkForContent(t, modelStr, "if utf8.RuneCountInString(string(plain.Name)) > 32 {")
+	checkForContent(t, modelStr, "if matched, _ := regexp.MatchString(\"^[a-z]+$\", string(plain.Name)); !matched {")
+	checkForContent(t, modelStr, "if plain.Opacity != nil && *plain.Opacity >= 1 {")
+	checkForContent(t, modelStr, "if plain.Weight != nil && *plain.Weight <= 0 {")
+	checkForContent(t, modelStr, "switch *plain.Tone {\n\t\tcase \"warm\", \"cool\":")
+	checkForContent(t, modelStr, "\t\"regexp\"\n")
+}
@@ -0,0 +1,103 @@
+package repostprocess
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"gitlab.com/tozd/go/errors"
+)
+
+// ErrStale is returned by a check that found the model on disk differs from
+// the one the schema generates
+var ErrStale = errors.New("generated code is out of date")
+
+// CheckedPath returns the file a processor writes its model to, which a check
+// compares with
+func (p *Processor) CheckedPath() string {
+	if p.InPlace {
+		return p.ModelPath
+	}
+	return filepath.Join(p.OutputDirPath, filepath.Base(p.ModelPath))
+}
+
+// check generates the model into a temporary directory, instead of writing it,
+// and compares it with the file on disk. It returns the generated model
+func (p *Processor) check(results *SchemaResults, source []byte) ([]byte, error) {
+	// The model an in-place run rewrote can't be merged into again
+	if p.InPlace && source == nil {
+		return nil, errors.Errorf("checking %s in place needs the model generated from the schema", p.ModelPath)
+	}
+
+	stageDir, err := os.MkdirTemp("", "postprocess-check-")
+	if err != nil {
+		return nil, errors.Errorf("creating check directory: %w", err)
+	}
+	defer os.RemoveAll(stageDir)
+
+	generator := NewCodeGenerator(p.ModelPath, stageDir, results)
+	generator.SetModelSource(source)
+	generator.SetTemplatesDir(p.TemplatesDir)
+	generator.SetConfig(p.Config)
+	generator.SetForce(p.Force)
+	if p.InPlace {
+		generator.SetPackageDir(filepath.Dir(p.ModelPath))
+		generator.SetSkipFiles(p.skipFiles()...)
+	}
+	if err := generator.Generate(); err != nil {
+		return nil, errors.Errorf("generating code: %w", err)
+	}
+
+	generated, err := os.ReadFile(filepath.Join(stageDir, filepath.Base(p.ModelPath)))
+	if err != nil {
+		return nil, errors.Errorf("reading generated model: %w", err)
+	}
+
+	return generated, p.compare(p.CheckedPath(), generated)
+}
+
+// compare records path as stale, and appends the unified diff from the file on
+// disk to the generated content to Diff, when they differ
+func (p *Processor) compare(path string, generated []byte) error {
+	// A file that was never written differs from everything generated
+	current, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Errorf("reading %s: %w", path, err)
+	}
+	if err == nil && bytes.Equal(current, generated) {
+		return nil
+	}
+
+	diff, err := unifiedDiff(path, current, generated)
+	if err != nil {
+		return err
+	}
+	p.Diff += diff
+	p.stale = append(p.stale, path)
+	return nil
+}
+
+// unifiedDiff returns the unified diff from the current content of path to the
+// generated one
+func unifiedDiff(path string, current, generated []byte) (string, error) {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        diffLines(current),
+		B:        diffLines(generated),
+		FromFile: "a/" + filepath.ToSlash(path),
+		ToFile:   "b/" + filepath.ToSlash(path),
+		Context:  3,
+	})
+	if err != nil {
+		return "", errors.Errorf("diffing %s: %w", path, err)
+	}
+	return diff, nil
+}
+
+// diffLines splits content into the lines of a diff, none for a missing file
+func diffLines(content []byte) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	return difflib.SplitLines(string(content))
+}
@@ -0,0 +1,86 @@
+package repostprocess
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProcessor_Check(t *testing.T) {
+	schemaPath := filepath.Join("testdata", "color", "color.schema.json")
+	modelPath := filepath.Join(t.TempDir(), "color", "model.go")
+
+	newProcessor := func(check bool) *Processor {
+		processor := NewProcessor(schemaPath, modelPath, "")
+		processor.InPlace = true
+		processor.GenerateModel = true
+		processor.Check = check
+		return processor
+	}
+
+	// A model that was never generated is stale, and the diff adds all of it
+	processor := newProcessor(true)
+	if err := processor.Process(); !errors.Is(err, ErrStale) {
+		t.Fatalf("Expected a missing model to be stale, got %v", err)
+	}
+	checkForContent(t, processor.Diff, "+++ b/"+filepath.ToSlash(modelPath))
+	checkForContent(t, processor.Diff, "+type Color interface {")
+	if _, err := os.Stat(modelPath); !os.IsNotExist(err) {
+		t.Errorf("Expected the check to write nothing, got %v", err)
+	}
+
+	if err := newProcessor(false).Process(); err != nil {
+		t.Fatalf("Failed to generate model: %v", err)
+	}
+	processor = newProcessor(true)
+	if err := processor.Process(); err != nil {
+		t.Fatalf("Expected the generated model to be up to date, got %v", err)
+	}
+	if processor.Diff != "" {
+		t.Errorf("Expected no diff, got:\n%s", processor.Diff)
+	}
+
+	// An edited model is stale, with the diff restoring it
+	content, err := os.ReadFile(modelPath)
+	if err != nil {
+		t.Fatalf("Failed to read model file: %v", err)
+	}
+	if err := os.WriteFile(modelPath, append(content, "\nvar edited = true\n"...), 0644); err != nil {
+		t.Fatalf("Failed to edit model file: %v", err)
+	}
+	if err := processor.Process(); !errors.Is(err, ErrStale) {
+		t.Fatalf("Expected the edited model to be stale, got %v", err)
+	}
+	checkForContent(t, processor.Diff, "-var edited = true")
+}
+
+func TestProcessor_CheckNeedsGeneratedModel(t *testing.T) {
+	processor := NewProcessor(filepath.Join("testdata", "color", "color.schema.json"), filepath.Join("testdata", "color", "model.gen.go"), "")
+	processor.InPlace = true
+	processor.Check = true
+	if err := processor.Process(); err == nil || errors.Is(err, ErrStale) {
+		t.Errorf("Expected checking in place without a generated model to fail, got %v", err)
+	}
+}
+
+func TestRepositoryModels_UpToDate(t *testing.T) {
+	// The models the generate-jsonschema task writes must match their schemas
+	config, err := LoadConfig(filepath.Join("..", "..", "..", DefaultConfigFile))
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	processors, err := config.Processors()
+	if err != nil {
+		t.Fatalf("Failed to list targets: %v", err)
+	}
+	for _, processor := range processors {
+		processor.Check = true
+	}
+
+	for _, result := range ProcessBatch(processors) {
+		if result.Err != nil {
+			t.Errorf("%s: %v, run `task generate-jsonschema`\n%s", result.Processor.SchemaPath, result.Err, result.Processor.Diff)
+		}
+	}
+}
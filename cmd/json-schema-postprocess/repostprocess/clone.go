@@ -0,0 +1,284 @@
+package repostprocess
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// constructorData is the New<Child> function of a concrete child
+type constructorData struct {
+	Child string
+	// Params are the child's required fields, in the schema's order
+	Params []paramData
+	// Local names the struct the constructor builds, which can't be a parameter's name
+	Local string
+	// Constraints are checked once the fields are set
+	Constraints []constraintData
+}
+
+// paramData is one constructor parameter and the field it sets
+type paramData struct {
+	Name    string
+	Type    string
+	GoField string
+}
+
+// cloneData is the Clone method of a struct
+type cloneData struct {
+	Struct string
+	// Copies are the statements deep copying each field that isn't copied
+	// by value, from j into clone
+	Copies []string
+}
+
+// constructors builds the New<Child> data for every concrete child, with the
+// child's required fields as parameters
+func (cg *CodeGenerator) constructors(model *modelFile, parents []parentData, constantFields map[string]string) []constructorData {
+	seen := make(map[string]bool)
+	var data []constructorData
+	for _, parent := range parents {
+		for _, visitor := range parent.Visitors {
+			if seen[visitor.Child] {
+				continue
+			}
+			seen[visitor.Child] = true
+
+			pointer := jsonPointer("definitions", visitor.Child)
+			c := constructorData{
+				Child:       visitor.Child,
+				Constraints: model.constraintData(visitor.Child, cg.results.Constraints[pointer]),
+			}
+			for _, name := range cg.results.Required[pointer] {
+				field, ok := model.structField(visitor.Child, name)
+				if !ok || len(field.Names) != 1 {
+					continue
+				}
+				goField := field.Names[0].Name
+				// The constant is set by the accessor that replaces its field
+				if constantFields[visitor.Child] == goField {
+					continue
+				}
+				c.Params = append(c.Params, paramData{
+					Name:    paramName(goField),
+					Type:    types.ExprString(field.Type),
+					GoField: goField,
+				})
+			}
+			c.Local = constructorLocal(c.Params)
+			data = append(data, c)
+		}
+	}
+	return data
+}
+
+// paramName returns the constructor parameter for a field, which only has to
+// avoid keywords, predeclared names and the fmt package the constructor uses
+func paramName(goField string) string {
+	name := camelCase(goField)
+	if token.IsKeyword(name) || types.Universe.Lookup(name) != nil || name == "fmt" {
+		return name + "Field"
+	}
+	return name
+}
+
+// constructorLocal returns a name for the struct a constructor builds that
+// none of its parameters have
+func constructorLocal(params []paramData) string {
+	taken := make(map[string]bool, len(params))
+	for _, p := range params {
+		taken[p.Name] = true
+	}
+	name := "plain"
+	for i := 2; taken[name]; i++ {
+		name = "plain" + strconv.Itoa(i)
+	}
+	return name
+}
+
+// constantFieldNames maps each concrete child to the Go name of its constant
+// field, which the merge drops in favor of the accessor method
+func (m *modelFile) constantFieldNames(parents []parentData) map[string]string {
+	fields := make(map[string]string)
+	for _, parent := range parents {
+		if parent.ConstantField == "" {
+			continue
+		}
+		for _, child := range parent.Info.LeafChildren {
+			if field, ok := m.structField(child, parent.ConstantField); ok {
+				fields[child] = field.Names[0].Name
+			}
+		}
+	}
+	return fields
+}
+
+// cloner writes the statements that deep copy the fields of model structs
+type cloner struct {
+	model   *modelFile
+	parents map[string]bool
+	// structs are the structs given a Clone method, including every struct
+	// a field of one of them refers to
+	structs  map[string]bool
+	queue    []string
+	needsAny bool
+}
+
+// clones builds the Clone data for the given structs and every model struct
+// their fields reach, and reports whether the cloneAny helper is used
+func clones(model *modelFile, parents []parentData, structs []string, constantFields map[string]string) ([]cloneData, bool) {
+	c := &cloner{
+		model:   model,
+		parents: make(map[string]bool),
+		structs: make(map[string]bool),
+	}
+	for _, parent := range parents {
+		c.parents[parent.Name] = true
+	}
+	for _, name := range structs {
+		c.addStruct(name)
+	}
+
+	var data []cloneData
+	for len(c.queue) > 0 {
+		name := c.queue[0]
+		c.queue = c.queue[1:]
+
+		d := cloneData{Struct: name}
+		for _, field := range c.model.types[name].Type.(*ast.StructType).Fields.List {
+			names := make([]string, 0, len(field.Names))
+			for _, ident := range field.Names {
+				names = append(names, ident.Name)
+			}
+			if len(names) == 0 {
+				names = append(names, embeddedName(field.Type))
+			}
+			for _, fieldName := range names {
+				if fieldName == "" || fieldName == "_" || constantFields[name] == fieldName {
+					continue
+				}
+				if stmts := c.copyStmts(field.Type, "clone."+fieldName, "j."+fieldName, 0); stmts != "" {
+					d.Copies = append(d.Copies, stmts)
+				}
+			}
+		}
+		data = append(data, d)
+	}
+
+	sort.Slice(data, func(i, j int) bool { return data[i].Struct < data[j].Struct })
+	return data, c.needsAny
+}
+
+// addStruct queues a model struct for a Clone method, reporting whether name is one
+func (c *cloner) addStruct(name string) bool {
+	typeSpec, ok := c.model.types[name]
+	if !ok {
+		return false
+	}
+	if _, ok := typeSpec.Type.(*ast.StructType); !ok {
+		return false
+	}
+	if !c.structs[name] {
+		c.structs[name] = true
+		c.queue = append(c.queue, name)
+	}
+	return true
+}
+
+// maxCloneDepth bounds how deeply copyStmts follows named types into each other
+const maxCloneDepth = 8
+
+// copyStmts returns statements that make dst a deep copy of src, of type expr,
+// when dst already holds a shallow copy. It returns nothing for types copied
+// by value, and for types from other packages, which are left shallow
+func (c *cloner) copyStmts(expr ast.Expr, dst, src string, depth int) string {
+	if depth > maxCloneDepth {
+		return ""
+	}
+
+	switch e := expr.(type) {
+	case *ast.Ident:
+		switch {
+		case e.Name == "any":
+			c.needsAny = true
+			return fmt.Sprintf("%s = cloneAny(%s)", dst, src)
+		case c.parents[e.Name]:
+			return fmt.Sprintf("%s = Clone%s(%s)", dst, e.Name, src)
+		case c.parents[strings.TrimSuffix(e.Name, "Slice")], c.parents[strings.TrimSuffix(e.Name, "Map")]:
+			return fmt.Sprintf("%s = %s.Clone()", dst, src)
+		case c.addStruct(e.Name):
+			return fmt.Sprintf("%s = *%s.Clone()", dst, src)
+		}
+		if typeSpec, ok := c.model.types[e.Name]; ok {
+			return c.copyStmts(typeSpec.Type, dst, src, depth+1)
+		}
+		return ""
+
+	case *ast.StarExpr:
+		if ident, ok := e.X.(*ast.Ident); ok && c.addStruct(ident.Name) {
+			return fmt.Sprintf("if %s != nil {\n%s = %s.Clone()\n}", src, dst, src)
+		}
+		v := fmt.Sprintf("v%d", depth)
+		stmts := fmt.Sprintf("%s := *%s", v, src)
+		if inner := c.copyStmts(e.X, v, v, depth+1); inner != "" {
+			stmts += "\n" + inner
+		}
+		return fmt.Sprintf("if %s != nil {\n%s\n%s = &%s\n}", src, stmts, dst, v)
+
+	case *ast.ArrayType:
+		i := fmt.Sprintf("i%d", depth)
+		inner := c.copyStmts(e.Elt, dst+"["+i+"]", src+"["+i+"]", depth+1)
+		if e.Len != nil {
+			if inner == "" {
+				return ""
+			}
+			return fmt.Sprintf("for %s := range %s {\n%s\n}", i, src, inner)
+		}
+		stmts := fmt.Sprintf("%s = make(%s, len(%s))\ncopy(%s, %s)", dst, types.ExprString(e), src, dst, src)
+		if inner != "" {
+			stmts += fmt.Sprintf("\nfor %s := range %s {\n%s\n}", i, src, inner)
+		}
+		return fmt.Sprintf("if %s != nil {\n%s\n}", src, stmts)
+
+	case *ast.MapType:
+		k, v := fmt.Sprintf("k%d", depth), fmt.Sprintf("v%d", depth)
+		loop := fmt.Sprintf("%s[%s] = %s", dst, k, v)
+		if inner := c.copyStmts(e.Value, v, v, depth+1); inner != "" {
+			loop = inner + "\n" + loop
+		}
+		return fmt.Sprintf("if %s != nil {\n%s = make(%s, len(%s))\nfor %s, %s := range %s {\n%s\n}\n}",
+			src, dst, types.ExprString(e), src, k, v, src, loop)
+
+	case *ast.InterfaceType:
+		if e.Methods == nil || len(e.Methods.List) == 0 {
+			c.needsAny = true
+			return fmt.Sprintf("%s = cloneAny(%s)", dst, src)
+		}
+		return ""
+
+	case *ast.SelectorExpr:
+		if types.ExprString(e) == "json.RawMessage" {
+			return fmt.Sprintf("if %s != nil {\n%s = append(json.RawMessage(nil), %s...)\n}", src, dst, src)
+		}
+		return ""
+	}
+
+	return ""
+}
+
+// embeddedName returns the field name of an embedded field's type
+func embeddedName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.StarExpr:
+		return embeddedName(e.X)
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	}
+	return ""
+}
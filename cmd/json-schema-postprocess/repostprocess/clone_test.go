@@ -0,0 +1,163 @@
+package repostprocess
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCodeGenerator_GenerateMethods(t *testing.T) {
+	schemaPath := filepath.Join("testdata", "color", "color.schema.json")
+	modelPath := filepath.Join("testdata", "color", "model.gen.go")
+
+	analyzer, err := NewSchemaAnalyzer(schemaPath)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+
+	results, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Failed to analyze schema: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	generator := NewCodeGenerator(modelPath, outputDir, results)
+	if err := generator.Generate(); err != nil {
+		t.Fatalf("Failed to generate code: %v", err)
+	}
+
+	modelStr := readModel(t, outputDir)
+
+	// Constructors take the required fields in schema order, without the constant
+	checkForContent(t, modelStr, "func NewHSLValue(h float64, s float64, l float64) (*HSLValue, error) {")
+	checkForContent(t, modelStr, "func NewRGBValue(r float64, g float64, b float64) (*RGBValue, error) {")
+	checkForContent(t, modelStr, "func NewLABValue(l float64, a float64, b float64) (*LABValue, error) {")
+	checkForContent(t, modelStr, "func (plain *HSLValue) checkConstraints() error {\n\tif plain.H < 0 {")
+	checkForContent(t, modelStr, "return nil, fmt.Errorf(\"new HSLValue: %w\", err)")
+	checkForAbsence(t, modelStr, "Model: model,")
+
+	// Clones copy the parent fields through the parent helpers
+	checkForContent(t, modelStr, "func CloneColor(value Color) Color {")
+	checkForContent(t, modelStr, "func (s ColorSlice) Clone() ColorSlice {")
+	checkForContent(t, modelStr, "func (m ColorMap) Clone() ColorMap {")
+	checkForContent(t, modelStr, "func (j *CategoricalPalette) Clone() *CategoricalPalette {")
+	checkForContent(t, modelStr, "clone.Colors = j.Colors.Clone()")
+	checkForContent(t, modelStr, "clone.Value = CloneColor(j.Value)")
+
+	// Equality for the parents, their collections and every child
+	checkForContent(t, modelStr, "func EqualColor(a, b Color) bool {")
+	checkForContent(t, modelStr, "func (s PaletteSlice) Equal(other PaletteSlice) bool {")
+	checkForContent(t, modelStr, "func (j *RGBValue) Equal(other *RGBValue) bool {")
+	checkForContent(t, modelStr, "func (j *AssetPack) Equal(other *AssetPack) bool {")
+
+	validateGoTypes(t, outputDir)
+}
+
+func TestConstructorNames(t *testing.T) {
+	// Parameters only avoid what Go itself reserves
+	for goField, want := range map[string]string{"B": "b", "Raw": "raw", "Type": "typeField", "Len": "lenField", "Fmt": "fmtField", "Plain": "plain"} {
+		if got := paramName(goField); got != want {
+			t.Errorf("paramName(%s): expected %s, got %s", goField, want, got)
+		}
+	}
+
+	// The constructor's own struct is renamed around them instead
+	if got := constructorLocal([]paramData{{Name: "h"}, {Name: "s"}}); got != "plain" {
+		t.Errorf("Expected plain, got %s", got)
+	}
+	if got := constructorLocal([]paramData{{Name: "plain"}, {Name: "plain2"}}); got != "plain3" {
+		t.Errorf("Expected plain3, got %s", got)
+	}
+}
+
+func TestClones_FieldShapes(t *testing.T) {
+	src := `package shapes
+
+import "encoding/json"
+
+type Inner struct {
+	Tags []string
+}
+
+type Names []string
+
+type Outer struct {
+	Count   int
+	Label   *string
+	Inner   Inner
+	Next    *Inner
+	Names   Names
+	Grid    [][]int
+	ByName  map[string]*Inner
+	Extra   map[string]interface{}
+	Raw     json.RawMessage
+	Fixed   [2]Inner
+	Value   any
+}
+`
+	dir := t.TempDir()
+	modelPath := filepath.Join(dir, "model.go")
+	if err := os.WriteFile(modelPath, []byte(src), 0644); err != nil {
+		t.Fatalf("Failed to write model: %v", err)
+	}
+	model, err := parseModelFile(modelPath)
+	if err != nil {
+		t.Fatalf("Failed to parse model: %v", err)
+	}
+
+	data, needsAny := clones(model, nil, []string{"Outer"}, nil)
+	if !needsAny {
+		t.Error("Expected the cloneAny helper to be needed")
+	}
+	if len(data) != 2 || data[0].Struct != "Inner" || data[1].Struct != "Outer" {
+		t.Fatalf("Expected clones of Inner and Outer, got %+v", data)
+	}
+
+	outer := strings.Join(data[1].Copies, "\n")
+	checkForContent(t, outer, "clone.Inner = *j.Inner.Clone()")
+	checkForContent(t, outer, "clone.Next = j.Next.Clone()")
+	checkForContent(t, outer, "clone.Names = make([]string, len(j.Names))")
+	checkForContent(t, outer, "clone.Grid[i0] = make([]int, len(j.Grid[i0]))")
+	checkForContent(t, outer, "v0 = v0.Clone()")
+	checkForContent(t, outer, "clone.Extra[k0] = v0")
+	checkForContent(t, outer, "clone.Raw = append(json.RawMessage(nil), j.Raw...)")
+	checkForContent(t, outer, "clone.Fixed[i0] = *j.Fixed[i0].Clone()")
+	checkForContent(t, outer, "clone.Value = cloneAny(j.Value)")
+	checkForAbsence(t, outer, "clone.Count")
+
+	// The statements have to compile against the model
+	tmpl, err := loadTemplates("")
+	if err != nil {
+		t.Fatalf("Failed to load templates: %v", err)
+	}
+	code := []byte(src)
+	for _, d := range data {
+		rendered, err := renderTemplate(tmpl, "clone", d)
+		if err != nil {
+			t.Fatalf("Failed to render clone: %v", err)
+		}
+		code = append(code, rendered...)
+	}
+	rendered, err := renderTemplate(tmpl, "cloneAny", nil)
+	if err != nil {
+		t.Fatalf("Failed to render cloneAny: %v", err)
+	}
+	code = append(code, rendered...)
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "model.go", code, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse clones: %v\n%s", err, code)
+	}
+	typeErrors, err := checkPackage(fset, []*ast.File{file}, ".")
+	if err != nil {
+		t.Fatalf("Failed to type-check clones: %v", err)
+	}
+	for _, typeErr := range typeErrors {
+		t.Errorf("Clones do not compile: %v", typeErr)
+	}
+}
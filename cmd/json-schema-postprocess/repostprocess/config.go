@@ -0,0 +1,348 @@
+package repostprocess
+
+import (
+	"bytes"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gitlab.com/tozd/go/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultConfigFile is the config the CLI reads from the working directory
+// when no -config flag is given
+const DefaultConfigFile = ".postprocess.yaml"
+
+// generatedFiles are the files the generator renders, in the order it renders them
+var generatedFiles = []string{"interfaces", "unmarshal", "yaml", "methods", "validate"}
+
+// requiredFiles are always rendered, since the model can't decode its parents without them
+var requiredFiles = map[string]bool{"interfaces": true, "unmarshal": true}
+
+// Config holds project-specific settings that can't be derived from the schema
+type Config struct {
+	// Targets lists the schemas one run processes, each with its model and
+	// where the result goes. Relative paths are resolved from the config file
+	// and a target's schema can be a directory or a glob, as for ExpandTarget
+	Targets []Target `json:"targets" yaml:"targets"`
+	// Parents overrides the names generated for a parent, by definition name
+	Parents map[string]ParentConfig `json:"parents" yaml:"parents"`
+	// Emit lists the generated files to render: interfaces, unmarshal, yaml,
+	// methods and validate. Empty means all of them; interfaces and unmarshal
+	// are rendered either way
+	Emit []string `json:"emit" yaml:"emit"`
+	// Package renames the package of the written models; empty keeps the model's
+	Package string `json:"package" yaml:"package"`
+	// Required lists extra keys the JSON of an object must contain, on top of
+	// its schema's required array. Keys are definition names, or JSON pointers
+	// such as "#/properties/config" for objects outside the definitions
+	Required map[string][]string `json:"required" yaml:"required"`
+}
+
+// Target is one schema, the model go-jsonschema generated from it, and where
+// the processed model is written
+type Target struct {
+	Schema string `json:"schema" yaml:"schema"`
+	Model  string `json:"model" yaml:"model"`
+	// Output is the directory the processed model is written to
+	Output string `json:"output" yaml:"output"`
+	// InPlace rewrites the model file itself instead of writing to Output
+	InPlace bool `json:"inplace" yaml:"inplace"`
+	// Generate runs go-jsonschema on the schema instead of reading a model
+	// generated beforehand, which then needn't exist
+	Generate bool `json:"generate" yaml:"generate"`
+	// TypeScript is the file the schema's TypeScript types are written to, if any
+	TypeScript string `json:"typescript" yaml:"typescript"`
+	// Proto is where the schema's protobuf definition and converters are written, if anywhere
+	Proto ProtoConfig `json:"proto" yaml:"proto"`
+	// Package and Parents replace the config's own settings for this target,
+	// since schemas can have definitions with the same name
+	Package string                  `json:"package" yaml:"package"`
+	Parents map[string]ParentConfig `json:"parents" yaml:"parents"`
+}
+
+// ProtoConfig says where a schema's protobuf definition and the Go converters
+// between its model and messages are written
+type ProtoConfig struct {
+	// File is the .proto file the messages and enums are written to
+	File string `json:"file" yaml:"file"`
+	// Package is the protobuf package File declares, which defaults to the
+	// model's Go package
+	Package string `json:"package" yaml:"package"`
+	// GoPackage is the import path of the code protoc-gen-go generates from
+	// File. It's File's go_package option and what the converters import
+	GoPackage string `json:"goPackage" yaml:"goPackage"`
+	// Converters is the Go file of the converters, which must be in the
+	// model's package
+	Converters string `json:"converters" yaml:"converters"`
+}
+
+// validate checks that the proto settings that depend on each other are set together
+func (pc ProtoConfig) validate() error {
+	switch {
+	case pc.File == "" && (pc.Package != "" || pc.GoPackage != "" || pc.Converters != ""):
+		return errors.New("proto needs a file")
+	case pc.Converters != "" && pc.GoPackage == "":
+		return errors.New("proto converters need the goPackage of the messages")
+	}
+	return nil
+}
+
+// ParentConfig overrides the names generated for one parent. Empty fields keep
+// the defaults
+type ParentConfig struct {
+	// Interface is the name of the parent's interface, which the names of its
+	// collections and helpers are built from. It defaults to the definition name
+	Interface string `json:"interface" yaml:"interface"`
+	// Discriminator is the constant field the children are told apart by,
+	// instead of the first one the analyzer finds
+	Discriminator string `json:"discriminator" yaml:"discriminator"`
+	// ConstantType is the name of the type of the discriminator's constants,
+	// which defaults to <Interface>Model
+	ConstantType string `json:"constantType" yaml:"constantType"`
+	// ConstantMethod is the name of the accessor returning a child's constant,
+	// which defaults to the discriminator's Go field name
+	ConstantMethod string `json:"constantMethod" yaml:"constantMethod"`
+	// Visitors names the visitor methods by child, as in Visit<Name>, instead
+	// of the child names without their common suffix
+	Visitors map[string]string `json:"visitors" yaml:"visitors"`
+}
+
+// LoadConfig reads a YAML or JSON config file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Errorf("reading config file: %w", err)
+	}
+
+	// YAML is a superset of JSON, so one decoder reads both. Unknown keys are
+	// errors, so a misspelled setting isn't silently left at its default
+	var config Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, errors.Errorf("loading config %s: %w", path, err)
+	}
+
+	for key := range config.Required {
+		if strings.HasPrefix(key, "#") && !strings.HasPrefix(key, "#/") {
+			return nil, errors.Errorf("config file %s: required key %q must be a definition name or a JSON pointer", path, key)
+		}
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, errors.Errorf("config file %s: %w", path, err)
+	}
+
+	// Targets are relative to the config, so the tool can run from anywhere
+	dir := filepath.Dir(path)
+	for i := range config.Targets {
+		target := &config.Targets[i]
+		for _, p := range []*string{&target.Schema, &target.Model, &target.Output, &target.TypeScript, &target.Proto.File, &target.Proto.Converters} {
+			if *p != "" && !filepath.IsAbs(*p) {
+				*p = filepath.Join(dir, *p)
+			}
+		}
+	}
+
+	return &config, nil
+}
+
+// validate checks the settings that would otherwise only fail once generated
+func (c *Config) validate() error {
+	for i, target := range c.Targets {
+		switch {
+		case target.Schema == "" || target.Model == "":
+			return errors.Errorf("target %d needs a schema and a model", i)
+		case target.Output == "" && !target.InPlace:
+			return errors.Errorf("target %s needs an output directory or inplace", target.Schema)
+		case target.Output != "" && target.InPlace:
+			return errors.Errorf("target %s can't have both an output directory and inplace", target.Schema)
+		}
+		if err := target.Proto.validate(); err != nil {
+			return errors.Errorf("target %s: %w", target.Schema, err)
+		}
+	}
+
+	for _, name := range c.Emit {
+		if !containsString(generatedFiles, name) {
+			return errors.Errorf("unknown file %q to emit, expected one of %s", name, strings.Join(generatedFiles, ", "))
+		}
+	}
+
+	if c.Package != "" && !isIdentifier(c.Package) {
+		return errors.Errorf("package %q is not a Go identifier", c.Package)
+	}
+	if err := validateParents(c.Parents); err != nil {
+		return err
+	}
+
+	for _, target := range c.Targets {
+		if target.Package != "" && !isIdentifier(target.Package) {
+			return errors.Errorf("target %s: package %q is not a Go identifier", target.Schema, target.Package)
+		}
+		if err := validateParents(c.forTarget(target).Parents); err != nil {
+			return errors.Errorf("target %s: %w", target.Schema, err)
+		}
+	}
+
+	return nil
+}
+
+// validateParents checks that the parent overrides name valid and distinct Go identifiers
+func validateParents(parents map[string]ParentConfig) error {
+	names := make(map[string]string)
+	for _, defName := range sortedConfigKeys(parents) {
+		parent := parents[defName]
+		for _, name := range []string{parent.Interface, parent.ConstantType, parent.ConstantMethod} {
+			if name != "" && !isIdentifier(name) {
+				return errors.Errorf("parent %s: %q is not a Go identifier", defName, name)
+			}
+		}
+		for child, name := range parent.Visitors {
+			if name == "" || !isIdentifier("Visit"+name) {
+				return errors.Errorf("parent %s: visitor %q of %s is not a Go identifier", defName, name, child)
+			}
+		}
+		if parent.Interface != "" {
+			if other, ok := names[parent.Interface]; ok {
+				return errors.Errorf("parents %s and %s are both named %s", other, defName, parent.Interface)
+			}
+			names[parent.Interface] = defName
+		}
+	}
+
+	return nil
+}
+
+// Processors returns a processor for each schema the targets name, applying
+// the rest of the config
+func (c *Config) Processors() ([]*Processor, error) {
+	var processors []*Processor
+	for _, target := range c.Targets {
+		expanded, err := ExpandTarget(target)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range expanded {
+			p := NewProcessor(t.Schema, t.Model, t.Output)
+			p.InPlace = t.InPlace
+			p.GenerateModel = t.Generate
+			p.TypeScriptPath = t.TypeScript
+			p.Proto = t.Proto
+			p.Config = c.forTarget(t)
+			processors = append(processors, p)
+		}
+	}
+	return processors, nil
+}
+
+// forTarget returns the config with the target's own settings applied
+func (c *Config) forTarget(target Target) *Config {
+	config := *c
+	config.Targets = nil
+	if target.Package != "" {
+		config.Package = target.Package
+	}
+	if len(target.Parents) > 0 {
+		config.Parents = make(map[string]ParentConfig, len(c.Parents)+len(target.Parents))
+		for defName, parent := range c.Parents {
+			config.Parents[defName] = parent
+		}
+		for defName, parent := range target.Parents {
+			config.Parents[defName] = parent
+		}
+	}
+	return &config
+}
+
+// Emits reports whether the named generated file is rendered
+func (c *Config) Emits(name string) bool {
+	if c == nil || len(c.Emit) == 0 || requiredFiles[name] {
+		return true
+	}
+	return containsString(c.Emit, name)
+}
+
+// Parent returns the overrides of the parent with the given definition name
+func (c *Config) Parent(defName string) ParentConfig {
+	if c == nil {
+		return ParentConfig{}
+	}
+	return c.Parents[defName]
+}
+
+// InterfaceName returns the Go name of the parent with the given definition name
+func (c *Config) InterfaceName(defName string) string {
+	if name := c.Parent(defName).Interface; name != "" {
+		return name
+	}
+	return defName
+}
+
+// PackageName returns the package the written model declares, given the
+// model's own
+func (c *Config) PackageName(modelPackage string) string {
+	if c == nil || c.Package == "" {
+		return modelPackage
+	}
+	return c.Package
+}
+
+// RequiredKeys returns the extra keys the config requires of the object at pointer
+func (c *Config) RequiredKeys(pointer string) []string {
+	if c == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(c.Required))
+	for name := range c.Required {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var keys []string
+	for _, name := range names {
+		if configPointer(name) == pointer {
+			keys = append(keys, c.Required[name]...)
+		}
+	}
+	return keys
+}
+
+// configPointer resolves a config key to the JSON pointer of the object it names
+func configPointer(key string) string {
+	if strings.HasPrefix(key, "#/") {
+		return key[1:]
+	}
+	return jsonPointer("definitions", key)
+}
+
+// sortedConfigKeys returns the keys of a config map in sorted order
+func sortedConfigKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// isIdentifier reports whether name can be declared in Go
+func isIdentifier(name string) bool {
+	return token.IsIdentifier(name) && name != "_"
+}
+
+// containsString reports whether values holds value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
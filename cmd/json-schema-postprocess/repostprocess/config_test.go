@@ -0,0 +1,356 @@
+package repostprocess
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"postprocess.yaml": "required:\n  ColorConfig: [id]\n  \"#/properties/theme\":\n    - mode\n",
+		"postprocess.json": `{"required": {"ColorConfig": ["id"], "#/properties/theme": ["mode"]}}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+
+		config, err := LoadConfig(path)
+		if err != nil {
+			t.Fatalf("Failed to load %s: %v", name, err)
+		}
+
+		if got := config.RequiredKeys("/definitions/ColorConfig"); !reflect.DeepEqual(got, []string{"id"}) {
+			t.Errorf("%s: expected [id] for ColorConfig, got %v", name, got)
+		}
+		if got := config.RequiredKeys("/properties/theme"); !reflect.DeepEqual(got, []string{"mode"}) {
+			t.Errorf("%s: expected [mode] for /properties/theme, got %v", name, got)
+		}
+		if got := config.RequiredKeys("/definitions/AssetPack"); got != nil {
+			t.Errorf("%s: expected no keys for AssetPack, got %v", name, got)
+		}
+	}
+
+	// A "#" key that isn't a pointer is most likely a typo
+	path := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(path, []byte("required:\n  \"#ColorConfig\": [id]\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("Expected an error for a malformed pointer key")
+	}
+
+	// A nil config requires nothing extra
+	var config *Config
+	if got := config.RequiredKeys("/definitions/ColorConfig"); got != nil {
+		t.Errorf("Expected no keys from a nil config, got %v", got)
+	}
+}
+
+func TestCodeGenerator_RequiredKeys(t *testing.T) {
+	schemaPath := filepath.Join("testdata", "color", "color.schema.json")
+	modelPath := filepath.Join("testdata", "color", "model.gen.go")
+
+	analyzer, err := NewSchemaAnalyzer(schemaPath)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+
+	results, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Failed to analyze schema: %v", err)
+	}
+
+	if got := results.Required["/definitions/AssetPack"]; !reflect.DeepEqual(got, []string{"brandName", "palettes"}) {
+		t.Errorf("Expected AssetPack to require [brandName palettes], got %v", got)
+	}
+
+	// Without a config the checks come from the schema alone
+	outputDir := t.TempDir()
+	generator := NewCodeGenerator(modelPath, outputDir, results)
+	if err := generator.Generate(); err != nil {
+		t.Fatalf("Failed to generate code: %v", err)
+	}
+	modelStr := readModel(t, outputDir)
+
+	checkForContent(t, modelStr, "field brandName in AssetPack: required")
+	checkForContent(t, modelStr, "field value in ColorConfig: required")
+	checkForAbsence(t, modelStr, "field semantic in AssetPack")
+	checkForAbsence(t, modelStr, "field semantic in ColorConfig")
+	valueChecks := strings.Count(modelStr, "field value in ColorConfig: required")
+
+	// Extra keys from the config are only required of the definition they're declared for
+	outputDir = t.TempDir()
+	generator = NewCodeGenerator(modelPath, outputDir, results)
+	generator.SetConfig(&Config{Required: map[string][]string{"ColorConfig": {"semantic", "value"}}})
+	if err := generator.Generate(); err != nil {
+		t.Fatalf("Failed to generate code: %v", err)
+	}
+	modelStr = readModel(t, outputDir)
+
+	checkForContent(t, modelStr, "field semantic in ColorConfig: required")
+	checkForAbsence(t, modelStr, "field semantic in AssetPack")
+	// A key the schema already requires isn't checked twice
+	if count := strings.Count(modelStr, "field value in ColorConfig: required"); count != valueChecks {
+		t.Errorf("Expected %d value checks, got %d", valueChecks, count)
+	}
+}
+
+func TestLoadConfig_Targets(t *testing.T) {
+	dir, other := t.TempDir(), t.TempDir()
+	for _, schema := range []string{filepath.Join(dir, "schema", "color.schema.json"), filepath.Join(other, "nested.schema.json")} {
+		if err := os.MkdirAll(filepath.Dir(schema), 0755); err != nil {
+			t.Fatalf("Failed to create schema directory: %v", err)
+		}
+		if err := os.WriteFile(schema, []byte(`{"definitions": {}}`), 0644); err != nil {
+			t.Fatalf("Failed to write schema: %v", err)
+		}
+	}
+
+	path := filepath.Join(dir, DefaultConfigFile)
+	content := `targets:
+  - schema: schema/color.schema.json
+    model: gen/color/model.go
+    inplace: true
+  - schema: ` + filepath.Join(other, "nested.schema.json") + `
+    model: ` + filepath.Join(other, "model.go") + `
+    output: out
+    package: nested
+    parents:
+      Color:
+        interface: NestedColor
+emit: [yaml]
+package: palette
+parents:
+  Color:
+    interface: AnyColor
+    constantMethod: Kind
+  Palette:
+    constantType: PaletteKind
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Relative paths are resolved from the config file
+	processors, err := config.Processors()
+	if err != nil {
+		t.Fatalf("Failed to list processors: %v", err)
+	}
+	if len(processors) != 2 {
+		t.Fatalf("Expected 2 processors, got %d", len(processors))
+	}
+	color, nested := processors[0], processors[1]
+	if color.SchemaPath != filepath.Join(dir, "schema", "color.schema.json") || !color.InPlace {
+		t.Errorf("Expected the color schema to be resolved and rewritten in place, got %+v", color)
+	}
+	if nested.SchemaPath != filepath.Join(other, "nested.schema.json") || nested.OutputDirPath != filepath.Join(dir, "out") {
+		t.Errorf("Expected the nested schema to keep its absolute path, got %+v", nested)
+	}
+
+	// A target's own settings replace the shared ones
+	if got := color.Config.PackageName("color"); got != "palette" {
+		t.Errorf("Expected the shared package, got %s", got)
+	}
+	if got := nested.Config.PackageName("nested"); got != "nested" {
+		t.Errorf("Expected the target's package, got %s", got)
+	}
+	if got := color.Config.InterfaceName("Color"); got != "AnyColor" {
+		t.Errorf("Expected AnyColor, got %s", got)
+	}
+	if got := nested.Config.Parent("Color"); got.Interface != "NestedColor" || got.ConstantMethod != "" {
+		t.Errorf("Expected the target's override of Color alone, got %+v", got)
+	}
+	if got := nested.Config.Parent("Palette").ConstantType; got != "PaletteKind" {
+		t.Errorf("Expected the shared override of Palette, got %q", got)
+	}
+
+	// The interfaces and unmarshal functions are always emitted
+	for name, want := range map[string]bool{"interfaces": true, "unmarshal": true, "yaml": true, "methods": false, "validate": false} {
+		if got := config.Emits(name); got != want {
+			t.Errorf("Expected Emits(%s) to be %v", name, want)
+		}
+	}
+	var none *Config
+	if !none.Emits("validate") || none.InterfaceName("Color") != "Color" || none.PackageName("color") != "color" {
+		t.Error("Expected a nil config to keep the defaults")
+	}
+}
+
+func TestLoadConfig_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"no model":         "targets:\n  - schema: a.json\n    output: out\n",
+		"no output":        "targets:\n  - schema: a.json\n    model: a.go\n",
+		"output inplace":   "targets:\n  - schema: a.json\n    model: a.go\n    output: out\n    inplace: true\n",
+		"unknown emit":     "emit: [interfaces, docs]\n",
+		"bad package":      "package: my-package\n",
+		"bad interface":    "parents:\n  Color:\n    interface: any color\n",
+		"bad visitor":      "parents:\n  Color:\n    visitors:\n      RGBValue: \"\"\n",
+		"same interface":   "parents:\n  Color:\n    interface: Shade\n  Tint:\n    interface: Shade\n",
+		"target interface": "parents:\n  Color:\n    interface: Shade\ntargets:\n  - schema: a.json\n    model: a.go\n    inplace: true\n    parents:\n      Tint:\n        interface: Shade\n",
+		"proto no file":    "targets:\n  - schema: a.json\n    model: a.go\n    inplace: true\n    proto:\n      package: a\n",
+		"proto converters": "targets:\n  - schema: a.json\n    model: a.go\n    inplace: true\n    proto:\n      file: a.proto\n      converters: a.gen.go\n",
+		"unknown key":      "packge: palette\n",
+		"unknown nested":   "parents:\n  Color:\n    interfce: Shade\n",
+		"unknown target":   "targets:\n  - schema: a.json\n    model: a.go\n    inplace: true\n    typescrpt: a.ts\n",
+	}
+	dir := t.TempDir()
+	for name, content := range tests {
+		path := filepath.Join(dir, strings.ReplaceAll(name, " ", "-")+".yaml")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write config: %v", err)
+		}
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestCodeGenerator_ConfigOverrides(t *testing.T) {
+	schemaPath := filepath.Join("testdata", "color", "color.schema.json")
+	modelPath := filepath.Join("testdata", "color", "model.gen.go")
+	config := &Config{
+		Emit:    []string{"validate"},
+		Package: "palette",
+		Parents: map[string]ParentConfig{
+			"Color": {
+				Interface:      "AnyColor",
+				Discriminator:  "model",
+				ConstantType:   "ColorKind",
+				ConstantMethod: "Kind",
+				Visitors:       map[string]string{"RGBValue": "Rgb"},
+			},
+		},
+	}
+
+	analyzer, err := NewSchemaAnalyzer(schemaPath)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+	analyzer.SetConfig(config)
+	results, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Failed to analyze schema: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	generator := NewCodeGenerator(modelPath, outputDir, results)
+	generator.SetConfig(config)
+	if err := generator.Generate(); err != nil {
+		t.Fatalf("Failed to generate code: %v", err)
+	}
+	modelStr := readModel(t, outputDir)
+
+	checkForContent(t, modelStr, "package palette")
+
+	// Everything built from the parent's name follows its interface name
+	checkForContent(t, modelStr, "type AnyColor interface {")
+	checkForContent(t, modelStr, "type AnyColorSlice []AnyColor")
+	checkForContent(t, modelStr, "func parseAnyColorRaw(data json.RawMessage) (AnyColor, error) {")
+	checkForContent(t, modelStr, "func ValidateAnyColor(value AnyColor) error {")
+	checkForContent(t, modelStr, "Colors AnyColorSlice")
+	checkForAbsence(t, modelStr, "type Color interface")
+
+	// The constants and their accessor take the configured names
+	checkForContent(t, modelStr, "type ColorKind string")
+	checkForContent(t, modelStr, "ColorKindRgb  ColorKind = \"rgb\"")
+	checkForContent(t, modelStr, "func (me *RGBValue) Kind() ColorKind { return ColorKindRgb }")
+	checkForContent(t, modelStr, "Kind ColorKind `json:\"model\"")
+	checkForAbsence(t, modelStr, "ColorModel")
+
+	// Only the RGB visitor is renamed
+	checkForContent(t, modelStr, "VisitRgb(*RGBValue)")
+	checkForContent(t, modelStr, "VisitHSL(*HSLValue)")
+
+	// The files left out of Emit aren't rendered
+	checkForAbsence(t, modelStr, "UnmarshalYAML")
+	checkForAbsence(t, modelStr, "func CloneAnyColor")
+
+	validateGoTypes(t, outputDir)
+}
+
+func TestSchemaAnalyzer_ConfiguredDiscriminator(t *testing.T) {
+	analyzer, err := NewSchemaAnalyzer(filepath.Join("testdata", "color", "color.schema.json"))
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+	analyzer.SetConfig(&Config{Parents: map[string]ParentConfig{"Color": {Discriminator: "r"}}})
+	results, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Failed to analyze schema: %v", err)
+	}
+
+	if err := CheckDiagnostics(results.Diagnostics, false); err == nil {
+		t.Fatal("Expected a discriminator that isn't in every child to fail")
+	}
+	found := false
+	for _, d := range results.Diagnostics {
+		if d.Code == DiagnosticInvalidDiscriminator && d.Pointer == "/definitions/Color" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected an invalid-discriminator diagnostic on Color, got %v", results.Diagnostics)
+	}
+}
+
+func TestConfig_ProcessesEveryTarget(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, DefaultConfigFile)
+	var content strings.Builder
+	content.WriteString("targets:\n")
+	for _, name := range []string{"color", "nested", "simple"} {
+		abs, err := filepath.Abs(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatalf("Failed to resolve testdata: %v", err)
+		}
+		content.WriteString("  - schema: " + filepath.Join(abs, name+".schema.json") + "\n")
+		content.WriteString("    model: " + filepath.Join(abs, "model.gen.go") + "\n")
+		content.WriteString("    output: out/" + name + "\n")
+	}
+	if err := os.WriteFile(path, []byte(content.String()), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	processors, err := config.Processors()
+	if err != nil {
+		t.Fatalf("Failed to list processors: %v", err)
+	}
+	for _, processor := range processors {
+		if err := processor.Process(); err != nil {
+			t.Fatalf("Failed to process %s: %v", processor.SchemaPath, err)
+		}
+		// The output directories are created as needed
+		if _, err := os.Stat(filepath.Join(processor.OutputDirPath, "model.gen.go")); err != nil {
+			t.Errorf("Expected a model for %s: %v", processor.SchemaPath, err)
+		}
+	}
+}
+
+func TestLoadConfig_UnknownKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("emit: [methods]\nparents:\n  Color:\n    interfce: Shade\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	// The misspelled key is named, rather than its setting left at the default
+	_, err := LoadConfig(path)
+	if err == nil || !strings.Contains(err.Error(), "loading config") || !strings.Contains(err.Error(), "field interfce not found") {
+		t.Errorf("Expected the unknown key to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,274 @@
+package repostprocess
+
+import (
+	"go/ast"
+	"strconv"
+	"strings"
+)
+
+// Constraint is a validation keyword on one property of an object holding parents
+type Constraint struct {
+	Field   string
+	Keyword string
+	// Value is a float64 for bounds, counts and multipleOf, the pattern for
+	// pattern, true for uniqueItems, the allowed values for enum and the single
+	// allowed value for const
+	Value interface{}
+	// Required is set when the property is required and can't be null, so a
+	// missing value counts as an empty one
+	Required bool
+}
+
+// constraintKeywords are the validation keywords carried into the generated
+// unmarshalers, in the order they're checked
+var constraintKeywords = []string{
+	"minimum", "exclusiveMinimum", "maximum", "exclusiveMaximum", "multipleOf",
+	"minLength", "maxLength", "pattern", "minItems", "maxItems", "uniqueItems",
+	"enum", "const",
+}
+
+// propertyConstraints returns the validation keywords on an object's properties
+func propertyConstraints(object map[string]interface{}) []Constraint {
+	properties, ok := object["properties"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	required := requiredSet(object)
+	var constraints []Constraint
+	for _, propName := range sortedKeys(properties) {
+		propMap, ok := properties[propName].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		_, nullable := nullableSchema(propMap)
+		nonNull := required[propName] && !nullable && !allowsNull(propMap)
+
+		for _, keyword := range constraintKeywords {
+			value, ok := propMap[keyword]
+			if !ok {
+				continue
+			}
+
+			switch keyword {
+			case "exclusiveMinimum", "exclusiveMaximum":
+				// Draft 4 writes these as booleans that modify minimum and maximum
+				if _, isBool := value.(bool); isBool {
+					continue
+				}
+			case "minimum", "maximum":
+				if exclusive, _ := propMap["exclusive"+strings.Title(keyword)].(bool); exclusive {
+					keyword = "exclusive" + strings.Title(keyword)
+				}
+			}
+
+			if constraint, ok := newConstraint(propName, keyword, value); ok {
+				constraint.Required = nonNull
+				constraints = append(constraints, constraint)
+			}
+		}
+	}
+	return constraints
+}
+
+// newConstraint checks that a keyword's value has the expected JSON type
+func newConstraint(field, keyword string, value interface{}) (Constraint, bool) {
+	switch keyword {
+	case "pattern":
+		if _, ok := value.(string); !ok {
+			return Constraint{}, false
+		}
+	case "enum":
+		values, ok := value.([]interface{})
+		if !ok || len(values) == 0 {
+			return Constraint{}, false
+		}
+		// Only scalar enums can be compared with a switch
+		for _, v := range values {
+			switch v.(type) {
+			case string, float64, bool:
+			default:
+				return Constraint{}, false
+			}
+		}
+	case "const":
+		switch value.(type) {
+		case string, float64, bool:
+		default:
+			return Constraint{}, false
+		}
+	case "multipleOf":
+		if divisor, ok := value.(float64); !ok || divisor <= 0 {
+			return Constraint{}, false
+		}
+	case "uniqueItems":
+		// uniqueItems: false allows anything, so only true is checked
+		if unique, _ := value.(bool); !unique {
+			return Constraint{}, false
+		}
+	default:
+		if _, ok := value.(float64); !ok {
+			return Constraint{}, false
+		}
+	}
+	return Constraint{Field: field, Keyword: keyword, Value: value}, true
+}
+
+// constraintData is one validation keyword checked on a decoded field of plain
+type constraintData struct {
+	Field string
+	// Path is the field's JSON pointer, relative to the object holding it
+	Path    string
+	GoField string
+	Keyword string
+	// Pointer is set for optional fields, which are only checked when present
+	Pointer bool
+	// Required is set when a missing value is checked as an empty one
+	Required bool
+	// Literal is the keyword's value as Go source: a number, a quoted pattern
+	// or const value, or the comma-separated enum values
+	Literal string
+}
+
+// goKind groups Go types by the keywords that can be checked on them
+type goKind int
+
+const (
+	goKindOther goKind = iota
+	goKindNumber
+	goKindString
+	goKindCollection
+)
+
+// keywordKinds maps each keyword to the kind of field it applies to
+var keywordKinds = map[string]goKind{
+	"minimum":          goKindNumber,
+	"exclusiveMinimum": goKindNumber,
+	"maximum":          goKindNumber,
+	"exclusiveMaximum": goKindNumber,
+	"multipleOf":       goKindNumber,
+	"minLength":        goKindString,
+	"maxLength":        goKindString,
+	"pattern":          goKindString,
+	"minItems":         goKindCollection,
+	"maxItems":         goKindCollection,
+	"uniqueItems":      goKindCollection,
+}
+
+// constraintData turns a struct's schema constraints into template data,
+// skipping fields the struct doesn't have and keywords its field types can't hold
+func (m *modelFile) constraintData(structName string, constraints []Constraint) []constraintData {
+	var data []constraintData
+	for _, constraint := range constraints {
+		field, ok := m.structField(structName, constraint.Field)
+		if !ok || len(field.Names) != 1 || m.constantFields[structName] == field.Names[0].Name {
+			continue
+		}
+
+		expr := field.Type
+		star, pointer := expr.(*ast.StarExpr)
+		if pointer {
+			expr = star.X
+		}
+
+		kind := m.goKind(expr)
+		if wanted, ok := keywordKinds[constraint.Keyword]; ok && wanted != kind {
+			continue
+		}
+		// Only arrays have items to compare
+		if constraint.Keyword == "uniqueItems" && !m.isArray(expr) {
+			continue
+		}
+		if (constraint.Keyword == "enum" || constraint.Keyword == "const") && kind != goKindNumber && kind != goKindString && !isIdent(expr, "bool") {
+			continue
+		}
+
+		data = append(data, constraintData{
+			Field:    constraint.Field,
+			Path:     jsonPointer(constraint.Field),
+			GoField:  field.Names[0].Name,
+			Keyword:  constraint.Keyword,
+			Pointer:  pointer,
+			Required: constraint.Required,
+			Literal:  constraintLiteral(constraint.Value),
+		})
+	}
+	return data
+}
+
+// goKind classifies a field type, looking through named types declared in the model
+func (m *modelFile) goKind(expr ast.Expr) goKind {
+	for i := 0; i < 8; i++ {
+		switch e := expr.(type) {
+		case *ast.ArrayType, *ast.MapType:
+			return goKindCollection
+		case *ast.Ident:
+			switch e.Name {
+			case "int", "int8", "int16", "int32", "int64",
+				"uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+				return goKindNumber
+			case "string":
+				return goKindString
+			}
+			if m.collections[e.Name] {
+				return goKindCollection
+			}
+			typeSpec, ok := m.types[e.Name]
+			if !ok {
+				return goKindOther
+			}
+			expr = typeSpec.Type
+		default:
+			return goKindOther
+		}
+	}
+	return goKindOther
+}
+
+// isArray reports whether a field type is a slice, looking through named types
+// declared in the model
+func (m *modelFile) isArray(expr ast.Expr) bool {
+	for i := 0; i < 8; i++ {
+		switch e := expr.(type) {
+		case *ast.ArrayType:
+			return true
+		case *ast.Ident:
+			if m.collections[e.Name] {
+				return strings.HasSuffix(e.Name, "Slice")
+			}
+			typeSpec, ok := m.types[e.Name]
+			if !ok {
+				return false
+			}
+			expr = typeSpec.Type
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// isIdent reports whether expr is the identifier name
+func isIdent(expr ast.Expr, name string) bool {
+	ident, ok := expr.(*ast.Ident)
+	return ok && ident.Name == name
+}
+
+// constraintLiteral writes a keyword's value as Go source
+func constraintLiteral(value interface{}) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case string:
+		return strconv.Quote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		literals := make([]string, 0, len(v))
+		for _, item := range v {
+			literals = append(literals, constraintLiteral(item))
+		}
+		return strings.Join(literals, ", ")
+	}
+	return ""
+}
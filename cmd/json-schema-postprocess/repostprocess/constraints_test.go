@@ -0,0 +1,151 @@
+package repostprocess
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPropertyConstraints(t *testing.T) {
+	object := map[string]interface{}{
+		"properties": map[string]interface{}{
+			"count": map[string]interface{}{
+				"type":    "integer",
+				"minimum": float64(1),
+				"maximum": float64(5),
+				// Draft 4 marks the bound itself as exclusive
+				"exclusiveMaximum": true,
+			},
+			"kind": map[string]interface{}{
+				"enum": []interface{}{"a", "b"},
+			},
+			"version": map[string]interface{}{
+				"const": float64(2),
+			},
+			"shape": map[string]interface{}{
+				// Only scalar consts are checked
+				"const": []interface{}{"a"},
+			},
+			"mixed": map[string]interface{}{
+				// Only scalar enums are checked
+				"enum": []interface{}{"a", map[string]interface{}{}},
+			},
+			"tags": map[string]interface{}{
+				"type":        "array",
+				"minItems":    float64(1),
+				"uniqueItems": true,
+			},
+			"notes": map[string]interface{}{
+				// Only true is checked
+				"type":        "array",
+				"uniqueItems": false,
+			},
+			"step": map[string]interface{}{
+				"type":       "number",
+				"multipleOf": 0.5,
+			},
+		},
+		"required": []interface{}{"tags", "version"},
+	}
+
+	expected := []Constraint{
+		{Field: "count", Keyword: "minimum", Value: float64(1)},
+		{Field: "count", Keyword: "exclusiveMaximum", Value: float64(5)},
+		{Field: "kind", Keyword: "enum", Value: []interface{}{"a", "b"}},
+		{Field: "step", Keyword: "multipleOf", Value: 0.5},
+		{Field: "tags", Keyword: "minItems", Value: float64(1), Required: true},
+		{Field: "tags", Keyword: "uniqueItems", Value: true, Required: true},
+		{Field: "version", Keyword: "const", Value: float64(2), Required: true},
+	}
+	if got := propertyConstraints(object); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected constraints %v, got %v", expected, got)
+	}
+}
+
+func TestConstraintLiteral(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected string
+	}{
+		{float64(1), "1"},
+		{-107.863, "-107.863"},
+		{"^[a-z]+$", `"^[a-z]+$"`},
+		{[]interface{}{"warm", float64(2), true}, `"warm", 2, true`},
+	}
+
+	for _, tt := range tests {
+		if got := constraintLiteral(tt.value); got != tt.expected {
+			t.Errorf("Expected literal %s for %v, got %s", tt.expected, tt.value, got)
+		}
+	}
+}
+
+// constraintsTest runs in the package generated from the constraints fixture
+const constraintsTest = `package constraints
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCallerConstraints(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{` + "`" + `{"shape": {"kind": "circle", "radius": 1.5}, "lines": ["a"], "quantity": 10, "price": 19.99, "tags": ["a", "b"]}` + "`" + `, ""},
+		{` + "`" + `{"shape": {"kind": "circle", "radius": 1.5}, "lines": null}` + "`" + `, "field lines length: must be >= 1"},
+		{` + "`" + `{"shape": {"kind": "circle", "radius": 1.5}, "lines": []}` + "`" + `, "field lines length: must be >= 1"},
+		{` + "`" + `{"shape": {"kind": "circle", "radius": 1.5}, "lines": ["a"], "quantity": 7}` + "`" + `, "field quantity: must be a multiple of 5"},
+		{` + "`" + `{"shape": {"kind": "circle", "radius": 1.5}, "lines": ["a"], "price": 1.005}` + "`" + `, "field price: must be a multiple of 0.01"},
+		{` + "`" + `{"shape": {"kind": "circle", "radius": 1.5}, "lines": ["a"], "tags": ["a", "b", "a"]}` + "`" + `, "field tags: item 2 repeats an earlier item"},
+		{` + "`" + `{"shape": {"kind": "circle", "radius": 1.5}, "lines": ["a"], "color": "red"}` + "`" + `, "field color in Order: not allowed"},
+	}
+
+	for _, tt := range tests {
+		var order Order
+		err := json.Unmarshal([]byte(tt.input), &order)
+		if tt.expected == "" {
+			if err != nil {
+				t.Errorf("Expected %s to decode, got %v", tt.input, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("Expected %s to fail with %q, got %v", tt.input, tt.expected, err)
+		}
+	}
+}
+
+func TestConstructorConstraints(t *testing.T) {
+	if _, err := NewCircle(1.5); err != nil {
+		t.Errorf("Expected a radius of 1.5 to be allowed, got %v", err)
+	}
+	if _, err := NewCircle(0.75); err == nil || !strings.Contains(err.Error(), "must be a multiple of 0.5") {
+		t.Errorf("Expected a radius of 0.75 to be rejected, got %v", err)
+	}
+}
+
+func TestValidateConstraints(t *testing.T) {
+	quantity := 3
+	order := Order{Shape: &Square{Side: 1}, Lines: []string{"a"}, Quantity: &quantity, Tags: []string{"a", "a"}}
+	err := order.Validate()
+	if err == nil {
+		t.Fatal("Expected the order to be invalid")
+	}
+	for _, expected := range []string{"/quantity: must be a multiple of 5", "/tags/1: repeats an earlier item"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected %q in %v", expected, err)
+		}
+	}
+}
+`
+
+// TestConstraints_Generated checks that the caller unmarshaler, constructors and
+// Validate enforce multipleOf, uniqueItems, a required minItems and
+// additionalProperties: false
+func TestConstraints_Generated(t *testing.T) {
+	dir := generatePackage(t, filepath.Join("testdata", "constraints", "constraints.schema.json"), "constraints")
+	runGoTest(t, dir, constraintsTest)
+}
@@ -0,0 +1,104 @@
+package repostprocess
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// determinismRuns is how many times each schema is regenerated and compared
+// against the first run
+const determinismRuns = 20
+
+// updateGolden rewrites the checked-in outputs the determinism test compares
+// against, after a change that's meant to alter the generated code
+var updateGolden = flag.Bool("update", false, "rewrite the golden outputs in testdata")
+
+func TestProcessor_DeterministicOutput(t *testing.T) {
+	schemas := []string{"color", "confusing", "nested", "discriminators", "overlap"}
+
+	for _, name := range schemas {
+		t.Run(name, func(t *testing.T) {
+			schemaPath := filepath.Join("testdata", name, name+".schema.json")
+			modelPath := filepath.Join("testdata", name, "model.gen.go")
+			tmpDir := t.TempDir()
+
+			// The first run has to match the output checked in, which other
+			// processes, platforms and Go versions generated
+			golden := generateOutputs(t, schemaPath, modelPath, filepath.Join(tmpDir, "golden"))
+			if len(golden) == 0 {
+				t.Fatalf("No generated files for %s", name)
+			}
+			for file, got := range golden {
+				goldenPath := filepath.Join("testdata", name, file+".golden")
+				if *updateGolden {
+					if err := os.WriteFile(goldenPath, got, 0644); err != nil {
+						t.Fatalf("Failed to write %s: %v", goldenPath, err)
+					}
+					continue
+				}
+				want, err := os.ReadFile(goldenPath)
+				if err != nil {
+					t.Fatalf("Failed to read %s, run the test with -update to create it: %v", goldenPath, err)
+				}
+				if !bytes.Equal(got, want) {
+					t.Fatalf("Generated %s differs from %s, run the test with -update if the change is intended", file, goldenPath)
+				}
+			}
+
+			// Every later run has to match the first
+
+			for i := 1; i < determinismRuns; i++ {
+				outputs := generateOutputs(t, schemaPath, modelPath, filepath.Join(tmpDir, fmt.Sprintf("run%d", i)))
+
+				if len(outputs) != len(golden) {
+					t.Fatalf("Run %d generated %d files, expected %d", i, len(outputs), len(golden))
+				}
+
+				for file, want := range golden {
+					if got := outputs[file]; !bytes.Equal(got, want) {
+						t.Fatalf("Run %d generated different content for %s", i, file)
+					}
+				}
+			}
+		})
+	}
+}
+
+// generateOutputs processes a schema into outputDir and returns the generated
+// files keyed by file name
+func generateOutputs(t *testing.T, schemaPath, modelPath, outputDir string) map[string][]byte {
+	t.Helper()
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		t.Fatalf("Failed to create output directory: %v", err)
+	}
+
+	processor := NewProcessor(schemaPath, modelPath, outputDir)
+	if err := processor.Process(); err != nil {
+		t.Fatalf("Failed to process %s: %v", schemaPath, err)
+	}
+
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		t.Fatalf("Failed to read output directory: %v", err)
+	}
+
+	outputs := make(map[string][]byte)
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".gen.go") {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(outputDir, entry.Name()))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", entry.Name(), err)
+		}
+		outputs[entry.Name()] = content
+	}
+
+	return outputs
+}
@@ -0,0 +1,217 @@
+package repostprocess
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gitlab.com/tozd/go/errors"
+)
+
+// Severity is how serious a diagnostic is
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// DiagnosticCode identifies the kind of problem a diagnostic reports
+type DiagnosticCode string
+
+const (
+	// DiagnosticInlineChild is reported for anyOf entries that aren't a $ref
+	DiagnosticInlineChild DiagnosticCode = "inline-child"
+	// DiagnosticMissingChild is reported for anyOf references that aren't in definitions
+	DiagnosticMissingChild DiagnosticCode = "missing-child"
+	// DiagnosticDuplicateChild is reported for anyOf entries that repeat an earlier reference
+	DiagnosticDuplicateChild DiagnosticCode = "duplicate-child"
+	// DiagnosticAmbiguousUnion is reported for parents whose children share no discriminator
+	DiagnosticAmbiguousUnion DiagnosticCode = "ambiguous-union"
+	// DiagnosticOverlappingChildren is reported for parents whose children share a constant value
+	DiagnosticOverlappingChildren DiagnosticCode = "overlapping-children"
+	// DiagnosticInvalidDiscriminator is reported for a discriminator from the
+	// config that doesn't tell the children of its parent apart
+	DiagnosticInvalidDiscriminator DiagnosticCode = "invalid-discriminator"
+	// DiagnosticTypeError is reported for generated code that doesn't type-check
+	DiagnosticTypeError DiagnosticCode = "type-error"
+)
+
+// Diagnostic describes a problem found while analyzing a schema or checking the
+// code generated from it
+type Diagnostic struct {
+	// Pointer is the JSON pointer to the schema location the diagnostic is about
+	Pointer  string         `json:"pointer"`
+	Severity Severity       `json:"severity"`
+	Code     DiagnosticCode `json:"code"`
+	Message  string         `json:"message"`
+}
+
+// String formats the diagnostic as a single line of text
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s %s %s: %s", d.Severity, d.Code, d.Pointer, d.Message)
+}
+
+// addDiagnostic records a diagnostic on the results
+func (r *SchemaResults) addDiagnostic(pointer string, severity Severity, code DiagnosticCode, message string) {
+	r.Diagnostics = append(r.Diagnostics, Diagnostic{
+		Pointer:  pointer,
+		Severity: severity,
+		Code:     code,
+		Message:  message,
+	})
+}
+
+// sortDiagnostics orders diagnostics by location, then code
+func (r *SchemaResults) sortDiagnostics() {
+	sort.SliceStable(r.Diagnostics, func(i, j int) bool {
+		if r.Diagnostics[i].Pointer != r.Diagnostics[j].Pointer {
+			return pointerLess(r.Diagnostics[i].Pointer, r.Diagnostics[j].Pointer)
+		}
+		return r.Diagnostics[i].Code < r.Diagnostics[j].Code
+	})
+}
+
+// pointerLess compares two JSON pointers token by token, so array indexes sort numerically
+func pointerLess(a, b string) bool {
+	aTokens := strings.Split(a, "/")
+	bTokens := strings.Split(b, "/")
+	for i := 0; i < len(aTokens) && i < len(bTokens); i++ {
+		if aTokens[i] == bTokens[i] {
+			continue
+		}
+		aIndex, aErr := strconv.Atoi(aTokens[i])
+		bIndex, bErr := strconv.Atoi(bTokens[i])
+		if aErr == nil && bErr == nil {
+			return aIndex < bIndex
+		}
+		return aTokens[i] < bTokens[i]
+	}
+	return len(aTokens) < len(bTokens)
+}
+
+// identifyAmbiguousParents reports parents that ended up without a discriminator
+func (sa *SchemaAnalyzer) identifyAmbiguousParents(definitions map[string]interface{}, results *SchemaResults) {
+	for _, parentName := range results.SortedParentNames() {
+		parent := results.Parents[parentName]
+		if parent.Discriminator != DiscriminatorNone || len(parent.LeafChildren) < 2 {
+			continue
+		}
+
+		pointer := jsonPointer("definitions", parentName)
+
+		// A constant field held by every child, but with repeated values, is worth calling out
+		if field, overlapping, ok := overlappingConstantField(definitions, parent.LeafChildren); ok {
+			results.addDiagnostic(pointer, SeverityWarning, DiagnosticOverlappingChildren,
+				fmt.Sprintf("children of %s share the %q value of %q: %s", parentName, overlapping[0], field, strings.Join(overlapping[1:], ", ")))
+			continue
+		}
+
+		// Otherwise name the children that a single input can match
+		if len(parent.Overlaps) > 0 {
+			results.addDiagnostic(pointer, SeverityWarning, DiagnosticOverlappingChildren,
+				fmt.Sprintf("an input can validly match more than one child of %s: %s", parentName, describeOverlaps(parent.Overlaps)))
+			continue
+		}
+
+		results.addDiagnostic(pointer, SeverityWarning, DiagnosticAmbiguousUnion,
+			fmt.Sprintf("children of %s share no discriminator, so each one is tried in turn", parentName))
+	}
+}
+
+// overlappingConstantField finds the first constant field held by every child where
+// some children repeat a value. It returns the field, then the repeated value
+// followed by the children that hold it
+func overlappingConstantField(definitions map[string]interface{}, children []string) (string, []string, bool) {
+	for _, propName := range constantFieldCandidates(definitions, children) {
+		holders := make(map[string][]string)
+		values := []string{}
+		inAll := true
+
+		for _, childName := range children {
+			value, _, ok := childConstantValue(definitions, childName, propName)
+			if !ok {
+				inAll = false
+				break
+			}
+			if _, ok := holders[value]; !ok {
+				values = append(values, value)
+			}
+			holders[value] = append(holders[value], childName)
+		}
+		if !inAll {
+			continue
+		}
+
+		for _, value := range values {
+			if len(holders[value]) > 1 {
+				return propName, append([]string{value}, holders[value]...), true
+			}
+		}
+	}
+	return "", nil, false
+}
+
+// jsonPointer builds an RFC 6901 JSON pointer from its reference tokens
+func jsonPointer(tokens ...string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		token = strings.ReplaceAll(token, "~", "~0")
+		token = strings.ReplaceAll(token, "/", "~1")
+		b.WriteString("/")
+		b.WriteString(token)
+	}
+	return b.String()
+}
+
+// CheckDiagnostics returns an error if any diagnostic is an error, or if strict
+// is set and any diagnostic is a warning
+func CheckDiagnostics(diagnostics []Diagnostic, strict bool) error {
+	var failures []string
+	for _, d := range diagnostics {
+		if d.Severity == SeverityError || (strict && d.Severity == SeverityWarning) {
+			failures = append(failures, d.String())
+		}
+	}
+	if len(failures) > 0 {
+		return errors.Errorf("schema has %d blocking diagnostics:\n%s", len(failures), strings.Join(failures, "\n"))
+	}
+	return nil
+}
+
+// formatDiagnostics formats diagnostics one per line
+func formatDiagnostics(diagnostics []Diagnostic) string {
+	lines := make([]string, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		lines = append(lines, d.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// WriteDiagnostics writes the diagnostics to w as "text" or "json"
+func WriteDiagnostics(w io.Writer, diagnostics []Diagnostic, format string) error {
+	switch format {
+	case "json":
+		if diagnostics == nil {
+			diagnostics = []Diagnostic{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(diagnostics); err != nil {
+			return errors.Errorf("encoding diagnostics: %w", err)
+		}
+	case "text":
+		for _, d := range diagnostics {
+			if _, err := fmt.Fprintln(w, d.String()); err != nil {
+				return errors.Errorf("writing diagnostic: %w", err)
+			}
+		}
+	default:
+		return errors.Errorf("unknown report format %q, expected text or json", format)
+	}
+	return nil
+}
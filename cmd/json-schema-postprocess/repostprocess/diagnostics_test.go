@@ -0,0 +1,134 @@
+package repostprocess
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiagnostics_AmbiguousSchema(t *testing.T) {
+	schemaPath := filepath.Join("testdata", "ambiguous", "ambiguous.schema.json")
+	analyzer, err := NewSchemaAnalyzer(schemaPath)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+
+	results, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Failed to analyze schema: %v", err)
+	}
+
+	expected := []Diagnostic{
+		{Pointer: "/definitions/Label", Severity: SeverityWarning, Code: DiagnosticOverlappingChildren},
+		{Pointer: "/definitions/Shape", Severity: SeverityWarning, Code: DiagnosticOverlappingChildren},
+		{Pointer: "/definitions/Shape/anyOf/2", Severity: SeverityError, Code: DiagnosticMissingChild},
+		{Pointer: "/definitions/Shape/anyOf/3", Severity: SeverityWarning, Code: DiagnosticInlineChild},
+	}
+
+	if len(results.Diagnostics) != len(expected) {
+		t.Fatalf("Expected %d diagnostics, got %d: %v", len(expected), len(results.Diagnostics), results.Diagnostics)
+	}
+	for i, want := range expected {
+		got := results.Diagnostics[i]
+		if got.Pointer != want.Pointer || got.Severity != want.Severity || got.Code != want.Code {
+			t.Errorf("Diagnostic %d: expected %s %s %s, got %s", i, want.Severity, want.Code, want.Pointer, got)
+		}
+		if got.Message == "" {
+			t.Errorf("Diagnostic %d has an empty message", i)
+		}
+	}
+
+	// The missing child is not kept as a child
+	shapeInfo := results.Parents["Shape"]
+	if len(shapeInfo.Children) != 2 {
+		t.Errorf("Expected Shape to keep 2 children, got %v", shapeInfo.Children)
+	}
+}
+
+func TestDiagnostics_DuplicateChildren(t *testing.T) {
+	schemaPath := filepath.Join("testdata", "color", "color.schema.json")
+	analyzer, err := NewSchemaAnalyzer(schemaPath)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+
+	results, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Failed to analyze schema: %v", err)
+	}
+
+	// Color repeats seven of its eight children
+	if len(results.Diagnostics) != 7 {
+		t.Fatalf("Expected 7 diagnostics, got %d: %v", len(results.Diagnostics), results.Diagnostics)
+	}
+	for _, d := range results.Diagnostics {
+		if d.Code != DiagnosticDuplicateChild {
+			t.Errorf("Expected only duplicate-child diagnostics, got %s", d)
+		}
+		if !strings.HasPrefix(d.Pointer, "/definitions/Color/anyOf/") {
+			t.Errorf("Expected diagnostic under /definitions/Color/anyOf, got %s", d.Pointer)
+		}
+	}
+}
+
+func TestDiagnostics_CheckDiagnostics(t *testing.T) {
+	warning := Diagnostic{Pointer: "/definitions/A", Severity: SeverityWarning, Code: DiagnosticAmbiguousUnion, Message: "m"}
+	failure := Diagnostic{Pointer: "/definitions/B", Severity: SeverityError, Code: DiagnosticMissingChild, Message: "m"}
+
+	if err := CheckDiagnostics([]Diagnostic{warning}, false); err != nil {
+		t.Errorf("Expected warnings to pass without strict mode, got %v", err)
+	}
+	if err := CheckDiagnostics([]Diagnostic{warning}, true); err == nil {
+		t.Error("Expected warnings to fail in strict mode")
+	}
+	if err := CheckDiagnostics([]Diagnostic{failure}, false); err == nil {
+		t.Error("Expected errors to fail without strict mode")
+	}
+}
+
+func TestDiagnostics_WriteDiagnostics(t *testing.T) {
+	diagnostics := []Diagnostic{
+		{Pointer: "/definitions/Label", Severity: SeverityWarning, Code: DiagnosticAmbiguousUnion, Message: "no discriminator"},
+	}
+
+	var text bytes.Buffer
+	if err := WriteDiagnostics(&text, diagnostics, "text"); err != nil {
+		t.Fatalf("Failed to write text report: %v", err)
+	}
+	if got := text.String(); got != "warning ambiguous-union /definitions/Label: no discriminator\n" {
+		t.Errorf("Unexpected text report: %q", got)
+	}
+
+	var jsonOut bytes.Buffer
+	if err := WriteDiagnostics(&jsonOut, diagnostics, "json"); err != nil {
+		t.Fatalf("Failed to write json report: %v", err)
+	}
+	var decoded []Diagnostic
+	if err := json.Unmarshal(jsonOut.Bytes(), &decoded); err != nil {
+		t.Fatalf("Failed to decode json report: %v", err)
+	}
+	if len(decoded) != 1 || decoded[0] != diagnostics[0] {
+		t.Errorf("Unexpected json report: %v", decoded)
+	}
+
+	if err := WriteDiagnostics(&text, diagnostics, "yaml"); err == nil {
+		t.Error("Expected an unknown format to fail")
+	}
+}
+
+func TestDiagnostics_PointerOrder(t *testing.T) {
+	if !pointerLess("/definitions/Color/anyOf/7", "/definitions/Color/anyOf/10") {
+		t.Error("Expected array indexes to sort numerically")
+	}
+	if !pointerLess("/definitions/Color", "/definitions/Color/anyOf/1") {
+		t.Error("Expected a pointer to sort before its children")
+	}
+}
+
+func TestDiagnostics_JSONPointer(t *testing.T) {
+	if got := jsonPointer("definitions", "a/b", "c~d"); got != "/definitions/a~1b/c~0d" {
+		t.Errorf("Unexpected pointer %q", got)
+	}
+}
@@ -0,0 +1,218 @@
+package repostprocess
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// DiscriminatorStrategy describes how the children of a parent are told apart
+type DiscriminatorStrategy string
+
+const (
+	// DiscriminatorNone means no discriminator was found, so each child is tried in turn
+	DiscriminatorNone DiscriminatorStrategy = ""
+	// DiscriminatorConstant means a single field holds a unique constant in every child
+	DiscriminatorConstant DiscriminatorStrategy = "constant"
+	// DiscriminatorComposite means several constant fields together identify a child
+	DiscriminatorComposite DiscriminatorStrategy = "composite"
+	// DiscriminatorPresence means each child has a required field no other child has
+	DiscriminatorPresence DiscriminatorStrategy = "presence"
+	// DiscriminatorKeys means the child is picked by a decision plan over the keys present
+	DiscriminatorKeys DiscriminatorStrategy = "keys"
+)
+
+// ConstantKind is the JSON kind of a discriminator constant
+type ConstantKind string
+
+const (
+	ConstantKindString  ConstantKind = "string"
+	ConstantKindInteger ConstantKind = "integer"
+	ConstantKindBoolean ConstantKind = "boolean"
+)
+
+// GoType returns the Go type used for constants of this kind
+func (k ConstantKind) GoType() string {
+	switch k {
+	case ConstantKindInteger:
+		return "int"
+	case ConstantKindBoolean:
+		return "bool"
+	default:
+		return "string"
+	}
+}
+
+// Literal returns the Go literal for a constant value of this kind
+func (k ConstantKind) Literal(value string) string {
+	if k == ConstantKindString || k == "" {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// Constant is a discriminator value along with its JSON kind, so the string
+// "1" and the integer 1 are told apart
+type Constant struct {
+	Value string
+	Kind  ConstantKind
+}
+
+// Decoded returns the Go expression equal to the constant once decoded into
+// an interface{}, where JSON numbers are float64
+func (c Constant) Decoded() string {
+	if c.Kind == ConstantKindInteger {
+		return "float64(" + c.Value + ")"
+	}
+	return c.Kind.Literal(c.Value)
+}
+
+// identifyCompositeDiscriminators looks for parents whose children are identified by a
+// combination of constant fields (e.g. kind + version)
+func (sa *SchemaAnalyzer) identifyCompositeDiscriminators(definitions map[string]interface{}, results *SchemaResults) error {
+	for _, parentName := range results.SortedParentNames() {
+		parent := results.Parents[parentName]
+		children := parent.LeafChildren
+		if parent.Discriminator != DiscriminatorNone || len(children) < 2 {
+			continue
+		}
+
+		// Only fields that hold a constant in every child can take part
+		shared := []string{}
+		for _, propName := range constantFieldCandidates(definitions, children) {
+			inAll := true
+			for _, childName := range children {
+				if _, _, ok := childConstantValue(definitions, childName, propName); !ok {
+					inAll = false
+					break
+				}
+			}
+			if inAll {
+				shared = append(shared, propName)
+			}
+		}
+
+		// Try the smallest combinations first, so the discriminator stays minimal
+		for size := 2; size <= len(shared); size++ {
+			fields, values, ok := firstUniqueCombination(definitions, children, shared, size)
+			if !ok {
+				continue
+			}
+			parent.Discriminator = DiscriminatorComposite
+			parent.CompositeFields = fields
+			parent.CompositeValues = values
+			results.Parents[parentName] = parent
+			break
+		}
+	}
+
+	return nil
+}
+
+// firstUniqueCombination returns the first combination of the given size whose
+// values are unique across all children. Values are compared field by field,
+// along with their kinds
+func firstUniqueCombination(definitions map[string]interface{}, children, fields []string, size int) ([]string, map[string][]Constant, bool) {
+	for _, combination := range combinations(fields, size) {
+		values := make(map[string][]Constant)
+		var seen [][]Constant
+		unique := true
+
+		for _, childName := range children {
+			childValues := []Constant{}
+			for _, propName := range combination {
+				value, kind, _ := childConstantValue(definitions, childName, propName)
+				childValues = append(childValues, Constant{Value: value, Kind: kind})
+			}
+			for _, other := range seen {
+				if reflect.DeepEqual(other, childValues) {
+					unique = false
+					break
+				}
+			}
+			if !unique {
+				break
+			}
+			seen = append(seen, childValues)
+			values[childName] = childValues
+		}
+
+		if unique {
+			return combination, values, true
+		}
+	}
+	return nil, nil, false
+}
+
+// combinations returns every combination of the given size, in lexical order
+func combinations(items []string, size int) [][]string {
+	if size == 0 {
+		return [][]string{{}}
+	}
+	if len(items) < size {
+		return nil
+	}
+
+	result := [][]string{}
+	for i := range items {
+		for _, rest := range combinations(items[i+1:], size-1) {
+			combination := append([]string{items[i]}, rest...)
+			result = append(result, combination)
+		}
+	}
+	return result
+}
+
+// identifyPresenceDiscriminators looks for parents whose children each have a required
+// field that no sibling declares, so the child can be chosen by which field exists
+func (sa *SchemaAnalyzer) identifyPresenceDiscriminators(definitions map[string]interface{}, results *SchemaResults) error {
+	for _, parentName := range results.SortedParentNames() {
+		parent := results.Parents[parentName]
+		children := parent.LeafChildren
+		if parent.Discriminator != DiscriminatorNone || len(children) < 2 {
+			continue
+		}
+
+		presence := make(map[string]string)
+		for _, childName := range children {
+			field, ok := uniqueRequiredField(definitions, childName, children)
+			if !ok {
+				break
+			}
+			presence[childName] = field
+		}
+
+		if len(presence) == len(children) {
+			parent.Discriminator = DiscriminatorPresence
+			parent.PresenceFields = presence
+			results.Parents[parentName] = parent
+		}
+	}
+
+	return nil
+}
+
+// uniqueRequiredField returns the first required field of a child, sorted by name,
+// that none of its siblings declare as a property
+func uniqueRequiredField(definitions map[string]interface{}, childName string, siblings []string) (string, bool) {
+	definition, _ := definitions[childName].(map[string]interface{})
+	required := requiredList(definition)
+	sort.Strings(required)
+
+	for _, field := range required {
+		unique := true
+		for _, sibling := range siblings {
+			if sibling == childName {
+				continue
+			}
+			if _, ok := definitionProperties(definitions, sibling)[field]; ok {
+				unique = false
+				break
+			}
+		}
+		if unique {
+			return field, true
+		}
+	}
+	return "", false
+}
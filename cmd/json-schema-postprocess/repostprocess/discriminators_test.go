@@ -0,0 +1,290 @@
+package repostprocess
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func analyzeDiscriminatorsSchema(t *testing.T) *SchemaResults {
+	t.Helper()
+
+	schemaPath := filepath.Join("testdata", "discriminators", "discriminators.schema.json")
+	analyzer, err := NewSchemaAnalyzer(schemaPath)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+
+	results, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Failed to analyze schema: %v", err)
+	}
+	return results
+}
+
+func TestDiscriminators_IntegerConstant(t *testing.T) {
+	results := analyzeDiscriminatorsSchema(t)
+
+	eventInfo := results.Parents["Event"]
+	if eventInfo.Discriminator != DiscriminatorConstant {
+		t.Fatalf("Expected Event to use a constant discriminator, got '%s'", eventInfo.Discriminator)
+	}
+	if eventInfo.ConstantField != "version" {
+		t.Errorf("Expected Event.ConstantField to be 'version', got '%s'", eventInfo.ConstantField)
+	}
+	if eventInfo.ConstantKind != ConstantKindInteger {
+		t.Errorf("Expected Event.ConstantKind to be integer, got '%s'", eventInfo.ConstantKind)
+	}
+
+	expectedValues := map[string]string{"EventV1": "1", "EventV2": "2"}
+	if !reflect.DeepEqual(eventInfo.ConstantValues, expectedValues) {
+		t.Errorf("Expected Event.ConstantValues to be %v, got %v", expectedValues, eventInfo.ConstantValues)
+	}
+}
+
+func TestConstantKind_Literal(t *testing.T) {
+	tests := []struct {
+		kind     ConstantKind
+		value    string
+		expected string
+	}{
+		{ConstantKindString, "rgb", `"rgb"`},
+		{ConstantKindString, `say "hi"`, `"say \"hi\""`},
+		{ConstantKindString, `C:\colors`, `"C:\\colors"`},
+		{ConstantKindInteger, "2", "2"},
+		{ConstantKindBoolean, "false", "false"},
+	}
+	for _, tt := range tests {
+		if got := tt.kind.Literal(tt.value); got != tt.expected {
+			t.Errorf("Literal(%q) for %s: expected %s, got %s", tt.value, tt.kind, tt.expected, got)
+		}
+	}
+}
+
+func TestDiscriminators_BooleanConstant(t *testing.T) {
+	results := analyzeDiscriminatorsSchema(t)
+
+	flagInfo := results.Parents["Flag"]
+	if flagInfo.Discriminator != DiscriminatorConstant {
+		t.Fatalf("Expected Flag to use a constant discriminator, got '%s'", flagInfo.Discriminator)
+	}
+	if flagInfo.ConstantKind != ConstantKindBoolean {
+		t.Errorf("Expected Flag.ConstantKind to be boolean, got '%s'", flagInfo.ConstantKind)
+	}
+
+	expectedValues := map[string]string{"EnabledFlag": "true", "DisabledFlag": "false"}
+	if !reflect.DeepEqual(flagInfo.ConstantValues, expectedValues) {
+		t.Errorf("Expected Flag.ConstantValues to be %v, got %v", expectedValues, flagInfo.ConstantValues)
+	}
+}
+
+func TestDiscriminators_Composite(t *testing.T) {
+	results := analyzeDiscriminatorsSchema(t)
+
+	messageInfo := results.Parents["Message"]
+	if messageInfo.Discriminator != DiscriminatorComposite {
+		t.Fatalf("Expected Message to use a composite discriminator, got '%s'", messageInfo.Discriminator)
+	}
+	if messageInfo.ConstantField != "" {
+		t.Errorf("Expected Message to have no single constant field, got '%s'", messageInfo.ConstantField)
+	}
+
+	expectedFields := []string{"kind", "version"}
+	if !reflect.DeepEqual(messageInfo.CompositeFields, expectedFields) {
+		t.Errorf("Expected Message.CompositeFields to be %v, got %v", expectedFields, messageInfo.CompositeFields)
+	}
+
+	expectedValues := map[string][]Constant{
+		"TextV1":  {{Value: "text", Kind: ConstantKindString}, {Value: "1", Kind: ConstantKindInteger}},
+		"TextV2":  {{Value: "text", Kind: ConstantKindString}, {Value: "2", Kind: ConstantKindInteger}},
+		"ImageV1": {{Value: "image", Kind: ConstantKindString}, {Value: "1", Kind: ConstantKindInteger}},
+	}
+	if !reflect.DeepEqual(messageInfo.CompositeValues, expectedValues) {
+		t.Errorf("Expected Message.CompositeValues to be %v, got %v", expectedValues, messageInfo.CompositeValues)
+	}
+}
+
+func TestDiscriminators_Presence(t *testing.T) {
+	results := analyzeDiscriminatorsSchema(t)
+
+	sourceInfo := results.Parents["Source"]
+	if sourceInfo.Discriminator != DiscriminatorPresence {
+		t.Fatalf("Expected Source to use a presence discriminator, got '%s'", sourceInfo.Discriminator)
+	}
+
+	expectedFields := map[string]string{"FileSource": "path", "URLSource": "url"}
+	if !reflect.DeepEqual(sourceInfo.PresenceFields, expectedFields) {
+		t.Errorf("Expected Source.PresenceFields to be %v, got %v", expectedFields, sourceInfo.PresenceFields)
+	}
+}
+
+func TestDiscriminators_NoneForPalette(t *testing.T) {
+	schemaPath := filepath.Join("testdata", "color", "color.schema.json")
+	analyzer, err := NewSchemaAnalyzer(schemaPath)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+
+	results, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Failed to analyze schema: %v", err)
+	}
+
+	// Palette children share a "type" constant, which is still the simplest strategy
+	if strategy := results.Parents["Palette"].Discriminator; strategy != DiscriminatorConstant {
+		t.Errorf("Expected Palette to use a constant discriminator, got '%s'", strategy)
+	}
+	if strategy := results.Parents["Color"].Discriminator; strategy != DiscriminatorConstant {
+		t.Errorf("Expected Color to use a constant discriminator, got '%s'", strategy)
+	}
+}
+
+func TestDiscriminators_Combinations(t *testing.T) {
+	got := combinations([]string{"a", "b", "c"}, 2)
+	expected := [][]string{{"a", "b"}, {"a", "c"}, {"b", "c"}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected combinations %v, got %v", expected, got)
+	}
+}
+
+func TestDiscriminators_GeneratedDispatch(t *testing.T) {
+	results := analyzeDiscriminatorsSchema(t)
+
+	outputDir := t.TempDir()
+	modelPath := filepath.Join("testdata", "discriminators", "model.gen.go")
+	generator := NewCodeGenerator(modelPath, outputDir, results)
+	if err := generator.Generate(); err != nil {
+		t.Fatalf("Failed to generate code: %v", err)
+	}
+
+	modelStr := readModel(t, outputDir)
+
+	// Integer and boolean constants get typed model types
+	checkForContent(t, modelStr, "type EventModel int")
+	checkForContent(t, modelStr, "EventModelX1 EventModel = 1")
+	checkForContent(t, modelStr, "type FlagModel bool")
+	checkForContent(t, modelStr, "FlagModelTrue  FlagModel = true")
+
+	// Constant dispatch
+	checkForContent(t, modelStr, "switch constant {")
+	checkForContent(t, modelStr, "case EventModelX2:")
+
+	// Composite dispatch
+	checkForContent(t, modelStr, "case plain.Kind == \"text\" && plain.Version == float64(2):")
+	checkForContent(t, modelStr, "fmt.Errorf(\"invalid Message discriminator: kind %#v, version %#v\", plain.Kind, plain.Version)")
+
+	// Presence dispatch
+	checkForContent(t, modelStr, "if _, ok := peekJSONField(data, \"path\"); ok {")
+	checkForContent(t, modelStr, "if _, ok := peekJSONField(data, \"url\"); ok {")
+}
+
+// compositeTest decodes a stream of tokens whose composite values would run
+// together if joined, or match if compared as text
+const compositeTest = `package composite
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestDispatch(t *testing.T) {
+	var stream Stream
+	input := ` + "`" + `{"tokens": [
+		{"a": "x|y", "b": "z"},
+		{"a": "x", "b": "y|z"},
+		{"a": "x", "b": "z"},
+		{"a": "x|y", "b": 1},
+		{"a": "x|y", "b": "1"}
+	]}` + "`" + `
+	if err := json.Unmarshal([]byte(input), &stream); err != nil {
+		t.Fatalf("Failed to decode tokens: %v", err)
+	}
+	var got []string
+	for _, token := range stream.Tokens {
+		got = append(got, fmt.Sprintf("%T", token))
+	}
+	if fmt.Sprint(got) != "[*composite.Joined *composite.Split *composite.Bare *composite.Count *composite.Label]" {
+		t.Errorf("Unexpected children %v", got)
+	}
+
+	if err := json.Unmarshal([]byte(` + "`" + `{"tokens": [{"a": "x", "b": 1}]}` + "`" + `), &stream); err == nil {
+		t.Errorf("Expected values matching no child to fail")
+	}
+}
+`
+
+func TestDiscriminators_CompositeValuesAreTyped(t *testing.T) {
+	schemaPath := filepath.Join("testdata", "composite", "composite.schema.json")
+	analyzer, err := NewSchemaAnalyzer(schemaPath)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+	results, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Failed to analyze schema: %v", err)
+	}
+
+	// Joined and Split, and Count and Label, only differ field by field and by kind
+	token := results.Parents["Token"]
+	if token.Discriminator != DiscriminatorComposite {
+		t.Fatalf("Expected Token to use a composite discriminator, got '%s'", token.Discriminator)
+	}
+
+	dir := generatePackage(t, schemaPath, "composite")
+	content, err := os.ReadFile(filepath.Join(dir, "model.go"))
+	if err != nil {
+		t.Fatalf("Failed to read model: %v", err)
+	}
+	modelStr := string(content)
+	checkForContent(t, modelStr, "case plain.A == \"x|y\" && plain.B == \"z\":")
+	checkForContent(t, modelStr, "case plain.A == \"x|y\" && plain.B == float64(1):")
+	checkForContent(t, modelStr, "case plain.A == \"x|y\" && plain.B == \"1\":")
+
+	runGoTest(t, dir, compositeTest)
+}
+
+// childConstantTest decodes children directly, without the parent's dispatch
+const childConstantTest = `package color
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestChildConstant(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{` + "`" + `{"model": "hsl", "h": 120, "s": 0.5, "l": 0.5}` + "`" + `, ""},
+		{` + "`" + `{"h": 120, "s": 0.5, "l": 0.5}` + "`" + `, ""},
+		{` + "`" + `{"model": "rgb", "h": 120, "s": 0.5, "l": 0.5}` + "`" + `, "field model in HSLValue: must be \"hsl\""},
+		{` + "`" + `{"model": null, "h": 120, "s": 0.5, "l": 0.5}` + "`" + `, "field model in HSLValue: must be \"hsl\""},
+		{` + "`" + `{"model": 1, "h": 120, "s": 0.5, "l": 0.5}` + "`" + `, "field model in HSLValue: must be \"hsl\""},
+	}
+
+	for _, tt := range tests {
+		var value HSLValue
+		err := json.Unmarshal([]byte(tt.input), &value)
+		if tt.expected == "" {
+			if err != nil {
+				t.Errorf("Expected %s to decode, got %v", tt.input, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("Expected %s to fail with %q, got %v", tt.input, tt.expected, err)
+		}
+	}
+}
+`
+
+func TestDiscriminators_ChildChecksConstant(t *testing.T) {
+	// The constant field makes way for its accessor, so the child's own
+	// UnmarshalJSON checks the value in the JSON
+	dir := generatePackage(t, filepath.Join("testdata", "color", "color.schema.json"), "color")
+	runGoTest(t, dir, childConstantTest)
+}
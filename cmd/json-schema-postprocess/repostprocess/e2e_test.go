@@ -40,12 +40,52 @@ func TestE2E_SimpleSchema(t *testing.T) {
 	}
 
 	// Define expected file contents based on the postprocess.mdc steps
+	// The generated code is merged into the model, so there is a single output file
 	expectedFiles := map[string]string{
-		"model_interfaces.gen.go": `// Code generated by json-schema-postprocess. DO NOT EDIT.
+		"model.gen.go": `// Code generated by json-schema-postprocess. DO NOT EDIT.
 
 package simple
 
-// This file contains interface definitions and implementation methods for parent types
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Circle struct {
+	// Radius corresponds to the JSON schema field "radius".
+	Radius float64 ` + "`" + `json:"radius" yaml:"radius" mapstructure:"radius"` + "`" + `
+}
+
+type SimpleSchemaJson struct {
+	// Config corresponds to the JSON schema field "config".
+	Config *SimpleSchemaJsonConfig ` + "`" + `json:"config,omitempty" yaml:"config,omitempty" mapstructure:"config,omitempty"` + "`" + `
+
+	// Shapes corresponds to the JSON schema field "shapes".
+	Shapes ShapeSlice ` + "`" + `json:"shapes,omitempty" yaml:"shapes,omitempty" mapstructure:"shapes,omitempty"` + "`" + `
+}
+
+type SimpleSchemaJsonConfig struct {
+	// Shape corresponds to the JSON schema field "shape".
+	Shape Shape ` + "`" + `json:"shape" yaml:"shape" mapstructure:"shape"` + "`" + `
+}
+
+type Square struct {
+	// Side corresponds to the JSON schema field "side".
+	Side float64 ` + "`" + `json:"side" yaml:"side" mapstructure:"side"` + "`" + `
+}
+
+type Triangle struct {
+	// Base corresponds to the JSON schema field "base".
+	Base float64 ` + "`" + `json:"base" yaml:"base" mapstructure:"base"` + "`" + `
+
+	// Height corresponds to the JSON schema field "height".
+	Height float64 ` + "`" + `json:"height" yaml:"height" mapstructure:"height"` + "`" + `
+}
 
 // Shape represents the parent type for Shape types
 type Shape interface {
@@ -85,59 +125,136 @@ const (
 	ShapeModelCircle   ShapeModel = "circle"
 	ShapeModelSquare   ShapeModel = "square"
 	ShapeModelTriangle ShapeModel = "triangle"
-)`,
-
-		"model_unmarshal.gen.go": `// Code generated by json-schema-postprocess. DO NOT EDIT.
+)
 
-package simple
+// ShapeVisitor has a method for each concrete Shape type, so an
+// implementation handles all of them
+type ShapeVisitor interface {
+	VisitCircle(*Circle)
+	VisitSquare(*Square)
+	VisitTriangle(*Triangle)
+}
 
-import (
-	"encoding/json"
-	"fmt"
-)
+// AcceptShape calls the method of visitor for the concrete type of value
+func AcceptShape(value Shape, visitor ShapeVisitor) error {
+	switch value := value.(type) {
+	case *Circle:
+		visitor.VisitCircle(value)
+	case *Square:
+		visitor.VisitSquare(value)
+	case *Triangle:
+		visitor.VisitTriangle(value)
+	default:
+		return fmt.Errorf("unknown Shape type %T", value)
+	}
+	return nil
+}
 
-// This file contains unmarshaling and marshaling functions for parent types
+// MatchShape calls the function for the concrete type of value and returns
+// its result. Adding a child to Shape adds a parameter, so every call
+// has to handle it
+func MatchShape[T any](value Shape, onCircle func(*Circle) T, onSquare func(*Square) T, onTriangle func(*Triangle) T) (T, error) {
+	switch value := value.(type) {
+	case *Circle:
+		return onCircle(value), nil
+	case *Square:
+		return onSquare(value), nil
+	case *Triangle:
+		return onTriangle(value), nil
+	}
+	var zero T
+	return zero, fmt.Errorf("unknown Shape type %T", value)
+}
 
-// parseUnknownShape parses an unknown Shape type based on its JSON representation
+// parseUnknownShape parses an unknown Shape type from an already decoded
+// JSON value, such as one read from YAML
 func parseUnknownShape(b interface{}) (Shape, error) {
 	str, err := json.Marshal(b)
 	if err != nil {
 		return nil, err
 	}
+	return parseShapeRaw(str)
+}
 
+// parseShapeRaw parses an unknown Shape type from its JSON, peeking at the
+// fields that tell the children apart without decoding the rest
+func parseShapeRaw(data json.RawMessage) (Shape, error) {
 	// Use the type field to determine the type
-	type Plain struct {
-		Type ShapeModel ` + "`json:\"type\" yaml:\"type\" mapstructure:\"type\"`" + `
-	}
-	var plain Plain
-	if err := json.Unmarshal(str, &plain); err != nil {
-		return nil, err
+	var constant ShapeModel
+	if value, ok := peekJSONField(data, "type"); ok {
+		if err := json.Unmarshal(value, &constant); err != nil {
+			return nil, err
+		}
 	}
 
-	switch plain.Type {
+	switch constant {
 	case ShapeModelCircle:
 		var circle Circle
-		err = json.Unmarshal(str, &circle)
+		err := json.Unmarshal(data, &circle)
 		return &circle, err
 	case ShapeModelSquare:
 		var square Square
-		err = json.Unmarshal(str, &square)
+		err := json.Unmarshal(data, &square)
 		return &square, err
 	case ShapeModelTriangle:
 		var triangle Triangle
-		err = json.Unmarshal(str, &triangle)
+		err := json.Unmarshal(data, &triangle)
 		return &triangle, err
 	default:
-		return nil, fmt.Errorf("invalid type: %s", plain.Type)
+		return nil, fmt.Errorf("invalid type: %s", constant)
 	}
 }
 
+// DecodeShapeStream reads a JSON array of Shape from dec and calls fn with
+// each element as soon as it's parsed, so the array is never held in memory
+func DecodeShapeStream(dec *json.Decoder, fn func(Shape) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected an array of Shape, got %v", tok)
+	}
+
+	for dec.More() {
+		var item json.RawMessage
+		if err := dec.Decode(&item); err != nil {
+			return err
+		}
+		parsed, err := parseShapeRaw(item)
+		if err != nil {
+			return err
+		}
+		if err := fn(parsed); err != nil {
+			return err
+		}
+	}
+
+	// Consume the closing bracket
+	_, err = dec.Token()
+	return err
+}
+
+// DecodeShapeSlice reads a JSON array of Shape from dec one element at a time
+func DecodeShapeSlice(dec *json.Decoder) (ShapeSlice, error) {
+	var items ShapeSlice
+	err := DecodeShapeStream(dec, func(item Shape) error {
+		items = append(items, item)
+		return nil
+	})
+	return items, err
+}
+
 // MarshalJSON implements json.Marshaler for Circle
 func (j Circle) MarshalJSON() ([]byte, error) {
+
 	// Add the constant field to the output
 	type Plain Circle
 	myMarshal := struct {
-		Type ShapeModel ` + "`json:\"type\" yaml:\"type\" mapstructure:\"type\"`" + `
+		Type ShapeModel ` + "`" + `json:"type" yaml:"type" mapstructure:"type"` + "`" + `
 		Plain
 	}{
 		Type:  j.Type(),
@@ -148,10 +265,11 @@ func (j Circle) MarshalJSON() ([]byte, error) {
 
 // MarshalJSON implements json.Marshaler for Square
 func (j Square) MarshalJSON() ([]byte, error) {
+
 	// Add the constant field to the output
 	type Plain Square
 	myMarshal := struct {
-		Type ShapeModel ` + "`json:\"type\" yaml:\"type\" mapstructure:\"type\"`" + `
+		Type ShapeModel ` + "`" + `json:"type" yaml:"type" mapstructure:"type"` + "`" + `
 		Plain
 	}{
 		Type:  j.Type(),
@@ -162,10 +280,11 @@ func (j Square) MarshalJSON() ([]byte, error) {
 
 // MarshalJSON implements json.Marshaler for Triangle
 func (j Triangle) MarshalJSON() ([]byte, error) {
+
 	// Add the constant field to the output
 	type Plain Triangle
 	myMarshal := struct {
-		Type ShapeModel ` + "`json:\"type\" yaml:\"type\" mapstructure:\"type\"`" + `
+		Type ShapeModel ` + "`" + `json:"type" yaml:"type" mapstructure:"type"` + "`" + `
 		Plain
 	}{
 		Type:  j.Type(),
@@ -174,9 +293,214 @@ func (j Triangle) MarshalJSON() ([]byte, error) {
 	return json.Marshal(myMarshal)
 }
 
+// peekJSONField returns the raw value of the first top-level field named key in
+// a JSON object, scanning past the fields before it without decoding them
+func peekJSONField(data []byte, key string) (json.RawMessage, bool) {
+	var found json.RawMessage
+	scanJSONObject(data, func(k string, value []byte) bool {
+		if k != key {
+			return true
+		}
+		found = value
+		return false
+	})
+	return found, found != nil
+}
+
+// scanJSONObject calls fn with each top-level key and raw value of a JSON
+// object until fn returns false. It stops quietly at malformed JSON, which the
+// decoding that follows reports
+func scanJSONObject(data []byte, fn func(key string, value []byte) bool) {
+	i := skipJSONSpace(data, 0)
+	if i >= len(data) || data[i] != '{' {
+		return
+	}
+	i++
+
+	for {
+		i = skipJSONSpace(data, i)
+		if i >= len(data) || data[i] != '"' {
+			return
+		}
+		end := skipJSONValue(data, i)
+		if end < 0 {
+			return
+		}
+		key := string(data[i+1 : end-1])
+		if strings.IndexByte(key, '\\') >= 0 {
+			if err := json.Unmarshal(data[i:end], &key); err != nil {
+				return
+			}
+		}
+
+		i = skipJSONSpace(data, end)
+		if i >= len(data) || data[i] != ':' {
+			return
+		}
+		i = skipJSONSpace(data, i+1)
+		end = skipJSONValue(data, i)
+		if end < 0 || !fn(key, data[i:end]) {
+			return
+		}
+
+		i = skipJSONSpace(data, end)
+		if i >= len(data) || data[i] != ',' {
+			return
+		}
+		i++
+	}
+}
+
+// skipJSONSpace returns the index of the first non-space byte at or after i
+func skipJSONSpace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+// skipJSONValue returns the index just past the JSON value starting at i, or
+// -1 if it's malformed
+func skipJSONValue(data []byte, i int) int {
+	if i >= len(data) {
+		return -1
+	}
+
+	switch data[i] {
+	case '"':
+		for j := i + 1; j < len(data); j++ {
+			switch data[j] {
+			case '\\':
+				j++
+			case '"':
+				return j + 1
+			}
+		}
+		return -1
+	case '{', '[':
+		depth := 0
+		for j := i; j < len(data); j++ {
+			switch data[j] {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return j + 1
+				}
+			case '"':
+				end := skipJSONValue(data, j)
+				if end < 0 {
+					return -1
+				}
+				j = end - 1
+			}
+		}
+		return -1
+	}
+
+	// Numbers, true, false and null run until the next delimiter
+	j := i
+	for j < len(data) && !strings.ContainsRune(",:]} \t\n\r", rune(data[j])) {
+		j++
+	}
+	if j == i {
+		return -1
+	}
+	return j
+}
+
+// isJSONNull reports whether a raw field is missing or null
+func isJSONNull(value json.RawMessage) bool {
+	return len(value) == 0 || string(bytes.TrimSpace(value)) == "null"
+}
+
+// UnmarshalJSON implements json.Unmarshaler for Circle
+func (j *Circle) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["type"]; raw != nil && !ok {
+		return fmt.Errorf("field type in Circle: required")
+	}
+	if _, ok := raw["radius"]; raw != nil && !ok {
+		return fmt.Errorf("field radius in Circle: required")
+	}
+	// The constant has no field to decode into, so it's checked in the JSON
+	if value, ok := raw["type"]; ok {
+		var constant interface{}
+		if err := json.Unmarshal(value, &constant); err != nil || constant != "circle" {
+			return fmt.Errorf("field type in Circle: must be %#v", "circle")
+		}
+	}
+
+	type Plain Circle
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+
+	*j = Circle(plain)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler for SimpleSchemaJson
+func (j *SimpleSchemaJson) UnmarshalJSON(b []byte) error {
+	// The fields stay raw JSON, so the parents are decoded straight from it
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	// The parent fields are shadowed here and parsed from raw below
+	type Plain SimpleSchemaJson
+	var decoded struct {
+		Plain
+		Shapes json.RawMessage ` + "`" + `json:"shapes"` + "`" + `
+	}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return err
+	}
+	plain := decoded.Plain
+
+	// Parse array of Shape types
+	if !isJSONNull(raw["shapes"]) {
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw["shapes"], &items); err != nil {
+			return fmt.Errorf("field shapes in SimpleSchemaJson: %w", err)
+		}
+		shapes := make(ShapeSlice, 0, len(items))
+		for _, item := range items {
+			parsed, err := parseShapeRaw(item)
+			if err != nil {
+				return err
+			}
+			shapes = append(shapes, parsed)
+		}
+		plain.Shapes = shapes
+	}
+
+	*j = SimpleSchemaJson(plain)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler for SimpleSchemaJson
+func (j SimpleSchemaJson) MarshalJSON() ([]byte, error) {
+	for i, item := range j.Shapes {
+		if item == nil {
+			return nil, fmt.Errorf("field shapes in SimpleSchemaJson: item %d is nil", i)
+		}
+	}
+
+	type Plain SimpleSchemaJson
+	return json.Marshal(Plain(j))
+}
+
 // UnmarshalJSON implements json.Unmarshaler for SimpleSchemaJsonConfig
 func (j *SimpleSchemaJsonConfig) UnmarshalJSON(b []byte) error {
-	var raw map[string]interface{}
+	// The fields stay raw JSON, so the parents are decoded straight from it
+	var raw map[string]json.RawMessage
 	if err := json.Unmarshal(b, &raw); err != nil {
 		return err
 	}
@@ -184,108 +508,734 @@ func (j *SimpleSchemaJsonConfig) UnmarshalJSON(b []byte) error {
 		return fmt.Errorf("field shape in SimpleSchemaJsonConfig: required")
 	}
 
+	// The parent fields are shadowed here and parsed from raw below
 	type Plain SimpleSchemaJsonConfig
-	var plain Plain
-	if err := json.Unmarshal(b, &plain); err != nil {
+	var decoded struct {
+		Plain
+		Shape json.RawMessage ` + "`" + `json:"shape"` + "`" + `
+	}
+	if err := json.Unmarshal(b, &decoded); err != nil {
 		return err
 	}
+	plain := decoded.Plain
 
 	// Parse Shape type
-	if raw["shape"] != nil {
-		parsed, err := parseUnknownShape(raw["shape"])
+	if !isJSONNull(raw["shape"]) {
+		parsed, err := parseShapeRaw(raw["shape"])
 		if err != nil {
 			return err
 		}
 		plain.Shape = parsed
+	} else if raw != nil {
+		return fmt.Errorf("field shape in SimpleSchemaJsonConfig: must not be null")
 	}
 
 	*j = SimpleSchemaJsonConfig(plain)
 	return nil
 }
 
-// UnmarshalJSON implements json.Unmarshaler for SimpleSchemaJson
-func (j *SimpleSchemaJson) UnmarshalJSON(b []byte) error {
-	var raw map[string]interface{}
+// MarshalJSON implements json.Marshaler for SimpleSchemaJsonConfig
+func (j SimpleSchemaJsonConfig) MarshalJSON() ([]byte, error) {
+	if j.Shape == nil {
+		return nil, fmt.Errorf("field shape in SimpleSchemaJsonConfig: required")
+	}
+
+	type Plain SimpleSchemaJsonConfig
+	return json.Marshal(Plain(j))
+}
+
+// UnmarshalJSON implements json.Unmarshaler for Square
+func (j *Square) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
 	if err := json.Unmarshal(b, &raw); err != nil {
 		return err
 	}
+	if _, ok := raw["type"]; raw != nil && !ok {
+		return fmt.Errorf("field type in Square: required")
+	}
+	if _, ok := raw["side"]; raw != nil && !ok {
+		return fmt.Errorf("field side in Square: required")
+	}
+	// The constant has no field to decode into, so it's checked in the JSON
+	if value, ok := raw["type"]; ok {
+		var constant interface{}
+		if err := json.Unmarshal(value, &constant); err != nil || constant != "square" {
+			return fmt.Errorf("field type in Square: must be %#v", "square")
+		}
+	}
 
-	type Plain SimpleSchemaJson
+	type Plain Square
 	var plain Plain
 	if err := json.Unmarshal(b, &plain); err != nil {
 		return err
 	}
 
-	// Parse array of Shape types
-	if raw["shapes"] != nil {
-		arr, ok := raw["shapes"].([]interface{})
-		if ok {
-			shapes := make(ShapeSlice, 0, len(arr))
-			for _, item := range arr {
-				parsed, err := parseUnknownShape(item)
-				if err != nil {
-					return err
-				}
-				shapes = append(shapes, parsed)
-			}
-			plain.Shapes = shapes
+	*j = Square(plain)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler for Triangle
+func (j *Triangle) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["type"]; raw != nil && !ok {
+		return fmt.Errorf("field type in Triangle: required")
+	}
+	if _, ok := raw["base"]; raw != nil && !ok {
+		return fmt.Errorf("field base in Triangle: required")
+	}
+	if _, ok := raw["height"]; raw != nil && !ok {
+		return fmt.Errorf("field height in Triangle: required")
+	}
+	// The constant has no field to decode into, so it's checked in the JSON
+	if value, ok := raw["type"]; ok {
+		var constant interface{}
+		if err := json.Unmarshal(value, &constant); err != nil || constant != "triangle" {
+			return fmt.Errorf("field type in Triangle: must be %#v", "triangle")
 		}
 	}
 
-	*j = SimpleSchemaJson(plain)
+	type Plain Triangle
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+
+	*j = Triangle(plain)
 	return nil
-}`,
+}
 
-		"model_enhanced.gen.go": `// Code generated by json-schema-postprocess. DO NOT EDIT.
+// UnmarshalYAML implements yaml.Unmarshaler for ShapeSlice
+func (s *ShapeSlice) UnmarshalYAML(node *yaml.Node) error {
+	value, err := yamlValue(node)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		*s = nil
+		return nil
+	}
 
-package simple
+	items, ok := value.([]interface{})
+	if !ok {
+		return fmt.Errorf("line %d: expected a sequence of Shape", node.Line)
+	}
+	parsed := make(ShapeSlice, 0, len(items))
+	for _, item := range items {
+		p, err := parseUnknownShape(item)
+		if err != nil {
+			return err
+		}
+		parsed = append(parsed, p)
+	}
+	*s = parsed
+	return nil
+}
 
-// This file contains enhanced versions of the original model types
+// UnmarshalYAML implements yaml.Unmarshaler for ShapeMap
+func (m *ShapeMap) UnmarshalYAML(node *yaml.Node) error {
+	value, err := yamlValue(node)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		*m = nil
+		return nil
+	}
 
-// Circle is a shape with a radius
-type Circle struct {
-	// Radius corresponds to the JSON schema field "radius".
-	Radius float64 ` + "`json:\"radius\" yaml:\"radius\" mapstructure:\"radius\"`" + `
+	items, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("line %d: expected a mapping of Shape", node.Line)
+	}
+	parsed := make(ShapeMap, len(items))
+	for key, item := range items {
+		p, err := parseUnknownShape(item)
+		if err != nil {
+			return err
+		}
+		parsed[key] = p
+	}
+	*m = parsed
+	return nil
+}
 
-	// Type corresponds to the JSON schema field "type".
-	Type string ` + "`json:\"type\" yaml:\"type\" mapstructure:\"type\"`" + `
+// UnmarshalYAML implements yaml.Unmarshaler for Circle using its JSON decoding
+func (j *Circle) UnmarshalYAML(node *yaml.Node) error {
+	b, err := yamlToJSON(node)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, j)
 }
 
-// Square is a shape with a side length
-type Square struct {
-	// Side corresponds to the JSON schema field "side".
-	Side float64 ` + "`json:\"side\" yaml:\"side\" mapstructure:\"side\"`" + `
+// MarshalYAML implements yaml.Marshaler for Circle using its JSON encoding
+func (j Circle) MarshalYAML() (interface{}, error) {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(b)
+}
 
-	// Type corresponds to the JSON schema field "type".
-	Type string ` + "`json:\"type\" yaml:\"type\" mapstructure:\"type\"`" + `
+// UnmarshalYAML implements yaml.Unmarshaler for SimpleSchemaJson using its JSON decoding
+func (j *SimpleSchemaJson) UnmarshalYAML(node *yaml.Node) error {
+	b, err := yamlToJSON(node)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, j)
 }
 
-// Triangle is a shape with a base and height
-type Triangle struct {
-	// Base corresponds to the JSON schema field "base".
-	Base float64 ` + "`json:\"base\" yaml:\"base\" mapstructure:\"base\"`" + `
+// MarshalYAML implements yaml.Marshaler for SimpleSchemaJson using its JSON encoding
+func (j SimpleSchemaJson) MarshalYAML() (interface{}, error) {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(b)
+}
 
-	// Height corresponds to the JSON schema field "height".
-	Height float64 ` + "`json:\"height\" yaml:\"height\" mapstructure:\"height\"`" + `
+// UnmarshalYAML implements yaml.Unmarshaler for SimpleSchemaJsonConfig using its JSON decoding
+func (j *SimpleSchemaJsonConfig) UnmarshalYAML(node *yaml.Node) error {
+	b, err := yamlToJSON(node)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, j)
+}
 
-	// Type corresponds to the JSON schema field "type".
-	Type string ` + "`json:\"type\" yaml:\"type\" mapstructure:\"type\"`" + `
+// MarshalYAML implements yaml.Marshaler for SimpleSchemaJsonConfig using its JSON encoding
+func (j SimpleSchemaJsonConfig) MarshalYAML() (interface{}, error) {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(b)
 }
 
-// SimpleSchemaJson represents the top-level schema
-type SimpleSchemaJson struct {
-	// Config corresponds to the JSON schema field "config".
-	Config *SimpleSchemaJsonConfig ` + "`json:\"config,omitempty\" yaml:\"config,omitempty\" mapstructure:\"config,omitempty\"`" + `
+// UnmarshalYAML implements yaml.Unmarshaler for Square using its JSON decoding
+func (j *Square) UnmarshalYAML(node *yaml.Node) error {
+	b, err := yamlToJSON(node)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, j)
+}
 
-	// Shapes corresponds to the JSON schema field "shapes".
-	Shapes ShapeSlice ` + "`json:\"shapes,omitempty\" yaml:\"shapes,omitempty\" mapstructure:\"shapes,omitempty\"`" + `
+// MarshalYAML implements yaml.Marshaler for Square using its JSON encoding
+func (j Square) MarshalYAML() (interface{}, error) {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(b)
 }
 
-// SimpleSchemaJsonConfig holds the configuration
-type SimpleSchemaJsonConfig struct {
-	// Shape corresponds to the JSON schema field "shape".
-	Shape Shape ` + "`json:\"shape\" yaml:\"shape\" mapstructure:\"shape\"`" + `
-}`,
+// UnmarshalYAML implements yaml.Unmarshaler for Triangle using its JSON decoding
+func (j *Triangle) UnmarshalYAML(node *yaml.Node) error {
+	b, err := yamlToJSON(node)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, j)
+}
+
+// MarshalYAML implements yaml.Marshaler for Triangle using its JSON encoding
+func (j Triangle) MarshalYAML() (interface{}, error) {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(b)
+}
+
+// yamlValue decodes a YAML node into the values encoding/json decodes to, so
+// the JSON parse functions can dispatch on it
+func yamlValue(node *yaml.Node) (interface{}, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return yamlValue(node.Content[0])
+	case yaml.AliasNode:
+		return yamlValue(node.Alias)
+	case yaml.SequenceNode:
+		items := make([]interface{}, 0, len(node.Content))
+		for _, item := range node.Content {
+			value, err := yamlValue(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+		}
+		return items, nil
+	case yaml.MappingNode:
+		object := make(map[string]interface{}, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value, err := yamlValue(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			object[node.Content[i].Value] = value
+		}
+		return object, nil
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!null":
+			return nil, nil
+		case "!!bool", "!!int", "!!float":
+			var value interface{}
+			if err := node.Decode(&value); err != nil {
+				return nil, err
+			}
+			return value, nil
+		}
+		// Timestamps and other tagged scalars stay strings, as they are in JSON
+		return node.Value, nil
+	}
+	return nil, fmt.Errorf("line %d: unsupported YAML node", node.Line)
+}
+
+// yamlToJSON converts a YAML node to JSON for the JSON unmarshalers
+func yamlToJSON(node *yaml.Node) ([]byte, error) {
+	value, err := yamlValue(node)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// jsonToYAML converts marshaled JSON to a block style YAML node, keeping the key order
+func jsonToYAML(b []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	node := doc.Content[0]
+	clearYAMLStyle(node)
+	return node, nil
+}
+
+// clearYAMLStyle drops the flow and quoted styles JSON is parsed with
+func clearYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearYAMLStyle(child)
+	}
+}
+
+// NewCircle returns a Circle with its required fields set, checked
+// against the schema's validation keywords
+func NewCircle(radius float64) (*Circle, error) {
+	plain := Circle{
+		Radius: radius,
+	}
+	return &plain, nil
+}
+
+// NewSquare returns a Square with its required fields set, checked
+// against the schema's validation keywords
+func NewSquare(side float64) (*Square, error) {
+	plain := Square{
+		Side: side,
+	}
+	return &plain, nil
+}
+
+// NewTriangle returns a Triangle with its required fields set, checked
+// against the schema's validation keywords
+func NewTriangle(base float64, height float64) (*Triangle, error) {
+	plain := Triangle{
+		Base:   base,
+		Height: height,
+	}
+	return &plain, nil
+}
+
+// CloneShape returns a deep copy of value
+func CloneShape(value Shape) Shape {
+	switch value := value.(type) {
+	case *Circle:
+		return value.Clone()
+	case *Square:
+		return value.Clone()
+	case *Triangle:
+		return value.Clone()
+	}
+	return value
+}
+
+// EqualShape reports whether a and b hold equal values of the same type
+func EqualShape(a, b Shape) bool {
+	switch a := a.(type) {
+	case *Circle:
+		b, ok := b.(*Circle)
+		return ok && a.Equal(b)
+	case *Square:
+		b, ok := b.(*Square)
+		return ok && a.Equal(b)
+	case *Triangle:
+		b, ok := b.(*Triangle)
+		return ok && a.Equal(b)
+	}
+	return a == nil && b == nil
+}
+
+// Clone returns a deep copy of s
+func (s ShapeSlice) Clone() ShapeSlice {
+	if s == nil {
+		return nil
+	}
+	clone := make(ShapeSlice, len(s))
+	for i, item := range s {
+		clone[i] = CloneShape(item)
+	}
+	return clone
+}
+
+// Equal reports whether s and other hold equal values in the same order,
+// treating nil as empty
+func (s ShapeSlice) Equal(other ShapeSlice) bool {
+	return equalSlices(s, other, EqualShape)
+}
+
+// Clone returns a deep copy of m
+func (m ShapeMap) Clone() ShapeMap {
+	if m == nil {
+		return nil
+	}
+	clone := make(ShapeMap, len(m))
+	for key, item := range m {
+		clone[key] = CloneShape(item)
+	}
+	return clone
+}
+
+// Equal reports whether m and other hold equal values under the same keys,
+// treating nil as empty
+func (m ShapeMap) Equal(other ShapeMap) bool {
+	return equalMaps(m, other, EqualShape)
+}
+
+// Clone returns a deep copy of j
+func (j *Circle) Clone() *Circle {
+	if j == nil {
+		return nil
+	}
+	clone := *j
+	return &clone
+}
+
+// Clone returns a deep copy of j
+func (j *SimpleSchemaJson) Clone() *SimpleSchemaJson {
+	if j == nil {
+		return nil
+	}
+	clone := *j
+	if j.Config != nil {
+		clone.Config = j.Config.Clone()
+	}
+	clone.Shapes = j.Shapes.Clone()
+	return &clone
+}
+
+// Clone returns a deep copy of j
+func (j *SimpleSchemaJsonConfig) Clone() *SimpleSchemaJsonConfig {
+	if j == nil {
+		return nil
+	}
+	clone := *j
+	clone.Shape = CloneShape(j.Shape)
+	return &clone
+}
+
+// Clone returns a deep copy of j
+func (j *Square) Clone() *Square {
+	if j == nil {
+		return nil
+	}
+	clone := *j
+	return &clone
+}
+
+// Clone returns a deep copy of j
+func (j *Triangle) Clone() *Triangle {
+	if j == nil {
+		return nil
+	}
+	clone := *j
+	return &clone
+}
+
+// Equal reports whether j and other hold equal values, treating nil and empty
+// slices and maps as equal
+func (j *Circle) Equal(other *Circle) bool {
+	if j == nil || other == nil {
+		return j == other
+	}
+	return j.Radius == other.Radius
+}
+
+// Equal reports whether j and other hold equal values, treating nil and empty
+// slices and maps as equal
+func (j *SimpleSchemaJson) Equal(other *SimpleSchemaJson) bool {
+	if j == nil || other == nil {
+		return j == other
+	}
+	return j.Config.Equal(other.Config) &&
+		j.Shapes.Equal(other.Shapes)
+}
+
+// Equal reports whether j and other hold equal values, treating nil and empty
+// slices and maps as equal
+func (j *SimpleSchemaJsonConfig) Equal(other *SimpleSchemaJsonConfig) bool {
+	if j == nil || other == nil {
+		return j == other
+	}
+	return EqualShape(j.Shape, other.Shape)
+}
+
+// Equal reports whether j and other hold equal values, treating nil and empty
+// slices and maps as equal
+func (j *Square) Equal(other *Square) bool {
+	if j == nil || other == nil {
+		return j == other
+	}
+	return j.Side == other.Side
+}
+
+// Equal reports whether j and other hold equal values, treating nil and empty
+// slices and maps as equal
+func (j *Triangle) Equal(other *Triangle) bool {
+	if j == nil || other == nil {
+		return j == other
+	}
+	return j.Base == other.Base &&
+		j.Height == other.Height
+}
+
+// equalSlices reports whether a and b hold equal items in the same order,
+// treating nil as empty
+func equalSlices[T any](a, b []T, equal func(T, T) bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalMaps reports whether a and b hold equal values under the same keys,
+// treating nil as empty
+func equalMaps[K comparable, V any](a, b map[K]V, equal func(V, V) bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		other, ok := b[key]
+		if !ok || !equal(value, other) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateShape checks value against the schema of the child it holds
+func ValidateShape(value Shape) error {
+	var errs ValidationErrors
+	validateShape(value, "", &errs)
+	return errs.err()
+}
+
+// validateShape appends the violations of value, found at path, to errs
+func validateShape(value Shape, path string, errs *ValidationErrors) {
+	switch value := value.(type) {
+	case nil:
+		errs.add(path, "must not be null")
+	case *Circle:
+		value.validate(path, errs)
+	case *Square:
+		value.validate(path, errs)
+	case *Triangle:
+		value.validate(path, errs)
+	}
+}
+
+// Validate checks every item of s against its schema
+func (s ShapeSlice) Validate() error {
+	var errs ValidationErrors
+	s.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of the items of s, found under path, to errs
+func (s ShapeSlice) validate(path string, errs *ValidationErrors) {
+	for i, item := range s {
+		validateShape(item, path+"/"+strconv.Itoa(i), errs)
+	}
+}
+
+// Validate checks every item of m against its schema
+func (m ShapeMap) Validate() error {
+	var errs ValidationErrors
+	m.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of the items of m, found under path, to errs
+func (m ShapeMap) validate(path string, errs *ValidationErrors) {
+	for _, key := range sortedJSONKeys(m) {
+		validateShape(m[key], path+"/"+jsonPointerToken(key), errs)
+	}
+}
+
+// Validate checks j against its schema, returning every violation as ValidationErrors
+func (j *Circle) Validate() error {
+	var errs ValidationErrors
+	j.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of j, found at path, to errs
+func (j *Circle) validate(path string, errs *ValidationErrors) {
+	if j == nil {
+		errs.add(path, "must not be null")
+		return
+	}
+}
+
+// Validate checks j against its schema, returning every violation as ValidationErrors
+func (j *SimpleSchemaJson) Validate() error {
+	var errs ValidationErrors
+	j.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of j, found at path, to errs
+func (j *SimpleSchemaJson) validate(path string, errs *ValidationErrors) {
+	if j == nil {
+		errs.add(path, "must not be null")
+		return
+	}
+	if j.Config != nil {
+		j.Config.validate(path+"/config", errs)
+	}
+	j.Shapes.validate(path+"/shapes", errs)
+}
+
+// Validate checks j against its schema, returning every violation as ValidationErrors
+func (j *SimpleSchemaJsonConfig) Validate() error {
+	var errs ValidationErrors
+	j.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of j, found at path, to errs
+func (j *SimpleSchemaJsonConfig) validate(path string, errs *ValidationErrors) {
+	if j == nil {
+		errs.add(path, "must not be null")
+		return
+	}
+	if j.Shape == nil {
+		errs.add(path+"/shape", "is required")
+	}
+	if j.Shape != nil {
+		validateShape(j.Shape, path+"/shape", errs)
+	}
+}
+
+// Validate checks j against its schema, returning every violation as ValidationErrors
+func (j *Square) Validate() error {
+	var errs ValidationErrors
+	j.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of j, found at path, to errs
+func (j *Square) validate(path string, errs *ValidationErrors) {
+	if j == nil {
+		errs.add(path, "must not be null")
+		return
+	}
+}
+
+// Validate checks j against its schema, returning every violation as ValidationErrors
+func (j *Triangle) Validate() error {
+	var errs ValidationErrors
+	j.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of j, found at path, to errs
+func (j *Triangle) validate(path string, errs *ValidationErrors) {
+	if j == nil {
+		errs.add(path, "must not be null")
+		return
+	}
+}
+
+// ValidationError is one value that breaks its schema
+type ValidationError struct {
+	// Path is the JSON pointer to the value, relative to the one validated
+	Path    string
+	Message string
+}
+
+// Error implements error for ValidationError
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// ValidationErrors are all the violations Validate found, in the order it found them
+type ValidationErrors []*ValidationError
+
+// Error implements error for ValidationErrors
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// add records a violation at path
+func (e *ValidationErrors) add(path, message string) {
+	*e = append(*e, &ValidationError{Path: path, Message: message})
+}
+
+// err returns the violations as an error, or nil if there are none
+func (e ValidationErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// jsonPointerToken escapes a map key for use in a JSON pointer
+func jsonPointerToken(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// sortedJSONKeys returns the keys of m in sorted order, so violations are
+// reported in the same order every time
+func sortedJSONKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+`,
 	}
 
 	// Verify output files were created and match expectations
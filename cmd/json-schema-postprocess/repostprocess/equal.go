@@ -0,0 +1,221 @@
+package repostprocess
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// equalData is the Equal method of a struct
+type equalData struct {
+	Struct string
+	// Compares are the expressions comparing each field of j and other,
+	// all of which have to hold
+	Compares []string
+}
+
+// comparer writes the expressions comparing the fields of model structs
+type comparer struct {
+	model   *modelFile
+	parents map[string]bool
+	// structs are the structs given an Equal method, including every struct
+	// a field of one of them refers to
+	structs map[string]bool
+	queue   []string
+	// helpers are the generic equal helpers the expressions call
+	helpers map[string]bool
+}
+
+// equals builds the Equal data for the given structs and every model struct
+// their fields reach, and returns the helpers they and the parents' Equal
+// functions call
+func equals(model *modelFile, parents []parentData, structs []string, constantFields map[string]string) ([]equalData, map[string]bool) {
+	c := &comparer{
+		model:   model,
+		parents: make(map[string]bool),
+		structs: make(map[string]bool),
+		helpers: make(map[string]bool),
+	}
+	for _, parent := range parents {
+		c.parents[parent.Name] = true
+		if len(parent.Visitors) > 0 {
+			c.use("equalSlices", "equalMaps")
+		}
+	}
+	for _, name := range structs {
+		c.addStruct(name)
+	}
+
+	var data []equalData
+	for len(c.queue) > 0 {
+		name := c.queue[0]
+		c.queue = c.queue[1:]
+
+		d := equalData{Struct: name}
+		for _, field := range c.model.types[name].Type.(*ast.StructType).Fields.List {
+			names := make([]string, 0, len(field.Names))
+			for _, ident := range field.Names {
+				names = append(names, ident.Name)
+			}
+			if len(names) == 0 {
+				names = append(names, embeddedName(field.Type))
+			}
+			for _, fieldName := range names {
+				if fieldName == "" || fieldName == "_" || constantFields[name] == fieldName {
+					continue
+				}
+				d.Compares = append(d.Compares, c.equalExpr(field.Type, "j."+fieldName, "other."+fieldName, 0))
+			}
+		}
+		data = append(data, d)
+	}
+
+	sort.Slice(data, func(i, j int) bool { return data[i].Struct < data[j].Struct })
+	return data, c.helpers
+}
+
+// use records the helpers an expression calls, along with the helpers they call
+func (c *comparer) use(helpers ...string) {
+	for _, helper := range helpers {
+		c.helpers[helper] = true
+		if helper == "equalAny" {
+			c.use("equalSlices", "equalMaps")
+		}
+	}
+}
+
+// addStruct queues a model struct for an Equal method, reporting whether name is one
+func (c *comparer) addStruct(name string) bool {
+	typeSpec, ok := c.model.types[name]
+	if !ok {
+		return false
+	}
+	if _, ok := typeSpec.Type.(*ast.StructType); !ok {
+		return false
+	}
+	if !c.structs[name] {
+		c.structs[name] = true
+		c.queue = append(c.queue, name)
+	}
+	return true
+}
+
+// isCollection reports whether name is the slice or map type of a parent
+func (c *comparer) isCollection(name string) bool {
+	return c.parents[strings.TrimSuffix(name, "Slice")] || c.parents[strings.TrimSuffix(name, "Map")]
+}
+
+// equalExpr returns an expression reporting whether a and b, of type expr,
+// hold equal values. Nil and empty slices and maps are equal, and types
+// from other packages fall back to reflect.DeepEqual
+func (c *comparer) equalExpr(expr ast.Expr, a, b string, depth int) string {
+	if depth > maxCloneDepth {
+		return fmt.Sprintf("reflect.DeepEqual(%s, %s)", a, b)
+	}
+
+	switch e := expr.(type) {
+	case *ast.Ident:
+		switch {
+		case e.Name == "any":
+			c.use("equalAny")
+			return fmt.Sprintf("equalAny(%s, %s)", a, b)
+		case c.parents[e.Name]:
+			return fmt.Sprintf("Equal%s(%s, %s)", e.Name, a, b)
+		case c.isCollection(e.Name):
+			return fmt.Sprintf("%s.Equal(%s)", a, b)
+		case c.addStruct(e.Name):
+			return fmt.Sprintf("%s.Equal(&%s)", a, b)
+		case c.isComparable(e, depth):
+			return fmt.Sprintf("%s == %s", a, b)
+		}
+		if typeSpec, ok := c.model.types[e.Name]; ok {
+			return c.equalExpr(typeSpec.Type, a, b, depth+1)
+		}
+
+	case *ast.StarExpr:
+		if ident, ok := e.X.(*ast.Ident); ok && c.addStruct(ident.Name) {
+			return fmt.Sprintf("%s.Equal(%s)", a, b)
+		}
+		c.use("equalPointers")
+		return fmt.Sprintf("equalPointers(%s, %s, %s)", a, b, c.equalFunc(e.X, depth+1))
+
+	case *ast.ArrayType:
+		c.use("equalSlices")
+		if e.Len != nil {
+			return fmt.Sprintf("equalSlices(%s[:], %s[:], %s)", a, b, c.equalFunc(e.Elt, depth+1))
+		}
+		return fmt.Sprintf("equalSlices(%s, %s, %s)", a, b, c.equalFunc(e.Elt, depth+1))
+
+	case *ast.MapType:
+		c.use("equalMaps")
+		return fmt.Sprintf("equalMaps(%s, %s, %s)", a, b, c.equalFunc(e.Value, depth+1))
+
+	case *ast.InterfaceType:
+		if e.Methods == nil || len(e.Methods.List) == 0 {
+			c.use("equalAny")
+			return fmt.Sprintf("equalAny(%s, %s)", a, b)
+		}
+
+	case *ast.SelectorExpr:
+		if types.ExprString(e) == "json.RawMessage" {
+			return fmt.Sprintf("bytes.Equal(%s, %s)", a, b)
+		}
+	}
+
+	return fmt.Sprintf("reflect.DeepEqual(%s, %s)", a, b)
+}
+
+// equalFunc returns a function comparing two values of type expr, naming an
+// existing function or method where there is one
+func (c *comparer) equalFunc(expr ast.Expr, depth int) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		switch {
+		case e.Name == "any":
+			c.use("equalAny")
+			return "equalAny"
+		case c.parents[e.Name]:
+			return "Equal" + e.Name
+		case c.isCollection(e.Name):
+			return e.Name + ".Equal"
+		case c.isComparable(e, depth):
+			c.use("equalComparable")
+			return "equalComparable[" + e.Name + "]"
+		}
+	case *ast.StarExpr:
+		if ident, ok := e.X.(*ast.Ident); ok && c.addStruct(ident.Name) {
+			return "(*" + ident.Name + ").Equal"
+		}
+	case *ast.InterfaceType:
+		if e.Methods == nil || len(e.Methods.List) == 0 {
+			c.use("equalAny")
+			return "equalAny"
+		}
+	}
+
+	a, b := fmt.Sprintf("a%d", depth), fmt.Sprintf("b%d", depth)
+	return fmt.Sprintf("func(%s, %s %s) bool {\nreturn %s\n}", a, b, types.ExprString(expr), c.equalExpr(expr, a, b, depth))
+}
+
+// isComparable reports whether a type is a predeclared scalar, or a model
+// type declared as one, which == compares
+func (c *comparer) isComparable(expr ast.Expr, depth int) bool {
+	ident, ok := expr.(*ast.Ident)
+	if !ok || depth > maxCloneDepth {
+		return false
+	}
+	switch ident.Name {
+	case "bool", "string", "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+		return true
+	}
+	if c.parents[ident.Name] || c.isCollection(ident.Name) {
+		return false
+	}
+	if typeSpec, ok := c.model.types[ident.Name]; ok {
+		return c.isComparable(typeSpec.Type, depth+1)
+	}
+	return false
+}
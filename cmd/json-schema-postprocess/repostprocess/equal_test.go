@@ -0,0 +1,146 @@
+package repostprocess
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEquals_FieldShapes(t *testing.T) {
+	src := `package shapes
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+)
+
+type Inner struct {
+	Tags []string
+}
+
+type Names []string
+
+type Kind string
+
+type Outer struct {
+	Count   int
+	Kind    Kind
+	Label   *string
+	Inner   Inner
+	Next    *Inner
+	Names   Names
+	Grid    [][]int
+	ByName  map[string]*Inner
+	Extra   map[string]interface{}
+	Raw     json.RawMessage
+	Fixed   [2]Inner
+	Value   any
+}
+`
+	dir := t.TempDir()
+	modelPath := filepath.Join(dir, "model.go")
+	if err := os.WriteFile(modelPath, []byte(src), 0644); err != nil {
+		t.Fatalf("Failed to write model: %v", err)
+	}
+	model, err := parseModelFile(modelPath)
+	if err != nil {
+		t.Fatalf("Failed to parse model: %v", err)
+	}
+
+	data, helpers := equals(model, nil, []string{"Outer"}, nil)
+	if len(data) != 2 || data[0].Struct != "Inner" || data[1].Struct != "Outer" {
+		t.Fatalf("Expected Equal methods of Inner and Outer, got %+v", data)
+	}
+	for _, helper := range []string{"equalComparable", "equalPointers", "equalSlices", "equalMaps", "equalAny"} {
+		if !helpers[helper] {
+			t.Errorf("Expected the %s helper to be needed", helper)
+		}
+	}
+
+	outer := strings.Join(data[1].Compares, "\n")
+	checkForContent(t, outer, "j.Count == other.Count")
+	checkForContent(t, outer, "j.Kind == other.Kind")
+	checkForContent(t, outer, "equalPointers(j.Label, other.Label, equalComparable[string])")
+	checkForContent(t, outer, "j.Inner.Equal(&other.Inner)")
+	checkForContent(t, outer, "j.Next.Equal(other.Next)")
+	checkForContent(t, outer, "equalSlices(j.Names, other.Names, equalComparable[string])")
+	checkForContent(t, outer, "return equalSlices(a1, b1, equalComparable[int])")
+	checkForContent(t, outer, "equalMaps(j.ByName, other.ByName, (*Inner).Equal)")
+	checkForContent(t, outer, "equalMaps(j.Extra, other.Extra, equalAny)")
+	checkForContent(t, outer, "bytes.Equal(j.Raw, other.Raw)")
+	checkForContent(t, outer, "equalSlices(j.Fixed[:], other.Fixed[:], func(a1, b1 Inner) bool {")
+	checkForContent(t, outer, "equalAny(j.Value, other.Value)")
+	checkForAbsence(t, outer, "reflect.DeepEqual")
+
+	// The expressions have to compile against the model
+	tmpl, err := loadTemplates("")
+	if err != nil {
+		t.Fatalf("Failed to load templates: %v", err)
+	}
+	code := []byte(src + "\nvar _ = bytes.Equal\nvar _ = reflect.DeepEqual\n\n")
+	for _, d := range data {
+		rendered, err := renderTemplate(tmpl, "equal", d)
+		if err != nil {
+			t.Fatalf("Failed to render equal: %v", err)
+		}
+		code = append(code, rendered...)
+	}
+	rendered, err := renderTemplate(tmpl, "equalHelpers", helpers)
+	if err != nil {
+		t.Fatalf("Failed to render equalHelpers: %v", err)
+	}
+	code = append(code, rendered...)
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "model.go", code, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse Equal methods: %v\n%s", err, code)
+	}
+	typeErrors, err := checkPackage(fset, []*ast.File{file}, ".")
+	if err != nil {
+		t.Fatalf("Failed to type-check Equal methods: %v", err)
+	}
+	for _, typeErr := range typeErrors {
+		t.Errorf("Equal methods do not compile: %v", typeErr)
+	}
+}
+
+// nilAndEmptyTest runs in the package generated from the color fixture
+const nilAndEmptyTest = `package color
+
+import "testing"
+
+func TestNilAndEmpty(t *testing.T) {
+	name := "warm"
+	a := &CategoricalPalette{Name: "warm", Semantic: &name, Colors: nil, Usage: nil}
+	b := &CategoricalPalette{Name: "warm", Semantic: &name, Colors: ColorSlice{}, Usage: []string{}}
+	if !a.Equal(b) || !b.Equal(a) {
+		t.Error("Expected nil and empty slices to be equal")
+	}
+
+	b.Usage = []string{"ui"}
+	if a.Equal(b) {
+		t.Error("Expected different usages to differ")
+	}
+
+	if !EqualColor(&RGBValue{R: 1}, &RGBValue{R: 1}) || EqualColor(&RGBValue{R: 1}, &RGBValue{R: 2}) {
+		t.Error("Expected colors to compare by value")
+	}
+	if EqualColor(&RGBValue{}, &HSLValue{}) || EqualColor(&RGBValue{}, nil) || !EqualColor(nil, nil) {
+		t.Error("Expected children of different types to differ")
+	}
+	if !(ColorMap(nil)).Equal(ColorMap{}) || !(ColorSlice{&RGBValue{}}).Equal(ColorSlice{&RGBValue{}}) {
+		t.Error("Expected the collections to compare their items")
+	}
+}
+`
+
+func TestEquals_NilAndEmpty(t *testing.T) {
+	dir := generatePackage(t, filepath.Join("testdata", "color", "color.schema.json"), "color")
+	runGoTest(t, dir, nilAndEmptyTest)
+}
@@ -0,0 +1,764 @@
+package repostprocess
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/ast"
+	"go/constant"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gitlab.com/tozd/go/errors"
+)
+
+// draft07 is the $schema of the schemas GenerateSchema writes
+const draft07 = "http://json-schema.org/draft-07/schema#"
+
+// GenerateSchema writes a draft-07 JSON Schema of the Go package in dir, the
+// reverse of generating a model. Interfaces with an is<Name>() marker method,
+// as the generator emits them, become parents whose anyOf lists the structs
+// and nested parents implementing the marker. A parent's constant method, such
+// as Model() ColorModel, becomes a const discriminator on each child, named by
+// the config's discriminator or else the method name in lower camel case.
+//
+// With a root, the schema describes the root type and the definitions it
+// reaches. Without one, every exported struct and parent is defined
+func GenerateSchema(dir, root string, config *Config) ([]byte, error) {
+	paths, err := packageFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, errors.Errorf("no Go files in %s", dir)
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, path := range paths {
+		// Doc comments become descriptions, so unlike parseFiles this keeps them
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, errors.Errorf("parsing %s: %w", path, err)
+		}
+		files = append(files, file)
+	}
+
+	imp, err := exportImporter(fset, files, dir)
+	if err != nil {
+		return nil, err
+	}
+	info := &types.Info{
+		Defs:  make(map[*ast.Ident]types.Object),
+		Types: make(map[ast.Expr]types.TypeAndValue),
+	}
+	conf := types.Config{Importer: imp}
+	pkg, err := conf.Check(files[0].Name.Name, fset, files, info)
+	if err != nil {
+		return nil, errors.Errorf("type-checking %s: %w", dir, err)
+	}
+
+	b := newSchemaBuilder(pkg, info, files, config)
+	if err := b.findParents(); err != nil {
+		return nil, err
+	}
+
+	schema := map[string]interface{}{"$schema": draft07}
+	if root != "" {
+		obj, ok := pkg.Scope().Lookup(root).(*types.TypeName)
+		if !ok {
+			return nil, errors.Errorf("no type %s in %s", root, dir)
+		}
+		schema["$ref"] = b.ref(obj)
+	} else {
+		for _, obj := range b.typeNames() {
+			if obj.Exported() && (b.parents[obj.Name()] != nil || isStructType(obj.Type())) {
+				b.enqueue(obj)
+			}
+		}
+	}
+
+	if err := b.build(); err != nil {
+		return nil, err
+	}
+	if len(b.definitions) > 0 {
+		schema["definitions"] = b.definitions
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetIndent("", "\t")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(schema); err != nil {
+		return nil, errors.Errorf("encoding schema: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// goParent is a union interface of the package and how its children are told apart
+type goParent struct {
+	obj *types.TypeName
+	// defName is the definition the parent is written to
+	defName string
+	// marker is the unexported method only the parent's children implement
+	marker string
+	// method is the accessor returning a child's constant, if the parent has one
+	method string
+	// field is the JSON property the constant method's values are written to
+	field string
+}
+
+// schemaBuilder collects the definitions of the schema of a Go package
+type schemaBuilder struct {
+	pkg    *types.Package
+	info   *types.Info
+	config *Config
+
+	// docs holds the doc comments of the package's types and struct fields
+	docs map[types.Object]string
+	// methods holds the declarations of the package's methods, by receiver and name
+	methods map[string]map[string]*ast.FuncDecl
+	parents map[string]*goParent
+
+	definitions map[string]interface{}
+	queue       []*types.TypeName
+	queued      map[*types.TypeName]bool
+}
+
+func newSchemaBuilder(pkg *types.Package, info *types.Info, files []*ast.File, config *Config) *schemaBuilder {
+	b := &schemaBuilder{
+		pkg:         pkg,
+		info:        info,
+		config:      config,
+		docs:        make(map[types.Object]string),
+		methods:     make(map[string]map[string]*ast.FuncDecl),
+		parents:     make(map[string]*goParent),
+		definitions: make(map[string]interface{}),
+		queued:      make(map[*types.TypeName]bool),
+	}
+
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				b.recordDocs(decl)
+			case *ast.FuncDecl:
+				if decl.Recv == nil || len(decl.Recv.List) != 1 {
+					continue
+				}
+				recv := receiverName(decl.Recv.List[0].Type)
+				if b.methods[recv] == nil {
+					b.methods[recv] = make(map[string]*ast.FuncDecl)
+				}
+				b.methods[recv][decl.Name.Name] = decl
+			}
+		}
+	}
+
+	return b
+}
+
+// recordDocs records the doc comments of the types a declaration declares and
+// of their struct fields
+func (b *schemaBuilder) recordDocs(decl *ast.GenDecl) {
+	for _, spec := range decl.Specs {
+		typeSpec, ok := spec.(*ast.TypeSpec)
+		if !ok {
+			continue
+		}
+		doc := typeSpec.Doc
+		if doc == nil && len(decl.Specs) == 1 {
+			doc = decl.Doc
+		}
+		if obj := b.info.Defs[typeSpec.Name]; obj != nil && doc != nil {
+			b.docs[obj] = strings.TrimSpace(doc.Text())
+		}
+
+		ast.Inspect(typeSpec.Type, func(n ast.Node) bool {
+			field, ok := n.(*ast.Field)
+			if !ok {
+				return true
+			}
+			doc := field.Doc
+			if doc == nil {
+				doc = field.Comment
+			}
+			if doc == nil {
+				return true
+			}
+			for _, name := range field.Names {
+				if obj := b.info.Defs[name]; obj != nil {
+					b.docs[obj] = strings.TrimSpace(doc.Text())
+				}
+			}
+			return true
+		})
+	}
+}
+
+// receiverName returns the name of the type of a method receiver
+func receiverName(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(expr.X)
+	case *ast.IndexExpr:
+		return receiverName(expr.X)
+	case *ast.Ident:
+		return expr.Name
+	}
+	return ""
+}
+
+// typeNames returns the types declared by the package, in declaration order
+func (b *schemaBuilder) typeNames() []*types.TypeName {
+	var names []*types.TypeName
+	scope := b.pkg.Scope()
+	for _, name := range scope.Names() {
+		if obj, ok := scope.Lookup(name).(*types.TypeName); ok && !obj.IsAlias() {
+			names = append(names, obj)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i].Pos() < names[j].Pos() })
+	return names
+}
+
+// findParents records the interfaces with an is<Name>() marker and their
+// constant methods
+func (b *schemaBuilder) findParents() error {
+	defNames := make(map[string]string)
+	if b.config != nil {
+		for _, defName := range sortedConfigKeys(b.config.Parents) {
+			if name := b.config.Parents[defName].Interface; name != "" {
+				defNames[name] = defName
+			}
+		}
+	}
+
+	for _, obj := range b.typeNames() {
+		iface, ok := obj.Type().Underlying().(*types.Interface)
+		if !ok {
+			continue
+		}
+		marker := "is" + obj.Name()
+		if !hasMarker(iface, marker) {
+			continue
+		}
+
+		parent := &goParent{obj: obj, defName: obj.Name(), marker: marker}
+		if defName, ok := defNames[obj.Name()]; ok {
+			parent.defName = defName
+		}
+
+		override := b.config.Parent(parent.defName)
+		var methods []string
+		for i := 0; i < iface.NumExplicitMethods(); i++ {
+			method := iface.ExplicitMethod(i)
+			if method.Name() == marker || (override.ConstantMethod != "" && method.Name() != override.ConstantMethod) {
+				continue
+			}
+			if isConstantMethod(method) {
+				methods = append(methods, method.Name())
+			}
+		}
+		switch {
+		case len(methods) > 1:
+			return errors.Errorf("parent %s has constant methods %s, set constantMethod in the config to pick one", obj.Name(), strings.Join(methods, ", "))
+		case len(methods) == 1:
+			parent.method = methods[0]
+			parent.field = override.Discriminator
+			if parent.field == "" {
+				parent.field = lowerFirst(parent.method)
+			}
+		case override.ConstantMethod != "":
+			return errors.Errorf("parent %s has no constant method %s", obj.Name(), override.ConstantMethod)
+		}
+
+		b.parents[obj.Name()] = parent
+	}
+
+	return nil
+}
+
+// hasMarker reports whether an interface declares the marker method itself
+func hasMarker(iface *types.Interface, marker string) bool {
+	for i := 0; i < iface.NumExplicitMethods(); i++ {
+		method := iface.ExplicitMethod(i)
+		sig := method.Type().(*types.Signature)
+		if method.Name() == marker && sig.Params().Len() == 0 && sig.Results().Len() == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// isConstantMethod reports whether a method takes nothing and returns a named
+// string, number or boolean, as the accessor of a child's constant does
+func isConstantMethod(method *types.Func) bool {
+	sig := method.Type().(*types.Signature)
+	if sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+		return false
+	}
+	named, ok := sig.Results().At(0).Type().(*types.Named)
+	if !ok {
+		return false
+	}
+	basic, ok := named.Underlying().(*types.Basic)
+	return ok && basic.Info()&(types.IsString|types.IsNumeric|types.IsBoolean) != 0
+}
+
+// lowerFirst lowercases the first letter of a name
+func lowerFirst(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(r)) + name[size:]
+}
+
+// implements reports whether a type or its pointer has a method
+func (b *schemaBuilder) implements(obj *types.TypeName, method string) bool {
+	found, _, _ := types.LookupFieldOrMethod(types.NewPointer(obj.Type()), true, b.pkg, method)
+	_, ok := found.(*types.Func)
+	return ok
+}
+
+// embeds reports whether the interface of child embeds the parent's
+func embeds(child, parent *goParent) bool {
+	iface := child.obj.Type().Underlying().(*types.Interface)
+	for i := 0; i < iface.NumEmbeddeds(); i++ {
+		if types.Identical(iface.EmbeddedType(i), parent.obj.Type()) {
+			return true
+		}
+	}
+	return false
+}
+
+// children returns the nested parents and structs a parent lists, in
+// declaration order. Structs reached through a nested parent are listed there
+func (b *schemaBuilder) children(parent *goParent) []*types.TypeName {
+	var nested []*goParent
+	for _, obj := range b.typeNames() {
+		if other := b.parents[obj.Name()]; other != nil && other != parent && embeds(other, parent) {
+			nested = append(nested, other)
+		}
+	}
+
+	var children []*types.TypeName
+	for _, obj := range b.typeNames() {
+		if other := b.parents[obj.Name()]; other != nil {
+			if embeds(other, parent) {
+				children = append(children, obj)
+			}
+			continue
+		}
+		if !isStructType(obj.Type()) || !b.implements(obj, parent.marker) {
+			continue
+		}
+		direct := true
+		for _, other := range nested {
+			if b.implements(obj, other.marker) {
+				direct = false
+			}
+		}
+		if direct {
+			children = append(children, obj)
+		}
+	}
+	return children
+}
+
+// isStructType reports whether a type is a struct
+func isStructType(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Struct)
+	return ok
+}
+
+// enqueue schedules the definition of a type
+func (b *schemaBuilder) enqueue(obj *types.TypeName) {
+	if !b.queued[obj] {
+		b.queued[obj] = true
+		b.queue = append(b.queue, obj)
+	}
+}
+
+// ref schedules the definition of a type and returns its reference
+func (b *schemaBuilder) ref(obj *types.TypeName) string {
+	b.enqueue(obj)
+	return "#/definitions/" + b.defName(obj)
+}
+
+// defName returns the name a type is defined under
+func (b *schemaBuilder) defName(obj *types.TypeName) string {
+	if parent := b.parents[obj.Name()]; parent != nil {
+		return parent.defName
+	}
+	return obj.Name()
+}
+
+// build defines the queued types and the types they reach
+func (b *schemaBuilder) build() error {
+	for len(b.queue) > 0 {
+		obj := b.queue[0]
+		b.queue = b.queue[1:]
+
+		definition, err := b.define(obj)
+		if err != nil {
+			return errors.Errorf("%s: %w", obj.Name(), err)
+		}
+		if doc := b.docs[obj]; doc != "" {
+			definition["description"] = doc
+		}
+		b.definitions[b.defName(obj)] = definition
+	}
+	return nil
+}
+
+// define returns the definition of a type of the package
+func (b *schemaBuilder) define(obj *types.TypeName) (map[string]interface{}, error) {
+	if parent := b.parents[obj.Name()]; parent != nil {
+		var anyOf []interface{}
+		for _, child := range b.children(parent) {
+			anyOf = append(anyOf, map[string]interface{}{"$ref": b.ref(child)})
+		}
+		if len(anyOf) == 0 {
+			return nil, errors.Errorf("no type implements %s()", parent.marker)
+		}
+		return map[string]interface{}{"anyOf": anyOf}, nil
+	}
+
+	if st, ok := obj.Type().Underlying().(*types.Struct); ok {
+		definition, err := b.objectSchema(st)
+		if err != nil {
+			return nil, err
+		}
+		if err := b.addDiscriminators(obj, definition); err != nil {
+			return nil, err
+		}
+		return definition, nil
+	}
+
+	definition, err := b.typeSchema(obj.Type().Underlying())
+	if err != nil {
+		return nil, err
+	}
+	if values := b.enumValues(obj); len(values) > 0 {
+		definition["enum"] = values
+	}
+	return definition, nil
+}
+
+// addDiscriminators adds the constant of each parent a struct is a child of,
+// directly or through nested parents, as a required const property
+func (b *schemaBuilder) addDiscriminators(obj *types.TypeName, definition map[string]interface{}) error {
+	properties := definition["properties"].(map[string]interface{})
+	required, _ := definition["required"].([]string)
+
+	for _, parentObj := range b.typeNames() {
+		parent := b.parents[parentObj.Name()]
+		if parent == nil || parent.method == "" || !b.implements(obj, parent.marker) {
+			continue
+		}
+		value, err := b.constantValue(obj, parent.method)
+		if err != nil {
+			return err
+		}
+
+		property, _ := properties[parent.field].(map[string]interface{})
+		if property == nil {
+			property = make(map[string]interface{})
+			properties[parent.field] = property
+		}
+		property["const"] = value
+		if !containsString(required, parent.field) {
+			required = append(required, parent.field)
+		}
+	}
+
+	if len(required) > 0 {
+		definition["required"] = required
+	}
+	return nil
+}
+
+// constantValue returns the constant a child's constant method returns, which
+// must be a single return of a constant expression
+func (b *schemaBuilder) constantValue(obj *types.TypeName, method string) (interface{}, error) {
+	decl := b.methods[obj.Name()][method]
+	if decl == nil || decl.Body == nil || len(decl.Body.List) != 1 {
+		return nil, errors.Errorf("%s() must be declared on %s and return a constant", method, obj.Name())
+	}
+	ret, ok := decl.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return nil, errors.Errorf("%s() of %s must return a constant", method, obj.Name())
+	}
+	value := b.info.Types[ret.Results[0]].Value
+	if value == nil {
+		return nil, errors.Errorf("%s() of %s must return a constant", method, obj.Name())
+	}
+	return constantJSON(value), nil
+}
+
+// constantJSON converts a constant to the JSON value it marshals to
+func constantJSON(value constant.Value) interface{} {
+	switch value.Kind() {
+	case constant.String:
+		return constant.StringVal(value)
+	case constant.Bool:
+		return constant.BoolVal(value)
+	case constant.Int:
+		if i, ok := constant.Int64Val(value); ok {
+			return i
+		}
+	}
+	f, _ := constant.Float64Val(value)
+	return f
+}
+
+// enumValues returns the values of the constants declared with a type, in
+// declaration order
+func (b *schemaBuilder) enumValues(obj *types.TypeName) []interface{} {
+	var consts []*types.Const
+	scope := b.pkg.Scope()
+	for _, name := range scope.Names() {
+		if c, ok := scope.Lookup(name).(*types.Const); ok && types.Identical(c.Type(), obj.Type()) {
+			consts = append(consts, c)
+		}
+	}
+	sort.Slice(consts, func(i, j int) bool { return consts[i].Pos() < consts[j].Pos() })
+
+	var values []interface{}
+	for _, c := range consts {
+		values = append(values, constantJSON(c.Val()))
+	}
+	return values
+}
+
+// objectSchema returns the schema of a struct, with a property for each field
+// encoding/json writes. Fields without omitempty are required, and pointers
+// among them nullable
+func (b *schemaBuilder) objectSchema(st *types.Struct) (map[string]interface{}, error) {
+	var fields []jsonField
+	collectFields(st, 0, make(map[*types.Struct]bool), &fields)
+
+	properties := make(map[string]interface{})
+	var required []string
+	for _, field := range dominantFields(fields) {
+		property, err := b.typeSchema(field.field.Type())
+		if err != nil {
+			return nil, errors.Errorf("field %s: %w", field.field.Name(), err)
+		}
+		if _, ok := field.field.Type().(*types.Pointer); ok && !field.omitEmpty {
+			property = nullable(property)
+		}
+		if doc := b.docs[field.field]; doc != "" {
+			property["description"] = doc
+		}
+		properties[field.name] = property
+		if !field.omitEmpty {
+			required = append(required, field.name)
+		}
+	}
+
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema, nil
+}
+
+// jsonField is a field encoding/json could write, at the depth of embedding
+// it's promoted from
+type jsonField struct {
+	name      string
+	depth     int
+	tagged    bool
+	omitEmpty bool
+	field     *types.Var
+}
+
+// collectFields lists the fields of a struct in order, promoting the fields of
+// untagged embedded structs as encoding/json does
+func collectFields(st *types.Struct, depth int, visiting map[*types.Struct]bool, fields *[]jsonField) {
+	// A struct embedding itself through a pointer would never end
+	if visiting[st] {
+		return
+	}
+	visiting[st] = true
+	defer delete(visiting, st)
+
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i)).Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		omitEmpty := containsString(strings.Split(options, ","), "omitempty")
+
+		if field.Embedded() && name == "" {
+			t := field.Type()
+			if pointer, ok := t.(*types.Pointer); ok {
+				t = pointer.Elem()
+			}
+			if embedded, ok := t.Underlying().(*types.Struct); ok {
+				collectFields(embedded, depth+1, visiting, fields)
+				continue
+			}
+		}
+		if !field.Exported() {
+			continue
+		}
+		tagged := name != ""
+		if !tagged {
+			name = field.Name()
+		}
+		*fields = append(*fields, jsonField{name: name, depth: depth, tagged: tagged, omitEmpty: omitEmpty, field: field})
+	}
+}
+
+// dominantFields returns the fields encoding/json writes, in order. Of the
+// fields sharing a name the shallowest wins, a tagged one among several at
+// that depth, and the name is dropped when that leaves more than one
+func dominantFields(fields []jsonField) []jsonField {
+	byName := make(map[string][]jsonField)
+	for _, field := range fields {
+		byName[field.name] = append(byName[field.name], field)
+	}
+
+	var dominant []jsonField
+	for _, field := range fields {
+		candidates := byName[field.name]
+		if winner, ok := dominantField(candidates); ok && winner.field == field.field {
+			dominant = append(dominant, field)
+		}
+	}
+	return dominant
+}
+
+// dominantField picks the field encoding/json writes among those sharing a name
+func dominantField(candidates []jsonField) (jsonField, bool) {
+	depth := candidates[0].depth
+	for _, c := range candidates {
+		depth = min(depth, c.depth)
+	}
+	var shallowest, tagged []jsonField
+	for _, c := range candidates {
+		if c.depth == depth {
+			shallowest = append(shallowest, c)
+			if c.tagged {
+				tagged = append(tagged, c)
+			}
+		}
+	}
+	switch {
+	case len(shallowest) == 1:
+		return shallowest[0], true
+	case len(tagged) == 1:
+		return tagged[0], true
+	}
+	return jsonField{}, false
+}
+
+// textMarshaler is encoding.TextMarshaler, which map keys encoding/json writes
+// as text implement
+var textMarshaler = types.NewInterfaceType([]*types.Func{
+	types.NewFunc(token.NoPos, nil, "MarshalText", types.NewSignatureType(nil, nil, nil, nil,
+		types.NewTuple(
+			types.NewVar(token.NoPos, nil, "", types.NewSlice(types.Typ[types.Byte])),
+			types.NewVar(token.NoPos, nil, "", types.Universe.Lookup("error").Type()),
+		), false)),
+}, nil).Complete()
+
+// nullable returns a schema that also allows null
+func nullable(schema map[string]interface{}) map[string]interface{} {
+	if t, ok := schema["type"].(string); ok {
+		schema["type"] = []interface{}{t, "null"}
+		return schema
+	}
+	return map[string]interface{}{"anyOf": []interface{}{schema, map[string]interface{}{"type": "null"}}}
+}
+
+// typeSchema returns the schema of the JSON encoding/json writes for a type.
+// The package's structs, parents and types with constants are referenced, and
+// its other named types inlined
+func (b *schemaBuilder) typeSchema(t types.Type) (map[string]interface{}, error) {
+	switch t := types.Unalias(t).(type) {
+	case *types.Named:
+		obj := t.Obj()
+		switch {
+		case obj.Pkg() == nil:
+			return nil, errors.Errorf("unsupported type %s", t)
+		case obj.Pkg() == b.pkg:
+			if b.parents[obj.Name()] != nil || isStructType(t) || len(b.enumValues(obj)) > 0 {
+				return map[string]interface{}{"$ref": b.ref(obj)}, nil
+			}
+		case obj.Pkg().Path() == "time" && obj.Name() == "Time":
+			return map[string]interface{}{"type": "string", "format": "date-time"}, nil
+		case obj.Pkg().Path() == "encoding/json" && obj.Name() == "RawMessage":
+			return map[string]interface{}{}, nil
+		}
+		return b.typeSchema(t.Underlying())
+	case *types.Basic:
+		switch info := t.Info(); {
+		case info&types.IsBoolean != 0:
+			return map[string]interface{}{"type": "boolean"}, nil
+		case info&types.IsInteger != 0:
+			return map[string]interface{}{"type": "integer"}, nil
+		case info&types.IsFloat != 0:
+			return map[string]interface{}{"type": "number"}, nil
+		case info&types.IsString != 0:
+			return map[string]interface{}{"type": "string"}, nil
+		}
+	case *types.Pointer:
+		return b.typeSchema(t.Elem())
+	case *types.Slice:
+		// encoding/json writes byte slices as base64 strings
+		if basic, ok := t.Elem().Underlying().(*types.Basic); ok && basic.Kind() == types.Byte {
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}, nil
+		}
+		items, err := b.typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "array", "items": items}, nil
+	case *types.Array:
+		items, err := b.typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "array", "items": items, "minItems": t.Len(), "maxItems": t.Len()}, nil
+	case *types.Map:
+		values, err := b.typeSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		schema := map[string]interface{}{"type": "object", "additionalProperties": values}
+		// encoding/json writes string keys as they are, then text marshalers
+		// and integers as their text
+		key, _ := t.Key().Underlying().(*types.Basic)
+		switch {
+		case key != nil && key.Info()&types.IsString != 0:
+		case types.Implements(t.Key(), textMarshaler):
+		case key != nil && key.Info()&types.IsUnsigned != 0:
+			schema["propertyNames"] = map[string]interface{}{"pattern": "^(0|[1-9][0-9]*)$"}
+		case key != nil && key.Info()&types.IsInteger != 0:
+			schema["propertyNames"] = map[string]interface{}{"pattern": "^-?(0|[1-9][0-9]*)$"}
+		default:
+			return nil, errors.Errorf("unsupported map key %s, encoding/json only writes string, integer and text marshaler keys", t.Key())
+		}
+		return schema, nil
+	case *types.Interface:
+		if t.Empty() {
+			return map[string]interface{}{}, nil
+		}
+	case *types.Struct:
+		return b.objectSchema(t)
+	}
+	return nil, errors.Errorf("unsupported type %s", t)
+}
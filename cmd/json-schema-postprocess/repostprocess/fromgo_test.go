@@ -0,0 +1,203 @@
+package repostprocess
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateSchema(t *testing.T) {
+	schema, err := GenerateSchema(filepath.Join("testdata", "gotypes"), "Drawing", nil)
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+	code := string(schema)
+
+	// The root is referenced, and only what it reaches is defined
+	checkForContent(t, code, "\"$ref\": \"#/definitions/Drawing\",\n\t\"$schema\": \"http://json-schema.org/draft-07/schema#\",")
+	checkForAbsence(t, code, "\"Meta\"")
+
+	// Parents list their children and nested parents, whose children aren't repeated
+	checkForContent(t, code, "\"Shape\": {\n\t\t\t\"anyOf\": [\n\t\t\t\t{\n\t\t\t\t\t\"$ref\": \"#/definitions/Polygon\"\n\t\t\t\t},\n\t\t\t\t{\n\t\t\t\t\t\"$ref\": \"#/definitions/Circle\"\n\t\t\t\t}\n\t\t\t],\n\t\t\t\"description\": \"Shape is anything a drawing is made of\"")
+	checkForContent(t, code, "\"Polygon\": {\n\t\t\t\"anyOf\": [\n\t\t\t\t{\n\t\t\t\t\t\"$ref\": \"#/definitions/Square\"\n\t\t\t\t},\n\t\t\t\t{\n\t\t\t\t\t\"$ref\": \"#/definitions/Triangle\"\n\t\t\t\t}\n\t\t\t]")
+
+	// Children get their constant as a required const discriminator
+	checkForContent(t, code, "\"kind\": {\n\t\t\t\t\t\"const\": \"circle\"\n\t\t\t\t}")
+	checkForContent(t, code, "\"kind\": {\n\t\t\t\t\t\"const\": \"triangle\"\n\t\t\t\t}")
+	checkForContent(t, code, "\"required\": [\n\t\t\t\t\"radius\",\n\t\t\t\t\"kind\"\n\t\t\t]")
+
+	// Fields map by their JSON tags, with omitempty making them optional and
+	// pointers without it nullable
+	checkForContent(t, code, "\"description\": \"Radius is in points\",\n\t\t\t\t\t\"type\": \"number\"")
+	checkForContent(t, code, "\"note\": {\n\t\t\t\t\t\"type\": [\n\t\t\t\t\t\t\"string\",\n\t\t\t\t\t\t\"null\"\n\t\t\t\t\t]")
+	checkForContent(t, code, "\"required\": [\n\t\t\t\t\"title\",\n\t\t\t\t\"shapes\",\n\t\t\t\t\"style\",\n\t\t\t\t\"note\",\n\t\t\t\t\"created\"\n\t\t\t]")
+	checkForContent(t, code, "\"layers\": {\n\t\t\t\t\t\"additionalProperties\": {\n\t\t\t\t\t\t\"$ref\": \"#/definitions/Shape\"\n\t\t\t\t\t},\n\t\t\t\t\t\"type\": \"object\"")
+	checkForContent(t, code, "\"created\": {\n\t\t\t\t\t\"format\": \"date-time\",\n\t\t\t\t\t\"type\": \"string\"")
+	checkForContent(t, code, "\"maxItems\": 3,\n\t\t\t\t\t\"minItems\": 3,")
+	checkForAbsence(t, code, "\"Draft\"")
+	checkForAbsence(t, code, "\"cache\"")
+
+	// Types with constants become enums
+	checkForContent(t, code, "\"Style\": {\n\t\t\t\"description\": \"Style is how a shape's outline is drawn\",\n\t\t\t\"enum\": [\n\t\t\t\t\"solid\",\n\t\t\t\t\"dashed\"\n\t\t\t],\n\t\t\t\"type\": \"string\"")
+}
+
+func TestGenerateSchema_WithoutRoot(t *testing.T) {
+	config := &Config{Parents: map[string]ParentConfig{"Figure": {Interface: "Shape", Discriminator: "type"}}}
+	schema, err := GenerateSchema(filepath.Join("testdata", "gotypes"), "", config)
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+	code := string(schema)
+
+	// Every exported struct is defined, and parents under their config names
+	checkForAbsence(t, code, "\"$ref\": \"#/definitions/Drawing\",\n\t\"$schema\"")
+	checkForContent(t, code, "\"Meta\": {")
+	checkForContent(t, code, "\"Figure\": {\n\t\t\t\"anyOf\"")
+	checkForContent(t, code, "\"$ref\": \"#/definitions/Figure\"")
+	checkForContent(t, code, "\"type\": {\n\t\t\t\t\t\"const\": \"square\"\n\t\t\t\t}")
+	checkForAbsence(t, code, "\"Shape\"")
+}
+
+func TestGenerateSchema_RoundTrip(t *testing.T) {
+	schema, err := GenerateSchema(filepath.Join("testdata", "gotypes"), "Drawing", nil)
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+	schemaPath := filepath.Join(t.TempDir(), "drawing.schema.json")
+	if err := os.WriteFile(schemaPath, schema, 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	// The analyzer finds the parents the schema was generated from
+	analyzer, err := NewSchemaAnalyzer(schemaPath)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+	results, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Failed to analyze schema: %v", err)
+	}
+
+	polygon, ok := results.Parents["Polygon"]
+	if !ok {
+		t.Fatalf("Expected Polygon to be a parent, got %v", results.Parents)
+	}
+	if polygon.ConstantField != "kind" || polygon.ConstantValues["Square"] != "square" {
+		t.Errorf("Expected Polygon to be discriminated by kind, got %q with %v", polygon.ConstantField, polygon.ConstantValues)
+	}
+	shape, ok := results.Parents["Shape"]
+	if !ok {
+		t.Fatalf("Expected Shape to be a parent, got %v", results.Parents)
+	}
+	if !containsString(shape.NestedParents, "Polygon") {
+		t.Errorf("Expected Polygon to be nested in Shape, got %v", shape.NestedParents)
+	}
+}
+
+// generateSchemaFrom writes source as a package and generates its schema
+func generateSchemaFrom(t *testing.T, source, root string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "types.go"), []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to write package: %v", err)
+	}
+	schema, err := GenerateSchema(dir, root, nil)
+	if err != nil {
+		t.Fatalf("Failed to generate schema: %v", err)
+	}
+	return string(schema)
+}
+
+func TestGenerateSchema_EmbeddedFields(t *testing.T) {
+	code := generateSchemaFrom(t, `package embedded
+
+type Base struct {
+	Name  string `+"`json:\"name\"`"+`
+	ID    string `+"`json:\"id\"`"+`
+	Label string
+}
+
+type Other struct {
+	ID    int    `+"`json:\"id\"`"+`
+	Label int    `+"`json:\"Label\"`"+`
+}
+
+type Outer struct {
+	Name int `+"`json:\"name\"`"+`
+	Base
+	Other
+}
+`, "Outer")
+
+	// The shallower field wins, and a tagged one among fields at the same depth
+	checkForContent(t, code, "\"name\": {\n\t\t\t\t\t\"type\": \"integer\"\n\t\t\t\t}")
+	checkForContent(t, code, "\"Label\": {\n\t\t\t\t\t\"type\": \"integer\"\n\t\t\t\t}")
+
+	// Fields at the same depth that are both tagged cancel each other out
+	checkForAbsence(t, code, "\"id\"")
+	checkForContent(t, code, "\"required\": [\n\t\t\t\t\"name\",\n\t\t\t\t\"Label\"\n\t\t\t]")
+}
+
+func TestGenerateSchema_MapKeys(t *testing.T) {
+	code := generateSchemaFrom(t, `package keys
+
+type Level string
+
+func (l Level) MarshalText() ([]byte, error) { return []byte(l), nil }
+
+type Code struct{ Major, Minor int }
+
+func (c Code) MarshalText() ([]byte, error) { return nil, nil }
+
+type Index struct {
+	ByCount map[int]string `+"`json:\"byCount\"`"+`
+	BySize  map[uint8]bool `+"`json:\"bySize\"`"+`
+	ByCode  map[Code]int   `+"`json:\"byCode\"`"+`
+	ByLevel map[Level]int  `+"`json:\"byLevel\"`"+`
+}
+`, "Index")
+
+	// Integer keys are written as their decimal text
+	checkForContent(t, code, "\"byCount\": {\n\t\t\t\t\t\"additionalProperties\": {\n\t\t\t\t\t\t\"type\": \"string\"\n\t\t\t\t\t},\n\t\t\t\t\t\"propertyNames\": {\n\t\t\t\t\t\t\"pattern\": \"^-?(0|[1-9][0-9]*)$\"\n\t\t\t\t\t},\n\t\t\t\t\t\"type\": \"object\"")
+	checkForContent(t, code, "\"pattern\": \"^(0|[1-9][0-9]*)$\"")
+
+	// Text marshalers are written as any string
+	checkForContent(t, code, "\"byCode\": {\n\t\t\t\t\t\"additionalProperties\": {\n\t\t\t\t\t\t\"type\": \"integer\"\n\t\t\t\t\t},\n\t\t\t\t\t\"type\": \"object\"")
+	checkForContent(t, code, "\"byLevel\": {\n\t\t\t\t\t\"additionalProperties\": {\n\t\t\t\t\t\t\"type\": \"integer\"\n\t\t\t\t\t},\n\t\t\t\t\t\"type\": \"object\"")
+}
+
+func TestGenerateSchema_Errors(t *testing.T) {
+	dir := t.TempDir()
+	source := `package broken
+
+type Shape interface {
+	isShape()
+	Kind() string
+	Name() Name
+	Side() Name
+}
+
+type Name string
+`
+	if err := os.WriteFile(filepath.Join(dir, "broken.go"), []byte(source), 0644); err != nil {
+		t.Fatalf("Failed to write package: %v", err)
+	}
+
+	if _, err := GenerateSchema(dir, "", nil); err == nil {
+		t.Fatal("Expected a parent with two constant methods to fail")
+	}
+	if _, err := GenerateSchema(dir, "Missing", &Config{Parents: map[string]ParentConfig{"Shape": {ConstantMethod: "Name"}}}); err == nil {
+		t.Fatal("Expected a missing root to fail")
+	}
+	if _, err := GenerateSchema(dir, "", &Config{Parents: map[string]ParentConfig{"Shape": {ConstantMethod: "Name"}}}); err == nil {
+		t.Fatal("Expected a parent without children to fail")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "broken.go"), []byte("package broken\n\ntype Grid struct {\n\tCells map[[2]int]string `json:\"cells\"`\n}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write package: %v", err)
+	}
+	if _, err := GenerateSchema(dir, "Grid", nil); err == nil {
+		t.Fatal("Expected a map with array keys to fail")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"go/parser"
 	"go/token"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -44,9 +45,7 @@ func TestCodeGenerator_Generate(t *testing.T) {
 
 	// Verify output files were created
 	expectedFiles := []string{
-		filepath.Join(outputDir, "model_enhanced.gen.go"),
-		filepath.Join(outputDir, "model_interfaces.gen.go"),
-		filepath.Join(outputDir, "model_unmarshal.gen.go"),
+		filepath.Join(outputDir, "model.gen.go"),
 	}
 
 	for _, file := range expectedFiles {
@@ -58,104 +57,100 @@ func TestCodeGenerator_Generate(t *testing.T) {
 	// Test specific functionality based on steps in postprocess.mdc
 
 	// Step 1: Check if interfaces are properly generated
-	interfacesContent, err := os.ReadFile(filepath.Join(outputDir, "model_interfaces.gen.go"))
-	if err != nil {
-		t.Fatalf("Failed to read interfaces file: %v", err)
-	}
-
-	interfacesStr := string(interfacesContent)
+	modelStr := readModel(t, outputDir)
 
 	// Interface declaration and methods for Color
-	checkForContent(t, interfacesStr, "type Color interface {")
-	checkForContent(t, interfacesStr, "isColor()")
-	checkForContent(t, interfacesStr, "Model() ColorModel")
+	checkForContent(t, modelStr, "type Color interface {")
+	checkForContent(t, modelStr, "isColor()")
+	checkForContent(t, modelStr, "Model() ColorModel")
 
 	// Implementation methods for all Color children
-	checkForContent(t, interfacesStr, "func (me *HSLValue) isColor()")
-	checkForContent(t, interfacesStr, "func (me *RGBValue) isColor()")
+	checkForContent(t, modelStr, "func (me *HSLValue) isColor()")
+	checkForContent(t, modelStr, "func (me *RGBValue) isColor()")
 
 	// ColorModel implementations for each color type - check simplified versions
-	checkForContent(t, interfacesStr, "func (me *HSLValue) Model() ColorModel")
-	checkForContent(t, interfacesStr, "func (me *RGBValue) Model() ColorModel")
+	checkForContent(t, modelStr, "func (me *HSLValue) Model() ColorModel")
+	checkForContent(t, modelStr, "func (me *RGBValue) Model() ColorModel")
 
 	// Check slice and map type declarations
-	checkForContent(t, interfacesStr, "type ColorSlice []Color")
-	checkForContent(t, interfacesStr, "type ColorMap map[string]Color")
+	checkForContent(t, modelStr, "type ColorSlice []Color")
+	checkForContent(t, modelStr, "type ColorMap map[string]Color")
 
 	// Interface declaration and methods for Palette
-	checkForContent(t, interfacesStr, "type Palette interface {")
-	checkForContent(t, interfacesStr, "isPalette()")
+	checkForContent(t, modelStr, "type Palette interface {")
+	checkForContent(t, modelStr, "isPalette()")
 
 	// Implementation methods for Palette children
-	checkForContent(t, interfacesStr, "func (me *CategoricalPalette) isPalette()")
-	checkForContent(t, interfacesStr, "func (me *DiscreteScalePalette) isPalette()")
+	checkForContent(t, modelStr, "func (me *CategoricalPalette) isPalette()")
+	checkForContent(t, modelStr, "func (me *DiscreteScalePalette) isPalette()")
 
 	// Check for constant type definitions
-	checkForContent(t, interfacesStr, "type ColorModel string")
-	checkForContent(t, interfacesStr, "const (")
-	checkForContent(t, interfacesStr, "ColorModelHsl")
+	checkForContent(t, modelStr, "type ColorModel string")
+	checkForContent(t, modelStr, "const (")
+	checkForContent(t, modelStr, "ColorModelHsl")
 
 	// Step 2: Check if parseUnknown functions are generated
-	unmarshalContent, err := os.ReadFile(filepath.Join(outputDir, "model_unmarshal.gen.go"))
-	if err != nil {
-		t.Fatalf("Failed to read unmarshal file: %v", err)
-	}
-
-	unmarshalStr := string(unmarshalContent)
 
 	// Check for parseUnknown functions
-	checkForContent(t, unmarshalStr, "func parseUnknownColor(b interface{}) (Color, error)")
-	checkForContent(t, unmarshalStr, "func parseUnknownPalette(b interface{}) (Palette, error)")
+	checkForContent(t, modelStr, "func parseUnknownColor(b interface{}) (Color, error)")
+	checkForContent(t, modelStr, "func parseUnknownPalette(b interface{}) (Palette, error)")
+	checkForContent(t, modelStr, "func parseColorRaw(data json.RawMessage) (Color, error)")
+	checkForContent(t, modelStr, "func parsePaletteRaw(data json.RawMessage) (Palette, error)")
 
 	// Check the constant field usage in parseUnknownColor - simplified checks
-	checkForContent(t, unmarshalStr, "Model ColorModel `json:\"model\"")
-	checkForContent(t, unmarshalStr, "switch plain.Model {")
+	checkForContent(t, modelStr, "Model ColorModel `json:\"model\"")
+	checkForContent(t, modelStr, "switch constant {")
 
 	// Step 3: Check for MarshalJSON methods
-	checkForContent(t, unmarshalStr, "func (j LCHValue) MarshalJSON() ([]byte, error)")
-	checkForContent(t, unmarshalStr, "Model: j.Model()")
-	checkForContent(t, unmarshalStr, "type Plain LCHValue")
+	checkForContent(t, modelStr, "func (j LCHValue) MarshalJSON() ([]byte, error)")
+	checkForContent(t, modelStr, "Model: j.Model()")
+	checkForContent(t, modelStr, "type Plain LCHValue")
 
 	// Check that all color types have MarshalJSON methods - simplified checks
-	checkForContent(t, unmarshalStr, "func (j HSLValue) MarshalJSON() ([]byte, error)")
-	checkForContent(t, unmarshalStr, "func (j RGBValue) MarshalJSON() ([]byte, error)")
-
-	// Step 4: Check type adjustments in enhanced model
-	enhancedContent, err := os.ReadFile(filepath.Join(outputDir, "model_enhanced.gen.go"))
-	if err != nil {
-		t.Fatalf("Failed to read enhanced model file: %v", err)
-	}
-
-	enhancedStr := string(enhancedContent)
+	checkForContent(t, modelStr, "func (j HSLValue) MarshalJSON() ([]byte, error)")
+	checkForContent(t, modelStr, "func (j RGBValue) MarshalJSON() ([]byte, error)")
 
+	// Step 4: Check type adjustments in the model
 	// Check for proper field type modifications - array parent callers
-	checkForContent(t, enhancedStr, "Colors ColorSlice")
-	checkForContent(t, enhancedStr, "Palettes PaletteSlice")
+	checkForContent(t, modelStr, "Colors ColorSlice")
+	checkForContent(t, modelStr, "Palettes PaletteSlice")
 
 	// Check for proper field type modifications - direct parent callers
-	checkForContent(t, enhancedStr, "Value Color")
+	checkForContent(t, modelStr, "Value Color")
 
 	// Step 5: Check UnmarshalJSON modifications in parent callers
-	checkForContent(t, unmarshalStr, "func (j *ColorConfig) UnmarshalJSON(b []byte) error")
-	checkForContent(t, unmarshalStr, "parsed, err := parseUnknownColor(raw[\"value\"])")
-	checkForContent(t, unmarshalStr, "plain.Value = parsed")
+	checkForContent(t, modelStr, "func (j *ColorConfig) UnmarshalJSON(b []byte) error")
+	checkForContent(t, modelStr, "parsed, err := parseColorRaw(raw[\"value\"])")
+	checkForContent(t, modelStr, "plain.Value = parsed")
 
 	// Check array parent callers
-	checkForContent(t, unmarshalStr, "func (j *AssetPack) UnmarshalJSON(b []byte) error")
-	checkForContent(t, unmarshalStr, "palettes := make(PaletteSlice")
-	checkForContent(t, unmarshalStr, "parsed, err := parseUnknownPalette(item)")
+	checkForContent(t, modelStr, "func (j *AssetPack) UnmarshalJSON(b []byte) error")
+	checkForContent(t, modelStr, "palettes := make(PaletteSlice")
+	checkForContent(t, modelStr, "parsed, err := parsePaletteRaw(item)")
 
 	// Additional checks per user request
 
 	// Check enhanced file doesn't contain type definitions that should have been replaced
-	checkForAbsence(t, enhancedStr, "Type string `json:\"type\" yaml:\"type\" mapstructure:\"type\"`")
-	checkForAbsence(t, enhancedStr, "type Palette interface{}")
+	checkForAbsence(t, modelStr, "Type string `json:\"type\" yaml:\"type\" mapstructure:\"type\"`")
+	checkForAbsence(t, modelStr, "type Palette interface{}")
+
+	// The placeholder types go-jsonschema used for the parents are gone
+	checkForAbsence(t, modelStr, "CategoricalPaletteColorsElem")
+	checkForAbsence(t, modelStr, "ColorConfigValue")
+	checkForAbsence(t, modelStr, "type Color interface{}")
+
+	// Each struct has a single UnmarshalJSON, and nested arrays are parsed row by row
+	if count := strings.Count(modelStr, "func (j *Origin) UnmarshalJSON("); count != 1 {
+		t.Errorf("Expected one UnmarshalJSON for Origin, found %d", count)
+	}
+	checkForContent(t, modelStr, "Colors []ColorSlice")
+	checkForContent(t, modelStr, "colors := make([]ColorSlice, 0, len(rows))")
 
-	// Check unmarshal file doesn't contain functions it shouldn't
-	checkForAbsence(t, unmarshalStr, "(j *MatrixPalette) UnmarshalJSON(")
+	// Constant fields are replaced by their accessors
+	checkForAbsence(t, modelStr, "Model *string")
 
 	// Check unmarshal file contains specific validation
-	checkForContent(t, unmarshalStr, "if _, ok := raw[\"semantic\"]; raw != nil && !ok {")
+	checkForContent(t, modelStr, "if _, ok := raw[\"semantic\"]; raw != nil && !ok {")
 }
 
 func TestCodeGenerator_GenerateConfusing(t *testing.T) {
@@ -191,9 +186,7 @@ func TestCodeGenerator_GenerateConfusing(t *testing.T) {
 
 	// Verify output files were created
 	expectedFiles := []string{
-		filepath.Join(outputDir, "model_enhanced.gen.go"),
-		filepath.Join(outputDir, "model_interfaces.gen.go"),
-		filepath.Join(outputDir, "model_unmarshal.gen.go"),
+		filepath.Join(outputDir, "model.gen.go"),
 	}
 
 	for _, file := range expectedFiles {
@@ -205,55 +198,98 @@ func TestCodeGenerator_GenerateConfusing(t *testing.T) {
 	// Test specific functionality based on steps in postprocess.mdc
 
 	// Step 1: Check if interfaces are properly generated
-	interfacesContent, err := os.ReadFile(filepath.Join(outputDir, "model_interfaces.gen.go"))
-	if err != nil {
-		t.Fatalf("Failed to read interfaces file: %v", err)
-	}
-
-	interfacesStr := string(interfacesContent)
+	modelStr := readModel(t, outputDir)
 
 	// Check for interface declaration and methods
-	checkForContent(t, interfacesStr, "type Horse interface {")
-	checkForContent(t, interfacesStr, "isHorse()")
-	checkForContent(t, interfacesStr, "Rice() HorseModel")
+	checkForContent(t, modelStr, "type Horse interface {")
+	checkForContent(t, modelStr, "isHorse()")
+	checkForContent(t, modelStr, "Rice() HorseModel")
 
 	// Check for implementation methods - just check a couple
-	checkForContent(t, interfacesStr, "func (me *HSLVarient) isHorse()")
-	checkForContent(t, interfacesStr, "func (me *RGBVarient) isHorse()")
+	checkForContent(t, modelStr, "func (me *HSLVarient) isHorse()")
+	checkForContent(t, modelStr, "func (me *RGBVarient) isHorse()")
 
 	// Check for constant type definitions
-	checkForContent(t, interfacesStr, "type HorseModel string")
-	checkForContent(t, interfacesStr, "const (")
-	checkForContent(t, interfacesStr, "HorseModelHsl")
+	checkForContent(t, modelStr, "type HorseModel string")
+	checkForContent(t, modelStr, "const (")
+	checkForContent(t, modelStr, "HorseModelHsl")
 
 	// Step 2: Check if parseUnknown functions are generated
-	unmarshalContent, err := os.ReadFile(filepath.Join(outputDir, "model_unmarshal.gen.go"))
-	if err != nil {
-		t.Fatalf("Failed to read unmarshal file: %v", err)
-	}
-
-	unmarshalStr := string(unmarshalContent)
 
 	// Check for parseUnknown functions
-	checkForContent(t, unmarshalStr, "func parseUnknownHorse(b interface{}) (Horse, error)")
+	checkForContent(t, modelStr, "func parseUnknownHorse(b interface{}) (Horse, error)")
 
 	// Check for the use of the "rice" field instead of "model"
-	checkForContent(t, unmarshalStr, "Rice HorseModel `json:\"rice\"")
+	checkForContent(t, modelStr, "Rice HorseModel `json:\"rice\"")
 
 	// Step 3: Check for MarshalJSON methods - just verify the function exists
-	checkForContent(t, unmarshalStr, "func (j LCHVarient) MarshalJSON()")
+	checkForContent(t, modelStr, "func (j LCHVarient) MarshalJSON()")
 
-	// Step 4: Check type adjustments in enhanced model
-	enhancedContent, err := os.ReadFile(filepath.Join(outputDir, "model_enhanced.gen.go"))
+	// Step 4: Check type adjustments in the model
+	// Check for proper field type modifications - 'Ices' is the equivalent of 'Colors' in confusing schema
+	checkForContent(t, modelStr, "Ices HorseSlice")
+	checkForContent(t, modelStr, "Rope Horse")
+}
+
+func TestCodeGenerator_GenerateNested(t *testing.T) {
+	// Set up paths
+	schemaPath := filepath.Join("testdata", "nested", "nested.schema.json")
+	modelPath := filepath.Join("testdata", "nested", "model.gen.go")
+
+	tmpDir := t.TempDir()
+	outputDir := filepath.Join(tmpDir, "output")
+	// Ensure output directory exists
+	err := os.MkdirAll(outputDir, 0755)
 	if err != nil {
-		t.Fatalf("Failed to read enhanced model file: %v", err)
+		t.Fatalf("Failed to create output directory: %v", err)
 	}
 
-	enhancedStr := string(enhancedContent)
+	// Create analyzer and get results
+	analyzer, err := NewSchemaAnalyzer(schemaPath)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
 
-	// Check for proper field type modifications - 'Ices' is the equivalent of 'Colors' in confusing schema
-	checkForContent(t, enhancedStr, "Ices HorseSlice")
-	checkForContent(t, enhancedStr, "Rope Horse")
+	results, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Failed to analyze schema: %v", err)
+	}
+
+	// Create and run generator
+	generator := NewCodeGenerator(modelPath, outputDir, results)
+	err = generator.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate code: %v", err)
+	}
+
+	modelStr := readModel(t, outputDir)
+
+	// Color embeds Fill, so every Color satisfies Fill
+	checkForContent(t, modelStr, "type Color interface {\n\tFill\n\tisColor()")
+	checkForContent(t, modelStr, "Kind() ColorModel")
+	checkForAbsence(t, modelStr, "Kind() FillModel\n}")
+
+	// The leaves of Color implement the Fill marker directly
+	checkForContent(t, modelStr, "func (me *HSLColor) isFill()")
+	checkForContent(t, modelStr, "func (me *RGBColor) isFill()")
+	checkForContent(t, modelStr, "func (me *Gradient) isFill()")
+	checkForContent(t, modelStr, "func (me *Gradient) Kind() FillModel")
+	checkForAbsence(t, modelStr, "func (me *HSLColor) Kind() FillModel")
+
+	// Fill dispatches through the Color parse function
+	checkForContent(t, modelStr, "func parseUnknownFill(b interface{}) (Fill, error)")
+	checkForContent(t, modelStr, "return parseColorRaw(data)")
+	checkForContent(t, modelStr, "func (j Gradient) MarshalJSON() ([]byte, error)")
+	if strings.Count(modelStr, "func (j HSLColor) MarshalJSON()") != 1 {
+		t.Errorf("Expected exactly one MarshalJSON for HSLColor")
+	}
+
+	// Gradient is both a Fill child and a caller, so its constant marshaler
+	// runs the caller checks instead of getting a second MarshalJSON
+	if strings.Count(modelStr, "func (j Gradient) MarshalJSON()") != 1 {
+		t.Errorf("Expected exactly one MarshalJSON for Gradient")
+	}
+	checkForContent(t, modelStr, "return nil, fmt.Errorf(\"field stops in Gradient: item %d is nil\", i)")
 }
 
 func TestJSONRoundTrip(t *testing.T) {
@@ -293,36 +329,32 @@ func TestJSONRoundTrip(t *testing.T) {
 	// so we'll verify the generation of the serialization code
 
 	// Verify marshaling functions for constants
-	unmarshalContent, err := os.ReadFile(filepath.Join(outputDir, "model_unmarshal.gen.go"))
-	if err != nil {
-		t.Fatalf("Failed to read unmarshal file: %v", err)
-	}
-	unmarshalStr := string(unmarshalContent)
+	modelStr := readModel(t, outputDir)
 
 	// Verify the structure of the MarshalJSON methods - just check a few
 	allColorTypes := []string{"HSLValue", "RGBValue", "LCHValue"}
 	for _, colorType := range allColorTypes {
 		// Check that each color type has a MarshalJSON method
-		checkForContent(t, unmarshalStr, fmt.Sprintf("func (j %s) MarshalJSON()", colorType))
+		checkForContent(t, modelStr, fmt.Sprintf("func (j %s) MarshalJSON()", colorType))
 	}
 
 	// Check basic marshaling elements
-	checkForContent(t, unmarshalStr, "Model: j.Model()")
-	checkForContent(t, unmarshalStr, "Plain: Plain(j)")
+	checkForContent(t, modelStr, "Model: j.Model()")
+	checkForContent(t, modelStr, "Plain: Plain(j)")
 
 	// Verify the parseUnknown functions that would handle unmarshaling
-	checkForContent(t, unmarshalStr, "func parseUnknownColor(b interface{}) (Color, error)")
-	checkForContent(t, unmarshalStr, "switch plain.Model {")
+	checkForContent(t, modelStr, "func parseUnknownColor(b interface{}) (Color, error)")
+	checkForContent(t, modelStr, "switch constant {")
 
 	// Verify that a few cases in the switch statement correctly handle specific color types
 	// Just pick a few representative cases
-	checkForContent(t, unmarshalStr, "case ColorModelHsl:")
-	checkForContent(t, unmarshalStr, "case ColorModelRgb:")
+	checkForContent(t, modelStr, "case ColorModelHsl:")
+	checkForContent(t, modelStr, "case ColorModelRgb:")
 
 	// Check for modified UnmarshalJSON methods in parent callers
-	checkForContent(t, unmarshalStr, "func (j *ColorConfig) UnmarshalJSON(b []byte) error")
-	checkForContent(t, unmarshalStr, "parsed, err := parseUnknownColor(raw[\"value\"])")
-	checkForContent(t, unmarshalStr, "plain.Value = parsed")
+	checkForContent(t, modelStr, "func (j *ColorConfig) UnmarshalJSON(b []byte) error")
+	checkForContent(t, modelStr, "parsed, err := parseColorRaw(raw[\"value\"])")
+	checkForContent(t, modelStr, "plain.Value = parsed")
 }
 
 func TestGeneratedCodeValidation(t *testing.T) {
@@ -346,6 +378,26 @@ func TestGeneratedCodeValidation(t *testing.T) {
 			schemaPath: filepath.Join("testdata", "simple", "simple.schema.json"),
 			modelPath:  filepath.Join("testdata", "simple", "model.gen.go"),
 		},
+		{
+			name:       "NestedSchema",
+			schemaPath: filepath.Join("testdata", "nested", "nested.schema.json"),
+			modelPath:  filepath.Join("testdata", "nested", "model.gen.go"),
+		},
+		{
+			name:       "DiscriminatorsSchema",
+			schemaPath: filepath.Join("testdata", "discriminators", "discriminators.schema.json"),
+			modelPath:  filepath.Join("testdata", "discriminators", "model.gen.go"),
+		},
+		{
+			name:       "OverlapSchema",
+			schemaPath: filepath.Join("testdata", "overlap", "overlap.schema.json"),
+			modelPath:  filepath.Join("testdata", "overlap", "model.gen.go"),
+		},
+		{
+			name:       "OptionalSchema",
+			schemaPath: filepath.Join("testdata", "optional", "optional.schema.json"),
+			modelPath:  filepath.Join("testdata", "optional", "model.gen.go"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -378,9 +430,7 @@ func TestGeneratedCodeValidation(t *testing.T) {
 
 			// List all generated files
 			outputFiles := []string{
-				filepath.Join(outputDir, "model_enhanced.gen.go"),
-				filepath.Join(outputDir, "model_interfaces.gen.go"),
-				filepath.Join(outputDir, "model_unmarshal.gen.go"),
+				filepath.Join(outputDir, "model.gen.go"),
 			}
 
 			for _, file := range outputFiles {
@@ -398,6 +448,9 @@ func TestGeneratedCodeValidation(t *testing.T) {
 
 			// Check for package name consistency
 			verifyPackageConsistency(t, outputFiles)
+
+			// The merged model must compile on its own
+			validateGoTypes(t, outputDir)
 		})
 	}
 }
@@ -444,6 +497,81 @@ func verifyPackageConsistency(t *testing.T, files []string) {
 	}
 }
 
+// readModel reads the merged model the generator wrote to outputDir
+func readModel(t *testing.T, outputDir string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "model.gen.go"))
+	if err != nil {
+		t.Fatalf("Failed to read model file: %v", err)
+	}
+	return string(content)
+}
+
+// validateGoTypes type-checks the Go files in dir as a single package
+func validateGoTypes(t *testing.T, dir string) {
+	t.Helper()
+
+	paths, err := packageFiles(dir)
+	if err != nil {
+		t.Fatalf("Failed to list files in %s: %v", dir, err)
+	}
+
+	fset := token.NewFileSet()
+	files, err := parseFiles(fset, paths)
+	if err != nil {
+		t.Fatalf("Failed to parse %s: %v", dir, err)
+	}
+
+	// The output directory is outside the module, so resolve imports from here
+	typeErrors, err := checkPackage(fset, files, ".")
+	if err != nil {
+		t.Fatalf("Failed to type-check %s: %v", dir, err)
+	}
+	for _, typeErr := range typeErrors {
+		t.Errorf("Generated package does not compile: %v", typeErr)
+	}
+}
+
+// moduleTempDir creates a directory for generated packages inside the module,
+// where the models' imports resolve, under testdata so that nothing else builds them
+func moduleTempDir(t *testing.T) string {
+	t.Helper()
+
+	dir, err := os.MkdirTemp("testdata", "run-")
+	if err != nil {
+		t.Fatalf("Failed to create package directory: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+// generatePackage processes a schema, with a model go-jsonschema generates,
+// into the package name inside the module, returning its directory
+func generatePackage(t *testing.T, schemaPath, name string) string {
+	t.Helper()
+
+	dir := filepath.Join(moduleTempDir(t), name)
+	processor := NewProcessor(schemaPath, filepath.Join(dir, "model.go"), dir)
+	processor.GenerateModel = true
+	if err := processor.Process(); err != nil {
+		t.Fatalf("Failed to process %s: %v", schemaPath, err)
+	}
+	return dir
+}
+
+// runGoTest adds test to the package in dir and runs it with go test
+func runGoTest(t *testing.T, dir, test string) {
+	t.Helper()
+
+	if err := os.WriteFile(filepath.Join(dir, "generated_test.go"), []byte(test), 0644); err != nil {
+		t.Fatalf("Failed to write test: %v", err)
+	}
+	if out, err := exec.Command("go", "test", "./"+filepath.ToSlash(dir)).CombinedOutput(); err != nil {
+		t.Fatalf("Test of the generated package failed: %v\n%s", err, out)
+	}
+}
+
 func checkForContent(t *testing.T, content, expected string) {
 	if !contains(content, expected) {
 		t.Errorf("Expected content not found: %s", expected)
@@ -0,0 +1,610 @@
+package repostprocess
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gitlab.com/tozd/go/errors"
+)
+
+// generatedHeader starts every file the postprocessor writes
+const generatedHeader = "// Code generated by json-schema-postprocess. DO NOT EDIT."
+
+// modelFile is the parsed go-jsonschema model that the generated code is merged into
+type modelFile struct {
+	fset  *token.FileSet
+	file  *ast.File
+	types map[string]*ast.TypeSpec
+	// methods maps a receiver type name to its methods by name
+	methods map[string]map[string]*ast.FuncDecl
+	// replaced holds the placeholder types of the caller fields, once they
+	// point at the parent types
+	replaced map[string]bool
+	// collections holds the generated slice and map types of the parents
+	collections map[string]bool
+	// constantFields maps each concrete child to the Go name of the constant
+	// field the merge drops in favor of its accessor
+	constantFields map[string]string
+}
+
+// parseModelFile parses the model file and indexes its types and methods
+func parseModelFile(modelPath string) (*modelFile, error) {
+	content, err := os.ReadFile(modelPath)
+	if err != nil {
+		return nil, errors.Errorf("reading model file: %w", err)
+	}
+	return parseModelSource(modelPath, content)
+}
+
+// parseModelSource parses the source of a model, named modelPath, and indexes
+// its types and methods
+func parseModelSource(modelPath string, content []byte) (*modelFile, error) {
+	// Merging again would wrap the parents a second time
+	if bytes.HasPrefix(content, []byte(generatedHeader)) {
+		return nil, errors.Errorf("model %s was already postprocessed, regenerate it with go-jsonschema first", modelPath)
+	}
+	return indexModel(modelPath, content)
+}
+
+// indexModel parses the source of a model, whether postprocessed or not, and
+// indexes its types and methods
+func indexModel(modelPath string, content []byte) (*modelFile, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, modelPath, content, parser.ParseComments)
+	if err != nil {
+		return nil, errors.Errorf("parsing model file: %w", err)
+	}
+
+	model := &modelFile{
+		fset:    fset,
+		file:    file,
+		types:   make(map[string]*ast.TypeSpec),
+		methods: make(map[string]map[string]*ast.FuncDecl),
+	}
+
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+					model.types[typeSpec.Name.Name] = typeSpec
+				}
+			}
+		case *ast.FuncDecl:
+			if recv := receiverTypeName(decl); recv != "" {
+				if model.methods[recv] == nil {
+					model.methods[recv] = make(map[string]*ast.FuncDecl)
+				}
+				model.methods[recv][decl.Name.Name] = decl
+			}
+		}
+	}
+
+	return model, nil
+}
+
+// receiverTypeName returns the name of a method's receiver type, or "" for functions
+func receiverTypeName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	expr := fn.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// jsonFieldName returns the name in a field's json tag
+func jsonFieldName(field *ast.Field) string {
+	if field.Tag == nil {
+		return ""
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	name, _, _ := strings.Cut(reflect.StructTag(tag).Get("json"), ",")
+	return name
+}
+
+// structField returns the field of a struct type with the given JSON name
+func (m *modelFile) structField(typeName, jsonName string) (*ast.Field, bool) {
+	typeSpec, ok := m.types[typeName]
+	if !ok {
+		return nil, false
+	}
+	structType, ok := typeSpec.Type.(*ast.StructType)
+	if !ok {
+		return nil, false
+	}
+	for _, field := range structType.Fields.List {
+		if len(field.Names) == 1 && jsonFieldName(field) == jsonName {
+			return field, true
+		}
+	}
+	return nil, false
+}
+
+// rootStruct returns the struct generated for the root schema: the one with a
+// field for every root property and the fewest other fields
+func (m *modelFile) rootStruct(rootProperties []string) (string, bool) {
+	best, bestExtra := "", -1
+	for _, name := range sortedTypeNames(m.types) {
+		structType, ok := m.types[name].Type.(*ast.StructType)
+		if !ok {
+			continue
+		}
+
+		matched := 0
+		for _, prop := range rootProperties {
+			if _, ok := m.structField(name, prop); ok {
+				matched++
+			}
+		}
+		if matched != len(rootProperties) {
+			continue
+		}
+
+		extra := len(structType.Fields.List) - matched
+		if bestExtra < 0 || extra < bestExtra {
+			best, bestExtra = name, extra
+		}
+	}
+	return best, bestExtra >= 0
+}
+
+// resolveCaller returns the name of the struct holding a caller's field, and the field
+func (m *modelFile) resolveCaller(caller ParentCallerInfo, rootProperties []string) (string, *ast.Field, error) {
+	structName := caller.Name
+	if caller.Root {
+		root, ok := m.rootStruct(rootProperties)
+		if !ok {
+			return "", nil, errors.Errorf("no struct in the model holds the root properties %s", strings.Join(rootProperties, ", "))
+		}
+
+		// Inline objects are named after the field holding them, so follow the path
+		structName = root
+		for _, prop := range caller.ObjectPath {
+			field, ok := m.structField(structName, prop)
+			if !ok {
+				return "", nil, errors.Errorf("no field %s in %s", prop, structName)
+			}
+			next, ok := baseTypeName(field.Type)
+			if !ok {
+				return "", nil, errors.Errorf("field %s in %s is not a named struct", prop, structName)
+			}
+			structName = next
+		}
+	}
+
+	field, ok := m.structField(structName, caller.Field)
+	if !ok {
+		return "", nil, errors.Errorf("no field %s in %s", caller.Field, structName)
+	}
+	return structName, field, nil
+}
+
+// baseTypeName returns the named type at the bottom of pointers, slices and maps
+func baseTypeName(expr ast.Expr) (string, bool) {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ArrayType:
+			expr = e.Elt
+		case *ast.MapType:
+			expr = e.Value
+		case *ast.Ident:
+			return e.Name, true
+		default:
+			return "", false
+		}
+	}
+}
+
+// generatedFile is a rendered template parsed back into declarations
+type generatedFile struct {
+	src  []byte
+	file *ast.File
+}
+
+// parseGenerated parses rendered code so its declarations can be merged
+func parseGenerated(name string, src []byte) (*generatedFile, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), name, src, parser.ParseComments)
+	if err != nil {
+		return nil, errors.Errorf("parsing rendered %s: %w", name, err)
+	}
+	return &generatedFile{src: src, file: file}, nil
+}
+
+// declarations returns the source of everything after the package clause and imports
+func (g *generatedFile) declarations() string {
+	for _, decl := range g.file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			continue
+		}
+		start := decl.Pos()
+		if doc := declDoc(decl); doc != nil {
+			start = doc.Pos()
+		}
+		// Each file has its own set, so positions are 1-based offsets into src
+		return string(g.src[start-1:])
+	}
+	return ""
+}
+
+// declDoc returns the doc comment of a declaration
+func declDoc(decl ast.Decl) *ast.CommentGroup {
+	switch decl := decl.(type) {
+	case *ast.GenDecl:
+		return decl.Doc
+	case *ast.FuncDecl:
+		return decl.Doc
+	}
+	return nil
+}
+
+// generatedNames records what the rendered code declares, so model declarations
+// with the same names can be replaced
+type generatedNames struct {
+	types   map[string]bool
+	funcs   map[string]bool
+	methods map[string]map[string]bool
+}
+
+// collectGeneratedNames records the top-level declarations of the rendered files
+func collectGeneratedNames(files ...*generatedFile) generatedNames {
+	names := generatedNames{
+		types:   make(map[string]bool),
+		funcs:   make(map[string]bool),
+		methods: make(map[string]map[string]bool),
+	}
+	for _, g := range files {
+		for _, decl := range g.file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+						names.types[typeSpec.Name.Name] = true
+					}
+				}
+			case *ast.FuncDecl:
+				recv := receiverTypeName(decl)
+				if recv == "" {
+					names.funcs[decl.Name.Name] = true
+					continue
+				}
+				if names.methods[recv] == nil {
+					names.methods[recv] = make(map[string]bool)
+				}
+				names.methods[recv][decl.Name.Name] = true
+			}
+		}
+	}
+	return names
+}
+
+// posRange is a span of source whose comments should not be printed
+type posRange struct {
+	start, end token.Pos
+}
+
+// mergeModel rewrites the model so it can hold the generated code and returns
+// the source of the single merged file: parent types and conflicting methods
+// are replaced by the generated ones, constant fields by their accessors,
+// caller fields use the parent types, and placeholder types that are no longer
+// used are dropped
+func (cg *CodeGenerator) mergeModel(model *modelFile, data fileData, files ...*generatedFile) ([]byte, error) {
+	names := collectGeneratedNames(files...)
+	var dropped []posRange
+
+	// Drop the constant fields replaced by their accessors, whatever the
+	// accessors are named. Any other field a generated method is named after
+	// holds data, so it's an error rather than something to drop
+	for _, typeName := range sortedTypeNames(model.types) {
+		methods := names.methods[typeName]
+		constantField, hasConstant := model.constantFields[typeName]
+		if methods == nil && !hasConstant {
+			continue
+		}
+		structType, ok := model.types[typeName].Type.(*ast.StructType)
+		if !ok {
+			continue
+		}
+		kept := structType.Fields.List[:0]
+		for _, field := range structType.Fields.List {
+			if len(field.Names) == 1 && hasConstant && field.Names[0].Name == constantField {
+				dropped = append(dropped, fieldRange(field))
+				continue
+			}
+			for _, ident := range field.Names {
+				if methods[ident.Name] {
+					return nil, errors.Errorf("field %s.%s has the name of a generated method", typeName, ident.Name)
+				}
+			}
+			kept = append(kept, field)
+		}
+		structType.Fields.List = kept
+	}
+
+	// Keep the model declarations that the generated code doesn't replace
+	var decls []ast.Decl
+	for _, decl := range model.file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			recv := receiverTypeName(decl)
+			if (recv == "" && names.funcs[decl.Name.Name]) || names.methods[recv][decl.Name.Name] {
+				continue
+			}
+			decls = append(decls, decl)
+		case *ast.GenDecl:
+			if decl.Tok == token.IMPORT {
+				continue
+			}
+			if decl.Tok != token.TYPE {
+				decls = append(decls, decl)
+				continue
+			}
+			specs := decl.Specs[:0]
+			for _, spec := range decl.Specs {
+				if typeSpec, ok := spec.(*ast.TypeSpec); ok && names.types[typeSpec.Name.Name] {
+					continue
+				}
+				specs = append(specs, spec)
+			}
+			if len(specs) > 0 {
+				decl.Specs = specs
+				decls = append(decls, decl)
+			}
+		default:
+			decls = append(decls, decl)
+		}
+	}
+
+	// Drop the placeholder types the caller fields used to have, once nothing refers to them
+	decls = dropUnusedTypes(decls, model.replaced, files)
+
+	var body strings.Builder
+	for _, decl := range decls {
+		code, err := model.printDecl(decl, dropped)
+		if err != nil {
+			return nil, err
+		}
+		body.WriteString(code)
+		body.WriteString("\n\n")
+	}
+	for _, g := range files {
+		body.WriteString(g.declarations())
+		body.WriteString("\n")
+	}
+
+	imports, err := usedImports(data.Package, body.String(), model.file, files)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	out.WriteString(generatedHeader + "\n\n")
+	out.WriteString("package " + data.Package + "\n\n")
+	if len(imports) > 0 {
+		out.WriteString("import (\n")
+		for _, imp := range imports {
+			out.WriteString("\t" + imp + "\n")
+		}
+		out.WriteString(")\n\n")
+	}
+	out.WriteString(body.String())
+
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, errors.Errorf("formatting merged model: %w", err)
+	}
+	return formatted, nil
+}
+
+// retypeCallerFields points the caller fields at the parent types, keeping the
+// placeholder types they had so the merge can drop them once they're unused
+func (m *modelFile) retypeCallerFields(callers []callerData) {
+	m.replaced = make(map[string]bool)
+	m.collections = make(map[string]bool)
+	for _, caller := range callers {
+		for _, f := range caller.Fields {
+			field, ok := m.structField(caller.Struct, f.Field)
+			if !ok {
+				continue
+			}
+			if name, ok := baseTypeName(field.Type); ok {
+				m.replaced[name] = true
+			}
+			switch {
+			case f.IsNestedArray:
+				field.Type = &ast.ArrayType{Elt: ast.NewIdent(f.Parent + "Slice")}
+			case f.IsArray:
+				field.Type = ast.NewIdent(f.Parent + "Slice")
+			case f.IsMap:
+				field.Type = ast.NewIdent(f.Parent + "Map")
+			default:
+				field.Type = ast.NewIdent(f.Parent)
+			}
+			m.collections[f.Parent+"Slice"] = true
+			m.collections[f.Parent+"Map"] = true
+		}
+	}
+}
+
+// fieldRange returns the span of a struct field and its comments
+func fieldRange(field *ast.Field) posRange {
+	r := posRange{start: field.Pos(), end: field.End()}
+	if field.Doc != nil {
+		r.start = field.Doc.Pos()
+	}
+	if field.Comment != nil {
+		r.end = field.Comment.End()
+	}
+	return r
+}
+
+// dropUnusedTypes removes the named type declarations that nothing else refers to
+func dropUnusedTypes(decls []ast.Decl, candidates map[string]bool, files []*generatedFile) []ast.Decl {
+	used := make(map[string]bool)
+	markUsed := func(node ast.Node, self string) {
+		ast.Inspect(node, func(n ast.Node) bool {
+			if ident, ok := n.(*ast.Ident); ok && ident.Name != self {
+				used[ident.Name] = true
+			}
+			return true
+		})
+	}
+	for _, decl := range decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.TYPE {
+			for _, spec := range gen.Specs {
+				if typeSpec, ok := spec.(*ast.TypeSpec); ok {
+					markUsed(typeSpec.Type, typeSpec.Name.Name)
+				}
+			}
+			continue
+		}
+		markUsed(decl, "")
+	}
+	for _, g := range files {
+		markUsed(g.file, "")
+	}
+
+	var kept []ast.Decl
+	for _, decl := range decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			if recv := receiverTypeName(fn); candidates[recv] && !used[recv] {
+				continue
+			}
+		}
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			kept = append(kept, decl)
+			continue
+		}
+		specs := gen.Specs[:0]
+		for _, spec := range gen.Specs {
+			if typeSpec, ok := spec.(*ast.TypeSpec); ok && candidates[typeSpec.Name.Name] && !used[typeSpec.Name.Name] {
+				continue
+			}
+			specs = append(specs, spec)
+		}
+		if len(specs) > 0 {
+			gen.Specs = specs
+			kept = append(kept, gen)
+		}
+	}
+	return kept
+}
+
+// printDecl prints a model declaration with its comments, leaving out those in dropped
+func (m *modelFile) printDecl(decl ast.Decl, dropped []posRange) (string, error) {
+	start := decl.Pos()
+	if doc := declDoc(decl); doc != nil {
+		start = doc.Pos()
+	}
+
+	var comments []*ast.CommentGroup
+	for _, group := range m.file.Comments {
+		if group.Pos() < start || group.End() > decl.End() {
+			continue
+		}
+		skip := false
+		for _, r := range dropped {
+			if group.Pos() >= r.start && group.End() <= r.end {
+				skip = true
+				break
+			}
+		}
+		if !skip {
+			comments = append(comments, group)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, m.fset, &printer.CommentedNode{Node: decl, Comments: comments}); err != nil {
+		return "", errors.Errorf("printing model declaration: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// usedImports returns the import specs, from the model and the rendered files,
+// whose packages the merged body refers to
+func usedImports(packageName, body string, model *ast.File, files []*generatedFile) ([]string, error) {
+	parsed, err := parser.ParseFile(token.NewFileSet(), "merged.go", "package "+packageName+"\n\n"+body, 0)
+	if err != nil {
+		return nil, errors.Errorf("parsing merged model: %w", err)
+	}
+
+	used := make(map[string]bool)
+	ast.Inspect(parsed, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+
+	specs := append([]*ast.ImportSpec{}, model.Imports...)
+	for _, g := range files {
+		specs = append(specs, g.file.Imports...)
+	}
+
+	seen := make(map[string]bool)
+	var imports []string
+	for _, spec := range specs {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := importName(importPath)
+		line := strconv.Quote(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+			line = name + " " + line
+		}
+		if seen[line] || (name != "_" && !used[name]) {
+			continue
+		}
+		seen[line] = true
+		imports = append(imports, line)
+	}
+	sort.Strings(imports)
+	return imports, nil
+}
+
+// importName guesses the name a package is referred to by from its import path
+func importName(importPath string) string {
+	name := path.Base(importPath)
+	// gopkg.in/yaml.v3 and friends drop their version suffix
+	if i := strings.Index(name, ".v"); i > 0 {
+		name = name[:i]
+	}
+	name = strings.TrimPrefix(name, "go-")
+	return strings.ReplaceAll(name, "-", "")
+}
+
+// sortedTypeNames returns the names of the model's types in sorted order
+func sortedTypeNames(types map[string]*ast.TypeSpec) []string {
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,175 @@
+package repostprocess
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DecisionStep is one check in a decision plan: if every key in Keys is present,
+// the input is Child. A step with no keys matches whatever is left
+type DecisionStep struct {
+	Child string
+	Keys  []string
+}
+
+// childShape is the part of a child schema that decides which object keys it accepts
+type childShape struct {
+	required   []string
+	properties map[string]bool
+	// closed is true when additionalProperties is false, so only declared properties are allowed
+	closed bool
+}
+
+// childShapeOf reads the shape of a definition
+func childShapeOf(definitions map[string]interface{}, name string) childShape {
+	definition, _ := definitions[name].(map[string]interface{})
+	shape := childShape{
+		required:   requiredList(definition),
+		properties: make(map[string]bool),
+	}
+	sort.Strings(shape.required)
+
+	for propName := range definitionProperties(definitions, name) {
+		shape.properties[propName] = true
+	}
+
+	_, hasPatterns := definition["patternProperties"]
+	if additional, ok := definition["additionalProperties"].(bool); ok && !additional && !hasPatterns {
+		shape.closed = true
+	}
+	return shape
+}
+
+// allows reports whether an object matching this shape may contain the key
+func (c childShape) allows(key string) bool {
+	return !c.closed || c.properties[key]
+}
+
+// shapesOverlap reports whether a single object can validly match both shapes,
+// which is the case when every key either requires is allowed by both
+func shapesOverlap(a, b childShape) bool {
+	for _, key := range append(append([]string{}, a.required...), b.required...) {
+		if !a.allows(key) || !b.allows(key) {
+			return false
+		}
+	}
+	return true
+}
+
+// identifyDecisionPlans compares the children of parents without a discriminator,
+// recording the pairs that overlap and, when none do, a plan that picks the child
+// from the keys present instead of trying each one in turn
+func (sa *SchemaAnalyzer) identifyDecisionPlans(definitions map[string]interface{}, results *SchemaResults) error {
+	for _, parentName := range results.SortedParentNames() {
+		parent := results.Parents[parentName]
+		children := parent.LeafChildren
+		if parent.Discriminator != DiscriminatorNone || len(children) < 2 {
+			continue
+		}
+
+		shapes := make(map[string]childShape)
+		for _, childName := range children {
+			shapes[childName] = childShapeOf(definitions, childName)
+		}
+
+		parent.Overlaps = nil
+		for i := range children {
+			for j := i + 1; j < len(children); j++ {
+				if shapesOverlap(shapes[children[i]], shapes[children[j]]) {
+					parent.Overlaps = append(parent.Overlaps, [2]string{children[i], children[j]})
+				}
+			}
+		}
+
+		if len(parent.Overlaps) == 0 {
+			if plan, ok := buildDecisionPlan(children, shapes); ok {
+				parent.Discriminator = DiscriminatorKeys
+				parent.DecisionPlan = plan
+			}
+		}
+		results.Parents[parentName] = parent
+	}
+
+	return nil
+}
+
+// buildDecisionPlan orders the children so each one can be recognized by a set of
+// its required keys that none of the children after it allow. It reports false when
+// no such order exists
+func buildDecisionPlan(children []string, shapes map[string]childShape) ([]DecisionStep, bool) {
+	remaining := append([]string{}, children...)
+	plan := []DecisionStep{}
+
+	for len(remaining) > 1 {
+		picked := -1
+		var keys []string
+		for i, candidate := range remaining {
+			others := append(append([]string{}, remaining[:i]...), remaining[i+1:]...)
+			if found, ok := separatingKeys(shapes[candidate], others, shapes); ok {
+				picked = i
+				keys = found
+				break
+			}
+		}
+		if picked < 0 {
+			return nil, false
+		}
+
+		plan = append(plan, DecisionStep{Child: remaining[picked], Keys: keys})
+		remaining = append(remaining[:picked], remaining[picked+1:]...)
+	}
+
+	// The last child needs no check, since every other child has been ruled out
+	plan = append(plan, DecisionStep{Child: remaining[0]})
+	return plan, true
+}
+
+// separatingKeys greedily picks required keys of the candidate until every other
+// child disallows at least one of them
+func separatingKeys(candidate childShape, others []string, shapes map[string]childShape) ([]string, bool) {
+	uncovered := make(map[string]bool)
+	for _, other := range others {
+		uncovered[other] = true
+	}
+
+	keys := []string{}
+	for len(uncovered) > 0 {
+		bestKey := ""
+		bestCount := 0
+		for _, key := range candidate.required {
+			count := 0
+			for other := range uncovered {
+				if !shapes[other].allows(key) {
+					count++
+				}
+			}
+			if count > bestCount {
+				bestKey = key
+				bestCount = count
+			}
+		}
+		if bestCount == 0 {
+			return nil, false
+		}
+
+		keys = append(keys, bestKey)
+		for other := range uncovered {
+			if !shapes[other].allows(bestKey) {
+				delete(uncovered, other)
+			}
+		}
+	}
+
+	sort.Strings(keys)
+	return keys, true
+}
+
+// describeOverlaps formats overlapping pairs for a diagnostic message
+func describeOverlaps(overlaps [][2]string) string {
+	pairs := make([]string, len(overlaps))
+	for i, pair := range overlaps {
+		pairs[i] = fmt.Sprintf("%s/%s", pair[0], pair[1])
+	}
+	return strings.Join(pairs, ", ")
+}
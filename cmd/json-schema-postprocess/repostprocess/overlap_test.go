@@ -0,0 +1,118 @@
+package repostprocess
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func analyzeOverlapSchema(t *testing.T) *SchemaResults {
+	t.Helper()
+
+	schemaPath := filepath.Join("testdata", "overlap", "overlap.schema.json")
+	analyzer, err := NewSchemaAnalyzer(schemaPath)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+
+	results, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Failed to analyze schema: %v", err)
+	}
+	return results
+}
+
+func TestOverlap_DecisionPlan(t *testing.T) {
+	results := analyzeOverlapSchema(t)
+
+	assetInfo := results.Parents["Asset"]
+	if assetInfo.Discriminator != DiscriminatorKeys {
+		t.Fatalf("Expected Asset to use a decision plan, got '%s'", assetInfo.Discriminator)
+	}
+	if len(assetInfo.Overlaps) != 0 {
+		t.Errorf("Expected no overlapping Asset children, got %v", assetInfo.Overlaps)
+	}
+
+	// Image can't go first, since Video allows every key Image requires
+	expected := []DecisionStep{
+		{Child: "Video", Keys: []string{"duration"}},
+		{Child: "Image", Keys: []string{"width"}},
+		{Child: "Document"},
+	}
+	if !reflect.DeepEqual(assetInfo.DecisionPlan, expected) {
+		t.Errorf("Expected Asset decision plan %v, got %v", expected, assetInfo.DecisionPlan)
+	}
+}
+
+func TestOverlap_OpenChildrenOverlap(t *testing.T) {
+	results := analyzeOverlapSchema(t)
+
+	noteInfo := results.Parents["Note"]
+	if noteInfo.Discriminator != DiscriminatorNone {
+		t.Errorf("Expected Note to have no discriminator, got '%s'", noteInfo.Discriminator)
+	}
+
+	expected := [][2]string{{"TextNote", "TaggedNote"}}
+	if !reflect.DeepEqual(noteInfo.Overlaps, expected) {
+		t.Errorf("Expected Note overlaps %v, got %v", expected, noteInfo.Overlaps)
+	}
+
+	found := false
+	for _, d := range results.Diagnostics {
+		if d.Pointer == "/definitions/Note" && d.Code == DiagnosticOverlappingChildren {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected an overlapping-children diagnostic for Note, got %v", results.Diagnostics)
+	}
+}
+
+func TestOverlap_ShapesOverlap(t *testing.T) {
+	closedA := childShape{required: []string{"a"}, properties: map[string]bool{"a": true}, closed: true}
+	closedB := childShape{required: []string{"b"}, properties: map[string]bool{"b": true}, closed: true}
+	openAB := childShape{required: []string{"a"}, properties: map[string]bool{"a": true, "b": true}}
+
+	if shapesOverlap(closedA, closedB) {
+		t.Error("Expected closed shapes with disjoint required keys not to overlap")
+	}
+	if !shapesOverlap(closedA, openAB) {
+		t.Error("Expected a closed shape to overlap an open shape requiring the same key")
+	}
+	if shapesOverlap(closedB, openAB) {
+		t.Error("Expected a closed shape not to overlap a shape requiring a key it disallows")
+	}
+}
+
+func TestOverlap_NoPlanForCycle(t *testing.T) {
+	// Each child's key rules out only one sibling, so no child can be checked first
+	shapes := map[string]childShape{
+		"A": {required: []string{"a"}, properties: map[string]bool{"a": true, "b": true}, closed: true},
+		"B": {required: []string{"b"}, properties: map[string]bool{"b": true, "c": true}, closed: true},
+		"C": {required: []string{"c"}, properties: map[string]bool{"c": true, "a": true}, closed: true},
+	}
+
+	if plan, ok := buildDecisionPlan([]string{"A", "B", "C"}, shapes); ok {
+		t.Errorf("Expected no plan for cyclic exclusions, got %v", plan)
+	}
+}
+
+func TestOverlap_GeneratedDispatch(t *testing.T) {
+	results := analyzeOverlapSchema(t)
+
+	outputDir := t.TempDir()
+	modelPath := filepath.Join("testdata", "overlap", "model.gen.go")
+	generator := NewCodeGenerator(modelPath, outputDir, results)
+	if err := generator.Generate(); err != nil {
+		t.Fatalf("Failed to generate code: %v", err)
+	}
+
+	modelStr := readModel(t, outputDir)
+
+	checkForContent(t, modelStr, "func hasJSONKeys(data []byte, keys ...string) bool")
+	checkForContent(t, modelStr, "if hasJSONKeys(data, \"duration\") {")
+	checkForContent(t, modelStr, "if hasJSONKeys(data, \"width\") {")
+
+	// Note still has overlapping children, so it keeps trying each one
+	checkForContent(t, modelStr, "opts := []Note{")
+}
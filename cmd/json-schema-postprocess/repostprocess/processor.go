@@ -1,6 +1,12 @@
 package repostprocess
 
 import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
 	"gitlab.com/tozd/go/errors"
 )
 
@@ -9,6 +15,34 @@ type Processor struct {
 	SchemaPath    string
 	ModelPath     string
 	OutputDirPath string
+	// Strict makes warning diagnostics fail processing, not just errors
+	Strict bool
+	// Force writes generated code even when it doesn't type-check
+	Force bool
+	// InPlace replaces the model file itself instead of writing to OutputDirPath
+	InPlace bool
+	// GenerateModel runs go-jsonschema on the schema itself and merges into its
+	// model in memory, so the model file doesn't have to exist beforehand
+	GenerateModel bool
+	// Check compares the generated model with the one on disk instead of
+	// writing it, failing with ErrStale when they differ
+	Check bool
+	// Config holds project-specific settings; nil means none
+	Config *Config
+	// TemplatesDir holds *.tmpl files that override the embedded templates
+	TemplatesDir string
+	// Diagnostics holds the diagnostics reported by the last analysis
+	Diagnostics []Diagnostic
+	// TypeScriptPath is where the TypeScript types of the schema are written,
+	// if anywhere
+	TypeScriptPath string
+	// Proto says where the protobuf definition of the schema and the
+	// converters between the model and its messages are written, if anywhere
+	Proto ProtoConfig
+	// Diff holds the unified diffs of the stale files found by the last check
+	Diff string
+	// stale lists the files the last check found out of date
+	stale []string
 }
 
 // NewProcessor creates a new processor
@@ -23,9 +57,9 @@ func NewProcessor(schemaPath, modelPath, outputDirPath string) *Processor {
 // Process performs the entire postprocessing workflow
 func (p *Processor) Process() error {
 	// Step 1: Analyze the schema
-	analyzer, err := NewSchemaAnalyzer(p.SchemaPath)
+	analyzer, err := p.newAnalyzer()
 	if err != nil {
-		return errors.Errorf("creating schema analyzer: %w", err)
+		return err
 	}
 
 	results, err := analyzer.Analyze()
@@ -34,10 +68,251 @@ func (p *Processor) Process() error {
 	}
 
 	// Step 2: Generate all the code
+	return p.generate(results)
+}
+
+// newAnalyzer loads the schema into an analyzer with the processor's config
+func (p *Processor) newAnalyzer() (*SchemaAnalyzer, error) {
+	analyzer, err := NewSchemaAnalyzer(p.SchemaPath)
+	if err != nil {
+		return nil, errors.Errorf("creating schema analyzer: %w", err)
+	}
+	analyzer.SetConfig(p.Config)
+	return analyzer, nil
+}
+
+// generate checks the analysis diagnostics and then writes the model, along
+// with the TypeScript types and protobuf definition if they're wanted
+func (p *Processor) generate(results *SchemaResults) error {
+	// Report the analysis diagnostics along with any type errors found while generating
+	defer func() { p.Diagnostics = results.Diagnostics }()
+	if err := CheckDiagnostics(results.Diagnostics, p.Strict); err != nil {
+		return errors.Errorf("checking diagnostics: %w", err)
+	}
+	if err := p.Proto.validate(); err != nil {
+		return err
+	}
+
+	var source []byte
+	if p.GenerateModel {
+		var err error
+		source, err = p.baseModel()
+		if err != nil {
+			return err
+		}
+	}
+
+	p.Diff, p.stale = "", nil
+	model, err := p.generateModel(results, source)
+	if err != nil {
+		return err
+	}
+	if p.TypeScriptPath != "" {
+		if err := p.generateTypeScript(results); err != nil {
+			return err
+		}
+	}
+	if p.Proto.File != "" {
+		if err := p.generateProto(results, model); err != nil {
+			return err
+		}
+	}
+
+	if len(p.stale) > 0 {
+		return errors.Errorf("%s: %w", strings.Join(p.stale, ", "), ErrStale)
+	}
+	return nil
+}
+
+// generateModel merges the generated code into the model and writes it, or
+// compares it with the model on disk when checking. It returns the merged model
+func (p *Processor) generateModel(results *SchemaResults, source []byte) ([]byte, error) {
+	if p.Check {
+		return p.check(results, source)
+	}
+
+	if p.InPlace {
+		return p.generateInPlace(results, source)
+	}
+
+	if err := os.MkdirAll(p.OutputDirPath, 0755); err != nil {
+		return nil, errors.Errorf("creating output directory: %w", err)
+	}
+
 	generator := NewCodeGenerator(p.ModelPath, p.OutputDirPath, results)
+	generator.SetModelSource(source)
+	generator.SetTemplatesDir(p.TemplatesDir)
+	generator.SetConfig(p.Config)
+	generator.SetSkipFiles(p.skipFiles()...)
+	generator.SetForce(p.Force)
 	if err := generator.Generate(); err != nil {
-		return errors.Errorf("generating code: %w", err)
+		return nil, errors.Errorf("generating code: %w", err)
+	}
+
+	model, err := os.ReadFile(p.CheckedPath())
+	if err != nil {
+		return nil, errors.Errorf("reading generated model: %w", err)
 	}
+	return model, nil
+}
+
+// skipFiles returns the files of the model's package generated from the model,
+// which the model is type-checked without, since they're only regenerated after it
+func (p *Processor) skipFiles() []string {
+	if p.Proto.Converters == "" || filepath.Clean(filepath.Dir(p.Proto.Converters)) != filepath.Clean(filepath.Dir(p.CheckedPath())) {
+		return nil
+	}
+	return []string{filepath.Base(p.Proto.Converters)}
+}
 
+// baseModel runs go-jsonschema on the schema, returning the model it generates
+func (p *Processor) baseModel() ([]byte, error) {
+	packageName, err := modelPackageName(p.ModelPath, p.Config)
+	if err != nil {
+		return nil, err
+	}
+	source, err := GenerateModel(p.SchemaPath, p.ModelPath, packageName)
+	if err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+// generateInPlace generates into a staging directory inside the model's package,
+// type-checking the model together with the rest of the package, and only then
+// renames it over the model, so a failed run leaves the package untouched. With
+// a generated source, the model file and its package are created if need be.
+// It returns the merged model
+func (p *Processor) generateInPlace(results *SchemaResults, source []byte) ([]byte, error) {
+	pkgDir := filepath.Dir(p.ModelPath)
+	modelName := filepath.Base(p.ModelPath)
+
+	mode := os.FileMode(0644)
+	info, err := os.Stat(p.ModelPath)
+	switch {
+	case err == nil:
+		mode = info.Mode().Perm()
+	case source == nil || !os.IsNotExist(err):
+		return nil, errors.Errorf("reading model file: %w", err)
+	default:
+		if err := os.MkdirAll(pkgDir, 0755); err != nil {
+			return nil, errors.Errorf("creating model directory: %w", err)
+		}
+	}
+
+	// The leading dot hides the staging directory from the go tool, and keeping
+	// it on the model's filesystem makes the final rename atomic
+	stageDir, err := os.MkdirTemp(pkgDir, ".postprocess-")
+	if err != nil {
+		return nil, errors.Errorf("creating staging directory: %w", err)
+	}
+	defer os.RemoveAll(stageDir)
+
+	generator := NewCodeGenerator(p.ModelPath, stageDir, results)
+	generator.SetModelSource(source)
+	generator.SetTemplatesDir(p.TemplatesDir)
+	generator.SetConfig(p.Config)
+	generator.SetPackageDir(pkgDir)
+	generator.SetSkipFiles(p.skipFiles()...)
+	generator.SetForce(p.Force)
+	if err := generator.Generate(); err != nil {
+		return nil, errors.Errorf("generating code: %w", err)
+	}
+
+	staged := filepath.Join(stageDir, modelName)
+	model, err := os.ReadFile(staged)
+	if err != nil {
+		return nil, errors.Errorf("reading generated model: %w", err)
+	}
+
+	if err := os.Chmod(staged, mode); err != nil {
+		return nil, errors.Errorf("setting model file mode: %w", err)
+	}
+	if err := os.Rename(staged, p.ModelPath); err != nil {
+		return nil, errors.Errorf("replacing model file: %w", err)
+	}
+
+	return model, nil
+}
+
+// generateTypeScript writes the TypeScript types of the schema, or compares
+// them with the file on disk when checking
+func (p *Processor) generateTypeScript(results *SchemaResults) error {
+	generator := NewTypeScriptGenerator(results, RootTypeName(p.SchemaPath))
+	generator.SetConfig(p.Config)
+	generator.SetTemplatesDir(p.TemplatesDir)
+	code, err := generator.Generate()
+	if err != nil {
+		return errors.Errorf("generating TypeScript: %w", err)
+	}
+
+	return p.writeGenerated(p.TypeScriptPath, code, "TypeScript")
+}
+
+// generateProto writes the protobuf definition of the schema and the
+// converters between the model and its messages, or compares them with the
+// files on disk when checking
+func (p *Processor) generateProto(results *SchemaResults, model []byte) error {
+	packageName := p.Proto.Package
+	if packageName == "" {
+		file, err := parser.ParseFile(token.NewFileSet(), p.ModelPath, model, parser.PackageClauseOnly)
+		if err != nil {
+			return errors.Errorf("parsing generated model: %w", err)
+		}
+		packageName = file.Name.Name
+	}
+	for _, part := range strings.Split(packageName, ".") {
+		if !isIdentifier(part) {
+			return errors.Errorf("proto package %q isn't a dotted name", packageName)
+		}
+	}
+
+	generator := NewProtoGenerator(results, RootTypeName(p.SchemaPath), packageName)
+	generator.SetConfig(p.Config)
+	generator.SetTemplatesDir(p.TemplatesDir)
+	generator.SetGoPackage(p.Proto.GoPackage)
+	// The numbers already written are kept, since messages already sent rely on them
+	previous, err := os.ReadFile(p.Proto.File)
+	switch {
+	case err == nil:
+		if err := generator.SetPrevious(previous); err != nil {
+			return errors.Errorf("reading %s: %w", p.Proto.File, err)
+		}
+	case !os.IsNotExist(err):
+		return errors.Errorf("reading protobuf file: %w", err)
+	}
+	code, err := generator.Generate()
+	if err != nil {
+		return errors.Errorf("generating protobuf definition: %w", err)
+	}
+	if err := p.writeGenerated(p.Proto.File, code, "protobuf"); err != nil {
+		return err
+	}
+
+	if p.Proto.Converters == "" {
+		return nil
+	}
+	if len(p.skipFiles()) == 0 {
+		return errors.Errorf("proto converters %s must be in the model's package %s", p.Proto.Converters, filepath.Dir(p.CheckedPath()))
+	}
+	converters, err := generator.GenerateConverters(p.ModelPath, model)
+	if err != nil {
+		return errors.Errorf("generating proto converters: %w", err)
+	}
+	return p.writeGenerated(p.Proto.Converters, converters, "proto converters")
+}
+
+// writeGenerated writes a file generated alongside the model, or compares it
+// with the file on disk when checking
+func (p *Processor) writeGenerated(path string, content []byte, what string) error {
+	if p.Check {
+		return p.compare(path, content)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Errorf("creating %s directory: %w", what, err)
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return errors.Errorf("writing %s file: %w", what, err)
+	}
 	return nil
 }
@@ -30,9 +30,7 @@ func TestProcessor_Process(t *testing.T) {
 
 	// Verify output files were created
 	expectedFiles := []string{
-		filepath.Join(outputDir, "model_enhanced.gen.go"),
-		filepath.Join(outputDir, "model_interfaces.gen.go"),
-		filepath.Join(outputDir, "model_unmarshal.gen.go"),
+		filepath.Join(outputDir, "model.gen.go"),
 	}
 
 	for _, file := range expectedFiles {
@@ -81,9 +79,7 @@ func TestProcessor_ProcessMultipleSchemas(t *testing.T) {
 
 		// Verify output files were created
 		expectedFiles := []string{
-			filepath.Join(outputDir, "model_enhanced.gen.go"),
-			filepath.Join(outputDir, "model_interfaces.gen.go"),
-			filepath.Join(outputDir, "model_unmarshal.gen.go"),
+			filepath.Join(outputDir, "model.gen.go"),
 		}
 
 		for _, file := range expectedFiles {
@@ -94,10 +90,10 @@ func TestProcessor_ProcessMultipleSchemas(t *testing.T) {
 	}
 
 	// Verify that color schema processing generated appropriate interface
-	colorInterfacesPath := filepath.Join(tmpDir, "color", "model_interfaces.gen.go")
+	colorInterfacesPath := filepath.Join(tmpDir, "color", "model.gen.go")
 	colorInterfacesContent, err := os.ReadFile(colorInterfacesPath)
 	if err != nil {
-		t.Fatalf("Failed to read interfaces file for color: %v", err)
+		t.Fatalf("Failed to read model file for color: %v", err)
 	}
 	colorInterfacesStr := string(colorInterfacesContent)
 
@@ -109,10 +105,10 @@ func TestProcessor_ProcessMultipleSchemas(t *testing.T) {
 	}
 
 	// Verify that confusing schema processing generated appropriate interface
-	confusingInterfacesPath := filepath.Join(tmpDir, "confusing", "model_interfaces.gen.go")
+	confusingInterfacesPath := filepath.Join(tmpDir, "confusing", "model.gen.go")
 	confusingInterfacesContent, err := os.ReadFile(confusingInterfacesPath)
 	if err != nil {
-		t.Fatalf("Failed to read interfaces file for confusing: %v", err)
+		t.Fatalf("Failed to read model file for confusing: %v", err)
 	}
 	confusingInterfacesStr := string(confusingInterfacesContent)
 
@@ -123,3 +119,124 @@ func TestProcessor_ProcessMultipleSchemas(t *testing.T) {
 		t.Errorf("Rice() method not found in Horse interface")
 	}
 }
+
+// copyInPlaceFixture copies the simple model and schema into a package
+// directory, along with any extra files, and returns the schema and model paths
+func copyInPlaceFixture(t *testing.T, extra map[string]string) (string, string) {
+	t.Helper()
+
+	pkgDir := t.TempDir()
+	files := map[string]string{
+		"simple.schema.json": filepath.Join("testdata", "simple", "simple.schema.json"),
+		"model.go":           filepath.Join("testdata", "simple", "model.gen.go"),
+	}
+	for name, src := range files {
+		content, err := os.ReadFile(src)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", src, err)
+		}
+		if err := os.WriteFile(filepath.Join(pkgDir, name), content, 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	for name, content := range extra {
+		if err := os.WriteFile(filepath.Join(pkgDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	return filepath.Join(pkgDir, "simple.schema.json"), filepath.Join(pkgDir, "model.go")
+}
+
+func TestProcessor_InPlace(t *testing.T) {
+	// A hand-written file in the package is type-checked with the model
+	schemaPath, modelPath := copyInPlaceFixture(t, map[string]string{
+		"shapes.go": "package simple\n\nvar DefaultShape Shape = &Circle{Radius: 1}\n",
+	})
+
+	processor := NewProcessor(schemaPath, modelPath, "")
+	processor.InPlace = true
+	if err := processor.Process(); err != nil {
+		t.Fatalf("Failed to process in place: %v", err)
+	}
+
+	content, err := os.ReadFile(modelPath)
+	if err != nil {
+		t.Fatalf("Failed to read model file: %v", err)
+	}
+	checkForContent(t, string(content), generatedHeader)
+	checkForContent(t, string(content), "type Shape interface {")
+
+	// The staging directory is removed once the model is swapped in
+	entries, err := os.ReadDir(filepath.Dir(modelPath))
+	if err != nil {
+		t.Fatalf("Failed to read package directory: %v", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			t.Errorf("Staging directory left behind: %s", entry.Name())
+		}
+	}
+
+	// The rewritten model is refused rather than merged a second time
+	if err := processor.Process(); err == nil || !strings.Contains(err.Error(), "already postprocessed") {
+		t.Errorf("Expected reprocessing the model to fail, got %v", err)
+	}
+}
+
+func TestProcessor_InPlaceTypeError(t *testing.T) {
+	// The generated code declares isShape on Circle, so this file clashes with it
+	schemaPath, modelPath := copyInPlaceFixture(t, map[string]string{
+		"shapes.go": "package simple\n\nfunc (me *Circle) isShape() {}\n",
+	})
+
+	original, err := os.ReadFile(modelPath)
+	if err != nil {
+		t.Fatalf("Failed to read model file: %v", err)
+	}
+
+	processor := NewProcessor(schemaPath, modelPath, "")
+	processor.InPlace = true
+	err = processor.Process()
+	if err == nil || !strings.Contains(err.Error(), "type errors") {
+		t.Fatalf("Expected a type-check error, got %v", err)
+	}
+
+	content, err := os.ReadFile(modelPath)
+	if err != nil {
+		t.Fatalf("Failed to read model file: %v", err)
+	}
+	if string(content) != string(original) {
+		t.Errorf("Model file was changed by a failed in-place run")
+	}
+}
+
+func TestProcessor_FieldNamedAfterMethod(t *testing.T) {
+	tests := []struct {
+		schema string
+		fields []string
+	}{
+		// Settings requires validate and equal, which Validate and Equal would replace
+		{schema: "required.schema.json", fields: []string{`Settings.Equal (property "equal")`, `Settings.Validate (property "validate")`}},
+		// Optional fields and the fields of children would be left dangling in
+		// Clone and the constructors instead
+		{schema: "optional.schema.json", fields: []string{`Circle.Validate (property "validate")`, `Settings.Clone (property "clone")`}},
+	}
+
+	for _, tt := range tests {
+		dir := filepath.Join(t.TempDir(), "settings")
+		processor := NewProcessor(filepath.Join("testdata", "collisions", tt.schema), filepath.Join(dir, "model.go"), dir)
+		processor.GenerateModel = true
+		err := processor.Process()
+		if err == nil {
+			t.Errorf("Expected the clashing fields of %s to fail", tt.schema)
+			continue
+		}
+		for _, field := range tt.fields {
+			checkForContent(t, err.Error(), field)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "model.go")); !os.IsNotExist(err) {
+			t.Errorf("Expected no model to be written for %s, got %v", tt.schema, err)
+		}
+	}
+}
@@ -0,0 +1,744 @@
+package repostprocess
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"gitlab.com/tozd/go/errors"
+)
+
+// ProtoGenerator renders a protobuf definition of an analyzed schema: a
+// message for every object definition, a message with a oneof for every
+// parent, and an enum for the constant values of each parent's children and
+// for every string enum. It also renders the Go functions converting between
+// the model and the messages protoc-gen-go generates from the definition
+type ProtoGenerator struct {
+	results      *SchemaResults
+	rootName     string
+	config       *Config
+	templatesDir string
+	// protoPackage is the package the definition declares, and goPackage the
+	// import path of the code protoc-gen-go generates from it
+	protoPackage string
+	goPackage    string
+	// previous holds the numbers of the definition written before, which
+	// are kept so the wire format doesn't change under existing messages
+	previous map[string]*protoNumbering
+}
+
+// NewProtoGenerator creates a protobuf generator for a definition in
+// protoPackage. The root schema's properties, if it has any, become the
+// message rootName
+func NewProtoGenerator(results *SchemaResults, rootName, protoPackage string) *ProtoGenerator {
+	return &ProtoGenerator{
+		results:      results,
+		rootName:     rootName,
+		protoPackage: protoPackage,
+	}
+}
+
+// SetConfig sets the project config, whose parent names the messages take
+func (pg *ProtoGenerator) SetConfig(config *Config) {
+	pg.config = config
+}
+
+// SetTemplatesDir sets a directory of *.tmpl files overriding the embedded templates
+func (pg *ProtoGenerator) SetTemplatesDir(dir string) {
+	pg.templatesDir = dir
+}
+
+// SetGoPackage sets the import path of the Go code protoc-gen-go generates
+// from the definition, which is its go_package option and what the
+// converters import
+func (pg *ProtoGenerator) SetGoPackage(importPath string) {
+	pg.goPackage = importPath
+}
+
+// SetPrevious sets the definition generated before, whose field and enum
+// value numbers are kept. Without one, fields are numbered in the order of
+// their names and enum values in the schema's order
+func (pg *ProtoGenerator) SetPrevious(source []byte) error {
+	previous, err := parseProtoNumbers(source)
+	if err != nil {
+		return errors.Errorf("reading previous numbers: %w", err)
+	}
+	pg.previous = previous
+	return nil
+}
+
+// protoKind is what a protobuf type is, which decides how it's declared and converted
+type protoKind int
+
+const (
+	protoScalar protoKind = iota
+	protoEnumKind
+	// protoMessageKind is the message of an object, and protoParentKind the
+	// message holding a parent's children in a oneof
+	protoMessageKind
+	protoParentKind
+	// protoWrapperKind is a message holding a repeated or map field named
+	// values, since neither can be the element of another
+	protoWrapperKind
+	// protoValueKind is google.protobuf.Value, for schemas that allow any JSON
+	protoValueKind
+	protoRepeated
+	protoMap
+	protoOptional
+)
+
+// protoType is the type of a field, or the element of a repeated, map or
+// optional field
+type protoType struct {
+	Kind protoKind
+	// Name is the type as the definition writes it, for all but repeated,
+	// map and optional types
+	Name    string
+	Elem    *protoType
+	Enum    *protoEnum
+	Message *protoMessage
+}
+
+// protoFile is the data passed to the proto template
+type protoFile struct {
+	Package   string
+	GoPackage string
+	Imports   []string
+	Enums     []*protoEnum
+	Messages  []*protoMessage
+}
+
+// protoEnum is an enum of the definition
+type protoEnum struct {
+	Name    string
+	Comment string
+	Values  []protoEnumValue
+	// Reserved are the numbers and names of values removed since the previous definition
+	Reserved      []int
+	ReservedNames []string
+}
+
+// protoEnumValue is a value of an enum and the JSON value it stands for. The
+// unspecified value every enum starts with has none
+type protoEnumValue struct {
+	Name   string
+	Number int
+	JSON   interface{}
+}
+
+// protoMessage is a message of the definition
+type protoMessage struct {
+	Name    string
+	Comment string
+	Fields  []*protoField
+	// Oneof lists the children of a parent's message
+	Oneof []*protoField
+	// Model is the definition the message is for, and Parent the parent's
+	// analysis if it is one. Root marks the message of the root schema
+	Model  string
+	Parent *ParentInfo
+	Root   bool
+	// Reserved are the numbers and names of fields removed since the previous definition
+	Reserved      []int
+	ReservedNames []string
+}
+
+// protoField is a field of a message, or a case of a parent's oneof
+type protoField struct {
+	Name    string
+	Number  int
+	Comment string
+	Type    *protoType
+	// JSON is the property the field holds
+	JSON string
+	// Const is the constant the field always holds, for children's
+	// discriminators, which the model keeps in methods instead of fields
+	Const interface{}
+}
+
+// Decl returns the field's type as its declaration writes it
+func (f *protoField) Decl() string {
+	switch f.Type.Kind {
+	case protoRepeated:
+		return "repeated " + f.Type.Elem.Name
+	case protoMap:
+		return "map<string, " + f.Type.Elem.Name + ">"
+	case protoOptional:
+		return "optional " + f.Type.Elem.Name
+	}
+	return f.Type.Name
+}
+
+// GoName returns the name protoc-gen-go gives the field
+func (f *protoField) GoName() string {
+	return goCamelCase(f.Name)
+}
+
+// valueImport is the file declaring google.protobuf.Value
+const valueImport = "google/protobuf/struct.proto"
+
+// Generate renders the protobuf definition
+func (pg *ProtoGenerator) Generate() ([]byte, error) {
+	templates, err := loadTemplates(pg.templatesDir)
+	if err != nil {
+		return nil, errors.Errorf("loading templates: %w", err)
+	}
+
+	file, err := pg.build()
+	if err != nil {
+		return nil, err
+	}
+	return renderTemplate(templates, "proto", file)
+}
+
+// protoBuilder maps the schema's definitions to messages and enums
+type protoBuilder struct {
+	pg          *ProtoGenerator
+	definitions map[string]interface{}
+	file        *protoFile
+	// types indexes the messages and enums by the definition they're for
+	types map[string]*protoType
+	// names holds every message and enum name, which must be distinct
+	names map[string]bool
+	// constants maps a child and one of its properties to the enum of the
+	// parent the property tells it apart by
+	constants map[string]map[string]*protoEnum
+}
+
+// build maps the schema to the messages and enums of the definition
+func (pg *ProtoGenerator) build() (*protoFile, error) {
+	b := &protoBuilder{
+		pg:        pg,
+		file:      &protoFile{Package: pg.protoPackage, GoPackage: pg.goPackage},
+		types:     make(map[string]*protoType),
+		names:     make(map[string]bool),
+		constants: make(map[string]map[string]*protoEnum),
+	}
+	b.definitions, _ = pg.results.Schema["definitions"].(map[string]interface{})
+
+	// Every definition's type is known before any field refers to one
+	for _, defName := range sortedKeys(b.definitions) {
+		if err := b.declare(defName); err != nil {
+			return nil, err
+		}
+	}
+	for _, parentName := range pg.results.SortedParentNames() {
+		if err := b.constantEnum(pg.results.Parents[parentName]); err != nil {
+			return nil, err
+		}
+	}
+
+	if properties, ok := pg.results.Schema["properties"].(map[string]interface{}); ok && len(properties) > 0 {
+		message := &protoMessage{Name: pg.rootName, Comment: description(pg.results.Schema), Root: true}
+		if err := b.addMessage(message); err != nil {
+			return nil, err
+		}
+		if err := b.fillMessage(message, pg.results.Schema); err != nil {
+			return nil, errors.Errorf("mapping root: %w", err)
+		}
+	}
+
+	for _, defName := range sortedKeys(b.definitions) {
+		t, ok := b.types[defName]
+		if !ok || t.Message == nil {
+			continue
+		}
+		schema, _ := b.definitions[defName].(map[string]interface{})
+		var err error
+		if t.Kind == protoParentKind {
+			err = b.fillParent(t.Message)
+		} else {
+			err = b.fillMessage(t.Message, schema)
+		}
+		if err != nil {
+			return nil, errors.Errorf("mapping %s: %w", defName, err)
+		}
+	}
+
+	for _, message := range b.file.Messages {
+		b.numberMessage(message)
+	}
+	for _, enum := range b.file.Enums {
+		b.numberEnum(enum)
+	}
+
+	sort.Slice(b.file.Enums, func(i, j int) bool { return b.file.Enums[i].Name < b.file.Enums[j].Name })
+	sort.Slice(b.file.Messages, func(i, j int) bool { return b.file.Messages[i].Name < b.file.Messages[j].Name })
+	return b.file, nil
+}
+
+// declare records the message or enum a definition becomes. Definitions that
+// are neither, such as a string with a pattern, are inlined where they're used
+func (b *protoBuilder) declare(defName string) error {
+	schema, ok := b.definitions[defName].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	if info, ok := b.pg.results.Parents[defName]; ok {
+		message := &protoMessage{
+			Name:    b.pg.config.InterfaceName(defName),
+			Comment: description(schema),
+			Model:   defName,
+			Parent:  &info,
+		}
+		b.types[defName] = &protoType{Kind: protoParentKind, Name: message.Name, Message: message}
+		return b.addMessage(message)
+	}
+
+	if values, ok := schema["enum"].([]interface{}); ok && allStrings(values) {
+		enum, err := b.addEnum(defName, description(schema), values)
+		if err != nil {
+			return err
+		}
+		b.types[defName] = &protoType{Kind: protoEnumKind, Name: enum.Name, Enum: enum}
+		return nil
+	}
+
+	if properties, ok := schema["properties"].(map[string]interface{}); ok && len(properties) > 0 {
+		message := &protoMessage{Name: defName, Comment: description(schema), Model: defName}
+		b.types[defName] = &protoType{Kind: protoMessageKind, Name: defName, Message: message}
+		return b.addMessage(message)
+	}
+	return nil
+}
+
+// constantEnum declares the enum of a parent's string discriminator, whose
+// values are its children's constants, and records the children's field as
+// holding it
+func (b *protoBuilder) constantEnum(info ParentInfo) error {
+	if info.Discriminator != DiscriminatorConstant || (info.ConstantKind != ConstantKindString && info.ConstantKind != "") {
+		return nil
+	}
+
+	name := b.pg.config.InterfaceName(info.Name) + "Model"
+	if override := b.pg.config.Parent(info.Name).ConstantType; override != "" {
+		name = override
+	}
+	values := make([]interface{}, 0, len(info.LeafChildren))
+	for _, child := range info.LeafChildren {
+		values = append(values, info.ConstantValues[child])
+	}
+	enum, err := b.addEnum(name, fmt.Sprintf("%s tells the children of %s apart by their %s", name, b.pg.config.InterfaceName(info.Name), info.ConstantField), values)
+	if err != nil {
+		return err
+	}
+
+	for _, child := range info.LeafChildren {
+		if b.constants[child] == nil {
+			b.constants[child] = make(map[string]*protoEnum)
+		}
+		if _, ok := b.constants[child][info.ConstantField]; !ok {
+			b.constants[child][info.ConstantField] = enum
+		}
+	}
+	return nil
+}
+
+// addMessage adds a message, whose name must not be taken
+func (b *protoBuilder) addMessage(message *protoMessage) error {
+	if b.names[message.Name] {
+		return errors.Errorf("two protobuf types are named %s", message.Name)
+	}
+	b.names[message.Name] = true
+	b.file.Messages = append(b.file.Messages, message)
+	return nil
+}
+
+// addEnum adds an enum of string values, with an unspecified value first as
+// proto3 requires, and each value prefixed with the enum's name
+func (b *protoBuilder) addEnum(name, comment string, values []interface{}) (*protoEnum, error) {
+	if b.names[name] {
+		return nil, errors.Errorf("two protobuf types are named %s", name)
+	}
+	b.names[name] = true
+
+	prefix := upperSnakeCase(name)
+	enum := &protoEnum{Name: name, Comment: comment}
+	enum.Values = append(enum.Values, protoEnumValue{Name: prefix + "_UNSPECIFIED"})
+	seen := map[string]bool{prefix + "_UNSPECIFIED": true}
+	for _, value := range values {
+		s, _ := value.(string)
+		valueName := prefix + "_" + upperSnakeCase(s)
+		if seen[valueName] {
+			return nil, errors.Errorf("enum %s has two values named %s", name, valueName)
+		}
+		seen[valueName] = true
+		enum.Values = append(enum.Values, protoEnumValue{Name: valueName, JSON: s})
+	}
+
+	b.file.Enums = append(b.file.Enums, enum)
+	return enum, nil
+}
+
+// fillParent adds a oneof case for each child of a parent, in the order the
+// schema lists them
+func (b *protoBuilder) fillParent(message *protoMessage) error {
+	for _, child := range message.Parent.Children {
+		t, ok := b.types[child]
+		if !ok || t.Message == nil {
+			return errors.Errorf("child %s isn't a message", child)
+		}
+		message.Oneof = append(message.Oneof, &protoField{
+			Name: snakeCase(t.Message.Name),
+			Type: t,
+		})
+	}
+	return nil
+}
+
+// fillMessage adds a field for each property of an object, in the order of
+// the property names. Scalars and enums a JSON object can leave out
+// or set to null are optional, so their presence survives the conversion
+func (b *protoBuilder) fillMessage(message *protoMessage, schema map[string]interface{}) error {
+	properties, _ := schema["properties"].(map[string]interface{})
+	required := requiredSet(schema)
+	seen := make(map[string]string)
+	for _, propName := range sortedKeys(properties) {
+		field := &protoField{Name: snakeCase(propName), JSON: propName}
+		if other, ok := seen[field.Name]; ok {
+			return errors.Errorf("properties %s and %s are both the field %s", other, propName, field.Name)
+		}
+		seen[field.Name] = propName
+
+		propMap, _ := properties[propName].(map[string]interface{})
+		field.Comment = description(propMap)
+		if enum := b.constants[message.Model][propName]; enum != nil {
+			field.Type = &protoType{Kind: protoEnumKind, Name: enum.Name, Enum: enum}
+			field.Const = enum.valueFor(propMap["const"])
+			message.Fields = append(message.Fields, field)
+			continue
+		}
+
+		t, err := b.fieldType(properties[propName], message.Name+exportedIdentifier(propName), 0)
+		if err != nil {
+			return errors.Errorf("mapping %s: %w", propName, err)
+		}
+		if constValue, ok := propMap["const"]; ok {
+			field.Const = constValue
+		}
+		if (t.Kind == protoScalar || t.Kind == protoEnumKind) && (!required[propName] || isNullable(propMap)) {
+			t = &protoType{Kind: protoOptional, Elem: t}
+		}
+		field.Type = t
+		message.Fields = append(message.Fields, field)
+	}
+	return nil
+}
+
+// numberMessage numbers the fields and oneof cases of a message, keeping the
+// numbers of the previous definition, and orders them by number
+func (b *protoBuilder) numberMessage(message *protoMessage) {
+	fields := append(append([]*protoField(nil), message.Oneof...), message.Fields...)
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Name
+	}
+	numbers, reserved, reservedNames := b.pg.previous[message.Name].assign(names, 1)
+	for _, field := range fields {
+		field.Number = numbers[field.Name]
+	}
+	message.Reserved, message.ReservedNames = reserved, reservedNames
+
+	byNumber := func(fields []*protoField) {
+		sort.Slice(fields, func(i, j int) bool { return fields[i].Number < fields[j].Number })
+	}
+	byNumber(message.Oneof)
+	byNumber(message.Fields)
+}
+
+// numberEnum numbers the values of an enum after its unspecified value, keeping
+// the numbers of the previous definition, and orders them by number
+func (b *protoBuilder) numberEnum(enum *protoEnum) {
+	values := enum.Values[1:]
+	names := make([]string, len(values))
+	for i, value := range values {
+		names[i] = value.Name
+	}
+	numbers, reserved, reservedNames := b.pg.previous[enum.Name].assign(names, 1)
+	for i := range values {
+		values[i].Number = numbers[values[i].Name]
+	}
+	enum.Reserved, enum.ReservedNames = reserved, reservedNames
+	sort.SliceStable(values, func(i, j int) bool { return values[i].Number < values[j].Number })
+}
+
+// isNullable reports whether a property's schema allows null alongside another type
+func isNullable(propMap map[string]interface{}) bool {
+	_, ok := nullableSchema(propMap)
+	return ok || allowsNull(propMap)
+}
+
+// valueFor returns the name of the enum value standing for a JSON value
+func (e *protoEnum) valueFor(value interface{}) interface{} {
+	for _, v := range e.Values {
+		if v.JSON != nil && v.JSON == value {
+			return v.Name
+		}
+	}
+	return nil
+}
+
+// fieldType returns the protobuf type of a property's schema. Inline objects
+// with properties become messages named after the property, as go-jsonschema
+// names their structs
+func (b *protoBuilder) fieldType(schema interface{}, inlineName string, depth int) (*protoType, error) {
+	if depth > maxCloneDepth {
+		return nil, errors.Errorf("schema nests deeper than %d levels", maxCloneDepth)
+	}
+	propMap, ok := schema.(map[string]interface{})
+	if !ok {
+		return b.valueType(), nil
+	}
+
+	if ref, ok := propMap["$ref"].(string); ok {
+		name := extractRefName(ref)
+		if t, ok := b.types[name]; ok {
+			return t, nil
+		}
+		if definition, ok := b.definitions[name]; ok {
+			return b.fieldType(definition, name, depth+1)
+		}
+		return b.valueType(), nil
+	}
+
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		members, ok := propMap[keyword].([]interface{})
+		if !ok {
+			continue
+		}
+		var others []interface{}
+		for _, member := range members {
+			if memberMap, ok := member.(map[string]interface{}); !ok || memberMap["type"] != "null" {
+				others = append(others, member)
+			}
+		}
+		if len(others) == 1 {
+			return b.fieldType(others[0], inlineName, depth+1)
+		}
+		return b.valueType(), nil
+	}
+
+	var types []string
+	switch t := propMap["type"].(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, item := range t {
+			if s, ok := item.(string); ok && s != "null" {
+				types = append(types, s)
+			}
+		}
+	}
+	if len(types) == 0 {
+		if constValue, ok := propMap["const"]; ok {
+			return constType(constValue), nil
+		}
+		return b.valueType(), nil
+	}
+	if len(types) > 1 {
+		return b.valueType(), nil
+	}
+
+	switch types[0] {
+	case "string":
+		return &protoType{Kind: protoScalar, Name: "string"}, nil
+	case "integer":
+		return &protoType{Kind: protoScalar, Name: "int64"}, nil
+	case "number":
+		return &protoType{Kind: protoScalar, Name: "double"}, nil
+	case "boolean":
+		return &protoType{Kind: protoScalar, Name: "bool"}, nil
+	case "array":
+		elem, err := b.elemType(propMap["items"], inlineName+"Elem", depth)
+		if err != nil {
+			return nil, err
+		}
+		return &protoType{Kind: protoRepeated, Elem: elem}, nil
+	case "object":
+		if properties, ok := propMap["properties"].(map[string]interface{}); ok && len(properties) > 0 {
+			message := &protoMessage{Name: inlineName, Comment: description(propMap)}
+			if err := b.addMessage(message); err != nil {
+				return nil, err
+			}
+			if err := b.fillMessage(message, propMap); err != nil {
+				return nil, err
+			}
+			return &protoType{Kind: protoMessageKind, Name: inlineName, Message: message}, nil
+		}
+		additional, ok := propMap["additionalProperties"]
+		if allowed, isBool := additional.(bool); !ok || isBool && allowed {
+			return &protoType{Kind: protoMap, Elem: b.valueType()}, nil
+		}
+		elem, err := b.elemType(additional, inlineName+"Value", depth)
+		if err != nil {
+			return nil, err
+		}
+		return &protoType{Kind: protoMap, Elem: elem}, nil
+	}
+	return b.valueType(), nil
+}
+
+// elemType returns the type of the elements of a repeated or map field,
+// wrapping repeated and map types in a message since they can't be nested
+func (b *protoBuilder) elemType(schema interface{}, inlineName string, depth int) (*protoType, error) {
+	t, err := b.fieldType(schema, inlineName, depth+1)
+	if err != nil {
+		return nil, err
+	}
+	if t.Kind != protoRepeated && t.Kind != protoMap {
+		return t, nil
+	}
+	return b.wrapper(t)
+}
+
+// wrapper returns the message holding a repeated or map type in its values
+// field, named after its element as in ColorList or StringMap
+func (b *protoBuilder) wrapper(t *protoType) (*protoType, error) {
+	suffix := "List"
+	if t.Kind == protoMap {
+		suffix = "Map"
+	}
+	elemName := t.Elem.Name
+	if t.Elem.Kind == protoValueKind {
+		elemName = "Value"
+	}
+	name := strings.ToUpper(elemName[:1]) + elemName[1:] + suffix
+
+	if existing, ok := b.types["\x00"+name]; ok {
+		return existing, nil
+	}
+	message := &protoMessage{
+		Name:    name,
+		Comment: fmt.Sprintf("%s wraps a %s field, which can't be the element of another", name, strings.ToLower(suffix)),
+		Fields:  []*protoField{{Name: "values", Type: t}},
+	}
+	if err := b.addMessage(message); err != nil {
+		return nil, err
+	}
+	wrapper := &protoType{Kind: protoWrapperKind, Name: name, Elem: t, Message: message}
+	// Wrappers are indexed apart from the definitions, which can't start with NUL
+	b.types["\x00"+name] = wrapper
+	return wrapper, nil
+}
+
+// valueType returns google.protobuf.Value, importing the file declaring it
+func (b *protoBuilder) valueType() *protoType {
+	if !containsString(b.file.Imports, valueImport) {
+		b.file.Imports = append(b.file.Imports, valueImport)
+	}
+	return &protoType{Kind: protoValueKind, Name: "google.protobuf.Value"}
+}
+
+// constType returns the scalar type of a constant without a type
+func constType(value interface{}) *protoType {
+	switch v := value.(type) {
+	case bool:
+		return &protoType{Kind: protoScalar, Name: "bool"}
+	case float64:
+		if v == float64(int64(v)) {
+			return &protoType{Kind: protoScalar, Name: "int64"}
+		}
+		return &protoType{Kind: protoScalar, Name: "double"}
+	}
+	return &protoType{Kind: protoScalar, Name: "string"}
+}
+
+// allStrings reports whether every value is a string
+func allStrings(values []interface{}) bool {
+	for _, value := range values {
+		if _, ok := value.(string); !ok {
+			return false
+		}
+	}
+	return len(values) > 0
+}
+
+// snakeCase returns a name as a protobuf field name, as in brand_name for
+// brandName or rgba_value for RGBAValue
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+				b.WriteRune('_')
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && i > 0 && !strings.HasSuffix(b.String(), "_") {
+			prev := runes[i-1]
+			next := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && next) {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	s := strings.TrimSuffix(b.String(), "_")
+	if s == "" || !unicode.IsLetter(rune(s[0])) {
+		s = "field_" + s
+	}
+	return s
+}
+
+// upperSnakeCase returns a name as an enum value name, as in COLOR_MODEL for ColorModel
+func upperSnakeCase(name string) string {
+	s := strings.ToUpper(snakeCase(name))
+	return strings.TrimPrefix(s, "FIELD_")
+}
+
+// goCamelCase returns the Go name protoc-gen-go gives a protobuf name, as in
+// BrandName for brand_name
+func goCamelCase(s string) string {
+	var b []byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '.' && i+1 < len(s) && isASCIILower(s[i+1]):
+		case c == '.':
+			b = append(b, '_')
+		case c == '_' && (i == 0 || s[i-1] == '.'):
+			b = append(b, 'X')
+		case c == '_' && i+1 < len(s) && isASCIILower(s[i+1]):
+		case '0' <= c && c <= '9':
+			b = append(b, c)
+		default:
+			if isASCIILower(c) {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+			for ; i+1 < len(s) && isASCIILower(s[i+1]); i++ {
+				b = append(b, s[i+1])
+			}
+		}
+	}
+	return string(b)
+}
+
+// isASCIILower reports whether c is a lowercase ASCII letter
+func isASCIILower(c byte) bool {
+	return 'a' <= c && c <= 'z'
+}
+
+// protoComment renders a description as line comments at the given indent
+func protoComment(desc, indent string) string {
+	if desc == "" {
+		return ""
+	}
+	var b strings.Builder
+	for _, line := range strings.Split(desc, "\n") {
+		b.WriteString(strings.TrimRightFunc(indent+"// "+line, unicode.IsSpace) + "\n")
+	}
+	return b.String()
+}
+
+// protoString returns a string literal, which JSON's are valid in protobuf
+// for the characters a path has
+func protoString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
@@ -0,0 +1,514 @@
+package repostprocess
+
+import (
+	"encoding/json"
+	"errors"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// generateProto analyzes a schema and renders its protobuf definition
+func generateProto(t *testing.T, schemaPath, protoPackage string, config *Config) string {
+	t.Helper()
+	return generateProtoAfter(t, schemaPath, protoPackage, config, nil)
+}
+
+// generateProtoAfter renders the protobuf definition of a schema, keeping the
+// numbers of a previous definition if there's one
+func generateProtoAfter(t *testing.T, schemaPath, protoPackage string, config *Config, previous []byte) string {
+	t.Helper()
+
+	analyzer, err := NewSchemaAnalyzer(schemaPath)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+	results, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Failed to analyze schema: %v", err)
+	}
+
+	generator := NewProtoGenerator(results, RootTypeName(schemaPath), protoPackage)
+	generator.SetConfig(config)
+	generator.SetGoPackage("example.com/" + protoPackage + "pb")
+	if previous != nil {
+		if err := generator.SetPrevious(previous); err != nil {
+			t.Fatalf("Failed to read previous definition: %v", err)
+		}
+	}
+	code, err := generator.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate protobuf definition: %v", err)
+	}
+	return string(code)
+}
+
+func TestProtoGenerator_Generate(t *testing.T) {
+	code := generateProto(t, filepath.Join("testdata", "color", "color.schema.json"), "color", nil)
+
+	checkForContent(t, code, "syntax = \"proto3\";\n\npackage color;\n\noption go_package = \"example.com/colorpb\";")
+
+	// Constant values become enum values, which the children's discriminators are typed by
+	checkForContent(t, code, "// ColorModel tells the children of Color apart by their model\nenum ColorModel {\n\tCOLOR_MODEL_UNSPECIFIED = 0;\n\tCOLOR_MODEL_HSL = 1; // hsl")
+	checkForContent(t, code, "\tPALETTE_MODEL_DISCRETE_SCALE = 2; // discrete-scale")
+	checkForContent(t, code, "message HSLValue {\n\tdouble h = 1;\n\tdouble l = 2;\n\tColorModel model = 3;\n\tdouble s = 4;\n}")
+
+	// Parents hold one of their children
+	checkForContent(t, code, "message Color {\n\toneof value {\n\t\tHSLValue hsl_value = 1;\n\t\tHSVValue hsv_value = 2;")
+
+	// Optional and nullable scalars keep their presence, and lists of lists are wrapped
+	checkForContent(t, code, "\toptional ColorSchemeType color_scheme = 1;\n\trepeated Color colors = 2;\n\toptional string description = 3;")
+	checkForContent(t, code, "\toptional string semantic = 6;")
+	checkForContent(t, code, "\trepeated ColorList colors = 2;")
+	checkForContent(t, code, "message ColorList {\n\trepeated Color values = 1;\n}")
+}
+
+func TestProtoGenerator_NamesAndValues(t *testing.T) {
+	dir := t.TempDir()
+	schemaPath := filepath.Join(dir, "badge.schema.json")
+	schema := `{
+	"type": "object",
+	"properties": {
+		"shade": {"$ref": "#/definitions/Shade"},
+		"font-size": {"type": "integer", "description": "Size in points"},
+		"meta": {"description": "Anything the renderer wants"},
+		"border": {"type": "object", "properties": {"width": {"type": "number"}}}
+	},
+	"required": ["shade"],
+	"definitions": {
+		"Shade": {"anyOf": [{"$ref": "#/definitions/Light"}, {"$ref": "#/definitions/Dark"}]},
+		"Light": {"type": "object", "properties": {"kind": {"const": "light"}, "level": {"type": "number"}}, "required": ["kind", "level"]},
+		"Dark": {"type": "object", "properties": {"kind": {"const": "dark"}, "level": {"type": "number"}}, "required": ["kind", "level"]}
+	}
+}`
+	if err := os.WriteFile(schemaPath, []byte(schema), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	config := &Config{Parents: map[string]ParentConfig{"Shade": {Interface: "BadgeShade", ConstantType: "ShadeKind"}}}
+	code := generateProto(t, schemaPath, "badge", config)
+
+	// Parents and their enums are named as the config names them
+	checkForContent(t, code, "enum ShadeKind {\n\tSHADE_KIND_UNSPECIFIED = 0;\n\tSHADE_KIND_LIGHT = 1; // light\n\tSHADE_KIND_DARK = 2; // dark\n}")
+	checkForContent(t, code, "message BadgeShade {\n\toneof value {\n\t\tLight light = 1;\n\t\tDark dark = 2;")
+	checkForAbsence(t, code, "message Shade ")
+
+	// The root is named as go-jsonschema names it, with inline objects as
+	// messages of their own and untyped values as google.protobuf.Value
+	checkForContent(t, code, "import \"google/protobuf/struct.proto\";")
+	checkForContent(t, code, "message BadgeSchemaJson {\n\tBadgeSchemaJsonBorder border = 1;\n\t// Size in points\n\toptional int64 font_size = 2;\n\t// Anything the renderer wants\n\tgoogle.protobuf.Value meta = 3;\n\tBadgeShade shade = 4;\n}")
+	checkForContent(t, code, "message BadgeSchemaJsonBorder {\n\toptional double width = 1;\n}")
+}
+
+func TestProtoGenerator_StableNumbers(t *testing.T) {
+	content, err := os.ReadFile(filepath.Join("testdata", "proto", "theme.schema.json"))
+	if err != nil {
+		t.Fatalf("Failed to read schema: %v", err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(content, &schema); err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	dir := t.TempDir()
+	write := func(name string) string {
+		t.Helper()
+		content, err := json.Marshal(schema)
+		if err != nil {
+			t.Fatalf("Failed to encode schema: %v", err)
+		}
+		path := filepath.Join(dir, name+".schema.json")
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			t.Fatalf("Failed to write schema: %v", err)
+		}
+		return path
+	}
+
+	before := generateProto(t, write("theme"), "theme", nil)
+	checkForContent(t, before, "message Gray {\n\tColorModel kind = 1;\n\t// Level is from black at 0 to white at 1\n\tdouble level = 2;\n}")
+
+	// A property sorting first, a child listed first, an enum value listed
+	// first and a property removed
+	definitions := schema["definitions"].(map[string]interface{})
+	gray := definitions["Gray"].(map[string]interface{})
+	gray["properties"].(map[string]interface{})["alpha"] = map[string]interface{}{"type": "number"}
+	delete(definitions["Named"].(map[string]interface{})["properties"].(map[string]interface{}), "tone")
+	definitions["Blend"] = map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"kind": map[string]interface{}{"const": "blend"}, "ratio": map[string]interface{}{"type": "number"}},
+		"required":   []interface{}{"kind", "ratio"},
+	}
+	color := definitions["Color"].(map[string]interface{})
+	color["anyOf"] = append([]interface{}{map[string]interface{}{"$ref": "#/definitions/Blend"}}, color["anyOf"].([]interface{})...)
+	tone := definitions["Tone"].(map[string]interface{})
+	tone["enum"] = []interface{}{"neutral", "warm"}
+	path := write("theme")
+
+	after := generateProtoAfter(t, path, "theme", nil, []byte(before))
+
+	// What was there keeps its number, and new fields and values come after it
+	checkForContent(t, after, "message Gray {\n\tColorModel kind = 1;\n\t// Level is from black at 0 to white at 1\n\tdouble level = 2;\n\toptional double alpha = 3;\n}")
+	checkForContent(t, after, "message Color {\n\toneof value {\n\t\tGray gray = 1;\n\t\tNamed named = 2;\n\t\tBlend blend = 3;\n\t}\n}")
+	checkForContent(t, after, "\tCOLOR_MODEL_GRAY = 1; // gray\n\tCOLOR_MODEL_NAMED = 2; // named\n\tCOLOR_MODEL_BLEND = 3; // blend\n")
+
+	// What's gone is reserved
+	checkForContent(t, after, "message Named {\n\treserved 3;\n\treserved \"tone\";\n\tColorModel kind = 1;\n\tstring name = 2;\n}")
+	checkForContent(t, after, "enum Tone {\n\treserved 2;\n\treserved \"TONE_COOL\";\n\tTONE_UNSPECIFIED = 0;\n\tTONE_WARM = 1; // warm\n\tTONE_NEUTRAL = 3; // neutral\n}")
+
+	// Generating again changes nothing, and a reserved name that's back gets a new number
+	if again := generateProtoAfter(t, path, "theme", nil, []byte(after)); again != after {
+		t.Errorf("Expected the definition to stay the same, got\n%s", again)
+	}
+	tone["enum"] = []interface{}{"neutral", "warm", "cool"}
+	path = write("theme")
+	restored := generateProtoAfter(t, path, "theme", nil, []byte(after))
+	checkForContent(t, restored, "enum Tone {\n\treserved 2;\n\tTONE_UNSPECIFIED = 0;\n\tTONE_WARM = 1; // warm\n\tTONE_NEUTRAL = 3; // neutral\n\tTONE_COOL = 4; // cool\n}")
+}
+
+func TestParseProtoNumbers(t *testing.T) {
+	source := `syntax = "proto3";
+/* A block
+   comment { */
+message Shape {
+	reserved 2, 4 to 6;
+	reserved 'old', "older";
+	option deprecated = true;
+	oneof value {
+		Circle circle = 1; // circle = 9;
+	}
+	map<string, int64> tags = 3 [deprecated = true];
+}
+enum Kind {
+	KIND_UNSPECIFIED = 0;
+	KIND_ROUND = 7;
+}
+`
+	numberings, err := parseProtoNumbers([]byte(source))
+	if err != nil {
+		t.Fatalf("Failed to parse numbers: %v", err)
+	}
+	shape := numberings["Shape"]
+	if shape == nil || shape.Numbers["circle"] != 1 || shape.Numbers["tags"] != 3 || len(shape.Numbers) != 2 {
+		t.Fatalf("Expected the oneof case and the map field, got %+v", shape)
+	}
+	if !reflect.DeepEqual(shape.Reserved, []int{2, 4, 5, 6}) || !reflect.DeepEqual(shape.ReservedNames, []string{"old", "older"}) {
+		t.Errorf("Expected the reserved numbers and names, got %v and %v", shape.Reserved, shape.ReservedNames)
+	}
+	if kind := numberings["Kind"]; kind == nil || kind.Numbers["KIND_ROUND"] != 7 {
+		t.Errorf("Expected the enum's values, got %+v", kind)
+	}
+
+	for _, broken := range []string{"message A {", "message A { int64 a = x; }", "message A {} message A {}", "message A { reserved 1 to max; }"} {
+		if _, err := parseProtoNumbers([]byte(broken)); err == nil {
+			t.Errorf("Expected an error parsing %q", broken)
+		}
+	}
+}
+
+// checkConverters type-checks the model package in dir against the stub of
+// the messages in testdata/proto/themepb
+func checkConverters(t *testing.T, dir string) {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	stubFiles, err := parseFiles(fset, []string{filepath.Join("testdata", "proto", "themepb", "theme.pb.go")})
+	if err != nil {
+		t.Fatalf("Failed to parse message stub: %v", err)
+	}
+	stub, err := (&types.Config{Importer: importer.Default()}).Check("example.com/themepb", fset, stubFiles, nil)
+	if err != nil {
+		t.Fatalf("Failed to type-check message stub: %v", err)
+	}
+
+	paths, err := packageFiles(dir)
+	if err != nil {
+		t.Fatalf("Failed to list package files: %v", err)
+	}
+	files, err := parseFiles(fset, paths)
+	if err != nil {
+		t.Fatalf("Failed to parse package: %v", err)
+	}
+	// The module resolves everything but the messages, which aren't in it
+	var modelFiles []*ast.File
+	for i, file := range files {
+		if filepath.Base(paths[i]) != "proto.gen.go" {
+			modelFiles = append(modelFiles, file)
+		}
+	}
+	imp, err := exportImporter(fset, modelFiles, ".")
+	if err != nil {
+		t.Fatalf("Failed to create importer: %v", err)
+	}
+	conf := types.Config{Importer: importerFunc(func(path string) (*types.Package, error) {
+		if path == stub.Path() {
+			return stub, nil
+		}
+		return imp.Import(path)
+	})}
+	if _, err := conf.Check(files[0].Name.Name, fset, files, nil); err != nil {
+		t.Fatalf("Converters don't type-check: %v", err)
+	}
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) {
+	return f(path)
+}
+
+// protoDeclarations describes the types, struct fields, constants and methods
+// a Go file declares, keyed by what they declare
+func protoDeclarations(t *testing.T, path string) map[string]string {
+	t.Helper()
+
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		t.Fatalf("Failed to parse %s: %v", path, err)
+	}
+	declarations := make(map[string]string)
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv != nil {
+				declarations["func ("+types.ExprString(decl.Recv.List[0].Type)+") "+decl.Name.Name] = types.ExprString(decl.Type)
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					structType, ok := spec.Type.(*ast.StructType)
+					if !ok {
+						declarations["type "+spec.Name.Name] = types.ExprString(spec.Type)
+						continue
+					}
+					declarations["type "+spec.Name.Name] = "struct"
+					for _, field := range structType.Fields.List {
+						for _, name := range field.Names {
+							declarations["field "+spec.Name.Name+"."+name.Name] = types.ExprString(field.Type)
+						}
+					}
+				case *ast.ValueSpec:
+					if decl.Tok != token.CONST || spec.Type == nil {
+						continue
+					}
+					for i, name := range spec.Names {
+						declarations["const "+name.Name] = types.ExprString(spec.Type) + " = " + types.ExprString(spec.Values[i])
+					}
+				}
+			}
+		}
+	}
+	return declarations
+}
+
+func TestProtoStub_MatchesProtocGenGo(t *testing.T) {
+	stub := protoDeclarations(t, filepath.Join("testdata", "proto", "themepb", "theme.pb.go"))
+	generated := protoDeclarations(t, filepath.Join("testdata", "proto", "protoc-gen-go", "theme.pb.go"))
+
+	// The converters rely on these names above all
+	for _, key := range []string{"field Color_Gray.Gray", "field Color_Named.Named", "const ColorModel_COLOR_MODEL_GRAY", "const Tone_TONE_WARM", "func (*Color) GetValue", "func (*ColorList) GetValues"} {
+		if _, ok := stub[key]; !ok {
+			t.Errorf("Expected the stub to declare %s", key)
+		}
+	}
+
+	keys := make([]string, 0, len(stub))
+	for key := range stub {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if generated[key] != stub[key] {
+			t.Errorf("Expected %s to be %q as protoc-gen-go generates it, got %q in the stub", key, generated[key], stub[key])
+		}
+	}
+}
+
+// roundTripTest converts a theme to its message and back, checking the
+// discriminators and enum values the message holds on the way
+const roundTripTest = `package theme
+
+import (
+	"testing"
+
+	pb "example.com/themepb"
+)
+
+func TestRoundTrip(t *testing.T) {
+	label, tone := "editor", ToneCool
+	theme := &Theme{
+		Accent: &Named{Name: "teal", Tone: &tone},
+		Extra:  ThemeExtra{"dim": true},
+		Grid:   []ColorSlice{{&Gray{Level: 0.5}, &Named{Name: "red"}}, {}},
+		Label:  &label,
+		Sizes:  []int{12, 14},
+	}
+
+	message, err := ThemeToProto(theme)
+	if err != nil {
+		t.Fatalf("Failed to convert theme to its message: %v", err)
+	}
+	accent, ok := message.Accent.GetValue().(*pb.Color_Named)
+	if !ok || accent.Named.Kind != pb.ColorModel_COLOR_MODEL_NAMED || accent.Named.Tone == nil || *accent.Named.Tone != pb.Tone_TONE_COOL {
+		t.Errorf("Expected a cool named accent, got %+v", message.Accent.GetValue())
+	}
+	gray, ok := message.Grid[0].GetValues()[0].GetValue().(*pb.Color_Gray)
+	if !ok || gray.Gray.Kind != pb.ColorModel_COLOR_MODEL_GRAY || gray.Gray.Level != 0.5 {
+		t.Errorf("Expected a gray first in the grid, got %+v", message.Grid[0].GetValues()[0].GetValue())
+	}
+
+	back, err := ThemeFromProto(message)
+	if err != nil {
+		t.Fatalf("Failed to convert message to its theme: %v", err)
+	}
+	if !theme.Equal(back) {
+		t.Errorf("Expected the theme back, got %+v", back)
+	}
+
+	// Enum values without a counterpart fail rather than getting lost
+	unknown := Tone("dark")
+	if _, err := NamedToProto(&Named{Name: "x", Tone: &unknown}); err == nil {
+		t.Errorf("Expected an unknown tone to fail")
+	}
+	unknownValue := pb.Tone(7)
+	if _, err := NamedFromProto(&pb.Named{Name: "x", Tone: &unknownValue}); err == nil {
+		t.Errorf("Expected an unknown tone value to fail")
+	}
+}
+`
+
+func TestProtoConverters_RoundTrip(t *testing.T) {
+	root := moduleTempDir(t)
+
+	stub, err := os.ReadFile(filepath.Join("testdata", "proto", "themepb", "theme.pb.go"))
+	if err != nil {
+		t.Fatalf("Failed to read message stub: %v", err)
+	}
+	stubDir := filepath.Join(root, "themepb")
+	if err := os.MkdirAll(stubDir, 0755); err != nil {
+		t.Fatalf("Failed to create stub directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(stubDir, "theme.pb.go"), stub, 0644); err != nil {
+		t.Fatalf("Failed to write message stub: %v", err)
+	}
+	out, err := exec.Command("go", "list", "-f", "{{.ImportPath}}", "./"+filepath.ToSlash(stubDir)).Output()
+	if err != nil {
+		t.Fatalf("Failed to list message stub: %v", err)
+	}
+	stubPath := strings.TrimSpace(string(out))
+
+	dir := filepath.Join(root, "theme")
+	processor := NewProcessor(filepath.Join("testdata", "proto", "theme.schema.json"), filepath.Join(dir, "model.go"), dir)
+	processor.GenerateModel = true
+	processor.Proto = ProtoConfig{File: filepath.Join(dir, "theme.proto"), GoPackage: stubPath, Converters: filepath.Join(dir, "proto.gen.go")}
+	if err := processor.Process(); err != nil {
+		t.Fatalf("Failed to process schema: %v", err)
+	}
+
+	runGoTest(t, dir, strings.Replace(roundTripTest, "example.com/themepb", stubPath, 1))
+}
+
+func TestProcessor_Proto(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "theme")
+	schemaPath := filepath.Join("testdata", "proto", "theme.schema.json")
+	protoPath := filepath.Join(dir, "proto", "theme.proto")
+
+	processor := NewProcessor(schemaPath, filepath.Join(dir, "model.go"), dir)
+	processor.GenerateModel = true
+	processor.Proto = ProtoConfig{File: protoPath, GoPackage: "example.com/themepb", Converters: filepath.Join(dir, "proto.gen.go")}
+	if err := processor.Process(); err != nil {
+		t.Fatalf("Failed to process schema: %v", err)
+	}
+
+	// The package defaults to the model's, and maps and lists map as they are
+	content, err := os.ReadFile(protoPath)
+	if err != nil {
+		t.Fatalf("Failed to read protobuf file: %v", err)
+	}
+	proto := string(content)
+	checkForContent(t, proto, "package theme;")
+	checkForContent(t, proto, "message Theme {\n\tColor accent = 1;\n\tmap<string, bool> extra = 2;\n\trepeated ColorList grid = 3;\n\toptional string label = 4;\n\trepeated int64 sizes = 5;\n}")
+	checkForContent(t, proto, "\toptional Tone tone = 3;")
+
+	// The protoc-gen-go output the message stub is checked against comes from
+	// this definition, and has to be generated again when it changes
+	golden, err := os.ReadFile(filepath.Join("testdata", "proto", "protoc-gen-go", "theme.proto"))
+	if err != nil {
+		t.Fatalf("Failed to read protoc-gen-go input: %v", err)
+	}
+	if proto != string(golden) {
+		t.Errorf("Expected the definition protoc-gen-go generated from, got\n%s", proto)
+	}
+
+	// The converters set the discriminators the messages keep and the model drops
+	content, err = os.ReadFile(processor.Proto.Converters)
+	if err != nil {
+		t.Fatalf("Failed to read converters: %v", err)
+	}
+	converters := string(content)
+	checkForContent(t, converters, "package theme\n\nimport (\n\t\"fmt\"\n\n\tpb \"example.com/themepb\"\n)")
+	checkForContent(t, converters, "\tmessage.Kind = pb.ColorModel_COLOR_MODEL_GRAY\n")
+	checkForContent(t, converters, "\t\treturn &pb.Color{Value: &pb.Color_Named{Named: child}}, nil")
+	checkForContent(t, converters, "\tcase *pb.Color_Gray:\n\t\tchild, err := GrayFromProto(value.Gray)")
+	checkForContent(t, converters, "func toneEnumToProto(value string) (pb.Tone, error) {")
+	checkForContent(t, converters, "return \"\", fmt.Errorf(\"unknown Tone %v\", value)")
+	checkConverters(t, dir)
+
+	// Processing again type-checks the model without the converters it's
+	// about to replace, and keeps the numbers the file on disk gives
+	renumbered := strings.Replace(proto, "double level = 2;", "double level = 9;", 1)
+	if err := os.WriteFile(protoPath, []byte(renumbered), 0644); err != nil {
+		t.Fatalf("Failed to renumber protobuf file: %v", err)
+	}
+	if err := processor.Process(); err != nil {
+		t.Fatalf("Failed to process schema again: %v", err)
+	}
+	content, err = os.ReadFile(protoPath)
+	if err != nil {
+		t.Fatalf("Failed to read protobuf file: %v", err)
+	}
+	checkForContent(t, string(content), "message Gray {\n\tColorModel kind = 1;\n\t// Level is from black at 0 to white at 1\n\tdouble level = 9;\n}")
+
+	// A check covers the protobuf file and converters along with the model
+	processor.Check = true
+	if err := processor.Process(); err != nil {
+		t.Fatalf("Expected the protobuf output to be up to date, got %v", err)
+	}
+	if err := os.WriteFile(protoPath, []byte("// edited\n"), 0644); err != nil {
+		t.Fatalf("Failed to edit protobuf file: %v", err)
+	}
+	if err := processor.Process(); !errors.Is(err, ErrStale) {
+		t.Fatalf("Expected the edited protobuf file to be stale, got %v", err)
+	}
+	checkForContent(t, processor.Diff, "-// edited")
+}
+
+func TestProcessor_ProtoErrors(t *testing.T) {
+	schemaPath := filepath.Join("testdata", "color", "color.schema.json")
+	modelPath := filepath.Join("testdata", "color", "model.gen.go")
+
+	for name, config := range map[string]ProtoConfig{
+		"converters elsewhere": {File: "color.proto", GoPackage: "example.com/colorpb", Converters: "other/proto.gen.go"},
+		"bad package":          {File: "color.proto", Package: "color-pb"},
+		"no go package":        {File: "color.proto", Converters: "proto.gen.go"},
+	} {
+		dir := t.TempDir()
+		config.File = filepath.Join(dir, config.File)
+		if config.Converters != "" {
+			config.Converters = filepath.Join(dir, config.Converters)
+		}
+		processor := NewProcessor(schemaPath, modelPath, dir)
+		processor.Proto = config
+		if err := processor.Process(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,518 @@
+package repostprocess
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/types"
+	"sort"
+	"strconv"
+
+	"gitlab.com/tozd/go/errors"
+)
+
+// protoImportName is the name the converters import the protobuf package as
+const protoImportName = "pb"
+
+// protoConvData is the data passed to the protoConverters template
+type protoConvData struct {
+	Package       string
+	GoPackage     string
+	NeedsFmt      bool
+	NeedsStructpb bool
+	Structs       []protoConvStruct
+	Parents       []protoConvParent
+	Enums         []protoConvEnum
+}
+
+// protoConvStruct converts a model struct to its message and back, one
+// field's statements at a time
+type protoConvStruct struct {
+	Struct    string
+	Message   string
+	ToProto   []string
+	FromProto []string
+}
+
+// protoConvParent converts a parent to the child set in its message's oneof and back
+type protoConvParent struct {
+	Name    string
+	Message string
+	Cases   []protoConvCase
+}
+
+// protoConvCase is a child of a parent and the oneof case holding it
+type protoConvCase struct {
+	// Type is what the type switch on the parent matches the child by
+	Type  string
+	Child string
+	// Wrapper is the oneof case's Go type and Field its field
+	Wrapper string
+	Field   string
+}
+
+// protoConvEnum converts the string values of an enum to its values and back
+type protoConvEnum struct {
+	Func   string
+	Enum   string
+	Values []protoConvValue
+}
+
+// protoConvValue is a JSON value and the Go name of the enum value standing for it
+type protoConvValue struct {
+	JSON  string
+	Const string
+}
+
+// GenerateConverters renders the Go functions converting between the model
+// and the protobuf messages, in the model's package. The model is the
+// postprocessed one, so caller fields already hold the parent interfaces
+func (pg *ProtoGenerator) GenerateConverters(modelPath string, model []byte) ([]byte, error) {
+	if pg.goPackage == "" {
+		return nil, errors.New("converters need the Go package of the protobuf messages")
+	}
+
+	templates, err := loadTemplates(pg.templatesDir)
+	if err != nil {
+		return nil, errors.Errorf("loading templates: %w", err)
+	}
+
+	file, err := pg.build()
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := indexModel(modelPath, model)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &protoConverter{
+		model:  parsed,
+		data:   &protoConvData{Package: parsed.file.Name.Name, GoPackage: pg.goPackage},
+		queued: make(map[string]bool),
+		enums:  make(map[*protoEnum]bool),
+	}
+	for _, message := range file.Messages {
+		switch {
+		case message.Parent != nil:
+			c.parent(message)
+		case message.Root:
+			if name, ok := parsed.rootStruct(pg.results.RootProperties); ok {
+				c.addStruct(name, message)
+			}
+		case message.Model != "":
+			if _, ok := parsed.types[message.Model]; ok {
+				c.addStruct(message.Model, message)
+			}
+		}
+	}
+	if err := c.build(); err != nil {
+		return nil, err
+	}
+
+	code, err := renderTemplate(templates, "protoConverters", c.data)
+	if err != nil {
+		return nil, err
+	}
+	formatted, err := format.Source(code)
+	if err != nil {
+		return nil, errors.Errorf("formatting converters: %w", err)
+	}
+	return formatted, nil
+}
+
+// protoConverter writes the statements converting each model struct's fields
+// to its message's and back
+type protoConverter struct {
+	model *modelFile
+	data  *protoConvData
+	// queue holds the structs still to convert and the messages they convert to
+	queue   []protoConvPair
+	queued  map[string]bool
+	enums   map[*protoEnum]bool
+	counter int
+}
+
+// protoConvPair is a model struct and its message
+type protoConvPair struct {
+	Struct  string
+	Message *protoMessage
+}
+
+// addStruct queues a struct's conversion, once
+func (c *protoConverter) addStruct(name string, message *protoMessage) {
+	if !c.queued[name] {
+		c.queued[name] = true
+		c.queue = append(c.queue, protoConvPair{Struct: name, Message: message})
+	}
+}
+
+// parent records the conversion of a parent, matching its children by type.
+// Nested parents are matched after the concrete children, since their
+// children implement the outer parent too
+func (c *protoConverter) parent(message *protoMessage) {
+	parent := protoConvParent{Name: message.Name, Message: message.Name}
+	var nested []protoConvCase
+	for _, field := range message.Oneof {
+		conv := protoConvCase{
+			Child:   field.Type.Message.Name,
+			Wrapper: message.Name + "_" + field.GoName(),
+			Field:   field.GoName(),
+		}
+		if field.Type.Kind == protoParentKind {
+			conv.Type = conv.Child
+			nested = append(nested, conv)
+			continue
+		}
+		conv.Type = "*" + conv.Child
+		c.addStruct(conv.Child, field.Type.Message)
+		parent.Cases = append(parent.Cases, conv)
+	}
+	parent.Cases = append(parent.Cases, nested...)
+	c.data.Parents = append(c.data.Parents, parent)
+	c.data.NeedsFmt = true
+}
+
+// build writes the conversions of the queued structs and every struct their
+// fields reach
+func (c *protoConverter) build() error {
+	for len(c.queue) > 0 {
+		pair := c.queue[0]
+		c.queue = c.queue[1:]
+
+		conv := protoConvStruct{Struct: pair.Struct, Message: pair.Message.Name}
+		for _, field := range pair.Message.Fields {
+			dst := "message." + field.GoName()
+			goField, ok := c.model.structField(pair.Struct, field.JSON)
+			if !ok {
+				// The merge drops the discriminators, which the message sets from the schema
+				if field.Const == nil {
+					return errors.Errorf("%s has no field for %s", pair.Struct, field.JSON)
+				}
+				conv.ToProto = append(conv.ToProto, fmt.Sprintf("%s = %s", dst, c.constant(field)))
+				continue
+			}
+
+			src := "value." + goField.Names[0].Name
+			to, err := c.toProto(goField.Type, field.Type, dst, src)
+			if err != nil {
+				return errors.Errorf("converting %s.%s: %w", pair.Struct, goField.Names[0].Name, err)
+			}
+			from, err := c.fromProto(goField.Type, field.Type, src, dst)
+			if err != nil {
+				return errors.Errorf("converting %s.%s: %w", pair.Struct, goField.Names[0].Name, err)
+			}
+			conv.ToProto = append(conv.ToProto, to)
+			conv.FromProto = append(conv.FromProto, from)
+		}
+		c.data.Structs = append(c.data.Structs, conv)
+	}
+
+	sort.Slice(c.data.Structs, func(i, j int) bool { return c.data.Structs[i].Struct < c.data.Structs[j].Struct })
+	sort.Slice(c.data.Enums, func(i, j int) bool { return c.data.Enums[i].Func < c.data.Enums[j].Func })
+	return nil
+}
+
+// constant returns the Go expression of the constant a field always holds
+func (c *protoConverter) constant(field *protoField) string {
+	if field.Type.Kind == protoEnumKind {
+		return protoImportName + "." + field.Type.Name + "_" + field.Const.(string)
+	}
+	if s, ok := field.Const.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(field.Const)
+}
+
+// local returns a new variable name, so the statements of different fields
+// can be run one after the other
+func (c *protoConverter) local(prefix string) string {
+	c.counter++
+	return fmt.Sprintf("%s%d", prefix, c.counter)
+}
+
+// resolve follows a named model type, such as ColorSlice, to the type it's
+// declared as, unless it's a struct or an interface
+func (c *protoConverter) resolve(expr ast.Expr) ast.Expr {
+	for depth := 0; depth < maxCloneDepth; depth++ {
+		ident, ok := expr.(*ast.Ident)
+		if !ok {
+			return expr
+		}
+		typeSpec, ok := c.model.types[ident.Name]
+		if !ok {
+			return expr
+		}
+		switch typeSpec.Type.(type) {
+		case *ast.StructType, *ast.InterfaceType, *ast.Ident:
+			return expr
+		}
+		expr = typeSpec.Type
+	}
+	return expr
+}
+
+// goType returns the Go type protoc-gen-go generates for the element of a
+// repeated or map field
+func goType(t *protoType) string {
+	switch t.Kind {
+	case protoScalar:
+		return scalarGoType(t.Name)
+	case protoEnumKind:
+		return protoImportName + "." + t.Name
+	case protoValueKind:
+		return "*structpb.Value"
+	}
+	return "*" + protoImportName + "." + t.Name
+}
+
+// scalarGoType returns the Go type of a protobuf scalar
+func scalarGoType(name string) string {
+	if name == "double" {
+		return "float64"
+	}
+	return name
+}
+
+// toProto returns the statements setting dst, of protobuf type t, from src,
+// of the model's type expr
+func (c *protoConverter) toProto(expr ast.Expr, t *protoType, dst, src string) (string, error) {
+	star, pointer := expr.(*ast.StarExpr)
+
+	switch t.Kind {
+	case protoOptional, protoScalar, protoEnumKind:
+		elem := t
+		if t.Kind == protoOptional {
+			elem = t.Elem
+		}
+		if pointer {
+			expr, src = star.X, "*"+src
+		}
+		stmts, value := c.scalarToProto(expr, elem, src)
+		switch {
+		case t.Kind == protoOptional && stmts != "":
+			// An enum's value is already a local
+			stmts += fmt.Sprintf("%s = &%s", dst, value)
+		case t.Kind == protoOptional:
+			v := c.local("v")
+			stmts += fmt.Sprintf("%s := %s\n%s = &%s", v, value, dst, v)
+		default:
+			stmts += fmt.Sprintf("%s = %s", dst, value)
+		}
+		if pointer {
+			return fmt.Sprintf("if %s != nil {\n%s\n}", src[1:], stmts), nil
+		}
+		return stmts, nil
+
+	case protoMessageKind, protoParentKind:
+		name, isPointer := namedType(expr)
+		if name == "" {
+			return "", errors.Errorf("%s can't be converted to %s", types.ExprString(expr), t.Name)
+		}
+		if t.Kind == protoMessageKind {
+			if _, ok := c.model.types[name]; !ok {
+				return "", errors.Errorf("no struct %s for %s", name, t.Name)
+			}
+			c.addStruct(name, t.Message)
+		}
+		arg := src
+		if t.Kind == protoMessageKind && !isPointer {
+			arg = "&" + src
+		}
+		v := c.local("m")
+		stmts := fmt.Sprintf("%s, err := %sToProto(%s)\nif err != nil {\nreturn nil, err\n}\n%s = %s", v, name, arg, dst, v)
+		if isPointer || t.Kind == protoParentKind {
+			return fmt.Sprintf("if %s != nil {\n%s\n}", src, stmts), nil
+		}
+		return stmts, nil
+
+	case protoValueKind:
+		c.data.NeedsStructpb = true
+		v := c.local("v")
+		return fmt.Sprintf("if %s != nil {\n%s, err := structpb.NewValue(%s)\nif err != nil {\nreturn nil, err\n}\n%s = %s\n}", src, v, src, dst, v), nil
+
+	case protoWrapperKind:
+		w := c.local("w")
+		inner, err := c.toProto(expr, t.Elem, w+".Values", src)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s := &%s.%s{}\n%s\n%s = %s", w, protoImportName, t.Name, inner, dst, w), nil
+
+	case protoRepeated:
+		array, ok := c.resolve(expr).(*ast.ArrayType)
+		if !ok || array.Len != nil {
+			return "", errors.Errorf("%s can't be converted to a repeated field", types.ExprString(expr))
+		}
+		i, v := c.local("i"), c.local("v")
+		inner, err := c.toProto(array.Elt, t.Elem, dst+"["+i+"]", v)
+		if err != nil {
+			return "", err
+		}
+		if inner == dst+"["+i+"] = "+v {
+			return fmt.Sprintf("%s = append([]%s(nil), %s...)", dst, goType(t.Elem), src), nil
+		}
+		return fmt.Sprintf("if %s != nil {\n%s = make([]%s, len(%s))\nfor %s, %s := range %s {\n%s\n}\n}",
+			src, dst, goType(t.Elem), src, i, v, src, inner), nil
+
+	case protoMap:
+		mapType, ok := c.resolve(expr).(*ast.MapType)
+		if !ok {
+			return "", errors.Errorf("%s can't be converted to a map field", types.ExprString(expr))
+		}
+		k, v := c.local("k"), c.local("v")
+		inner, err := c.toProto(mapType.Value, t.Elem, dst+"[string("+k+")]", v)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("if %s != nil {\n%s = make(map[string]%s, len(%s))\nfor %s, %s := range %s {\n%s\n}\n}",
+			src, dst, goType(t.Elem), src, k, v, src, inner), nil
+	}
+	return "", errors.Errorf("unsupported protobuf type %s", t.Name)
+}
+
+// scalarToProto returns the statements and the expression converting src, of
+// the model's type expr, to a scalar or enum value
+func (c *protoConverter) scalarToProto(expr ast.Expr, t *protoType, src string) (string, string) {
+	if t.Kind == protoEnumKind {
+		v := c.local("e")
+		return fmt.Sprintf("%s, err := %s(string(%s))\nif err != nil {\nreturn nil, err\n}\n", v, c.enumFunc(t.Enum, "ToProto"), src), v
+	}
+	goName := scalarGoType(t.Name)
+	if ident, ok := expr.(*ast.Ident); ok && ident.Name == goName {
+		return "", src
+	}
+	return "", goName + "(" + src + ")"
+}
+
+// fromProto returns the statements setting dst, of the model's type expr,
+// from src, of protobuf type t
+func (c *protoConverter) fromProto(expr ast.Expr, t *protoType, dst, src string) (string, error) {
+	star, pointer := expr.(*ast.StarExpr)
+
+	switch t.Kind {
+	case protoOptional, protoScalar, protoEnumKind:
+		elem, value := t, src
+		if t.Kind == protoOptional {
+			elem, value = t.Elem, "*"+src
+		}
+		goExpr := expr
+		if pointer {
+			goExpr = star.X
+		}
+		stmts, converted := c.scalarFromProto(goExpr, elem, value)
+		if pointer {
+			v := c.local("v")
+			stmts += fmt.Sprintf("%s := %s\n%s = &%s", v, converted, dst, v)
+		} else {
+			stmts += fmt.Sprintf("%s = %s", dst, converted)
+		}
+		if t.Kind == protoOptional {
+			return fmt.Sprintf("if %s != nil {\n%s\n}", src, stmts), nil
+		}
+		return stmts, nil
+
+	case protoMessageKind, protoParentKind:
+		name, isPointer := namedType(expr)
+		v := c.local("v")
+		stmts := fmt.Sprintf("%s, err := %sFromProto(%s)\nif err != nil {\nreturn nil, err\n}\n", v, name, src)
+		if t.Kind == protoMessageKind && !isPointer {
+			return fmt.Sprintf("if %s != nil {\n%s%s = *%s\n}", src, stmts, dst, v), nil
+		}
+		return stmts + fmt.Sprintf("%s = %s", dst, v), nil
+
+	case protoValueKind:
+		if iface, ok := c.resolve(expr).(*ast.InterfaceType); ok && len(iface.Methods.List) == 0 {
+			return fmt.Sprintf("if %s != nil {\n%s = %s.AsInterface()\n}", src, dst, src), nil
+		}
+		v := c.local("v")
+		return fmt.Sprintf("if %s, ok := %s.AsInterface().(%s); ok {\n%s = %s\n}", v, src, types.ExprString(expr), dst, v), nil
+
+	case protoWrapperKind:
+		return c.fromProto(expr, t.Elem, dst, src+".GetValues()")
+
+	case protoRepeated:
+		array, ok := c.resolve(expr).(*ast.ArrayType)
+		if !ok || array.Len != nil {
+			return "", errors.Errorf("%s can't be converted from a repeated field", types.ExprString(expr))
+		}
+		i, v := c.local("i"), c.local("v")
+		inner, err := c.fromProto(array.Elt, t.Elem, dst+"["+i+"]", v)
+		if err != nil {
+			return "", err
+		}
+		if inner == dst+"["+i+"] = "+v {
+			return fmt.Sprintf("%s = append(make(%s, 0, len(%s)), %s...)", dst, types.ExprString(expr), src, src), nil
+		}
+		// Required arrays are written as [] rather than null, so the slice is
+		// made even when the field is empty
+		return fmt.Sprintf("%s = make(%s, len(%s))\nfor %s, %s := range %s {\n%s\n}",
+			dst, types.ExprString(expr), src, i, v, src, inner), nil
+
+	case protoMap:
+		mapType, ok := c.resolve(expr).(*ast.MapType)
+		if !ok {
+			return "", errors.Errorf("%s can't be converted from a map field", types.ExprString(expr))
+		}
+		k, v := c.local("k"), c.local("v")
+		key := k
+		if keyType := types.ExprString(mapType.Key); keyType != "string" {
+			key = keyType + "(" + k + ")"
+		}
+		inner, err := c.fromProto(mapType.Value, t.Elem, dst+"["+key+"]", v)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s = make(%s, len(%s))\nfor %s, %s := range %s {\n%s\n}",
+			dst, types.ExprString(expr), src, k, v, src, inner), nil
+	}
+	return "", errors.Errorf("unsupported protobuf type %s", t.Name)
+}
+
+// scalarFromProto returns the statements and the expression converting src,
+// a scalar or enum value, to the model's type expr
+func (c *protoConverter) scalarFromProto(expr ast.Expr, t *protoType, src string) (string, string) {
+	goName := types.ExprString(expr)
+	if t.Kind == protoEnumKind {
+		v := c.local("e")
+		stmts := fmt.Sprintf("%s, err := %s(%s)\nif err != nil {\nreturn nil, err\n}\n", v, c.enumFunc(t.Enum, "FromProto"), src)
+		if goName == "string" {
+			return stmts, v
+		}
+		return stmts, goName + "(" + v + ")"
+	}
+	if goName == scalarGoType(t.Name) {
+		return "", src
+	}
+	return "", goName + "(" + src + ")"
+}
+
+// enumFunc returns the name of an enum's converter in the given direction,
+// recording that the enum needs its converters
+func (c *protoConverter) enumFunc(enum *protoEnum, direction string) string {
+	name := lowerFirst(enum.Name) + "Enum"
+	if !c.enums[enum] {
+		c.enums[enum] = true
+		conv := protoConvEnum{Func: name, Enum: enum.Name}
+		for _, value := range enum.Values {
+			if s, ok := value.JSON.(string); ok {
+				conv.Values = append(conv.Values, protoConvValue{JSON: strconv.Quote(s), Const: enum.Name + "_" + value.Name})
+			}
+		}
+		c.data.Enums = append(c.data.Enums, conv)
+		c.data.NeedsFmt = true
+	}
+	return name + direction
+}
+
+// namedType returns the name of a model type, and whether expr points to it
+func namedType(expr ast.Expr) (string, bool) {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		name, _ := namedType(star.X)
+		return name, true
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name, false
+	}
+	return "", false
+}
@@ -0,0 +1,267 @@
+package repostprocess
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gitlab.com/tozd/go/errors"
+)
+
+// maxReservedRange bounds the reserved ranges a previous definition can have,
+// since each number in one is kept on its own
+const maxReservedRange = 10000
+
+// protoNumbering is the numbers a previous definition gave the fields of a
+// message, or the values of an enum, and the ones it reserved
+type protoNumbering struct {
+	Numbers       map[string]int
+	Reserved      []int
+	ReservedNames []string
+}
+
+// parseProtoNumbers reads the numbers of every message and enum of a
+// protobuf definition, keyed by the message or enum's name. The cases of a
+// oneof count as fields of the message holding it
+func parseProtoNumbers(source []byte) (map[string]*protoNumbering, error) {
+	tokens, err := protoTokens(string(source))
+	if err != nil {
+		return nil, err
+	}
+
+	numberings := make(map[string]*protoNumbering)
+	// scopes holds the numbering of each open block, nil for blocks that
+	// aren't a message, oneof or enum
+	var scopes []*protoNumbering
+	current := func() *protoNumbering {
+		if len(scopes) == 0 {
+			return nil
+		}
+		return scopes[len(scopes)-1]
+	}
+
+	var statement []string
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		switch token {
+		case "{":
+			var scope *protoNumbering
+			switch {
+			case len(statement) == 2 && (statement[0] == "message" || statement[0] == "enum"):
+				if _, ok := numberings[statement[1]]; ok {
+					return nil, errors.Errorf("%s is declared twice", statement[1])
+				}
+				scope = &protoNumbering{Numbers: make(map[string]int)}
+				numberings[statement[1]] = scope
+			case len(statement) == 2 && statement[0] == "oneof":
+				scope = current()
+			}
+			scopes = append(scopes, scope)
+			statement = nil
+		case "}":
+			if len(scopes) == 0 {
+				return nil, errors.New("unbalanced }")
+			}
+			scopes = scopes[:len(scopes)-1]
+			statement = nil
+		case ";":
+			if scope := current(); scope != nil {
+				if err := scope.addStatement(statement); err != nil {
+					return nil, err
+				}
+			}
+			statement = nil
+		default:
+			statement = append(statement, token)
+		}
+	}
+	if len(scopes) > 0 {
+		return nil, errors.New("unbalanced {")
+	}
+	return numberings, nil
+}
+
+// addStatement records the number a field or enum value statement declares,
+// or the numbers and names a reserved statement holds
+func (n *protoNumbering) addStatement(statement []string) error {
+	if len(statement) == 0 || statement[0] == "option" {
+		return nil
+	}
+
+	if statement[0] == "reserved" {
+		items := statement[1:]
+		for i := 0; i < len(items); i++ {
+			item := items[i]
+			switch {
+			case item == ",":
+			case strings.HasPrefix(item, "\""):
+				name, err := strconv.Unquote(item)
+				if err != nil {
+					return errors.Errorf("reserved name %s: %w", item, err)
+				}
+				n.ReservedNames = append(n.ReservedNames, name)
+			default:
+				from, err := strconv.Atoi(item)
+				if err != nil {
+					return errors.Errorf("reserved number %s: %w", item, err)
+				}
+				to := from
+				if i+2 < len(items) && items[i+1] == "to" {
+					if to, err = strconv.Atoi(items[i+2]); err != nil || to < from || to-from > maxReservedRange {
+						return errors.Errorf("unsupported reserved range %d to %s", from, items[i+2])
+					}
+					i += 2
+				}
+				for number := from; number <= to; number++ {
+					n.Reserved = append(n.Reserved, number)
+				}
+			}
+		}
+		return nil
+	}
+
+	// The name comes right before the first =, and the number right after it
+	for i := 1; i+1 < len(statement); i++ {
+		if statement[i] != "=" {
+			continue
+		}
+		number, err := strconv.Atoi(statement[i+1])
+		if err != nil {
+			return errors.Errorf("number of %s: %w", statement[i-1], err)
+		}
+		n.Numbers[statement[i-1]] = number
+		return nil
+	}
+	return nil
+}
+
+// protoTokens splits a protobuf definition into identifiers, numbers, string
+// literals and punctuation, leaving out the comments
+func protoTokens(source string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(source); {
+		c := source[i]
+		switch {
+		case strings.HasPrefix(source[i:], "//"):
+			end := strings.IndexByte(source[i:], '\n')
+			if end < 0 {
+				return tokens, nil
+			}
+			i += end
+		case strings.HasPrefix(source[i:], "/*"):
+			end := strings.Index(source[i+2:], "*/")
+			if end < 0 {
+				return nil, errors.New("unterminated comment")
+			}
+			i += end + 4
+		case c == '"' || c == '\'':
+			j := i + 1
+			for ; j < len(source) && source[j] != c; j++ {
+				if source[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(source) {
+				return nil, errors.New("unterminated string")
+			}
+			literal := source[i : j+1]
+			if c == '\'' {
+				literal = strconv.Quote(source[i+1 : j])
+			}
+			tokens = append(tokens, literal)
+			i = j + 1
+		case unicode.IsSpace(rune(c)):
+			i++
+		case isProtoWord(c):
+			j := i
+			for j < len(source) && isProtoWord(source[j]) {
+				j++
+			}
+			tokens = append(tokens, source[i:j])
+			i = j
+		default:
+			tokens = append(tokens, string(c))
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// isProtoWord reports whether c can be part of an identifier or number
+func isProtoWord(c byte) bool {
+	return c == '_' || c == '.' || c == '-' || c == '+' || '0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// assign numbers names, starting at first. Names the previous numbering had
+// keep their numbers, and new ones take numbers past every number it used or
+// reserved. The numbers and names it had that are gone are reserved, along
+// with those it reserved itself, so they aren't reused for something else
+func (n *protoNumbering) assign(names []string, first int) (map[string]int, []int, []string) {
+	numbers := make(map[string]int, len(names))
+	if n == nil {
+		for i, name := range names {
+			numbers[name] = first + i
+		}
+		return numbers, nil, nil
+	}
+
+	next := first
+	for _, number := range n.Numbers {
+		next = max(next, number+1)
+	}
+	for _, number := range n.Reserved {
+		next = max(next, number+1)
+	}
+
+	current := make(map[string]bool, len(names))
+	for _, name := range names {
+		current[name] = true
+		if number, ok := n.Numbers[name]; ok {
+			numbers[name] = number
+			continue
+		}
+		numbers[name] = next
+		next++
+	}
+
+	reserved := append([]int(nil), n.Reserved...)
+	var reservedNames []string
+	for _, name := range n.ReservedNames {
+		// A name that's back is a new field, under a new number
+		if !current[name] {
+			reservedNames = append(reservedNames, name)
+		}
+	}
+	for name, number := range n.Numbers {
+		if !current[name] && number >= first {
+			reserved = append(reserved, number)
+			reservedNames = append(reservedNames, name)
+		}
+	}
+	return numbers, uniqueInts(reserved), uniqueStrings(reservedNames)
+}
+
+// uniqueInts returns numbers sorted without duplicates
+func uniqueInts(numbers []int) []int {
+	sort.Ints(numbers)
+	var unique []int
+	for i, number := range numbers {
+		if i == 0 || number != numbers[i-1] {
+			unique = append(unique, number)
+		}
+	}
+	return unique
+}
+
+// uniqueStrings returns names sorted without duplicates
+func uniqueStrings(names []string) []string {
+	sort.Strings(names)
+	var unique []string
+	for i, name := range names {
+		if i == 0 || name != names[i-1] {
+			unique = append(unique, name)
+		}
+	}
+	return unique
+}
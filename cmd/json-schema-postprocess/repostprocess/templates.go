@@ -0,0 +1,686 @@
+package repostprocess
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"gitlab.com/tozd/go/errors"
+)
+
+// defaultTemplates holds the templates the generator renders with unless a
+// templates directory overrides them
+//
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// templateFuncs are the helper functions available to every template
+var templateFuncs = template.FuncMap{
+	"camel":        camelCase,
+	"exported":     exportedIdentifier,
+	"join":         strings.Join,
+	"protoComment": protoComment,
+	"protoString":  protoString,
+	"tags":         structTags,
+	"tsComment":    tsComment,
+}
+
+// loadTemplates parses the embedded templates and then any *.tmpl files in dir,
+// so a file in dir can redefine any of the named templates
+func loadTemplates(dir string) (*template.Template, error) {
+	tmpl, err := template.New("postprocess").Funcs(templateFuncs).ParseFS(defaultTemplates, "templates/*.tmpl")
+	if err != nil {
+		return nil, errors.Errorf("parsing default templates: %w", err)
+	}
+
+	if dir == "" {
+		return tmpl, nil
+	}
+
+	overrides, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return nil, errors.Errorf("listing templates in %s: %w", dir, err)
+	}
+	if len(overrides) == 0 {
+		return nil, errors.Errorf("no *.tmpl files in templates directory %s", dir)
+	}
+
+	tmpl, err = tmpl.ParseFiles(overrides...)
+	if err != nil {
+		return nil, errors.Errorf("parsing templates in %s: %w", dir, err)
+	}
+
+	return tmpl, nil
+}
+
+// renderTemplate executes the named template with data
+func renderTemplate(tmpl *template.Template, name string, data interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return nil, errors.Errorf("executing template %s: %w", name, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// exportedIdentifier turns a JSON field name into an exported Go identifier
+func exportedIdentifier(s string) string {
+	return strings.Title(sanitizeIdentifier(s))
+}
+
+// structTags returns the json, yaml and mapstructure tags for a field
+func structTags(name string) string {
+	return fmt.Sprintf("`json:\"%s\" yaml:\"%s\" mapstructure:\"%s\"`", name, name, name)
+}
+
+// fileData is the data passed to the top-level file templates
+type fileData struct {
+	Package        string
+	Parents        []parentData
+	Callers        []callerData
+	NeedsKeyHelper bool
+	// NeedsConstraintHelpers is set when a check of multipleOf or uniqueItems
+	// is rendered anywhere in the model
+	NeedsConstraintHelpers bool
+	// Structs are the concrete children and the callers, in sorted order,
+	// which get YAML, clone and equality methods
+	Structs []string
+	// Constructors are the New<Child> functions of the concrete children
+	Constructors []constructorData
+	// Clones are the Clone methods of Structs and the structs they reach
+	Clones        []cloneData
+	NeedsCloneAny bool
+	// Equals are the Equal methods of Structs and the structs they reach
+	Equals []equalData
+	// EqualHelpers names the generic equal helpers the Equal methods call
+	EqualHelpers map[string]bool
+	// Validations are the Validate methods of every struct and enum in the model
+	Validations []validateData
+}
+
+// parentData describes one parent for the templates
+type parentData struct {
+	Name string
+	// Definition is the parent's name in the schema, which Name defaults to
+	Definition    string
+	Info          ParentInfo
+	Children      []string
+	EmbeddedIn    []string
+	Discriminator DiscriminatorStrategy
+
+	// ConstantField is the JSON name of the constant discriminator field, if any
+	ConstantField         string
+	ConstantMethod        string
+	ConstantGoType        string
+	ModelType             string
+	DeclareConstantMethod bool
+	Constants             []constantData
+	ErrorVerb             string
+
+	Implementations []implementationData
+	// Visitors are the concrete children the visitor, accept and match
+	// helpers cover, in schema order
+	Visitors   []visitorData
+	Marshalers []string
+	// MarshalChecks holds the caller checks of the marshaled children that
+	// also hold parents, so their MarshalJSON runs them
+	MarshalChecks map[string]*callerData
+
+	// Cases are the branches of the dispatch, in order
+	Cases               []caseData
+	Fallback            *caseData
+	CompositeFields     []fieldData
+	CompositeFieldNames []string
+	TrialOptions        []string
+	NestedParents       []string
+}
+
+// constantData is one constant of a parent's model type
+type constantData struct {
+	Name    string
+	Literal string
+}
+
+// implementationData is one child implementing a parent interface
+type implementationData struct {
+	Child string
+	// ConstantName is the constant the child's accessor returns, if it has one
+	ConstantName string
+}
+
+// visitorData is one concrete child of a parent's visitor
+type visitorData struct {
+	Child string
+	// Name is the child's name without the words every child ends with, as
+	// in VisitRGB for RGBValue
+	Name string
+}
+
+// caseData is one branch of a parse function
+type caseData struct {
+	Child string
+	// Route is the nested parent the child is reached through, if any
+	Route string
+	// Label is the case value, the condition for composite dispatch, or the
+	// identifying field for presence dispatch
+	Label string
+	Keys  []string
+}
+
+// fieldData is a JSON field and its Go name
+type fieldData struct {
+	Name   string
+	GoName string
+}
+
+// callerData describes a struct holding parents, whose UnmarshalJSON is generated
+type callerData struct {
+	Struct string
+	// RequiredKeys are checked before decoding, in order
+	RequiredKeys []string
+	// Closed lists the fields the struct's schema allows, when it sets
+	// additionalProperties to false, so any other field is rejected
+	Closed []string
+	Fields []callerFieldData
+	// Constraints are the schema's validation keywords on the struct's fields,
+	// checked on plain once the parents are parsed
+	Constraints []constraintData
+	// Constants are the constant fields of a child, dropped for their
+	// accessors, whose values are checked in the JSON instead
+	Constants []constantCheckData
+	// ChildMarshaler is set when a parent's marshalers already declare the
+	// struct's MarshalJSON, which then runs the caller checks itself
+	ChildMarshaler bool
+}
+
+// constantCheckData is a constant field a child's UnmarshalJSON checks
+type constantCheckData struct {
+	Field string
+	// Value is the constant as Go source, equal to the JSON value once
+	// decoded into an interface{}
+	Value string
+}
+
+// callerFieldData is one field of a caller struct holding a parent
+type callerFieldData struct {
+	Struct        string
+	Field         string
+	GoField       string
+	Local         string
+	Parent        string
+	IsArray       bool
+	IsNestedArray bool
+	IsMap         bool
+	IsRequired    bool
+	IsNullable    bool
+}
+
+// templateData builds the data for the file templates from the analysis results
+// and the model the generated code is merged into
+func (cg *CodeGenerator) templateData(model *modelFile) (fileData, error) {
+	data := fileData{Package: cg.config.PackageName(model.file.Name.Name)}
+
+	for _, parentName := range cg.results.SortedParentNames() {
+		parent := cg.newParentData(parentName, cg.results.Parents[parentName])
+		if parent.Discriminator == DiscriminatorKeys {
+			data.NeedsKeyHelper = true
+		}
+		data.Parents = append(data.Parents, parent)
+	}
+	constantFields := model.constantFieldNames(data.Parents)
+	model.constantFields = constantFields
+
+	// Group the callers by struct, since each struct gets one UnmarshalJSON
+	callers := make(map[string]*callerData)
+	var order []string
+	for _, caller := range cg.results.SortedParentCallers() {
+		structName, field, err := model.resolveCaller(caller, cg.results.RootProperties)
+		if err != nil {
+			return fileData{}, errors.Errorf("resolving caller %s.%s: %w", caller.Name, caller.Field, err)
+		}
+
+		c, ok := callers[structName]
+		if !ok {
+			c = &callerData{
+				Struct:       structName,
+				RequiredKeys: cg.requiredKeys(caller.ObjectPointer()),
+				Closed:       cg.results.Closed[caller.ObjectPointer()],
+				Constraints:  model.constraintData(structName, cg.results.Constraints[caller.ObjectPointer()]),
+			}
+			callers[structName] = c
+			order = append(order, structName)
+		}
+
+		goField := field.Names[0].Name
+		c.Fields = append(c.Fields, callerFieldData{
+			Struct:        structName,
+			Field:         caller.Field,
+			GoField:       goField,
+			Local:         localName(goField),
+			Parent:        cg.config.InterfaceName(caller.ParentRef),
+			IsArray:       caller.IsArray,
+			IsNestedArray: caller.IsNestedArray,
+			IsMap:         caller.IsMap,
+			IsRequired:    caller.IsRequired,
+			IsNullable:    caller.IsNullable,
+		})
+	}
+
+	// A child's constant field makes way for its accessor, so the child gets an
+	// UnmarshalJSON that checks the constant, even when it holds no parents
+	for _, parent := range data.Parents {
+		for _, child := range parent.Marshalers {
+			c, ok := callers[child]
+			if !ok {
+				pointer := jsonPointer("definitions", child)
+				c = &callerData{
+					Struct:       child,
+					RequiredKeys: cg.requiredKeys(pointer),
+					Closed:       cg.results.Closed[pointer],
+					Constraints:  model.constraintData(child, cg.results.Constraints[pointer]),
+				}
+				callers[child] = c
+				order = append(order, child)
+			}
+			constant := Constant{Value: parent.Info.ConstantValues[child], Kind: parent.Info.ConstantKind}
+			c.Constants = append(c.Constants, constantCheckData{Field: parent.ConstantField, Value: constant.Decoded()})
+		}
+	}
+
+	sort.Strings(order)
+	for _, structName := range order {
+		data.Callers = append(data.Callers, *callers[structName])
+	}
+
+	// A child with a constant marshaler can't get a second MarshalJSON, so its
+	// marshaler runs the caller checks instead
+	for i := range data.Parents {
+		parent := &data.Parents[i]
+		for _, child := range parent.Marshalers {
+			for j := range data.Callers {
+				if data.Callers[j].Struct != child {
+					continue
+				}
+				data.Callers[j].ChildMarshaler = true
+				if parent.MarshalChecks == nil {
+					parent.MarshalChecks = make(map[string]*callerData)
+				}
+				parent.MarshalChecks[child] = &data.Callers[j]
+			}
+		}
+	}
+
+	// The constructors and clones see the fields with the types they'll have
+	// once merged
+	model.retypeCallerFields(data.Callers)
+	// A renamed parent leaves behind the placeholder go-jsonschema declared for it
+	for _, parent := range data.Parents {
+		if parent.Name != parent.Definition {
+			model.replaced[parent.Definition] = true
+		}
+	}
+	data.Structs = unionStructs(data)
+	data.Constructors = cg.constructors(model, data.Parents, constantFields)
+	data.Clones, data.NeedsCloneAny = clones(model, data.Parents, data.Structs, constantFields)
+	data.Equals, data.EqualHelpers = equals(model, data.Parents, data.Structs, constantFields)
+	data.Validations = cg.validations(model, data.Parents, data.Callers)
+	data.NeedsConstraintHelpers = needsConstraintHelpers(data)
+
+	if err := model.checkMethodFields(cg.generatedMethods(data), constantFields); err != nil {
+		return fileData{}, err
+	}
+
+	return data, nil
+}
+
+// needsConstraintHelpers reports whether any rendered check calls isMultipleOf
+// or duplicateJSONItem
+func needsConstraintHelpers(data fileData) bool {
+	var constraints []constraintData
+	for _, caller := range data.Callers {
+		constraints = append(constraints, caller.Constraints...)
+	}
+	for _, constructor := range data.Constructors {
+		constraints = append(constraints, constructor.Constraints...)
+	}
+	for _, validation := range data.Validations {
+		constraints = append(constraints, validation.Constraints...)
+	}
+	for _, constraint := range constraints {
+		if constraint.Keyword == "multipleOf" || constraint.Keyword == "uniqueItems" {
+			return true
+		}
+	}
+	return false
+}
+
+// generatedMethods maps each struct to the exported methods the emitted
+// templates declare on it
+func (cg *CodeGenerator) generatedMethods(data fileData) map[string][]string {
+	methods := make(map[string][]string)
+	add := func(structName string, names ...string) {
+		for _, name := range names {
+			methods[structName] = appendUnique(methods[structName], name)
+		}
+	}
+	for _, parent := range data.Parents {
+		for _, impl := range parent.Implementations {
+			if impl.ConstantName != "" {
+				add(impl.Child, parent.ConstantMethod)
+			}
+		}
+	}
+	if cg.config.Emits("yaml") {
+		for _, name := range data.Structs {
+			add(name, "UnmarshalYAML", "MarshalYAML")
+		}
+	}
+	if cg.config.Emits("methods") {
+		for _, clone := range data.Clones {
+			add(clone.Struct, "Clone")
+		}
+		for _, equal := range data.Equals {
+			add(equal.Struct, "Equal")
+		}
+	}
+	if cg.config.Emits("validate") {
+		for _, validation := range data.Validations {
+			if !validation.Enum {
+				add(validation.Type, "Validate")
+			}
+		}
+	}
+	return methods
+}
+
+// checkMethodFields fails when a struct has a field named after one of its
+// generated methods, other than the constant field its accessor replaces,
+// since the field can't be kept alongside the method
+func (m *modelFile) checkMethodFields(methods map[string][]string, constantFields map[string]string) error {
+	var clashes []string
+	for _, structName := range sortedTypeNames(m.types) {
+		structType, ok := m.types[structName].Type.(*ast.StructType)
+		if !ok || len(methods[structName]) == 0 {
+			continue
+		}
+		for _, field := range structType.Fields.List {
+			for _, ident := range field.Names {
+				if ident.Name != constantFields[structName] && containsString(methods[structName], ident.Name) {
+					clashes = append(clashes, fmt.Sprintf("%s.%s (property %q)", structName, ident.Name, jsonFieldName(field)))
+				}
+			}
+		}
+	}
+	if len(clashes) > 0 {
+		return errors.Errorf("fields named like the methods generated for their structs: %s", strings.Join(clashes, ", "))
+	}
+	return nil
+}
+
+// unionStructs lists the leaf children of every parent and the callers, which
+// all need YAML methods for yaml.v3 to decode the parents they're reached through
+func unionStructs(data fileData) []string {
+	seen := make(map[string]bool)
+	var names []string
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, parent := range data.Parents {
+		for _, child := range parent.Info.LeafChildren {
+			add(child)
+		}
+	}
+	for _, caller := range data.Callers {
+		add(caller.Struct)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// requiredKeys returns the keys an object's JSON must contain: those its schema
+// requires, then any extra keys the config declares for it
+func (cg *CodeGenerator) requiredKeys(pointer string) []string {
+	var keys []string
+	for _, key := range cg.results.Required[pointer] {
+		keys = appendUnique(keys, key)
+	}
+	for _, key := range cg.config.RequiredKeys(pointer) {
+		keys = appendUnique(keys, key)
+	}
+	return keys
+}
+
+// appendUnique appends value unless it's already in values
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// reservedLocals are the variable names the caller template declares itself
+var reservedLocals = map[string]bool{
+	"b": true, "j": true, "raw": true, "plain": true, "decoded": true, "err": true, "ok": true,
+	"arr": true, "item": true, "items": true, "row": true, "rows": true, "parsed": true, "key": true,
+}
+
+// localName returns a variable name for a field that can't clash with keywords
+// or the template's own variables
+func localName(goField string) string {
+	name := camelCase(goField)
+	if token.IsKeyword(name) || reservedLocals[name] {
+		return name + "Field"
+	}
+	return name
+}
+
+// newParentData flattens a parent's analysis into the shape the templates use,
+// with the names the config gives it
+func (cg *CodeGenerator) newParentData(defName string, info ParentInfo) parentData {
+	override := cg.config.Parent(defName)
+	parentName := cg.config.InterfaceName(defName)
+	parent := parentData{
+		Name:          parentName,
+		Definition:    defName,
+		Info:          info,
+		Children:      info.Children,
+		EmbeddedIn:    cg.interfaceNames(info.EmbeddedIn),
+		Discriminator: info.Discriminator,
+		ModelType:     parentName + "Model",
+		TrialOptions:  info.DirectChildren(),
+		NestedParents: cg.interfaceNames(info.NestedParents),
+	}
+	if override.ConstantType != "" {
+		parent.ModelType = override.ConstantType
+	}
+
+	if info.ConstantField != "" {
+		parent.ConstantField = info.ConstantField
+		parent.ConstantMethod = exportedIdentifier(info.ConstantField)
+		if override.ConstantMethod != "" {
+			parent.ConstantMethod = override.ConstantMethod
+		}
+		parent.ConstantGoType = info.ConstantKind.GoType()
+		// Parents with nested parents skip the accessor, since each nested
+		// parent declares it with its own return type
+		parent.DeclareConstantMethod = len(info.NestedParents) == 0
+		parent.ErrorVerb = "%s"
+		if info.ConstantKind != ConstantKindString {
+			parent.ErrorVerb = "%v"
+		}
+	}
+
+	written := make(map[string]bool)
+	constWritten := make(map[string]bool)
+	for _, childName := range info.LeafChildren {
+		if written[childName] {
+			continue
+		}
+		written[childName] = true
+
+		impl := implementationData{Child: childName}
+		constValue, ok := info.ConstantValues[childName]
+		routed := info.isRoutedThroughNested(childName)
+		if info.ConstantField != "" && ok && constValue != "" && !routed {
+			impl.ConstantName = constantName(parent.ModelType, constValue)
+		}
+		parent.Implementations = append(parent.Implementations, impl)
+
+		if info.ConstantField != "" && ok {
+			if !constWritten[constValue] {
+				constWritten[constValue] = true
+				parent.Constants = append(parent.Constants, constantData{
+					Name:    constantName(parent.ModelType, constValue),
+					Literal: info.ConstantKind.Literal(constValue),
+				})
+			}
+			if !routed {
+				parent.Marshalers = append(parent.Marshalers, childName)
+			}
+		}
+	}
+
+	children := make([]string, 0, len(parent.Implementations))
+	for _, impl := range parent.Implementations {
+		children = append(children, impl.Child)
+	}
+	for i, name := range visitorNames(children) {
+		if visitor, ok := override.Visitors[children[i]]; ok {
+			name = visitor
+		}
+		parent.Visitors = append(parent.Visitors, visitorData{Child: children[i], Name: name})
+	}
+
+	switch info.Discriminator {
+	case DiscriminatorConstant:
+		for _, childName := range info.LeafChildren {
+			if constValue, ok := info.ConstantValues[childName]; ok {
+				parent.Cases = append(parent.Cases, cg.newCaseData(info, childName, constantName(parent.ModelType, constValue)))
+			}
+		}
+	case DiscriminatorComposite:
+		for _, field := range info.CompositeFields {
+			parent.CompositeFields = append(parent.CompositeFields, fieldData{Name: field, GoName: exportedIdentifier(field)})
+		}
+		parent.CompositeFieldNames = info.CompositeFields
+		for _, childName := range info.LeafChildren {
+			values, ok := info.CompositeValues[childName]
+			if !ok {
+				continue
+			}
+			conditions := make([]string, len(values))
+			for i, value := range values {
+				conditions[i] = "plain." + parent.CompositeFields[i].GoName + " == " + value.Decoded()
+			}
+			parent.Cases = append(parent.Cases, cg.newCaseData(info, childName, strings.Join(conditions, " && ")))
+		}
+	case DiscriminatorPresence:
+		for _, childName := range info.LeafChildren {
+			if field, ok := info.PresenceFields[childName]; ok {
+				parent.Cases = append(parent.Cases, cg.newCaseData(info, childName, field))
+			}
+		}
+	case DiscriminatorKeys:
+		for _, step := range info.DecisionPlan {
+			c := cg.newCaseData(info, step.Child, "")
+			if len(step.Keys) == 0 {
+				parent.Fallback = &c
+				break
+			}
+			c.Keys = step.Keys
+			parent.Cases = append(parent.Cases, c)
+		}
+	}
+
+	return parent
+}
+
+// visitorNames shortens the children's names by the camel case words they all
+// end with, keeping at least one word of each, so VisitRGB and VisitHSL stand
+// for RGBValue and HSLValue. A single child keeps its full name
+func visitorNames(children []string) []string {
+	names := append([]string{}, children...)
+	if len(children) < 2 {
+		return names
+	}
+
+	words := make([][]string, len(children))
+	shortest := -1
+	for i, child := range children {
+		words[i] = camelWords(child)
+		if shortest < 0 || len(words[i]) < shortest {
+			shortest = len(words[i])
+		}
+	}
+
+	common := 0
+	for common < shortest-1 {
+		word := words[0][len(words[0])-1-common]
+		same := true
+		for _, w := range words[1:] {
+			if w[len(w)-1-common] != word {
+				same = false
+				break
+			}
+		}
+		if !same {
+			break
+		}
+		common++
+	}
+
+	for i, w := range words {
+		names[i] = strings.Join(w[:len(w)-common], "")
+	}
+	return names
+}
+
+// camelWords splits a camel case identifier into words, keeping acronyms
+// together, so RGBValue splits into RGB and Value
+func camelWords(s string) []string {
+	runes := []rune(s)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		lowerToUpper := !unicode.IsUpper(prev) && unicode.IsUpper(cur)
+		acronymEnd := unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if lowerToUpper || acronymEnd {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(runes[start:]))
+}
+
+// newCaseData creates the dispatch branch for a leaf child
+func (cg *CodeGenerator) newCaseData(info ParentInfo, childName, label string) caseData {
+	c := caseData{Child: childName, Label: label}
+	if info.isRoutedThroughNested(childName) {
+		c.Route = cg.config.InterfaceName(info.LeafRoutes[childName])
+	}
+	return c
+}
+
+// interfaceNames returns the Go names of the parents with the given definition names
+func (cg *CodeGenerator) interfaceNames(defNames []string) []string {
+	if len(defNames) == 0 {
+		return defNames
+	}
+	names := make([]string, 0, len(defNames))
+	for _, defName := range defNames {
+		names = append(names, cg.config.InterfaceName(defName))
+	}
+	return names
+}
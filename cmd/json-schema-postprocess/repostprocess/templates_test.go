@@ -0,0 +1,58 @@
+package repostprocess
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCodeGenerator_TemplateOverride(t *testing.T) {
+	// Set up paths
+	schemaPath := filepath.Join("testdata", "color", "color.schema.json")
+	modelPath := filepath.Join("testdata", "color", "model.gen.go")
+
+	tmpDir := t.TempDir()
+	outputDir := filepath.Join(tmpDir, "output")
+	templatesDir := filepath.Join(tmpDir, "templates")
+	for _, dir := range []string{outputDir, templatesDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+	}
+
+	// Override only the marshalers template; everything else stays built in
+	override := `{{define "marshalers"}}{{range .Marshalers}}// {{.}} is marshaled by the project's own code
+
+{{end}}{{end}}`
+	if err := os.WriteFile(filepath.Join(templatesDir, "marshalers.tmpl"), []byte(override), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	analyzer, err := NewSchemaAnalyzer(schemaPath)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+
+	results, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Failed to analyze schema: %v", err)
+	}
+
+	generator := NewCodeGenerator(modelPath, outputDir, results)
+	generator.SetTemplatesDir(templatesDir)
+	if err := generator.Generate(); err != nil {
+		t.Fatalf("Failed to generate code: %v", err)
+	}
+
+	modelStr := readModel(t, outputDir)
+
+	checkForContent(t, modelStr, "// RGBValue is marshaled by the project's own code")
+	checkForAbsence(t, modelStr, "func (j RGBValue) MarshalJSON()")
+	checkForContent(t, modelStr, "func parseUnknownColor(b interface{}) (Color, error)")
+}
+
+func TestLoadTemplates_EmptyDir(t *testing.T) {
+	if _, err := loadTemplates(t.TempDir()); err == nil {
+		t.Fatalf("Expected an error for a templates directory without *.tmpl files")
+	}
+}
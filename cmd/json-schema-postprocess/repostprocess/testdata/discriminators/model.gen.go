@@ -0,0 +1,345 @@
+// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+
+package discriminators
+
+import "encoding/json"
+import "fmt"
+
+type DisabledFlag struct {
+	// Enabled corresponds to the JSON schema field "enabled".
+	Enabled bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+
+	// Reason corresponds to the JSON schema field "reason".
+	Reason string `json:"reason" yaml:"reason" mapstructure:"reason"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *DisabledFlag) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["enabled"]; raw != nil && !ok {
+		return fmt.Errorf("field enabled in DisabledFlag: required")
+	}
+	if _, ok := raw["reason"]; raw != nil && !ok {
+		return fmt.Errorf("field reason in DisabledFlag: required")
+	}
+	type Plain DisabledFlag
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = DisabledFlag(plain)
+	return nil
+}
+
+type EnabledFlag struct {
+	// Enabled corresponds to the JSON schema field "enabled".
+	Enabled bool `json:"enabled" yaml:"enabled" mapstructure:"enabled"`
+
+	// Rollout corresponds to the JSON schema field "rollout".
+	Rollout *float64 `json:"rollout,omitempty" yaml:"rollout,omitempty" mapstructure:"rollout,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *EnabledFlag) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["enabled"]; raw != nil && !ok {
+		return fmt.Errorf("field enabled in EnabledFlag: required")
+	}
+	type Plain EnabledFlag
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	if plain.Rollout != nil && 1 < *plain.Rollout {
+		return fmt.Errorf("field %s: must be <= %v", "rollout", 1)
+	}
+	if plain.Rollout != nil && 0 > *plain.Rollout {
+		return fmt.Errorf("field %s: must be >= %v", "rollout", 0)
+	}
+	*j = EnabledFlag(plain)
+	return nil
+}
+
+type Envelope struct {
+	// Event corresponds to the JSON schema field "event".
+	Event EnvelopeEvent `json:"event" yaml:"event" mapstructure:"event"`
+
+	// Flag corresponds to the JSON schema field "flag".
+	Flag EnvelopeFlag `json:"flag" yaml:"flag" mapstructure:"flag"`
+
+	// Messages corresponds to the JSON schema field "messages".
+	Messages []EnvelopeMessagesElem `json:"messages" yaml:"messages" mapstructure:"messages"`
+
+	// Source corresponds to the JSON schema field "source".
+	Source EnvelopeSource `json:"source" yaml:"source" mapstructure:"source"`
+}
+
+type EnvelopeEvent interface{}
+
+type EnvelopeFlag interface{}
+
+type EnvelopeMessagesElem interface{}
+
+type EnvelopeSource interface{}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *Envelope) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["event"]; raw != nil && !ok {
+		return fmt.Errorf("field event in Envelope: required")
+	}
+	if _, ok := raw["flag"]; raw != nil && !ok {
+		return fmt.Errorf("field flag in Envelope: required")
+	}
+	if _, ok := raw["messages"]; raw != nil && !ok {
+		return fmt.Errorf("field messages in Envelope: required")
+	}
+	if _, ok := raw["source"]; raw != nil && !ok {
+		return fmt.Errorf("field source in Envelope: required")
+	}
+	type Plain Envelope
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = Envelope(plain)
+	return nil
+}
+
+type Event interface{}
+
+type EventV1 struct {
+	// Name corresponds to the JSON schema field "name".
+	Name string `json:"name" yaml:"name" mapstructure:"name"`
+
+	// Version corresponds to the JSON schema field "version".
+	Version int `json:"version" yaml:"version" mapstructure:"version"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *EventV1) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["name"]; raw != nil && !ok {
+		return fmt.Errorf("field name in EventV1: required")
+	}
+	if _, ok := raw["version"]; raw != nil && !ok {
+		return fmt.Errorf("field version in EventV1: required")
+	}
+	type Plain EventV1
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = EventV1(plain)
+	return nil
+}
+
+type EventV2 struct {
+	// Name corresponds to the JSON schema field "name".
+	Name string `json:"name" yaml:"name" mapstructure:"name"`
+
+	// Tags corresponds to the JSON schema field "tags".
+	Tags []string `json:"tags" yaml:"tags" mapstructure:"tags"`
+
+	// Version corresponds to the JSON schema field "version".
+	Version int `json:"version" yaml:"version" mapstructure:"version"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *EventV2) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["name"]; raw != nil && !ok {
+		return fmt.Errorf("field name in EventV2: required")
+	}
+	if _, ok := raw["tags"]; raw != nil && !ok {
+		return fmt.Errorf("field tags in EventV2: required")
+	}
+	if _, ok := raw["version"]; raw != nil && !ok {
+		return fmt.Errorf("field version in EventV2: required")
+	}
+	type Plain EventV2
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = EventV2(plain)
+	return nil
+}
+
+type FileSource struct {
+	// Label corresponds to the JSON schema field "label".
+	Label *string `json:"label,omitempty" yaml:"label,omitempty" mapstructure:"label,omitempty"`
+
+	// Path corresponds to the JSON schema field "path".
+	Path string `json:"path" yaml:"path" mapstructure:"path"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *FileSource) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["path"]; raw != nil && !ok {
+		return fmt.Errorf("field path in FileSource: required")
+	}
+	type Plain FileSource
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = FileSource(plain)
+	return nil
+}
+
+type Flag interface{}
+
+type ImageV1 struct {
+	// Kind corresponds to the JSON schema field "kind".
+	Kind string `json:"kind" yaml:"kind" mapstructure:"kind"`
+
+	// Url corresponds to the JSON schema field "url".
+	Url string `json:"url" yaml:"url" mapstructure:"url"`
+
+	// Version corresponds to the JSON schema field "version".
+	Version int `json:"version" yaml:"version" mapstructure:"version"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *ImageV1) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["kind"]; raw != nil && !ok {
+		return fmt.Errorf("field kind in ImageV1: required")
+	}
+	if _, ok := raw["url"]; raw != nil && !ok {
+		return fmt.Errorf("field url in ImageV1: required")
+	}
+	if _, ok := raw["version"]; raw != nil && !ok {
+		return fmt.Errorf("field version in ImageV1: required")
+	}
+	type Plain ImageV1
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = ImageV1(plain)
+	return nil
+}
+
+type Message interface{}
+
+type Source interface{}
+
+type TextV1 struct {
+	// Body corresponds to the JSON schema field "body".
+	Body string `json:"body" yaml:"body" mapstructure:"body"`
+
+	// Kind corresponds to the JSON schema field "kind".
+	Kind string `json:"kind" yaml:"kind" mapstructure:"kind"`
+
+	// Version corresponds to the JSON schema field "version".
+	Version int `json:"version" yaml:"version" mapstructure:"version"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *TextV1) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["body"]; raw != nil && !ok {
+		return fmt.Errorf("field body in TextV1: required")
+	}
+	if _, ok := raw["kind"]; raw != nil && !ok {
+		return fmt.Errorf("field kind in TextV1: required")
+	}
+	if _, ok := raw["version"]; raw != nil && !ok {
+		return fmt.Errorf("field version in TextV1: required")
+	}
+	type Plain TextV1
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = TextV1(plain)
+	return nil
+}
+
+type TextV2 struct {
+	// Kind corresponds to the JSON schema field "kind".
+	Kind string `json:"kind" yaml:"kind" mapstructure:"kind"`
+
+	// Markdown corresponds to the JSON schema field "markdown".
+	Markdown string `json:"markdown" yaml:"markdown" mapstructure:"markdown"`
+
+	// Version corresponds to the JSON schema field "version".
+	Version int `json:"version" yaml:"version" mapstructure:"version"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *TextV2) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["kind"]; raw != nil && !ok {
+		return fmt.Errorf("field kind in TextV2: required")
+	}
+	if _, ok := raw["markdown"]; raw != nil && !ok {
+		return fmt.Errorf("field markdown in TextV2: required")
+	}
+	if _, ok := raw["version"]; raw != nil && !ok {
+		return fmt.Errorf("field version in TextV2: required")
+	}
+	type Plain TextV2
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = TextV2(plain)
+	return nil
+}
+
+type URLSource struct {
+	// Label corresponds to the JSON schema field "label".
+	Label *string `json:"label,omitempty" yaml:"label,omitempty" mapstructure:"label,omitempty"`
+
+	// Url corresponds to the JSON schema field "url".
+	Url string `json:"url" yaml:"url" mapstructure:"url"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *URLSource) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["url"]; raw != nil && !ok {
+		return fmt.Errorf("field url in URLSource: required")
+	}
+	type Plain URLSource
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = URLSource(plain)
+	return nil
+}
@@ -5,7 +5,11 @@ import (
 )
 
 //go:generate go tool go-jsonschema ./color/color.schema.json -o=./color/model.gen.go -p=color
+//go:generate go tool go-jsonschema ./discriminators/discriminators.schema.json -o=./discriminators/model.gen.go -p=discriminators
 //go:generate go tool go-jsonschema ./confusing/confusing.schema.json -o=./confusing/model.gen.go -p=confusing
+//go:generate go tool go-jsonschema ./nested/nested.schema.json -o=./nested/model.gen.go -p=nested
+//go:generate go tool go-jsonschema ./overlap/overlap.schema.json -o=./overlap/model.gen.go -p=overlap
+//go:generate go tool go-jsonschema ./optional/optional.schema.json -o=./optional/model.gen.go -p=optional
 //go:generate go tool go-jsonschema ./simple/simple.schema.json -o=./simple/model.gen.go -p=simple
 
 //go:embed *
@@ -0,0 +1,81 @@
+package gotypes
+
+import "time"
+
+// Shape is anything a drawing is made of
+type Shape interface {
+	isShape()
+	Kind() ShapeKind
+}
+
+// Polygon is a shape with straight sides
+type Polygon interface {
+	Shape
+	isPolygon()
+}
+
+// ShapeKind tells the shapes apart
+type ShapeKind string
+
+const (
+	ShapeKindCircle   ShapeKind = "circle"
+	ShapeKindSquare   ShapeKind = "square"
+	ShapeKindTriangle ShapeKind = "triangle"
+)
+
+// Circle is a round shape
+type Circle struct {
+	// Radius is in points
+	Radius float64 `json:"radius"`
+	Label  *string `json:"label,omitempty"`
+}
+
+func (me *Circle) isShape()        {}
+func (me *Circle) Kind() ShapeKind { return ShapeKindCircle }
+
+type Square struct {
+	Side int `json:"side"`
+}
+
+func (me *Square) isShape()        {}
+func (me *Square) isPolygon()      {}
+func (me *Square) Kind() ShapeKind { return ShapeKindSquare }
+
+type Triangle struct {
+	Corners [3]Point `json:"corners"`
+}
+
+func (me *Triangle) isShape()        {}
+func (me *Triangle) isPolygon()      {}
+func (me *Triangle) Kind() ShapeKind { return ShapeKindTriangle }
+
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Style is how a shape's outline is drawn
+type Style string
+
+const (
+	StyleSolid  Style = "solid"
+	StyleDashed Style = "dashed"
+)
+
+// Drawing is a set of shapes
+type Drawing struct {
+	Meta
+	Shapes  []Shape          `json:"shapes"`
+	Layers  map[string]Shape `json:"layers,omitempty"`
+	Style   Style            `json:"style"`
+	Note    *string          `json:"note"`
+	Tags    []string         `json:"tags,omitempty"`
+	Created time.Time        `json:"created"`
+	cache   []byte
+}
+
+// Meta is embedded in the drawing, so its fields are the drawing's own
+type Meta struct {
+	Title string `json:"title"`
+	Draft bool   `json:"-"`
+}
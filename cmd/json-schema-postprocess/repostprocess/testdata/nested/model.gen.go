@@ -0,0 +1,221 @@
+// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+
+package nested
+
+import "encoding/json"
+import "fmt"
+
+type Canvas struct {
+	// Background corresponds to the JSON schema field "background".
+	Background CanvasBackground `json:"background" yaml:"background" mapstructure:"background"`
+
+	// Layers corresponds to the JSON schema field "layers".
+	Layers []Layer `json:"layers" yaml:"layers" mapstructure:"layers"`
+}
+
+type CanvasBackground interface{}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *Canvas) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["background"]; raw != nil && !ok {
+		return fmt.Errorf("field background in Canvas: required")
+	}
+	if _, ok := raw["layers"]; raw != nil && !ok {
+		return fmt.Errorf("field layers in Canvas: required")
+	}
+	type Plain Canvas
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = Canvas(plain)
+	return nil
+}
+
+type Color interface{}
+
+type Fill interface{}
+
+type Gradient struct {
+	// Kind corresponds to the JSON schema field "kind".
+	Kind string `json:"kind" yaml:"kind" mapstructure:"kind"`
+
+	// Stops corresponds to the JSON schema field "stops".
+	Stops []GradientStopsElem `json:"stops" yaml:"stops" mapstructure:"stops"`
+}
+
+type GradientStopsElem interface{}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *Gradient) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["kind"]; raw != nil && !ok {
+		return fmt.Errorf("field kind in Gradient: required")
+	}
+	if _, ok := raw["stops"]; raw != nil && !ok {
+		return fmt.Errorf("field stops in Gradient: required")
+	}
+	type Plain Gradient
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = Gradient(plain)
+	return nil
+}
+
+type HSLColor struct {
+	// H corresponds to the JSON schema field "h".
+	H float64 `json:"h" yaml:"h" mapstructure:"h"`
+
+	// Kind corresponds to the JSON schema field "kind".
+	Kind string `json:"kind" yaml:"kind" mapstructure:"kind"`
+
+	// L corresponds to the JSON schema field "l".
+	L float64 `json:"l" yaml:"l" mapstructure:"l"`
+
+	// S corresponds to the JSON schema field "s".
+	S float64 `json:"s" yaml:"s" mapstructure:"s"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *HSLColor) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["h"]; raw != nil && !ok {
+		return fmt.Errorf("field h in HSLColor: required")
+	}
+	if _, ok := raw["kind"]; raw != nil && !ok {
+		return fmt.Errorf("field kind in HSLColor: required")
+	}
+	if _, ok := raw["l"]; raw != nil && !ok {
+		return fmt.Errorf("field l in HSLColor: required")
+	}
+	if _, ok := raw["s"]; raw != nil && !ok {
+		return fmt.Errorf("field s in HSLColor: required")
+	}
+	type Plain HSLColor
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	if 360 < plain.H {
+		return fmt.Errorf("field %s: must be <= %v", "h", 360)
+	}
+	if 0 > plain.H {
+		return fmt.Errorf("field %s: must be >= %v", "h", 0)
+	}
+	if 1 < plain.L {
+		return fmt.Errorf("field %s: must be <= %v", "l", 1)
+	}
+	if 0 > plain.L {
+		return fmt.Errorf("field %s: must be >= %v", "l", 0)
+	}
+	if 1 < plain.S {
+		return fmt.Errorf("field %s: must be <= %v", "s", 1)
+	}
+	if 0 > plain.S {
+		return fmt.Errorf("field %s: must be >= %v", "s", 0)
+	}
+	*j = HSLColor(plain)
+	return nil
+}
+
+type Layer struct {
+	// Fill corresponds to the JSON schema field "fill".
+	Fill LayerFill `json:"fill" yaml:"fill" mapstructure:"fill"`
+
+	// Name corresponds to the JSON schema field "name".
+	Name string `json:"name" yaml:"name" mapstructure:"name"`
+}
+
+type LayerFill interface{}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *Layer) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["fill"]; raw != nil && !ok {
+		return fmt.Errorf("field fill in Layer: required")
+	}
+	if _, ok := raw["name"]; raw != nil && !ok {
+		return fmt.Errorf("field name in Layer: required")
+	}
+	type Plain Layer
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = Layer(plain)
+	return nil
+}
+
+type RGBColor struct {
+	// B corresponds to the JSON schema field "b".
+	B float64 `json:"b" yaml:"b" mapstructure:"b"`
+
+	// G corresponds to the JSON schema field "g".
+	G float64 `json:"g" yaml:"g" mapstructure:"g"`
+
+	// Kind corresponds to the JSON schema field "kind".
+	Kind string `json:"kind" yaml:"kind" mapstructure:"kind"`
+
+	// R corresponds to the JSON schema field "r".
+	R float64 `json:"r" yaml:"r" mapstructure:"r"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *RGBColor) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["b"]; raw != nil && !ok {
+		return fmt.Errorf("field b in RGBColor: required")
+	}
+	if _, ok := raw["g"]; raw != nil && !ok {
+		return fmt.Errorf("field g in RGBColor: required")
+	}
+	if _, ok := raw["kind"]; raw != nil && !ok {
+		return fmt.Errorf("field kind in RGBColor: required")
+	}
+	if _, ok := raw["r"]; raw != nil && !ok {
+		return fmt.Errorf("field r in RGBColor: required")
+	}
+	type Plain RGBColor
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	if 255 < plain.B {
+		return fmt.Errorf("field %s: must be <= %v", "b", 255)
+	}
+	if 0 > plain.B {
+		return fmt.Errorf("field %s: must be >= %v", "b", 0)
+	}
+	if 255 < plain.G {
+		return fmt.Errorf("field %s: must be <= %v", "g", 255)
+	}
+	if 0 > plain.G {
+		return fmt.Errorf("field %s: must be >= %v", "g", 0)
+	}
+	if 255 < plain.R {
+		return fmt.Errorf("field %s: must be <= %v", "r", 255)
+	}
+	if 0 > plain.R {
+		return fmt.Errorf("field %s: must be >= %v", "r", 0)
+	}
+	*j = RGBColor(plain)
+	return nil
+}
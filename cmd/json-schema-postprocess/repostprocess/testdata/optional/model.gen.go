@@ -0,0 +1,196 @@
+// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+
+package optional
+
+import "encoding/json"
+import "fmt"
+import "reflect"
+import "regexp"
+
+type Color interface{}
+
+type HSLValue struct {
+	// H corresponds to the JSON schema field "h".
+	H float64 `json:"h" yaml:"h" mapstructure:"h"`
+
+	// Model corresponds to the JSON schema field "model".
+	Model string `json:"model" yaml:"model" mapstructure:"model"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *HSLValue) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["h"]; raw != nil && !ok {
+		return fmt.Errorf("field h in HSLValue: required")
+	}
+	if _, ok := raw["model"]; raw != nil && !ok {
+		return fmt.Errorf("field model in HSLValue: required")
+	}
+	type Plain HSLValue
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = HSLValue(plain)
+	return nil
+}
+
+type OptionalSchemaJson struct {
+	// Swatches corresponds to the JSON schema field "swatches".
+	Swatches []Swatch `json:"swatches,omitempty" yaml:"swatches,omitempty" mapstructure:"swatches,omitempty"`
+}
+
+type RGBValue struct {
+	// Model corresponds to the JSON schema field "model".
+	Model string `json:"model" yaml:"model" mapstructure:"model"`
+
+	// R corresponds to the JSON schema field "r".
+	R float64 `json:"r" yaml:"r" mapstructure:"r"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *RGBValue) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["model"]; raw != nil && !ok {
+		return fmt.Errorf("field model in RGBValue: required")
+	}
+	if _, ok := raw["r"]; raw != nil && !ok {
+		return fmt.Errorf("field r in RGBValue: required")
+	}
+	type Plain RGBValue
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = RGBValue(plain)
+	return nil
+}
+
+type Swatch struct {
+	// Accents corresponds to the JSON schema field "accents".
+	Accents []SwatchAccentsElem `json:"accents,omitempty" yaml:"accents,omitempty" mapstructure:"accents,omitempty"`
+
+	// Background corresponds to the JSON schema field "background".
+	Background interface{} `json:"background,omitempty" yaml:"background,omitempty" mapstructure:"background,omitempty"`
+
+	// Highlight corresponds to the JSON schema field "highlight".
+	Highlight SwatchHighlight `json:"highlight,omitempty" yaml:"highlight,omitempty" mapstructure:"highlight,omitempty"`
+
+	// Label corresponds to the JSON schema field "label".
+	Label *string `json:"label,omitempty" yaml:"label,omitempty" mapstructure:"label,omitempty"`
+
+	// Name corresponds to the JSON schema field "name".
+	Name string `json:"name" yaml:"name" mapstructure:"name"`
+
+	// Named corresponds to the JSON schema field "named".
+	Named SwatchNamed `json:"named,omitempty" yaml:"named,omitempty" mapstructure:"named,omitempty"`
+
+	// Opacity corresponds to the JSON schema field "opacity".
+	Opacity *float64 `json:"opacity,omitempty" yaml:"opacity,omitempty" mapstructure:"opacity,omitempty"`
+
+	// Primary corresponds to the JSON schema field "primary".
+	Primary SwatchPrimary `json:"primary" yaml:"primary" mapstructure:"primary"`
+
+	// Tone corresponds to the JSON schema field "tone".
+	Tone *SwatchTone `json:"tone,omitempty" yaml:"tone,omitempty" mapstructure:"tone,omitempty"`
+
+	// Weight corresponds to the JSON schema field "weight".
+	Weight *int `json:"weight,omitempty" yaml:"weight,omitempty" mapstructure:"weight,omitempty"`
+}
+
+type SwatchAccentsElem interface{}
+
+type SwatchHighlight interface{}
+
+type SwatchNamed map[string]interface{}
+
+type SwatchPrimary interface{}
+
+type SwatchTone string
+
+const SwatchToneCool SwatchTone = "cool"
+const SwatchToneWarm SwatchTone = "warm"
+
+var enumValues_SwatchTone = []interface{}{
+	"warm",
+	"cool",
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *SwatchTone) UnmarshalJSON(b []byte) error {
+	var v string
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	var ok bool
+	for _, expected := range enumValues_SwatchTone {
+		if reflect.DeepEqual(v, expected) {
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		return fmt.Errorf("invalid value (expected one of %#v): %#v", enumValues_SwatchTone, v)
+	}
+	*j = SwatchTone(v)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *Swatch) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["name"]; raw != nil && !ok {
+		return fmt.Errorf("field name in Swatch: required")
+	}
+	if _, ok := raw["primary"]; raw != nil && !ok {
+		return fmt.Errorf("field primary in Swatch: required")
+	}
+	type Plain Swatch
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	if plain.Accents != nil && len(plain.Accents) < 1 {
+		return fmt.Errorf("field %s length: must be >= %d", "accents", 1)
+	}
+	if len(plain.Accents) > 4 {
+		return fmt.Errorf("field %s length: must be <= %d", "accents", 4)
+	}
+	if plain.Label != nil {
+		if matched, _ := regexp.MatchString("^[A-Z]", string(*plain.Label)); !matched {
+			return fmt.Errorf("field %s pattern match: must match %s", "^[A-Z]", "Label")
+		}
+	}
+	if matched, _ := regexp.MatchString("^[a-z]+$", string(plain.Name)); !matched {
+		return fmt.Errorf("field %s pattern match: must match %s", "^[a-z]+$", "Name")
+	}
+	if len(plain.Name) < 1 {
+		return fmt.Errorf("field %s length: must be >= %d", "name", 1)
+	}
+	if len(plain.Name) > 32 {
+		return fmt.Errorf("field %s length: must be <= %d", "name", 32)
+	}
+	if plain.Opacity != nil && 1 <= *plain.Opacity {
+		return fmt.Errorf("field %s: must be < %v", "opacity", 1)
+	}
+	if plain.Opacity != nil && 0 > *plain.Opacity {
+		return fmt.Errorf("field %s: must be >= %v", "opacity", 0)
+	}
+	if plain.Weight != nil && 10 < *plain.Weight {
+		return fmt.Errorf("field %s: must be <= %v", "weight", 10)
+	}
+	if plain.Weight != nil && 0 >= *plain.Weight {
+		return fmt.Errorf("field %s: must be > %v", "weight", 0)
+	}
+	*j = Swatch(plain)
+	return nil
+}
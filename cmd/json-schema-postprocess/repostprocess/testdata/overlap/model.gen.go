@@ -0,0 +1,185 @@
+// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+
+package overlap
+
+import "encoding/json"
+import "fmt"
+
+type Asset interface{}
+
+type Document struct {
+	// Pages corresponds to the JSON schema field "pages".
+	Pages *int `json:"pages,omitempty" yaml:"pages,omitempty" mapstructure:"pages,omitempty"`
+
+	// Url corresponds to the JSON schema field "url".
+	Url string `json:"url" yaml:"url" mapstructure:"url"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *Document) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["url"]; raw != nil && !ok {
+		return fmt.Errorf("field url in Document: required")
+	}
+	type Plain Document
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = Document(plain)
+	return nil
+}
+
+type Image struct {
+	// Height corresponds to the JSON schema field "height".
+	Height *int `json:"height,omitempty" yaml:"height,omitempty" mapstructure:"height,omitempty"`
+
+	// Url corresponds to the JSON schema field "url".
+	Url string `json:"url" yaml:"url" mapstructure:"url"`
+
+	// Width corresponds to the JSON schema field "width".
+	Width int `json:"width" yaml:"width" mapstructure:"width"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *Image) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["url"]; raw != nil && !ok {
+		return fmt.Errorf("field url in Image: required")
+	}
+	if _, ok := raw["width"]; raw != nil && !ok {
+		return fmt.Errorf("field width in Image: required")
+	}
+	type Plain Image
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = Image(plain)
+	return nil
+}
+
+type Library struct {
+	// Assets corresponds to the JSON schema field "assets".
+	Assets []LibraryAssetsElem `json:"assets" yaml:"assets" mapstructure:"assets"`
+
+	// Note corresponds to the JSON schema field "note".
+	Note LibraryNote `json:"note" yaml:"note" mapstructure:"note"`
+}
+
+type LibraryAssetsElem interface{}
+
+type LibraryNote interface{}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *Library) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["assets"]; raw != nil && !ok {
+		return fmt.Errorf("field assets in Library: required")
+	}
+	if _, ok := raw["note"]; raw != nil && !ok {
+		return fmt.Errorf("field note in Library: required")
+	}
+	type Plain Library
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = Library(plain)
+	return nil
+}
+
+type Note interface{}
+
+type TaggedNote struct {
+	// Tags corresponds to the JSON schema field "tags".
+	Tags []string `json:"tags" yaml:"tags" mapstructure:"tags"`
+
+	// Text corresponds to the JSON schema field "text".
+	Text string `json:"text" yaml:"text" mapstructure:"text"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *TaggedNote) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["tags"]; raw != nil && !ok {
+		return fmt.Errorf("field tags in TaggedNote: required")
+	}
+	if _, ok := raw["text"]; raw != nil && !ok {
+		return fmt.Errorf("field text in TaggedNote: required")
+	}
+	type Plain TaggedNote
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = TaggedNote(plain)
+	return nil
+}
+
+type TextNote struct {
+	// Text corresponds to the JSON schema field "text".
+	Text string `json:"text" yaml:"text" mapstructure:"text"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *TextNote) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["text"]; raw != nil && !ok {
+		return fmt.Errorf("field text in TextNote: required")
+	}
+	type Plain TextNote
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = TextNote(plain)
+	return nil
+}
+
+type Video struct {
+	// Duration corresponds to the JSON schema field "duration".
+	Duration float64 `json:"duration" yaml:"duration" mapstructure:"duration"`
+
+	// Url corresponds to the JSON schema field "url".
+	Url string `json:"url" yaml:"url" mapstructure:"url"`
+
+	// Width corresponds to the JSON schema field "width".
+	Width *int `json:"width,omitempty" yaml:"width,omitempty" mapstructure:"width,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *Video) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["duration"]; raw != nil && !ok {
+		return fmt.Errorf("field duration in Video: required")
+	}
+	if _, ok := raw["url"]; raw != nil && !ok {
+		return fmt.Errorf("field url in Video: required")
+	}
+	type Plain Video
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = Video(plain)
+	return nil
+}
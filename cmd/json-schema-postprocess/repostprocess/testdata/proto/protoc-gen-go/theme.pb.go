@@ -0,0 +1,546 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: theme.proto
+
+package themepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ColorModel int32
+
+const (
+	ColorModel_COLOR_MODEL_UNSPECIFIED ColorModel = 0
+	ColorModel_COLOR_MODEL_GRAY        ColorModel = 1
+	ColorModel_COLOR_MODEL_NAMED       ColorModel = 2
+)
+
+// Enum value maps for ColorModel.
+var (
+	ColorModel_name = map[int32]string{
+		0: "COLOR_MODEL_UNSPECIFIED",
+		1: "COLOR_MODEL_GRAY",
+		2: "COLOR_MODEL_NAMED",
+	}
+	ColorModel_value = map[string]int32{
+		"COLOR_MODEL_UNSPECIFIED": 0,
+		"COLOR_MODEL_GRAY":        1,
+		"COLOR_MODEL_NAMED":       2,
+	}
+)
+
+func (x ColorModel) Enum() *ColorModel {
+	p := new(ColorModel)
+	*p = x
+	return p
+}
+
+func (x ColorModel) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ColorModel) Descriptor() protoreflect.EnumDescriptor {
+	return file_theme_proto_enumTypes[0].Descriptor()
+}
+
+func (ColorModel) Type() protoreflect.EnumType {
+	return &file_theme_proto_enumTypes[0]
+}
+
+func (x ColorModel) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ColorModel.Descriptor instead.
+func (ColorModel) EnumDescriptor() ([]byte, []int) {
+	return file_theme_proto_rawDescGZIP(), []int{0}
+}
+
+type Tone int32
+
+const (
+	Tone_TONE_UNSPECIFIED Tone = 0
+	Tone_TONE_WARM        Tone = 1
+	Tone_TONE_COOL        Tone = 2
+)
+
+// Enum value maps for Tone.
+var (
+	Tone_name = map[int32]string{
+		0: "TONE_UNSPECIFIED",
+		1: "TONE_WARM",
+		2: "TONE_COOL",
+	}
+	Tone_value = map[string]int32{
+		"TONE_UNSPECIFIED": 0,
+		"TONE_WARM":        1,
+		"TONE_COOL":        2,
+	}
+)
+
+func (x Tone) Enum() *Tone {
+	p := new(Tone)
+	*p = x
+	return p
+}
+
+func (x Tone) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Tone) Descriptor() protoreflect.EnumDescriptor {
+	return file_theme_proto_enumTypes[1].Descriptor()
+}
+
+func (Tone) Type() protoreflect.EnumType {
+	return &file_theme_proto_enumTypes[1]
+}
+
+func (x Tone) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Tone.Descriptor instead.
+func (Tone) EnumDescriptor() ([]byte, []int) {
+	return file_theme_proto_rawDescGZIP(), []int{1}
+}
+
+type Color struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Value:
+	//
+	//	*Color_Gray
+	//	*Color_Named
+	Value         isColor_Value `protobuf_oneof:"value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Color) Reset() {
+	*x = Color{}
+	mi := &file_theme_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Color) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Color) ProtoMessage() {}
+
+func (x *Color) ProtoReflect() protoreflect.Message {
+	mi := &file_theme_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Color.ProtoReflect.Descriptor instead.
+func (*Color) Descriptor() ([]byte, []int) {
+	return file_theme_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Color) GetValue() isColor_Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *Color) GetGray() *Gray {
+	if x != nil {
+		if x, ok := x.Value.(*Color_Gray); ok {
+			return x.Gray
+		}
+	}
+	return nil
+}
+
+func (x *Color) GetNamed() *Named {
+	if x != nil {
+		if x, ok := x.Value.(*Color_Named); ok {
+			return x.Named
+		}
+	}
+	return nil
+}
+
+type isColor_Value interface {
+	isColor_Value()
+}
+
+type Color_Gray struct {
+	Gray *Gray `protobuf:"bytes,1,opt,name=gray,proto3,oneof"`
+}
+
+type Color_Named struct {
+	Named *Named `protobuf:"bytes,2,opt,name=named,proto3,oneof"`
+}
+
+func (*Color_Gray) isColor_Value() {}
+
+func (*Color_Named) isColor_Value() {}
+
+type ColorList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []*Color               `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ColorList) Reset() {
+	*x = ColorList{}
+	mi := &file_theme_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ColorList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ColorList) ProtoMessage() {}
+
+func (x *ColorList) ProtoReflect() protoreflect.Message {
+	mi := &file_theme_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ColorList.ProtoReflect.Descriptor instead.
+func (*ColorList) Descriptor() ([]byte, []int) {
+	return file_theme_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ColorList) GetValues() []*Color {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type Gray struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          ColorModel             `protobuf:"varint,1,opt,name=kind,proto3,enum=theme.ColorModel" json:"kind,omitempty"`
+	Level         float64                `protobuf:"fixed64,2,opt,name=level,proto3" json:"level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Gray) Reset() {
+	*x = Gray{}
+	mi := &file_theme_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Gray) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Gray) ProtoMessage() {}
+
+func (x *Gray) ProtoReflect() protoreflect.Message {
+	mi := &file_theme_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Gray.ProtoReflect.Descriptor instead.
+func (*Gray) Descriptor() ([]byte, []int) {
+	return file_theme_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Gray) GetKind() ColorModel {
+	if x != nil {
+		return x.Kind
+	}
+	return ColorModel_COLOR_MODEL_UNSPECIFIED
+}
+
+func (x *Gray) GetLevel() float64 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+type Named struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          ColorModel             `protobuf:"varint,1,opt,name=kind,proto3,enum=theme.ColorModel" json:"kind,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Tone          *Tone                  `protobuf:"varint,3,opt,name=tone,proto3,enum=theme.Tone,oneof" json:"tone,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Named) Reset() {
+	*x = Named{}
+	mi := &file_theme_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Named) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Named) ProtoMessage() {}
+
+func (x *Named) ProtoReflect() protoreflect.Message {
+	mi := &file_theme_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Named.ProtoReflect.Descriptor instead.
+func (*Named) Descriptor() ([]byte, []int) {
+	return file_theme_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Named) GetKind() ColorModel {
+	if x != nil {
+		return x.Kind
+	}
+	return ColorModel_COLOR_MODEL_UNSPECIFIED
+}
+
+func (x *Named) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Named) GetTone() Tone {
+	if x != nil && x.Tone != nil {
+		return *x.Tone
+	}
+	return Tone_TONE_UNSPECIFIED
+}
+
+type Theme struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accent        *Color                 `protobuf:"bytes,1,opt,name=accent,proto3" json:"accent,omitempty"`
+	Extra         map[string]bool        `protobuf:"bytes,2,rep,name=extra,proto3" json:"extra,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Grid          []*ColorList           `protobuf:"bytes,3,rep,name=grid,proto3" json:"grid,omitempty"`
+	Label         *string                `protobuf:"bytes,4,opt,name=label,proto3,oneof" json:"label,omitempty"`
+	Sizes         []int64                `protobuf:"varint,5,rep,packed,name=sizes,proto3" json:"sizes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Theme) Reset() {
+	*x = Theme{}
+	mi := &file_theme_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Theme) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Theme) ProtoMessage() {}
+
+func (x *Theme) ProtoReflect() protoreflect.Message {
+	mi := &file_theme_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Theme.ProtoReflect.Descriptor instead.
+func (*Theme) Descriptor() ([]byte, []int) {
+	return file_theme_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Theme) GetAccent() *Color {
+	if x != nil {
+		return x.Accent
+	}
+	return nil
+}
+
+func (x *Theme) GetExtra() map[string]bool {
+	if x != nil {
+		return x.Extra
+	}
+	return nil
+}
+
+func (x *Theme) GetGrid() []*ColorList {
+	if x != nil {
+		return x.Grid
+	}
+	return nil
+}
+
+func (x *Theme) GetLabel() string {
+	if x != nil && x.Label != nil {
+		return *x.Label
+	}
+	return ""
+}
+
+func (x *Theme) GetSizes() []int64 {
+	if x != nil {
+		return x.Sizes
+	}
+	return nil
+}
+
+var File_theme_proto protoreflect.FileDescriptor
+
+const file_theme_proto_rawDesc = "" +
+	"\n" +
+	"\vtheme.proto\x12\x05theme\"Y\n" +
+	"\x05Color\x12!\n" +
+	"\x04gray\x18\x01 \x01(\v2\v.theme.GrayH\x00R\x04gray\x12$\n" +
+	"\x05named\x18\x02 \x01(\v2\f.theme.NamedH\x00R\x05namedB\a\n" +
+	"\x05value\"1\n" +
+	"\tColorList\x12$\n" +
+	"\x06values\x18\x01 \x03(\v2\f.theme.ColorR\x06values\"C\n" +
+	"\x04Gray\x12%\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x11.theme.ColorModelR\x04kind\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\x01R\x05level\"q\n" +
+	"\x05Named\x12%\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x11.theme.ColorModelR\x04kind\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12$\n" +
+	"\x04tone\x18\x03 \x01(\x0e2\v.theme.ToneH\x00R\x04tone\x88\x01\x01B\a\n" +
+	"\x05_tone\"\xf7\x01\n" +
+	"\x05Theme\x12$\n" +
+	"\x06accent\x18\x01 \x01(\v2\f.theme.ColorR\x06accent\x12-\n" +
+	"\x05extra\x18\x02 \x03(\v2\x17.theme.Theme.ExtraEntryR\x05extra\x12$\n" +
+	"\x04grid\x18\x03 \x03(\v2\x10.theme.ColorListR\x04grid\x12\x19\n" +
+	"\x05label\x18\x04 \x01(\tH\x00R\x05label\x88\x01\x01\x12\x14\n" +
+	"\x05sizes\x18\x05 \x03(\x03R\x05sizes\x1a8\n" +
+	"\n" +
+	"ExtraEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\bR\x05value:\x028\x01B\b\n" +
+	"\x06_label*V\n" +
+	"\n" +
+	"ColorModel\x12\x1b\n" +
+	"\x17COLOR_MODEL_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10COLOR_MODEL_GRAY\x10\x01\x12\x15\n" +
+	"\x11COLOR_MODEL_NAMED\x10\x02*:\n" +
+	"\x04Tone\x12\x14\n" +
+	"\x10TONE_UNSPECIFIED\x10\x00\x12\r\n" +
+	"\tTONE_WARM\x10\x01\x12\r\n" +
+	"\tTONE_COOL\x10\x02B\x15Z\x13example.com/themepbb\x06proto3"
+
+var (
+	file_theme_proto_rawDescOnce sync.Once
+	file_theme_proto_rawDescData []byte
+)
+
+func file_theme_proto_rawDescGZIP() []byte {
+	file_theme_proto_rawDescOnce.Do(func() {
+		file_theme_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_theme_proto_rawDesc), len(file_theme_proto_rawDesc)))
+	})
+	return file_theme_proto_rawDescData
+}
+
+var file_theme_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_theme_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_theme_proto_goTypes = []any{
+	(ColorModel)(0),   // 0: theme.ColorModel
+	(Tone)(0),         // 1: theme.Tone
+	(*Color)(nil),     // 2: theme.Color
+	(*ColorList)(nil), // 3: theme.ColorList
+	(*Gray)(nil),      // 4: theme.Gray
+	(*Named)(nil),     // 5: theme.Named
+	(*Theme)(nil),     // 6: theme.Theme
+	nil,               // 7: theme.Theme.ExtraEntry
+}
+var file_theme_proto_depIdxs = []int32{
+	4, // 0: theme.Color.gray:type_name -> theme.Gray
+	5, // 1: theme.Color.named:type_name -> theme.Named
+	2, // 2: theme.ColorList.values:type_name -> theme.Color
+	0, // 3: theme.Gray.kind:type_name -> theme.ColorModel
+	0, // 4: theme.Named.kind:type_name -> theme.ColorModel
+	1, // 5: theme.Named.tone:type_name -> theme.Tone
+	2, // 6: theme.Theme.accent:type_name -> theme.Color
+	7, // 7: theme.Theme.extra:type_name -> theme.Theme.ExtraEntry
+	3, // 8: theme.Theme.grid:type_name -> theme.ColorList
+	9, // [9:9] is the sub-list for method output_type
+	9, // [9:9] is the sub-list for method input_type
+	9, // [9:9] is the sub-list for extension type_name
+	9, // [9:9] is the sub-list for extension extendee
+	0, // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_theme_proto_init() }
+func file_theme_proto_init() {
+	if File_theme_proto != nil {
+		return
+	}
+	file_theme_proto_msgTypes[0].OneofWrappers = []any{
+		(*Color_Gray)(nil),
+		(*Color_Named)(nil),
+	}
+	file_theme_proto_msgTypes[3].OneofWrappers = []any{}
+	file_theme_proto_msgTypes[4].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_theme_proto_rawDesc), len(file_theme_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_theme_proto_goTypes,
+		DependencyIndexes: file_theme_proto_depIdxs,
+		EnumInfos:         file_theme_proto_enumTypes,
+		MessageInfos:      file_theme_proto_msgTypes,
+	}.Build()
+	File_theme_proto = out.File
+	file_theme_proto_goTypes = nil
+	file_theme_proto_depIdxs = nil
+}
@@ -0,0 +1,78 @@
+// Package themepb stands in for what protoc-gen-go generates from the proto
+// file of theme.schema.json, declaring just what the converters use. Its
+// declarations are checked against ../protoc-gen-go/theme.pb.go, which is
+// protoc-gen-go's actual output
+package themepb
+
+type ColorModel int32
+
+const (
+	ColorModel_COLOR_MODEL_UNSPECIFIED ColorModel = 0
+	ColorModel_COLOR_MODEL_GRAY        ColorModel = 1
+	ColorModel_COLOR_MODEL_NAMED       ColorModel = 2
+)
+
+type Tone int32
+
+const (
+	Tone_TONE_UNSPECIFIED Tone = 0
+	Tone_TONE_WARM        Tone = 1
+	Tone_TONE_COOL        Tone = 2
+)
+
+type Color struct {
+	Value isColor_Value
+}
+
+func (x *Color) GetValue() isColor_Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+type isColor_Value interface {
+	isColor_Value()
+}
+
+type Color_Gray struct {
+	Gray *Gray
+}
+
+type Color_Named struct {
+	Named *Named
+}
+
+func (*Color_Gray) isColor_Value() {}
+
+func (*Color_Named) isColor_Value() {}
+
+type ColorList struct {
+	Values []*Color
+}
+
+func (x *ColorList) GetValues() []*Color {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type Gray struct {
+	Kind  ColorModel
+	Level float64
+}
+
+type Named struct {
+	Kind ColorModel
+	Name string
+	Tone *Tone
+}
+
+type Theme struct {
+	Accent *Color
+	Extra  map[string]bool
+	Grid   []*ColorList
+	Label  *string
+	Sizes  []int64
+}
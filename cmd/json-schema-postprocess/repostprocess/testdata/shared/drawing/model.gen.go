@@ -0,0 +1,133 @@
+// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+
+package drawing
+
+import "encoding/json"
+import "fmt"
+
+type Circle struct {
+	// Kind corresponds to the JSON schema field "kind".
+	Kind interface{} `json:"kind" yaml:"kind" mapstructure:"kind"`
+
+	// Radius corresponds to the JSON schema field "radius".
+	Radius float64 `json:"radius" yaml:"radius" mapstructure:"radius"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *Circle) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["kind"]; raw != nil && !ok {
+		return fmt.Errorf("field kind in Circle: required")
+	}
+	if _, ok := raw["radius"]; raw != nil && !ok {
+		return fmt.Errorf("field radius in Circle: required")
+	}
+	type Plain Circle
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	if 0 > plain.Radius {
+		return fmt.Errorf("field %s: must be >= %v", "radius", 0)
+	}
+	*j = Circle(plain)
+	return nil
+}
+
+type DrawingSchemaJson struct {
+	// Layers corresponds to the JSON schema field "layers".
+	Layers []Layer `json:"layers" yaml:"layers" mapstructure:"layers"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *DrawingSchemaJson) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["layers"]; raw != nil && !ok {
+		return fmt.Errorf("field layers in DrawingSchemaJson: required")
+	}
+	type Plain DrawingSchemaJson
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = DrawingSchemaJson(plain)
+	return nil
+}
+
+type Layer struct {
+	// Name corresponds to the JSON schema field "name".
+	Name string `json:"name" yaml:"name" mapstructure:"name"`
+
+	// Shapes corresponds to the JSON schema field "shapes".
+	Shapes []LayerShapesElem `json:"shapes" yaml:"shapes" mapstructure:"shapes"`
+}
+
+type LayerShapesElem interface{}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *Layer) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["name"]; raw != nil && !ok {
+		return fmt.Errorf("field name in Layer: required")
+	}
+	if _, ok := raw["shapes"]; raw != nil && !ok {
+		return fmt.Errorf("field shapes in Layer: required")
+	}
+	type Plain Layer
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = Layer(plain)
+	return nil
+}
+
+type Shape interface{}
+
+type ShapesSchemaJson struct {
+	// Shape corresponds to the JSON schema field "shape".
+	Shape ShapesSchemaJsonShape `json:"shape,omitempty" yaml:"shape,omitempty" mapstructure:"shape,omitempty"`
+}
+
+type ShapesSchemaJsonShape interface{}
+
+type Square struct {
+	// Kind corresponds to the JSON schema field "kind".
+	Kind interface{} `json:"kind" yaml:"kind" mapstructure:"kind"`
+
+	// Side corresponds to the JSON schema field "side".
+	Side float64 `json:"side" yaml:"side" mapstructure:"side"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *Square) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["kind"]; raw != nil && !ok {
+		return fmt.Errorf("field kind in Square: required")
+	}
+	if _, ok := raw["side"]; raw != nil && !ok {
+		return fmt.Errorf("field side in Square: required")
+	}
+	type Plain Square
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	if 0 > plain.Side {
+		return fmt.Errorf("field %s: must be >= %v", "side", 0)
+	}
+	*j = Square(plain)
+	return nil
+}
@@ -0,0 +1,377 @@
+package repostprocess
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gitlab.com/tozd/go/errors"
+)
+
+// parseFiles parses the Go files at paths into fset
+func parseFiles(fset *token.FileSet, paths []string) ([]*ast.File, error) {
+	var files []*ast.File
+	for _, path := range paths {
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return nil, errors.Errorf("parsing %s: %w", path, err)
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// checkPackage type-checks files as a single package and returns every error
+// go/types finds, not just the first. Imports are resolved from the module
+// containing dir
+func checkPackage(fset *token.FileSet, files []*ast.File, dir string) ([]types.Error, error) {
+	if len(files) == 0 {
+		return nil, errors.New("no files to type-check")
+	}
+
+	imp, err := exportImporter(fset, files, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var typeErrors []types.Error
+	conf := types.Config{
+		Importer: imp,
+		Error: func(err error) {
+			if typeErr, ok := err.(types.Error); ok {
+				typeErrors = append(typeErrors, typeErr)
+			}
+		},
+	}
+	_, err = conf.Check(files[0].Name.Name, fset, files, nil)
+	if err != nil && len(typeErrors) == 0 {
+		return nil, errors.Errorf("type-checking package %s: %w", files[0].Name.Name, err)
+	}
+
+	return typeErrors, nil
+}
+
+// exportImporter returns an importer reading the export data of the packages
+// files import, as `go list -export` builds it in dir. Unlike importer.Default,
+// this also finds the module's dependencies, such as gopkg.in/yaml.v3
+func exportImporter(fset *token.FileSet, files []*ast.File, dir string) (types.Importer, error) {
+	seen := make(map[string]bool)
+	var paths []string
+	for _, file := range files {
+		for _, spec := range file.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err != nil || seen[importPath] || importPath == "C" || importPath == "unsafe" {
+				continue
+			}
+			seen[importPath] = true
+			paths = append(paths, importPath)
+		}
+	}
+
+	exports, err := cachedExports(dir, paths)
+	if err != nil {
+		return nil, err
+	}
+
+	return importer.ForCompiler(fset, "gc", func(importPath string) (io.ReadCloser, error) {
+		export, ok := exports[importPath]
+		if !ok {
+			return nil, errors.Errorf("no export data for %s", importPath)
+		}
+		return os.Open(export)
+	}), nil
+}
+
+// exportCache holds the export data files already listed, by directory, since
+// they don't change while the tool runs and listing them is slow
+var exportCache = struct {
+	sync.Mutex
+	dirs map[string]map[string]string
+}{dirs: make(map[string]map[string]string)}
+
+// cachedExports returns the export data files of paths and their dependencies.
+// Packages dir's module doesn't provide, as when dir isn't in a module at all,
+// are looked up from the working directory instead
+func cachedExports(dir string, paths []string) (map[string]string, error) {
+	exportCache.Lock()
+	defer exportCache.Unlock()
+
+	exports, ok := exportCache.dirs[dir]
+	if !ok {
+		exports = make(map[string]string)
+		exportCache.dirs[dir] = exports
+	}
+
+	for _, listDir := range []string{dir, ""} {
+		var missing []string
+		for _, importPath := range paths {
+			if _, ok := exports[importPath]; !ok {
+				missing = append(missing, importPath)
+			}
+		}
+		if len(missing) == 0 {
+			break
+		}
+		if err := listExports(listDir, missing, exports); err != nil {
+			return nil, err
+		}
+	}
+
+	// The importer reads its copy after the lock is released
+	found := make(map[string]string, len(exports))
+	for importPath, export := range exports {
+		found[importPath] = export
+	}
+	return found, nil
+}
+
+// listExports records the export data files of paths and their dependencies,
+// as `go list -export` builds them in dir, in exports
+func listExports(dir string, paths []string, exports map[string]string) error {
+	cmd := exec.Command("go", append([]string{"list", "-e", "-export", "-deps", "-f", "{{.ImportPath}}={{.Export}}"}, paths...)...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return errors.Errorf("listing export data of %s: %w: %s", strings.Join(paths, ", "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		importPath, export, ok := strings.Cut(line, "=")
+		if _, found := exports[importPath]; ok && export != "" && !found {
+			exports[importPath] = export
+		}
+	}
+	return nil
+}
+
+// packageFiles lists the Go files in dir that build into the package, skipping
+// tests, files excluded by build constraints and the files named in exclude
+func packageFiles(dir string, exclude ...string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Errorf("reading package directory %s: %w", dir, err)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || containsString(exclude, name) || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		match, err := build.Default.MatchFile(dir, name)
+		if err != nil {
+			return nil, errors.Errorf("checking build constraints of %s: %w", name, err)
+		}
+		if match {
+			paths = append(paths, filepath.Join(dir, name))
+		}
+	}
+
+	return paths, nil
+}
+
+// typeCheck type-checks the merged model together with the other files of the
+// package it's written into, and reports each type error as a diagnostic
+// pointing at the parent or caller whose generated code it was found in
+func (cg *CodeGenerator) typeCheck(model *modelFile, merged []byte) ([]Diagnostic, error) {
+	packageDir := cg.packageDir
+	if packageDir == "" {
+		packageDir = cg.outputDir
+	}
+
+	modelName := filepath.Base(cg.modelPath)
+	siblings, err := packageFiles(packageDir, append([]string{modelName}, cg.skipFiles...)...)
+	if err != nil {
+		return nil, errors.Errorf("listing package files: %w", err)
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, modelName, merged, 0)
+	if err != nil {
+		return nil, errors.Errorf("parsing merged model: %w", err)
+	}
+	files, err := parseFiles(fset, siblings)
+	if err != nil {
+		return nil, err
+	}
+
+	// The model's imports resolve from the module it's in, even when the
+	// output directory is elsewhere
+	typeErrors, err := checkPackage(fset, append([]*ast.File{file}, files...), existingDir(filepath.Dir(cg.modelPath)))
+	if err != nil {
+		return nil, err
+	}
+
+	pointers, err := cg.declPointers(model)
+	if err != nil {
+		return nil, err
+	}
+
+	var diagnostics []Diagnostic
+	for _, typeErr := range typeErrors {
+		position := typeErr.Fset.Position(typeErr.Pos)
+		position.Filename = filepath.Base(position.Filename)
+
+		// Errors in the project's own files can't be traced to the schema
+		pointer, origin := "", "package code"
+		if position.Filename == modelName {
+			if key := declKey(file, typeErr.Pos); key != "" {
+				origin = key
+				if p, ok := pointers[key]; ok {
+					pointer = p
+				}
+			}
+		}
+
+		diagnostics = append(diagnostics, Diagnostic{
+			Pointer:  pointer,
+			Severity: SeverityError,
+			Code:     DiagnosticTypeError,
+			Message:  fmt.Sprintf("%s: %s (in %s)", position, typeErr.Msg, origin),
+		})
+	}
+
+	return diagnostics, nil
+}
+
+// declPointers maps the names of generated declarations, and Recv.Method for
+// methods, to the JSON pointer of the parent or caller they were generated for
+func (cg *CodeGenerator) declPointers(model *modelFile) (map[string]string, error) {
+	pointers := make(map[string]string)
+	set := func(key, pointer string) {
+		if _, ok := pointers[key]; !ok {
+			pointers[key] = pointer
+		}
+	}
+
+	for _, defName := range cg.results.SortedParentNames() {
+		parent := cg.results.Parents[defName]
+		pointer := jsonPointer("definitions", defName)
+		// The declarations are named after the parent's Go name
+		names := cg.newParentData(defName, parent)
+		parentName := names.Name
+
+		for _, name := range []string{parentName, parentName + "Slice", parentName + "Map", names.ModelType, "parseUnknown" + parentName, "parse" + parentName + "Raw", "Decode" + parentName + "Stream", "Decode" + parentName + "Slice"} {
+			set(name, pointer)
+		}
+		for _, value := range parent.ConstantValues {
+			set(constantName(names.ModelType, value), pointer)
+		}
+		// The markers are declared on the leaves, including those of nested parents
+		for _, childName := range parent.LeafChildren {
+			set(childName+".is"+parentName, pointer)
+		}
+		for _, name := range []string{parentName + "Visitor", "Accept" + parentName, "Match" + parentName, "Clone" + parentName, "Equal" + parentName, "Validate" + parentName, "validate" + parentName} {
+			set(name, pointer)
+		}
+		for _, method := range []string{"UnmarshalYAML", "Clone", "Equal", "Validate", "validate"} {
+			set(parentName+"Slice."+method, pointer)
+			set(parentName+"Map."+method, pointer)
+		}
+		if parent.ConstantField != "" {
+			for _, childName := range parent.LeafChildren {
+				set(childName+"."+names.ConstantMethod, pointer)
+				set(childName+".MarshalJSON", pointer)
+			}
+		}
+		for _, childName := range parent.LeafChildren {
+			set(childName+".UnmarshalYAML", pointer)
+			set(childName+".MarshalYAML", pointer)
+		}
+		// Constructors and the other helpers of a child come from its own definition
+		for _, childName := range parent.LeafChildren {
+			childPointer := jsonPointer("definitions", childName)
+			set("New"+childName, childPointer)
+			for _, method := range []string{"checkConstraints", "Clone", "Equal"} {
+				set(childName+"."+method, childPointer)
+			}
+		}
+	}
+
+	for _, caller := range cg.results.SortedParentCallers() {
+		structName, _, err := model.resolveCaller(caller, cg.results.RootProperties)
+		if err != nil {
+			return nil, errors.Errorf("resolving caller %s.%s: %w", caller.Name, caller.Field, err)
+		}
+		pointer := caller.ObjectPointer()
+		set(structName, pointer)
+		set(structName+".UnmarshalJSON", pointer)
+		set(structName+".MarshalJSON", pointer)
+		set(structName+".UnmarshalYAML", pointer)
+		set(structName+".MarshalYAML", pointer)
+		set(structName+".Clone", pointer)
+		set(structName+".Equal", pointer)
+	}
+
+	// Every other struct validates against the schema it was generated from
+	for structName, pointer := range cg.structPointers(model) {
+		set(structName+".Validate", pointer)
+		set(structName+".validate", pointer)
+	}
+
+	return pointers, nil
+}
+
+// declKey names the top-level declaration in file holding pos, as Recv.Method
+// for methods and by the spec's first name for type, const and var specs
+func declKey(file *ast.File, pos token.Pos) string {
+	for _, decl := range file.Decls {
+		if pos < decl.Pos() || pos >= decl.End() {
+			continue
+		}
+
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if recv := receiverTypeName(decl); recv != "" {
+				return recv + "." + decl.Name.Name
+			}
+			return decl.Name.Name
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				if pos < spec.Pos() || pos >= spec.End() {
+					continue
+				}
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					return spec.Name.Name
+				case *ast.ValueSpec:
+					return spec.Names[0].Name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// existingDir returns dir, or its closest ancestor that exists when the model's
+// package hasn't been created yet, so its module can still resolve imports
+func existingDir(dir string) string {
+	for {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		dir = parent
+	}
+}
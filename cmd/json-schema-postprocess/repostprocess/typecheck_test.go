@@ -0,0 +1,181 @@
+package repostprocess
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// generateBrokenMarshalers generates the simple fixture with a marshalers
+// template that references an undefined function
+func generateBrokenMarshalers(t *testing.T, force bool) (*SchemaResults, string, error) {
+	t.Helper()
+
+	override := `{{define "marshalers"}}{{range .Marshalers}}
+// MarshalJSON implements json.Marshaler for {{.}}
+func (j {{.}}) MarshalJSON() ([]byte, error) {
+	return marshalWithConstant(j)
+}
+{{end}}{{end}}`
+	return generateWithTemplate(t, "simple", "marshalers", override, force)
+}
+
+// generateWithTemplate generates a fixture with one template overridden
+func generateWithTemplate(t *testing.T, fixture, name, override string, force bool) (*SchemaResults, string, error) {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	outputDir := filepath.Join(tmpDir, "output")
+	templatesDir := filepath.Join(tmpDir, "templates")
+	for _, dir := range []string{outputDir, templatesDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(templatesDir, name+".tmpl"), []byte(override), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	analyzer, err := NewSchemaAnalyzer(filepath.Join("testdata", fixture, fixture+".schema.json"))
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+	results, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Failed to analyze schema: %v", err)
+	}
+
+	generator := NewCodeGenerator(filepath.Join("testdata", fixture, "model.gen.go"), outputDir, results)
+	generator.SetTemplatesDir(templatesDir)
+	generator.SetForce(force)
+	return results, outputDir, generator.Generate()
+}
+
+func TestTypeCheck_ErrorsMappedToParent(t *testing.T) {
+	results, outputDir, err := generateBrokenMarshalers(t, false)
+	if err == nil {
+		t.Fatalf("Expected generation to fail on type errors")
+	}
+
+	if _, statErr := os.Stat(filepath.Join(outputDir, "model.gen.go")); !os.IsNotExist(statErr) {
+		t.Errorf("Expected no model to be written, got %v", statErr)
+	}
+
+	var typeErrors []Diagnostic
+	for _, d := range results.Diagnostics {
+		if d.Code == DiagnosticTypeError {
+			typeErrors = append(typeErrors, d)
+		}
+	}
+	if len(typeErrors) != 3 {
+		t.Fatalf("Expected 3 type errors, one per marshaler, got %d: %v", len(typeErrors), typeErrors)
+	}
+
+	for _, d := range typeErrors {
+		if d.Pointer != "/definitions/Shape" {
+			t.Errorf("Expected the type error to point at Shape, got '%s'", d.Pointer)
+		}
+		if d.Severity != SeverityError {
+			t.Errorf("Expected type errors to be errors, got '%s'", d.Severity)
+		}
+		if !strings.Contains(d.Message, "undefined: marshalWithConstant") {
+			t.Errorf("Expected the message to name the undefined function, got '%s'", d.Message)
+		}
+	}
+	checkForContent(t, typeErrors[0].Message, "(in Circle.MarshalJSON)")
+}
+
+func TestTypeCheck_NestedParentMarkers(t *testing.T) {
+	// The markers of Fill are declared on the leaves of the nested Color
+	override := `{{define "parentInterface" -}}
+type {{.Name}} interface {
+{{- range .EmbeddedIn}}
+	{{.}}
+{{- end}}
+	is{{.Name}}()
+{{- if .DeclareConstantMethod}}
+	{{.ConstantMethod}}() {{.ModelType}}
+{{- end}}
+}
+
+type {{.Name}}Slice []{{.Name}}
+
+type {{.Name}}Map map[string]{{.Name}}
+
+{{range $impl := .Implementations -}}
+func (me *{{$impl.Child}}) is{{$.Name}}() { undefinedMarker() }
+
+{{if $impl.ConstantName -}}
+func (me *{{$impl.Child}}) {{$.ConstantMethod}}() {{$.ModelType}} { return {{$impl.ConstantName}} }
+
+{{end}}
+{{- end}}
+{{- if .ConstantField -}}
+type {{.ModelType}} {{.ConstantGoType}}
+
+const (
+{{- range .Constants}}
+	{{.Name}} {{$.ModelType}} = {{.Literal}}
+{{- end}}
+)
+
+{{end}}
+{{- end}}`
+	results, _, err := generateWithTemplate(t, "nested", "interfaces", override, false)
+	if err == nil {
+		t.Fatalf("Expected generation to fail on type errors")
+	}
+
+	pointers := make(map[string]string)
+	for _, d := range results.Diagnostics {
+		if d.Code == DiagnosticTypeError {
+			for _, key := range []string{"RGBColor.isFill", "HSLColor.isFill", "Gradient.isFill", "RGBColor.isColor"} {
+				if strings.Contains(d.Message, "(in "+key+")") {
+					pointers[key] = d.Pointer
+				}
+			}
+		}
+	}
+	for key, want := range map[string]string{
+		"RGBColor.isFill":  "/definitions/Fill",
+		"HSLColor.isFill":  "/definitions/Fill",
+		"Gradient.isFill":  "/definitions/Fill",
+		"RGBColor.isColor": "/definitions/Color",
+	} {
+		if pointers[key] != want {
+			t.Errorf("Expected the type error in %s to point at %s, got '%s'", key, want, pointers[key])
+		}
+	}
+}
+
+func TestTypeCheck_Force(t *testing.T) {
+	_, outputDir, err := generateBrokenMarshalers(t, true)
+	if err != nil {
+		t.Fatalf("Expected -force to write the model, got %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "model.gen.go"))
+	if err != nil {
+		t.Fatalf("Failed to read model file: %v", err)
+	}
+	checkForContent(t, string(content), "return marshalWithConstant(j)")
+}
+
+func TestParentCallerInfo_ObjectPointer(t *testing.T) {
+	tests := []struct {
+		caller   ParentCallerInfo
+		expected string
+	}{
+		{ParentCallerInfo{Name: "Palette", Field: "colors"}, "/definitions/Palette"},
+		{ParentCallerInfo{Field: "shapes", Root: true}, ""},
+		{ParentCallerInfo{Name: "config", Field: "shape", Root: true, ObjectPath: []string{"config"}}, "/properties/config"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.caller.ObjectPointer(); got != tt.expected {
+			t.Errorf("Expected pointer '%s' for %s, got '%s'", tt.expected, tt.caller.Field, got)
+		}
+	}
+}
@@ -0,0 +1,691 @@
+package repostprocess
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"gitlab.com/tozd/go/errors"
+)
+
+// TypeScriptGenerator renders TypeScript types for an analyzed schema: a type
+// for every definition, with each parent a discriminated union of its
+// children, along with type guards and decode functions for the parents that
+// also decode the unions nested in the child they match
+type TypeScriptGenerator struct {
+	results      *SchemaResults
+	rootName     string
+	config       *Config
+	templatesDir string
+}
+
+// NewTypeScriptGenerator creates a TypeScript generator. The root schema's
+// properties, if it has any, are declared as rootName
+func NewTypeScriptGenerator(results *SchemaResults, rootName string) *TypeScriptGenerator {
+	return &TypeScriptGenerator{
+		results:  results,
+		rootName: rootName,
+	}
+}
+
+// SetConfig sets the project config, whose parent interface names the unions take
+func (tg *TypeScriptGenerator) SetConfig(config *Config) {
+	tg.config = config
+}
+
+// SetTemplatesDir sets a directory of *.tmpl files overriding the embedded templates
+func (tg *TypeScriptGenerator) SetTemplatesDir(dir string) {
+	tg.templatesDir = dir
+}
+
+// tsData is the data passed to the typescript template
+type tsData struct {
+	Declarations []tsDeclaration
+	Guards       []tsGuard
+	Parents      []tsParent
+	// Checks are the functions decoding the unions nested in a definition
+	Checks []tsCheckFunc
+	// NeedsItems and NeedsEntries are set when a check walks an array or a
+	// map of values holding unions
+	NeedsItems   bool
+	NeedsEntries bool
+}
+
+// tsDeclaration is a type declared for a definition or the root schema
+type tsDeclaration struct {
+	Name    string
+	Comment string
+	// Properties are set for objects, which become interfaces; anything else
+	// is a type alias of Type
+	Properties []tsProperty
+	Interface  bool
+	Type       string
+}
+
+// tsProperty is a property of an interface
+type tsProperty struct {
+	Name     string
+	Type     string
+	Optional bool
+	Comment  string
+}
+
+// tsGuard is the type guard of a child, which checks the keys it requires and
+// the constants it holds
+type tsGuard struct {
+	Type   string
+	Checks []string
+}
+
+// tsParent is the union of a parent's children, with its guard and decoder
+type tsParent struct {
+	Name     string
+	Children []string
+	// Field is the discriminator the decoder switches on, if there is one,
+	// and Unknown the message for a value none of the children hold
+	Field   string
+	Unknown string
+	Cases   []tsCase
+	// Order is the order the decoder tries the children in without a discriminator
+	Order []tsCase
+}
+
+// tsCase is a discriminator value and the child holding it
+type tsCase struct {
+	Literal string
+	Child   string
+	// Check is the statement decoding the unions nested in the child, if it
+	// holds any, and Returns is set when it returns the decoded child
+	Check   string
+	Returns bool
+}
+
+// tsCheckFunc is the function decoding the unions nested in a definition
+type tsCheckFunc struct {
+	Name string
+	// Statements check one property each
+	Statements []string
+}
+
+// Generate renders the TypeScript source
+func (tg *TypeScriptGenerator) Generate() ([]byte, error) {
+	templates, err := loadTemplates(tg.templatesDir)
+	if err != nil {
+		return nil, errors.Errorf("loading templates: %w", err)
+	}
+
+	data, err := tg.templateData()
+	if err != nil {
+		return nil, err
+	}
+	return renderTemplate(templates, "typescript", data)
+}
+
+// templateData builds the declarations, guards and parents of the schema
+func (tg *TypeScriptGenerator) templateData() (*tsData, error) {
+	definitions, _ := tg.results.Schema["definitions"].(map[string]interface{})
+	data := &tsData{}
+
+	// The discriminators of a parent's children are required in TypeScript,
+	// so the union narrows on them
+	discriminators := make(map[string]map[string]bool)
+	for _, parentName := range tg.results.SortedParentNames() {
+		parent := tg.results.Parents[parentName]
+		fields := append([]string{}, parent.CompositeFields...)
+		if parent.Discriminator == DiscriminatorConstant {
+			fields = append(fields, parent.ConstantField)
+		}
+		for _, child := range parent.LeafChildren {
+			if discriminators[child] == nil {
+				discriminators[child] = make(map[string]bool)
+			}
+			for _, field := range fields {
+				discriminators[child][field] = true
+			}
+		}
+	}
+
+	if properties, ok := tg.results.Schema["properties"].(map[string]interface{}); ok && len(properties) > 0 {
+		decl, err := tg.declaration(tg.rootName, tg.results.Schema, nil)
+		if err != nil {
+			return nil, err
+		}
+		data.Declarations = append(data.Declarations, decl)
+	}
+
+	children := make(map[string]bool)
+	for _, parent := range tg.results.Parents {
+		for _, child := range parent.LeafChildren {
+			children[child] = true
+		}
+	}
+
+	for _, defName := range sortedKeys(definitions) {
+		schema, ok := definitions[defName].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name := tg.typeName(defName)
+		if parent, ok := tg.results.Parents[defName]; ok {
+			members := make([]string, 0, len(parent.Children))
+			for _, child := range parent.Children {
+				members = append(members, tg.typeName(child))
+			}
+			data.Declarations = append(data.Declarations, tsDeclaration{
+				Name:    name,
+				Comment: description(schema),
+				Type:    strings.Join(members, " | "),
+			})
+			continue
+		}
+
+		decl, err := tg.declaration(name, schema, discriminators[defName])
+		if err != nil {
+			return nil, errors.Errorf("declaring %s: %w", defName, err)
+		}
+		data.Declarations = append(data.Declarations, decl)
+
+		if children[defName] {
+			guard, err := tg.guard(name, schema, discriminators[defName])
+			if err != nil {
+				return nil, errors.Errorf("guarding %s: %w", defName, err)
+			}
+			data.Guards = append(data.Guards, guard)
+		}
+	}
+
+	c := newTSChecker(tg, definitions)
+	for _, parentName := range tg.results.SortedParentNames() {
+		data.Parents = append(data.Parents, tg.parent(tg.results.Parents[parentName], c))
+	}
+
+	// Only the checks a decoder calls are rendered, including the ones other
+	// checks call
+	statements := make(map[string][]string)
+	for len(statements) < len(c.used) {
+		for defName := range c.used {
+			if _, ok := statements[defName]; !ok {
+				schema, _ := definitions[defName].(map[string]interface{})
+				statements[defName] = c.properties(schema)
+			}
+		}
+	}
+	for _, defName := range sortedKeys(definitions) {
+		if c.used[defName] {
+			data.Checks = append(data.Checks, tsCheckFunc{Name: defName, Statements: statements[defName]})
+		}
+	}
+	data.NeedsItems, data.NeedsEntries = c.needsItems, c.needsEntries
+	return data, nil
+}
+
+// typeName returns the TypeScript name of a definition, which for a parent is
+// the interface name the Go code uses
+func (tg *TypeScriptGenerator) typeName(defName string) string {
+	if _, ok := tg.results.Parents[defName]; ok {
+		return tg.config.InterfaceName(defName)
+	}
+	return defName
+}
+
+// declaration declares a definition: an interface for an object with
+// properties and an alias for anything else. The properties in discriminators
+// are required whatever the schema says
+func (tg *TypeScriptGenerator) declaration(name string, schema map[string]interface{}, discriminators map[string]bool) (tsDeclaration, error) {
+	decl := tsDeclaration{Name: name, Comment: description(schema)}
+	properties, ok := schema["properties"].(map[string]interface{})
+	if !ok {
+		typ, err := tg.tsType(schema, 0)
+		if err != nil {
+			return decl, err
+		}
+		decl.Type = typ
+		return decl, nil
+	}
+
+	decl.Interface = true
+	required := requiredSet(schema)
+	for _, propName := range sortedKeys(properties) {
+		typ, err := tg.tsType(properties[propName], 0)
+		if err != nil {
+			return decl, errors.Errorf("typing %s: %w", propName, err)
+		}
+		propMap, _ := properties[propName].(map[string]interface{})
+		decl.Properties = append(decl.Properties, tsProperty{
+			Name:     tsPropertyName(propName),
+			Type:     typ,
+			Optional: !required[propName] && !discriminators[propName],
+			Comment:  description(propMap),
+		})
+	}
+	return decl, nil
+}
+
+// guard builds the checks of a child's type guard: every key it requires is
+// present, and every constant it declares holds
+func (tg *TypeScriptGenerator) guard(name string, schema map[string]interface{}, discriminators map[string]bool) (tsGuard, error) {
+	guard := tsGuard{Type: name}
+	properties, _ := schema["properties"].(map[string]interface{})
+	required := requiredSet(schema)
+	for _, propName := range sortedKeys(properties) {
+		propMap, _ := properties[propName].(map[string]interface{})
+		access := "value[" + jsString(propName) + "]"
+		if constValue, ok := propMap["const"]; ok {
+			literal, err := json.Marshal(constValue)
+			if err != nil {
+				return guard, errors.Errorf("encoding const of %s: %w", propName, err)
+			}
+			check := access + " === " + string(literal)
+			if !required[propName] && !discriminators[propName] {
+				check = "(" + access + " === undefined || " + check + ")"
+			}
+			guard.Checks = append(guard.Checks, check)
+			continue
+		}
+		if required[propName] {
+			guard.Checks = append(guard.Checks, jsString(propName)+" in value")
+		}
+	}
+	return guard, nil
+}
+
+// parent builds the guard and decoder of a parent, which switch on a constant
+// discriminator or else try each child in turn
+func (tg *TypeScriptGenerator) parent(info ParentInfo, c *tsChecker) tsParent {
+	parent := tsParent{Name: tg.typeName(info.Name)}
+	for _, child := range info.Children {
+		parent.Children = append(parent.Children, tg.typeName(child))
+	}
+
+	if info.Discriminator == DiscriminatorConstant {
+		parent.Field = jsString(info.ConstantField)
+		parent.Unknown = jsString("unknown " + parent.Name + " " + info.ConstantField + ": ")
+		for _, child := range info.LeafChildren {
+			literal := info.ConstantValues[child]
+			if info.ConstantKind == ConstantKindString || info.ConstantKind == "" {
+				literal = jsString(literal)
+			}
+			parent.Cases = append(parent.Cases, c.child(tsCase{Literal: literal, Child: tg.typeName(child)}, child))
+		}
+		return parent
+	}
+
+	// The decision plan orders the children whose keys overlap
+	seen := make(map[string]bool)
+	for _, step := range info.DecisionPlan {
+		if !seen[step.Child] {
+			seen[step.Child] = true
+			parent.Order = append(parent.Order, c.child(tsCase{Child: tg.typeName(step.Child)}, step.Child))
+		}
+	}
+	for _, child := range info.Children {
+		if !seen[child] {
+			seen[child] = true
+			parent.Order = append(parent.Order, c.child(tsCase{Child: tg.typeName(child)}, child))
+		}
+	}
+	return parent
+}
+
+// tsChecker writes the statements decoding the unions nested in values, so a
+// decoder rejects a child whose fields hold invalid children of other parents
+type tsChecker struct {
+	tg          *TypeScriptGenerator
+	definitions map[string]interface{}
+	// reaches holds the definitions that aren't parents but hold a union,
+	// directly or through arrays, maps and other definitions
+	reaches map[string]bool
+	// used holds the definitions whose checks the statements call
+	used         map[string]bool
+	needsItems   bool
+	needsEntries bool
+}
+
+// newTSChecker finds the definitions that reach a union, repeating until
+// nothing changes so definitions that refer to each other are covered
+func newTSChecker(tg *TypeScriptGenerator, definitions map[string]interface{}) *tsChecker {
+	c := &tsChecker{tg: tg, definitions: definitions, reaches: make(map[string]bool), used: make(map[string]bool)}
+	for changed := true; changed; {
+		changed = false
+		for _, defName := range sortedKeys(definitions) {
+			if c.reaches[defName] || c.isParent(defName) {
+				continue
+			}
+			schema, _ := definitions[defName].(map[string]interface{})
+			if len(c.properties(schema)) > 0 {
+				c.reaches[defName] = true
+				changed = true
+			}
+		}
+	}
+	c.used, c.needsItems, c.needsEntries = make(map[string]bool), false, false
+	return c
+}
+
+// isParent reports whether a definition is a parent, decoded by its own decoder
+func (c *tsChecker) isParent(name string) bool {
+	_, ok := c.tg.results.Parents[name]
+	return ok
+}
+
+// child sets the statement a decoder runs on a child it matched: decoding a
+// nested parent, or checking the unions a definition holds
+func (c *tsChecker) child(tc tsCase, name string) tsCase {
+	if c.isParent(name) {
+		tc.Check, tc.Returns = "return decode"+c.tg.typeName(name)+"(value, path);", true
+	} else if c.reaches[name] {
+		c.used[name] = true
+		tc.Check = "check" + name + "(value, path);"
+	}
+	return tc
+}
+
+// properties returns a statement for each property of an object schema that
+// holds a union, skipping optional properties that are left out
+func (c *tsChecker) properties(schema map[string]interface{}) []string {
+	properties, _ := schema["properties"].(map[string]interface{})
+	required := requiredSet(schema)
+	var statements []string
+	for _, propName := range sortedKeys(properties) {
+		check := c.check(properties[propName], 0)
+		if check == nil {
+			continue
+		}
+		access := "value[" + jsString(propName) + "]"
+		statement := check(access, "path + "+jsString(jsonPointer(propName)))
+		if !required[propName] {
+			statement = "if (" + access + " !== undefined) { " + statement + " }"
+		}
+		statements = append(statements, statement)
+	}
+	return statements
+}
+
+// tsCheck renders the statement checking a value, from the expressions of the
+// value and its JSON pointer
+type tsCheck func(value, path string) string
+
+// check returns the check of a value of schema, or nil when it can't hold a union
+func (c *tsChecker) check(schema interface{}, depth int) tsCheck {
+	propMap, ok := schema.(map[string]interface{})
+	if !ok || depth > maxCloneDepth {
+		return nil
+	}
+
+	if ref, ok := propMap["$ref"].(string); ok {
+		name := extractRefName(ref)
+		switch {
+		case c.isParent(name):
+			return c.call("decode" + c.tg.typeName(name))
+		case c.reaches[name]:
+			c.used[name] = true
+			return c.call("check" + name)
+		}
+		// A definition without properties is an alias, checked in place
+		if def, ok := c.definitions[name].(map[string]interface{}); ok {
+			if _, ok := def["properties"]; !ok {
+				return c.check(def, depth+1)
+			}
+		}
+		return nil
+	}
+
+	if inner, ok := nullableSchema(propMap); ok {
+		return skipNull(c.check(inner, depth+1))
+	}
+
+	var check tsCheck
+	switch propMap["type"] {
+	case "array":
+		if items := c.check(propMap["items"], depth+1); items != nil {
+			c.needsItems = true
+			check = c.walk("checkItems", items, depth)
+		}
+	case "object":
+		if _, ok := propMap["properties"]; !ok {
+			if entries := c.check(propMap["additionalProperties"], depth+1); entries != nil {
+				c.needsEntries = true
+				check = c.walk("checkEntries", entries, depth)
+			}
+		}
+	}
+	if allowsNull(propMap) {
+		return skipNull(check)
+	}
+	return check
+}
+
+// call checks a value with a function taking it and its JSON pointer
+func (c *tsChecker) call(fn string) tsCheck {
+	return func(value, path string) string {
+		return fn + "(" + value + ", " + path + ");"
+	}
+}
+
+// walk checks every item of an array or entry of a map with the helper fn,
+// passing an arrow function unless the item check is already a single call
+func (c *tsChecker) walk(fn string, items tsCheck, depth int) tsCheck {
+	item, itemPath := fmt.Sprintf("item%d", depth), fmt.Sprintf("itemPath%d", depth)
+	statement := items(item, itemPath)
+	callback := "(" + item + ": unknown, " + itemPath + ": string) => { " + statement + " }"
+	if name := strings.TrimSuffix(statement, "("+item+", "+itemPath+");"); name != statement && !strings.ContainsAny(name, " ()") {
+		callback = name
+	}
+	return func(value, path string) string {
+		return fn + "(" + value + ", " + path + ", " + callback + ");"
+	}
+}
+
+// skipNull skips the check of a value that is null
+func skipNull(check tsCheck) tsCheck {
+	if check == nil {
+		return nil
+	}
+	return func(value, path string) string {
+		return "if (" + value + " !== null) { " + check(value, path) + " }"
+	}
+}
+
+// tsType returns the TypeScript type of a schema
+func (tg *TypeScriptGenerator) tsType(schema interface{}, depth int) (string, error) {
+	if depth > maxCloneDepth {
+		return "", errors.Errorf("schema nests deeper than %d levels", maxCloneDepth)
+	}
+	propMap, ok := schema.(map[string]interface{})
+	if !ok {
+		// true allows anything, and false nothing
+		if allowed, ok := schema.(bool); ok && !allowed {
+			return "never", nil
+		}
+		return "unknown", nil
+	}
+
+	if ref, ok := propMap["$ref"].(string); ok {
+		name := extractRefName(ref)
+		if name == "" {
+			return "unknown", nil
+		}
+		return tg.typeName(name), nil
+	}
+
+	if constValue, ok := propMap["const"]; ok {
+		literal, err := json.Marshal(constValue)
+		if err != nil {
+			return "", errors.Errorf("encoding const: %w", err)
+		}
+		return string(literal), nil
+	}
+
+	if values, ok := propMap["enum"].([]interface{}); ok {
+		literals := make([]string, 0, len(values))
+		for _, value := range values {
+			literal, err := json.Marshal(value)
+			if err != nil {
+				return "", errors.Errorf("encoding enum value: %w", err)
+			}
+			literals = append(literals, string(literal))
+		}
+		return strings.Join(literals, " | "), nil
+	}
+
+	for _, keyword := range []string{"anyOf", "oneOf", "allOf"} {
+		members, ok := propMap[keyword].([]interface{})
+		if !ok {
+			continue
+		}
+		types := make([]string, 0, len(members))
+		for _, member := range members {
+			typ, err := tg.tsType(member, depth+1)
+			if err != nil {
+				return "", err
+			}
+			types = append(types, parenthesize(typ))
+		}
+		if keyword == "allOf" {
+			return strings.Join(types, " & "), nil
+		}
+		return strings.Join(types, " | "), nil
+	}
+
+	var types []string
+	switch t := propMap["type"].(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				types = append(types, s)
+			}
+		}
+	}
+	if len(types) == 0 {
+		return "unknown", nil
+	}
+
+	var union []string
+	for _, typ := range types {
+		switch typ {
+		case "string":
+			union = append(union, "string")
+		case "number", "integer":
+			union = append(union, "number")
+		case "boolean":
+			union = append(union, "boolean")
+		case "null":
+			union = append(union, "null")
+		case "array":
+			items, err := tg.tsType(propMap["items"], depth+1)
+			if err != nil {
+				return "", err
+			}
+			union = append(union, parenthesize(items)+"[]")
+		case "object":
+			object, err := tg.objectType(propMap, depth)
+			if err != nil {
+				return "", err
+			}
+			union = append(union, object)
+		default:
+			union = append(union, "unknown")
+		}
+	}
+	return strings.Join(union, " | "), nil
+}
+
+// objectType returns the inline type of an object schema: a type literal of
+// its properties, or a record of its additional properties
+func (tg *TypeScriptGenerator) objectType(propMap map[string]interface{}, depth int) (string, error) {
+	properties, ok := propMap["properties"].(map[string]interface{})
+	if !ok || len(properties) == 0 {
+		additional, ok := propMap["additionalProperties"]
+		if !ok {
+			return "Record<string, unknown>", nil
+		}
+		value, err := tg.tsType(additional, depth+1)
+		if err != nil {
+			return "", err
+		}
+		return "Record<string, " + value + ">", nil
+	}
+
+	required := requiredSet(propMap)
+	fields := make([]string, 0, len(properties))
+	for _, propName := range sortedKeys(properties) {
+		typ, err := tg.tsType(properties[propName], depth+1)
+		if err != nil {
+			return "", err
+		}
+		optional := ""
+		if !required[propName] {
+			optional = "?"
+		}
+		fields = append(fields, tsPropertyName(propName)+optional+": "+typ)
+	}
+	return "{ " + strings.Join(fields, "; ") + " }", nil
+}
+
+// tsIdentifierPattern matches property names that needn't be quoted
+var tsIdentifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsPropertyName returns a property name as it's written in a type, quoted
+// unless it's an identifier
+func tsPropertyName(name string) string {
+	if tsIdentifierPattern.MatchString(name) {
+		return name
+	}
+	return jsString(name)
+}
+
+// jsString returns a string literal, which JSON's are valid in TypeScript
+func jsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// parenthesize wraps a union or intersection, so it binds as one type
+func parenthesize(typ string) string {
+	if strings.Contains(typ, " | ") || strings.Contains(typ, " & ") {
+		return "(" + typ + ")"
+	}
+	return typ
+}
+
+// description returns a schema's description, for the comment of its type
+func description(schema map[string]interface{}) string {
+	desc, _ := schema["description"].(string)
+	return strings.TrimSpace(desc)
+}
+
+// tsComment renders a description as a doc comment at the given indent
+func tsComment(desc, indent string) string {
+	if desc == "" {
+		return ""
+	}
+	lines := strings.Split(strings.ReplaceAll(desc, "*/", "*\\/"), "\n")
+	if len(lines) == 1 {
+		return indent + "/** " + lines[0] + " */\n"
+	}
+	var b strings.Builder
+	b.WriteString(indent + "/**\n")
+	for _, line := range lines {
+		b.WriteString(strings.TrimRightFunc(indent+" * "+line, unicode.IsSpace) + "\n")
+	}
+	b.WriteString(indent + " */\n")
+	return b.String()
+}
+
+// RootTypeName returns the name go-jsonschema gives the root of a schema file,
+// such as ColorSchemaJson for color.schema.json
+func RootTypeName(schemaPath string) string {
+	parts := strings.FieldsFunc(filepath.Base(schemaPath), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, part := range parts {
+		b.WriteString(strings.Title(part))
+	}
+	return b.String()
+}
@@ -0,0 +1,177 @@
+package repostprocess
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// generateTypeScript analyzes a schema and renders its TypeScript types
+func generateTypeScript(t *testing.T, schemaPath string, config *Config) string {
+	t.Helper()
+
+	analyzer, err := NewSchemaAnalyzer(schemaPath)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+	results, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Failed to analyze schema: %v", err)
+	}
+
+	generator := NewTypeScriptGenerator(results, RootTypeName(schemaPath))
+	generator.SetConfig(config)
+	code, err := generator.Generate()
+	if err != nil {
+		t.Fatalf("Failed to generate TypeScript: %v", err)
+	}
+	return string(code)
+}
+
+func TestTypeScriptGenerator_Generate(t *testing.T) {
+	code := generateTypeScript(t, filepath.Join("testdata", "color", "color.schema.json"), nil)
+
+	// Parents are unions of their children, which narrow on literal discriminators
+	checkForContent(t, code, "export type Color = HSLValue | HSVValue | HSIValue | RGBValue | RGBAValue | LABValue | CMYKValue | LCHValue;")
+	checkForContent(t, code, "export interface HSLValue {\n\th: number;\n\tl: number;\n\tmodel: \"hsl\";\n\ts: number;\n}")
+
+	// The other definitions keep the schema's types and required properties
+	checkForContent(t, code, "\tcolors: Color[][];")
+	checkForContent(t, code, "\tsemantic: string | null;")
+	checkForContent(t, code, "\tcolorScheme?: ColorSchemeType;")
+	checkForContent(t, code, "export type Undertone = \"warm\" | \"neutral\" | \"cool\";")
+	checkForContent(t, code, "export type ColorSchema = AssetPack;")
+
+	// Children get type guards, and parents a guard and a decoder
+	checkForContent(t, code, "return isRecord(value) && \"h\" in value && \"l\" in value && value[\"model\"] === \"hsl\" && \"s\" in value;")
+	checkForContent(t, code, "export function isColor(value: unknown): value is Color {")
+	checkForContent(t, code, "export function decodeColor(value: unknown, path = \"\"): Color {")
+	checkForContent(t, code, "\tswitch (value[\"model\"]) {\n\t\tcase \"hsl\":\n\t\t\tif (isHSLValue(value)) {")
+	checkForContent(t, code, "throw new Error(pathPrefix(path) + \"unknown Color model: \" + JSON.stringify(value[\"model\"]));")
+
+	// Decoding a child decodes the unions nested in it, through arrays and
+	// other definitions
+	checkForContent(t, code, "\t\t\tif (isMatrixPalette(value)) {\n\t\t\t\tcheckMatrixPalette(value, path);\n\t\t\t\treturn value;")
+	checkForContent(t, code, "\tcheckItems(value[\"colors\"], path + \"/colors\", (item0: unknown, itemPath0: string) => { checkItems(item0, itemPath0, decodeColor); });\n\tcheckOrigin(value[\"origin\"], path + \"/origin\");")
+	checkForContent(t, code, "\tcheckItems(value[\"colors\"], path + \"/colors\", checkContinuousColor);")
+	checkForContent(t, code, "\tdecodeColor(value[\"value\"], path + \"/value\");")
+	checkForAbsence(t, code, "function checkAssetPack(")
+	checkForAbsence(t, code, "function checkEntries(")
+}
+
+func TestTypeScriptGenerator_Discriminators(t *testing.T) {
+	code := generateTypeScript(t, filepath.Join("testdata", "discriminators", "discriminators.schema.json"), nil)
+
+	// Integer and boolean constants are switched on as they are
+	checkForContent(t, code, "\t\tcase 1:\n\t\t\tif (isEventV1(value)) {")
+	checkForContent(t, code, "\t\tcase false:\n\t\t\tif (isDisabledFlag(value)) {")
+
+	// Composite and presence discriminators try each child's guard in turn
+	checkForContent(t, code, "return isRecord(value) && \"body\" in value && value[\"kind\"] === \"text\" && value[\"version\"] === 1;")
+	checkForContent(t, code, "\tif (isFileSource(value)) {\n\t\treturn value;\n\t}\n\tif (isURLSource(value)) {")
+	checkForContent(t, code, "throw new Error(pathPrefix(path) + \"value matches none of the children of Source\");")
+}
+
+func TestTypeScriptGenerator_RootAndNames(t *testing.T) {
+	dir := t.TempDir()
+	schemaPath := filepath.Join(dir, "theme.schema.json")
+	schema := `{
+	"type": "object",
+	"properties": {
+		"accent": {"$ref": "#/definitions/Color"},
+		"font-size": {"type": "integer", "description": "Size in points"},
+		"extra": {"type": "object", "additionalProperties": {"type": "boolean"}}
+	},
+	"required": ["accent"],
+	"definitions": {
+		"Color": {"anyOf": [{"$ref": "#/definitions/Gray"}, {"$ref": "#/definitions/Named"}]},
+		"Gray": {"type": "object", "properties": {"kind": {"const": "gray"}, "level": {"type": "number"}}, "required": ["level"]},
+		"Named": {"type": "object", "properties": {"kind": {"const": "named"}, "name": {"type": "string"}}, "required": ["name"]}
+	}
+}`
+	if err := os.WriteFile(schemaPath, []byte(schema), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	config := &Config{Parents: map[string]ParentConfig{"Color": {Interface: "ThemeColor"}}}
+	code := generateTypeScript(t, schemaPath, config)
+
+	// The root is named as go-jsonschema names it, and parents as the config does
+	checkForContent(t, code, "export interface ThemeSchemaJson {\n\taccent: ThemeColor;")
+	checkForContent(t, code, "\textra?: Record<string, boolean>;")
+	checkForContent(t, code, "\t/** Size in points */\n\t\"font-size\"?: number;")
+	checkForContent(t, code, "export type ThemeColor = Gray | Named;")
+	checkForContent(t, code, "export function decodeThemeColor(value: unknown, path = \"\"): ThemeColor {")
+	checkForAbsence(t, code, "export type Color ")
+}
+
+func TestTypeScriptGenerator_NestedUnions(t *testing.T) {
+	dir := t.TempDir()
+	schemaPath := filepath.Join(dir, "shapes.schema.json")
+	schema := `{
+	"type": "object",
+	"properties": {"shape": {"$ref": "#/definitions/Shape"}},
+	"definitions": {
+		"Shape": {"anyOf": [{"$ref": "#/definitions/Circle"}, {"$ref": "#/definitions/Group"}]},
+		"Circle": {"type": "object", "properties": {"kind": {"const": "circle"}, "radius": {"type": "number"}}, "required": ["kind", "radius"]},
+		"Group": {
+			"type": "object",
+			"properties": {
+				"kind": {"const": "group"},
+				"children": {"type": "array", "items": {"$ref": "#/definitions/Shape"}},
+				"named": {"type": "object", "additionalProperties": {"$ref": "#/definitions/Shape"}},
+				"mask": {"oneOf": [{"$ref": "#/definitions/Shape"}, {"type": "null"}]},
+				"layers": {"$ref": "#/definitions/Layers"}
+			},
+			"required": ["kind", "children", "mask"]
+		},
+		"Layers": {"type": "array", "items": {"$ref": "#/definitions/Layer"}},
+		"Layer": {"type": "object", "properties": {"fill": {"$ref": "#/definitions/Shape"}}, "required": ["fill"]}
+	}
+}`
+	if err := os.WriteFile(schemaPath, []byte(schema), 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	code := generateTypeScript(t, schemaPath, nil)
+
+	// Required, optional, nullable, array, map and aliased fields are each
+	// decoded at their own path
+	checkForContent(t, code, "\tcheckItems(value[\"children\"], path + \"/children\", decodeShape);")
+	checkForContent(t, code, "\tif (value[\"layers\"] !== undefined) { checkItems(value[\"layers\"], path + \"/layers\", checkLayer); }")
+	checkForContent(t, code, "\tif (value[\"mask\"] !== null) { decodeShape(value[\"mask\"], path + \"/mask\"); }")
+	checkForContent(t, code, "\tif (value[\"named\"] !== undefined) { checkEntries(value[\"named\"], path + \"/named\", decodeShape); }")
+	checkForContent(t, code, "function checkLayer(value: unknown, path: string): void {")
+	checkForContent(t, code, "function checkEntries(value: unknown, path: string, check: (item: unknown, itemPath: string) => void): void {")
+	checkForAbsence(t, code, "function checkCircle(")
+}
+
+func TestProcessor_TypeScript(t *testing.T) {
+	dir := t.TempDir()
+	schemaPath := filepath.Join("testdata", "color", "color.schema.json")
+	tsPath := filepath.Join(dir, "ts", "color.ts")
+
+	processor := NewProcessor(schemaPath, filepath.Join("testdata", "color", "model.gen.go"), dir)
+	processor.TypeScriptPath = tsPath
+	if err := processor.Process(); err != nil {
+		t.Fatalf("Failed to process schema: %v", err)
+	}
+	content, err := os.ReadFile(tsPath)
+	if err != nil {
+		t.Fatalf("Failed to read TypeScript file: %v", err)
+	}
+	checkForContent(t, string(content), "export function decodePalette(value: unknown, path = \"\"): Palette {")
+
+	// A check covers the TypeScript file along with the model
+	processor.Check = true
+	if err := processor.Process(); err != nil {
+		t.Fatalf("Expected the TypeScript to be up to date, got %v", err)
+	}
+	if err := os.WriteFile(tsPath, []byte("// edited\n"), 0644); err != nil {
+		t.Fatalf("Failed to edit TypeScript file: %v", err)
+	}
+	if err := processor.Process(); !errors.Is(err, ErrStale) {
+		t.Fatalf("Expected the edited TypeScript to be stale, got %v", err)
+	}
+	checkForContent(t, processor.Diff, "-// edited")
+}
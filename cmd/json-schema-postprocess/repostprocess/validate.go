@@ -0,0 +1,322 @@
+package repostprocess
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+)
+
+// validateData is the Validate method of a struct or enum type
+type validateData struct {
+	Type string
+	// Enum is set for named scalar types, which are checked against the
+	// enumValues_<Type> variable go-jsonschema declares for them
+	Enum bool
+	// Required are the required fields that can hold nil
+	Required []requiredData
+	// Constraints are the schema's validation keywords on the struct's fields
+	Constraints []constraintData
+	// Nested are the statements validating the values held by the fields
+	Nested []string
+}
+
+// requiredData is a required field that must not be nil
+type requiredData struct {
+	Path    string
+	GoField string
+}
+
+// validator writes the statements that validate the values model fields hold
+type validator struct {
+	model   *modelFile
+	parents map[string]bool
+	// types are the structs and enums given a validate method
+	types map[string]bool
+}
+
+// validations builds the Validate data for every struct of the model and every
+// enum go-jsonschema declared, with the keywords of the schema each one was
+// generated from
+func (cg *CodeGenerator) validations(model *modelFile, parents []parentData, callers []callerData) []validateData {
+	v := &validator{
+		model:   model,
+		parents: make(map[string]bool),
+		types:   make(map[string]bool),
+	}
+	for _, parent := range parents {
+		v.parents[parent.Name] = true
+	}
+
+	enums := make(map[string]bool)
+	for _, name := range sortedTypeNames(model.types) {
+		if model.replaced[name] {
+			continue
+		}
+		switch {
+		case isStruct(model.types[name]):
+			v.types[name] = true
+		case model.isEnum(name):
+			v.types[name] = true
+			enums[name] = true
+		}
+	}
+
+	// Nullable parents can be nil even when they're required
+	nullable := make(map[string]bool)
+	for _, caller := range callers {
+		for _, field := range caller.Fields {
+			if field.IsNullable {
+				nullable[caller.Struct+"."+field.GoField] = true
+			}
+		}
+	}
+
+	pointers := cg.structPointers(model)
+	var data []validateData
+	for _, name := range sortedTypeNames(model.types) {
+		if !v.types[name] {
+			continue
+		}
+		if enums[name] {
+			data = append(data, validateData{Type: name, Enum: true})
+			continue
+		}
+
+		d := validateData{Type: name}
+		pointer, known := pointers[name]
+		required := make(map[string]bool)
+		if known {
+			for _, key := range cg.results.Required[pointer] {
+				required[key] = true
+			}
+			// An enum type checks its own values
+			for _, constraint := range model.constraintData(name, cg.results.Constraints[pointer]) {
+				if constraint.Keyword == "enum" && enums[v.fieldType(name, constraint.GoField)] {
+					continue
+				}
+				d.Constraints = append(d.Constraints, constraint)
+			}
+		}
+
+		for _, field := range model.types[name].Type.(*ast.StructType).Fields.List {
+			jsonName := jsonFieldName(field)
+			if len(field.Names) != 1 || jsonName == "" || jsonName == "-" {
+				continue
+			}
+			goField := field.Names[0].Name
+			if model.constantFields[name] == goField {
+				continue
+			}
+
+			path := jsonPointer(jsonName)
+			if required[jsonName] && !nullable[name+"."+goField] && v.nilable(field.Type) {
+				d.Required = append(d.Required, requiredData{Path: path, GoField: goField})
+			}
+			if stmts := v.nestedStmts(field.Type, "j."+goField, fmt.Sprintf("path+%q", path), 0, false); stmts != "" {
+				d.Nested = append(d.Nested, stmts)
+			}
+		}
+		data = append(data, d)
+	}
+	return data
+}
+
+// structPointers maps each model struct to the JSON pointer of the schema it
+// was generated from: the definitions by name, the root schema, and the inline
+// objects reached through their fields
+func (cg *CodeGenerator) structPointers(model *modelFile) map[string]string {
+	pointers := make(map[string]string)
+	var queue []string
+	assign := func(name, pointer string) {
+		if _, done := pointers[name]; done {
+			return
+		}
+		if _, known := cg.results.Required[pointer]; !known {
+			return
+		}
+		if typeSpec, ok := model.types[name]; !ok || !isStruct(typeSpec) {
+			return
+		}
+		pointers[name] = pointer
+		queue = append(queue, name)
+	}
+
+	for _, name := range sortedTypeNames(model.types) {
+		assign(name, jsonPointer("definitions", name))
+	}
+	if len(cg.results.RootProperties) > 0 {
+		if root, ok := model.rootStruct(cg.results.RootProperties); ok {
+			assign(root, "")
+		}
+	}
+
+	var walk func(expr ast.Expr, pointer string)
+	walk = func(expr ast.Expr, pointer string) {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			walk(e.X, pointer)
+		case *ast.ArrayType:
+			walk(e.Elt, pointer+"/items")
+		case *ast.MapType:
+			walk(e.Value, pointer+"/additionalProperties")
+		case *ast.Ident:
+			assign(e.Name, pointer)
+		}
+	}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		for _, field := range model.types[name].Type.(*ast.StructType).Fields.List {
+			if jsonName := jsonFieldName(field); jsonName != "" && jsonName != "-" {
+				walk(field.Type, pointers[name]+jsonPointer("properties", jsonName))
+			}
+		}
+	}
+	return pointers
+}
+
+// fieldType returns the name of a struct field's type, looking through a pointer
+func (v *validator) fieldType(structName, goField string) string {
+	for _, field := range v.model.types[structName].Type.(*ast.StructType).Fields.List {
+		if len(field.Names) == 1 && field.Names[0].Name == goField {
+			name, _ := baseTypeName(field.Type)
+			return name
+		}
+	}
+	return ""
+}
+
+// nilable reports whether a field type can hold nil, which a required field
+// must not. Pointers are left out, since go-jsonschema only uses them for
+// fields that are optional or nullable
+func (v *validator) nilable(expr ast.Expr) bool {
+	for i := 0; i < maxCloneDepth; i++ {
+		switch e := expr.(type) {
+		case *ast.ArrayType:
+			return e.Len == nil
+		case *ast.MapType, *ast.InterfaceType:
+			return true
+		case *ast.Ident:
+			if e.Name == "any" || v.parents[e.Name] || v.model.collections[e.Name] {
+				return true
+			}
+			typeSpec, ok := v.model.types[e.Name]
+			if !ok {
+				return false
+			}
+			expr = typeSpec.Type
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// nestedStmts returns statements validating value, of type expr, found at the
+// JSON pointer the Go expression path evaluates to. Items of collections are
+// checked even when they're nil, since a null item breaks the schema
+func (v *validator) nestedStmts(expr ast.Expr, value, path string, depth int, item bool) string {
+	if depth > maxCloneDepth {
+		return ""
+	}
+
+	switch e := expr.(type) {
+	case *ast.Ident:
+		switch {
+		case v.parents[e.Name]:
+			call := fmt.Sprintf("validate%s(%s, %s, errs)", e.Name, value, path)
+			if item {
+				return call
+			}
+			return fmt.Sprintf("if %s != nil {\n%s\n}", value, call)
+		case v.parents[strings.TrimSuffix(e.Name, "Slice")], v.parents[strings.TrimSuffix(e.Name, "Map")], v.types[e.Name]:
+			return fmt.Sprintf("%s.validate(%s, errs)", value, path)
+		}
+		if typeSpec, ok := v.model.types[e.Name]; ok {
+			return v.nestedStmts(typeSpec.Type, value, path, depth+1, item)
+		}
+		return ""
+
+	case *ast.StarExpr:
+		if ident, ok := e.X.(*ast.Ident); ok && v.types[ident.Name] && isStruct(v.model.types[ident.Name]) {
+			// The validate methods of structs report a nil item themselves
+			call := fmt.Sprintf("%s.validate(%s, errs)", value, path)
+			if item {
+				return call
+			}
+			return fmt.Sprintf("if %s != nil {\n%s\n}", value, call)
+		}
+		inner := v.nestedStmts(e.X, "(*"+value+")", path, depth+1, false)
+		if inner == "" {
+			return ""
+		}
+		return fmt.Sprintf("if %s != nil {\n%s\n}", value, inner)
+
+	case *ast.ArrayType:
+		i := fmt.Sprintf("i%d", depth)
+		inner := v.nestedStmts(e.Elt, value+"["+i+"]", appendPath(path, "strconv.Itoa("+i+")"), depth+1, true)
+		if inner == "" {
+			return ""
+		}
+		return fmt.Sprintf("for %s := range %s {\n%s\n}", i, value, inner)
+
+	case *ast.MapType:
+		k, val := fmt.Sprintf("k%d", depth), fmt.Sprintf("v%d", depth)
+		inner := v.nestedStmts(e.Value, val, appendPath(path, "jsonPointerToken("+k+")"), depth+1, true)
+		if inner == "" {
+			return ""
+		}
+		return fmt.Sprintf("for _, %s := range sortedJSONKeys(%s) {\n%s := %s[%s]\n%s\n}", k, value, val, value, k, inner)
+	}
+
+	return ""
+}
+
+// appendPath returns the Go expression for the JSON pointer path, of one more
+// token the Go expression token evaluates to
+func appendPath(path, token string) string {
+	// Fold the separator into a string literal path ends with
+	if strings.HasSuffix(path, `"`) {
+		return path[:len(path)-1] + `/"+` + token
+	}
+	return path + `+"/"+` + token
+}
+
+// isStruct reports whether a type spec declares a struct
+func isStruct(typeSpec *ast.TypeSpec) bool {
+	if typeSpec == nil {
+		return false
+	}
+	_, ok := typeSpec.Type.(*ast.StructType)
+	return ok
+}
+
+// isEnum reports whether a model type is a scalar with the enumValues_<name>
+// variable go-jsonschema declares for enums
+func (m *modelFile) isEnum(name string) bool {
+	ident, ok := m.types[name].Type.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	if kind := m.goKind(ident); kind != goKindNumber && kind != goKindString && ident.Name != "bool" {
+		return false
+	}
+	for _, decl := range m.file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for _, ident := range valueSpec.Names {
+				if ident.Name == "enumValues_"+name {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
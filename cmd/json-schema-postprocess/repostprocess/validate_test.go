@@ -0,0 +1,151 @@
+package repostprocess
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCodeGenerator_GenerateValidate(t *testing.T) {
+	schemaPath := filepath.Join("testdata", "color", "color.schema.json")
+	modelPath := filepath.Join("testdata", "color", "model.gen.go")
+
+	analyzer, err := NewSchemaAnalyzer(schemaPath)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+
+	results, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Failed to analyze schema: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	generator := NewCodeGenerator(modelPath, outputDir, results)
+	if err := generator.Generate(); err != nil {
+		t.Fatalf("Failed to generate code: %v", err)
+	}
+
+	modelStr := readModel(t, outputDir)
+
+	// Every struct and enum of the model can be validated
+	for _, typeName := range []string{"AssetPack", "HSLValue", "MatrixPalette", "Origin", "ContinuousColor"} {
+		checkForContent(t, modelStr, "func (j *"+typeName+") Validate() error {")
+	}
+	checkForContent(t, modelStr, "func (j ColorSchemeType) Validate() error {")
+	checkForContent(t, modelStr, "for _, expected := range enumValues_ColorSchemeType {")
+
+	// The parents dispatch to their children, and report null items
+	checkForContent(t, modelStr, "func ValidateColor(value Color) error {")
+	checkForContent(t, modelStr, "func (s PaletteSlice) Validate() error {")
+	checkForContent(t, modelStr, "validateColor(item, path+\"/\"+strconv.Itoa(i), errs)")
+	checkForContent(t, modelStr, "case nil:\n\t\terrs.add(path, \"must not be null\")")
+
+	// Fields are checked against the schema, with the paths of the values
+	checkForContent(t, modelStr, "errs.add(path+\"/h\", fmt.Sprintf(\"must be >= %v\", 0))")
+	checkForContent(t, modelStr, "errs.add(path+\"/palettes\", \"is required\")")
+	checkForContent(t, modelStr, "j.Palettes.validate(path+\"/palettes\", errs)")
+	checkForContent(t, modelStr, "j.Colors[i0].validate(path+\"/colors/\"+strconv.Itoa(i0), errs)")
+	checkForContent(t, modelStr, "type ValidationErrors []*ValidationError")
+
+	// The constant discriminators are methods, so there's nothing to check
+	checkForAbsence(t, modelStr, "path+\"/model\"")
+
+	validateGoTypes(t, outputDir)
+}
+
+func TestCodeGenerator_ValidateInlineObjects(t *testing.T) {
+	dir := t.TempDir()
+	schema := `{
+	"type": "object",
+	"properties": {
+		"theme": {
+			"type": "object",
+			"properties": {
+				"mode": {"type": "string", "minLength": 1}
+			},
+			"required": ["mode"]
+		},
+		"layers": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {
+					"tags": {"type": "array", "items": {"type": "string"}},
+					"opacity": {"type": "number", "maximum": 1}
+				},
+				"required": ["tags"]
+			}
+		}
+	},
+	"definitions": {}
+}`
+	model := `package inline
+
+type InlineSchemaJson struct {
+	Layers []InlineSchemaJsonLayersElem ` + "`json:\"layers,omitempty\"`" + `
+	Theme *InlineSchemaJsonTheme ` + "`json:\"theme,omitempty\"`" + `
+}
+
+type InlineSchemaJsonLayersElem struct {
+	Opacity *float64 ` + "`json:\"opacity,omitempty\"`" + `
+	Tags []string ` + "`json:\"tags\"`" + `
+}
+
+type InlineSchemaJsonTheme struct {
+	Mode string ` + "`json:\"mode\"`" + `
+}
+`
+	schemaPath := filepath.Join(dir, "inline.schema.json")
+	modelPath := filepath.Join(dir, "model.go")
+	for path, content := range map[string]string{schemaPath: schema, modelPath: model} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	analyzer, err := NewSchemaAnalyzer(schemaPath)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+	results, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Failed to analyze schema: %v", err)
+	}
+	parsed, err := parseModelFile(modelPath)
+	if err != nil {
+		t.Fatalf("Failed to parse model: %v", err)
+	}
+
+	generator := NewCodeGenerator(modelPath, dir, results)
+	pointers := generator.structPointers(parsed)
+	expected := map[string]string{
+		"InlineSchemaJson":           "",
+		"InlineSchemaJsonTheme":      "/properties/theme",
+		"InlineSchemaJsonLayersElem": "/properties/layers/items",
+	}
+	for name, pointer := range expected {
+		if got, ok := pointers[name]; !ok || got != pointer {
+			t.Errorf("Expected %s to come from %q, got %q", name, pointer, got)
+		}
+	}
+
+	data := generator.validations(parsed, nil, nil)
+	if len(data) != 3 {
+		t.Fatalf("Expected 3 validations, got %+v", data)
+	}
+	root, layer, theme := data[0], data[1], data[2]
+	checkForContent(t, strings.Join(root.Nested, "\n"), "j.Layers[i0].validate(path+\"/layers/\"+strconv.Itoa(i0), errs)")
+	checkForContent(t, strings.Join(root.Nested, "\n"), "if j.Theme != nil {\nj.Theme.validate(path+\"/theme\", errs)\n}")
+	if len(layer.Required) != 1 || layer.Required[0].Path != "/tags" {
+		t.Errorf("Expected tags to be required of the layers, got %+v", layer.Required)
+	}
+	if len(layer.Constraints) != 1 || layer.Constraints[0].Keyword != "maximum" || !layer.Constraints[0].Pointer {
+		t.Errorf("Expected an optional maximum on the layers, got %+v", layer.Constraints)
+	}
+	// A required string is always present, so only its length is checked
+	if len(theme.Required) != 0 || len(theme.Constraints) != 1 || theme.Constraints[0].Keyword != "minLength" {
+		t.Errorf("Expected only the minLength of the theme, got %+v", theme)
+	}
+}
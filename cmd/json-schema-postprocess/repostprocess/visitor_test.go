@@ -0,0 +1,81 @@
+package repostprocess
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVisitorNames(t *testing.T) {
+	tests := []struct {
+		children []string
+		want     []string
+	}{
+		{[]string{"RGBValue", "HSLValue", "CMYKValue"}, []string{"RGB", "HSL", "CMYK"}},
+		{[]string{"CategoricalPalette", "ContinuousScalePalette"}, []string{"Categorical", "ContinuousScale"}},
+		// Every child keeps at least one word
+		{[]string{"Value", "RGBValue"}, []string{"Value", "RGBValue"}},
+		{[]string{"RGBColor", "HSLColor", "Gradient"}, []string{"RGBColor", "HSLColor", "Gradient"}},
+		{[]string{"RGBValue"}, []string{"RGBValue"}},
+	}
+
+	for _, tt := range tests {
+		if got := visitorNames(tt.children); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("visitorNames(%v) = %v, want %v", tt.children, got, tt.want)
+		}
+	}
+}
+
+func TestCamelWords(t *testing.T) {
+	tests := map[string][]string{
+		"RGBValue":     {"RGB", "Value"},
+		"HSLColor":     {"HSL", "Color"},
+		"URLSource":    {"URL", "Source"},
+		"ColorConfig":  {"Color", "Config"},
+		"CMYK":         {"CMYK"},
+		"Value2Update": {"Value2", "Update"},
+	}
+
+	for input, want := range tests {
+		if got := camelWords(input); !reflect.DeepEqual(got, want) {
+			t.Errorf("camelWords(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
+
+func TestCodeGenerator_GenerateVisitors(t *testing.T) {
+	schemaPath := filepath.Join("testdata", "nested", "nested.schema.json")
+	modelPath := filepath.Join("testdata", "nested", "model.gen.go")
+
+	analyzer, err := NewSchemaAnalyzer(schemaPath)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+
+	results, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Failed to analyze schema: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	generator := NewCodeGenerator(modelPath, outputDir, results)
+	if err := generator.Generate(); err != nil {
+		t.Fatalf("Failed to generate code: %v", err)
+	}
+
+	modelStr := readModel(t, outputDir)
+
+	checkForContent(t, modelStr, "type ColorVisitor interface {\n\tVisitRGB(*RGBColor)\n\tVisitHSL(*HSLColor)\n}")
+	checkForContent(t, modelStr, "func AcceptColor(value Color, visitor ColorVisitor) error {")
+	checkForContent(t, modelStr, "func MatchColor[T any](value Color, onRGB func(*RGBColor) T, onHSL func(*HSLColor) T) (T, error) {")
+
+	// The outer parent covers the children reached through the nested one too
+	checkForContent(t, modelStr, "type FillVisitor interface {\n\tVisitRGBColor(*RGBColor)\n\tVisitHSLColor(*HSLColor)\n\tVisitGradient(*Gradient)\n}")
+	checkForContent(t, modelStr, "\tcase *Gradient:\n\t\tvisitor.VisitGradient(value)")
+	if count := strings.Count(modelStr, "\tcase *RGBColor:\n\t\treturn onRGB(value), nil"); count != 1 {
+		t.Errorf("Expected one MatchColor case for RGBColor, got %d", count)
+	}
+
+	validateGoTypes(t, outputDir)
+}
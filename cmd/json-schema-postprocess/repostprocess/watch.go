@@ -0,0 +1,213 @@
+package repostprocess
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"gitlab.com/tozd/go/errors"
+)
+
+// DefaultDebounce is how long Watch waits for changes to settle before running
+// again, since editors often write a file in several steps
+const DefaultDebounce = 200 * time.Millisecond
+
+// WatchedFiles returns the files the processor's output depends on: its schema,
+// every schema it $refs, directly or not, and the model unless it's generated
+// from the schema
+func (p *Processor) WatchedFiles() ([]string, error) {
+	files := make(map[string]bool)
+	if err := collectSchemaFiles(p.SchemaPath, files); err != nil {
+		return nil, err
+	}
+	if !p.GenerateModel {
+		model, err := filepath.Abs(p.ModelPath)
+		if err != nil {
+			return nil, errors.Errorf("resolving model path: %w", err)
+		}
+		files[model] = true
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// collectSchemaFiles adds the schema at path, and the schemas it refers to, to
+// files. A schema that's missing or broken is still watched, since it can be
+// fixed while watching
+func collectSchemaFiles(path string, files map[string]bool) error {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return errors.Errorf("resolving schema path: %w", err)
+	}
+	if files[path] {
+		return nil
+	}
+	files[path] = true
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var schema interface{}
+	if err := json.Unmarshal(content, &schema); err != nil {
+		return nil
+	}
+
+	var walk func(node interface{}) error
+	walk = func(node interface{}) error {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			if ref, ok := n["$ref"].(string); ok {
+				file, _, _ := strings.Cut(ref, "#")
+				// Remote schemas can't be watched
+				if file != "" && !strings.Contains(file, "://") {
+					if err := collectSchemaFiles(filepath.Join(filepath.Dir(path), file), files); err != nil {
+						return err
+					}
+				}
+			}
+			for _, key := range sortedKeys(n) {
+				if err := walk(n[key]); err != nil {
+					return err
+				}
+			}
+		case []interface{}:
+			for _, item := range n {
+				if err := walk(item); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	return walk(schema)
+}
+
+// Watch processes the schemas, reports the results, and then does so again
+// each time a file they depend on changes, until ctx is done. Changes are
+// debounced, and a run is skipped when no file's content actually changed, so
+// the models written in place don't set off another run. Errors from the file
+// watcher are logged, and the files are compared again in case a change was missed
+func Watch(ctx context.Context, processors []*Processor, debounce time.Duration, report func([]BatchResult)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.Errorf("creating file watcher: %w", err)
+	}
+	defer watcher.Close()
+	return watch(ctx, watcher, processors, debounce, report)
+}
+
+// watch runs Watch's loop over the events and errors of watcher
+func watch(ctx context.Context, watcher *fsnotify.Watcher, processors []*Processor, debounce time.Duration, report func([]BatchResult)) error {
+	// Directories are watched rather than files, since editors often save by
+	// replacing a file, which ends a watch on the file itself
+	dirs := make(map[string]bool)
+	var files map[string][sha256.Size]byte
+	run := func() error {
+		// Hashing before processing means a change made meanwhile runs again
+		files = make(map[string][sha256.Size]byte)
+		for _, p := range processors {
+			paths, err := p.WatchedFiles()
+			if err != nil {
+				return err
+			}
+			for _, path := range paths {
+				files[path] = fileHash(path)
+			}
+		}
+
+		results := ProcessBatch(processors)
+
+		// A model rewritten in place is the processor's own change
+		for _, p := range processors {
+			if p.InPlace && !p.GenerateModel && !p.Check {
+				if model, err := filepath.Abs(p.ModelPath); err == nil {
+					files[model] = fileHash(model)
+				}
+			}
+		}
+
+		for path := range files {
+			dir := filepath.Dir(path)
+			if dirs[dir] {
+				continue
+			}
+			// A directory that doesn't exist yet can't be watched
+			if err := watcher.Add(dir); err == nil {
+				dirs[dir] = true
+			}
+		}
+
+		// Reporting last means any change made once the results are seen is caught
+		report(results)
+		return nil
+	}
+
+	if err := run(); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if _, watched := files[filepath.Clean(event.Name)]; watched {
+				timer.Reset(debounce)
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			// The errors are recoverable, such as a full event queue, but
+			// changes may have been missed, so the files are compared as
+			// though one had been seen
+			log.Printf("Warning: watching files: %v", err)
+			timer.Reset(debounce)
+
+		case <-timer.C:
+			changed := false
+			for path, hash := range files {
+				if fileHash(path) != hash {
+					changed = true
+					break
+				}
+			}
+			if !changed {
+				continue
+			}
+			if err := run(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// fileHash returns the hash of a file's content, or the zero hash if it can't be read
+func fileHash(path string) [sha256.Size]byte {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return [sha256.Size]byte{}
+	}
+	return sha256.Sum256(content)
+}
@@ -0,0 +1,175 @@
+package repostprocess
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+func TestProcessor_WatchedFiles(t *testing.T) {
+	processor := NewProcessor(filepath.Join("testdata", "shared", "drawing", "drawing.schema.json"), filepath.Join("testdata", "shared", "drawing", "model.gen.go"), t.TempDir())
+
+	files, err := processor.WatchedFiles()
+	if err != nil {
+		t.Fatalf("Failed to list watched files: %v", err)
+	}
+	var names []string
+	for _, file := range files {
+		if !filepath.IsAbs(file) {
+			t.Errorf("Expected an absolute path, got %s", file)
+		}
+		names = append(names, filepath.Base(file))
+	}
+	// The schema drawing.schema.json refers to is watched with it
+	expected := "drawing.schema.json,model.gen.go,shapes.schema.json"
+	if strings.Join(names, ",") != expected {
+		t.Errorf("Expected to watch %s, got %s", expected, strings.Join(names, ","))
+	}
+
+	// A generated model is written, not read, so there's nothing to watch
+	processor.GenerateModel = true
+	files, err = processor.WatchedFiles()
+	if err != nil {
+		t.Fatalf("Failed to list watched files: %v", err)
+	}
+	if len(files) != 2 {
+		t.Errorf("Expected to watch only the schemas, got %v", files)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	schema, err := os.ReadFile(filepath.Join("testdata", "color", "color.schema.json"))
+	if err != nil {
+		t.Fatalf("Failed to read schema: %v", err)
+	}
+	schemaPath := filepath.Join(dir, "color.schema.json")
+	if err := os.WriteFile(schemaPath, schema, 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	processor := NewProcessor(schemaPath, filepath.Join(dir, "color", "model.go"), "")
+	processor.InPlace = true
+	processor.GenerateModel = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runs := make(chan []BatchResult, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- Watch(ctx, []*Processor{processor}, 50*time.Millisecond, func(results []BatchResult) {
+			runs <- results
+		})
+	}()
+
+	next := func() []BatchResult {
+		t.Helper()
+		select {
+		case results := <-runs:
+			return results
+		case <-time.After(30 * time.Second):
+			t.Fatalf("Timed out waiting for a run")
+			return nil
+		}
+	}
+
+	if results := next(); results[0].Err != nil {
+		t.Fatalf("First run failed: %v", results[0].Err)
+	}
+
+	// A broken schema is reported, and fixing it is picked up
+	if err := os.WriteFile(schemaPath, []byte("{"), 0644); err != nil {
+		t.Fatalf("Failed to break schema: %v", err)
+	}
+	if results := next(); results[0].Err == nil {
+		t.Errorf("Expected the broken schema to fail")
+	}
+	if err := os.WriteFile(schemaPath, schema, 0644); err != nil {
+		t.Fatalf("Failed to restore schema: %v", err)
+	}
+	if results := next(); results[0].Err != nil {
+		t.Errorf("Expected the restored schema to succeed, got %v", results[0].Err)
+	}
+
+	// Touching a file without changing it doesn't run again
+	if err := os.WriteFile(schemaPath, schema, 0644); err != nil {
+		t.Fatalf("Failed to rewrite schema: %v", err)
+	}
+	select {
+	case <-runs:
+		t.Errorf("Expected no run for an unchanged schema")
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Watch failed: %v", err)
+	}
+}
+
+func TestWatch_RecoversFromErrors(t *testing.T) {
+	dir := t.TempDir()
+	schema, err := os.ReadFile(filepath.Join("testdata", "color", "color.schema.json"))
+	if err != nil {
+		t.Fatalf("Failed to read schema: %v", err)
+	}
+	schemaPath := filepath.Join(dir, "color.schema.json")
+	if err := os.WriteFile(schemaPath, schema, 0644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+
+	processor := NewProcessor(schemaPath, filepath.Join(dir, "color", "model.go"), "")
+	processor.InPlace = true
+	processor.GenerateModel = true
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatalf("Failed to create watcher: %v", err)
+	}
+	defer watcher.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	runs := make(chan []BatchResult, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- watch(ctx, watcher, []*Processor{processor}, 50*time.Millisecond, func(results []BatchResult) {
+			runs <- results
+		})
+	}()
+
+	select {
+	case <-runs:
+	case <-time.After(30 * time.Second):
+		t.Fatalf("Timed out waiting for the first run")
+	}
+
+	// A change whose event is lost is still picked up once an error is reported
+	if err := watcher.Remove(dir); err != nil {
+		t.Fatalf("Failed to stop watching: %v", err)
+	}
+	if err := os.WriteFile(schemaPath, []byte("{"), 0644); err != nil {
+		t.Fatalf("Failed to break schema: %v", err)
+	}
+	watcher.Errors <- fsnotify.ErrEventOverflow
+	select {
+	case results := <-runs:
+		if results[0].Err == nil {
+			t.Errorf("Expected the broken schema to fail")
+		}
+	case err := <-done:
+		t.Fatalf("Expected the watch to go on after an error, got %v", err)
+	case <-time.After(30 * time.Second):
+		t.Fatalf("Timed out waiting for a run")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Watch failed: %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package repostprocess
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCodeGenerator_GenerateYAML(t *testing.T) {
+	schemaPath := filepath.Join("testdata", "color", "color.schema.json")
+	modelPath := filepath.Join("testdata", "color", "model.gen.go")
+
+	analyzer, err := NewSchemaAnalyzer(schemaPath)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+
+	results, err := analyzer.Analyze()
+	if err != nil {
+		t.Fatalf("Failed to analyze schema: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	generator := NewCodeGenerator(modelPath, outputDir, results)
+	if err := generator.Generate(); err != nil {
+		t.Fatalf("Failed to generate code: %v", err)
+	}
+
+	modelStr := readModel(t, outputDir)
+	checkForContent(t, modelStr, "\"gopkg.in/yaml.v3\"")
+
+	// The parent collections dispatch through parseUnknown, like the JSON callers
+	for _, parent := range []string{"Color", "Palette"} {
+		checkForContent(t, modelStr, fmt.Sprintf("func (s *%sSlice) UnmarshalYAML(node *yaml.Node) error", parent))
+		checkForContent(t, modelStr, fmt.Sprintf("func (m *%sMap) UnmarshalYAML(node *yaml.Node) error", parent))
+		checkForContent(t, modelStr, fmt.Sprintf("p, err := parseUnknown%s(item)", parent))
+	}
+
+	// Children and callers go through their JSON methods, once each
+	for _, typeName := range []string{"RGBValue", "CategoricalPalette", "AssetPack", "ColorConfig"} {
+		unmarshal := fmt.Sprintf("func (j *%s) UnmarshalYAML(node *yaml.Node) error", typeName)
+		if count := strings.Count(modelStr, unmarshal); count != 1 {
+			t.Errorf("Expected one UnmarshalYAML for %s, got %d", typeName, count)
+		}
+		marshal := fmt.Sprintf("func (j %s) MarshalYAML() (interface{}, error)", typeName)
+		if count := strings.Count(modelStr, marshal); count != 1 {
+			t.Errorf("Expected one MarshalYAML for %s, got %d", typeName, count)
+		}
+	}
+	checkForContent(t, modelStr, "func yamlValue(node *yaml.Node) (interface{}, error)")
+	checkForContent(t, modelStr, "func jsonToYAML(b []byte) (*yaml.Node, error)")
+
+	// Parents are interfaces and can't have methods of their own
+	checkForAbsence(t, modelStr, "func (j Color) MarshalYAML")
+
+	validateGoTypes(t, outputDir)
+}
+
+func TestUnionStructs(t *testing.T) {
+	data := fileData{
+		Parents: []parentData{
+			{Name: "Color", Info: ParentInfo{LeafChildren: []string{"RGBValue", "HSLValue"}}},
+			{Name: "Palette", Info: ParentInfo{LeafChildren: []string{"MatrixPalette"}}},
+		},
+		Callers: []callerData{{Struct: "MatrixPalette"}, {Struct: "AssetPack"}},
+	}
+
+	got := strings.Join(unionStructs(data), ",")
+	if want := "AssetPack,HSLValue,MatrixPalette,RGBValue"; got != want {
+		t.Errorf("Expected structs %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,370 @@
+// Code generated by json-schema-postprocess. DO NOT EDIT.
+
+export interface AssetPack {
+	brandName: string;
+	palettes: Palette[];
+}
+
+export interface CMYKValue {
+	c: number;
+	k: number;
+	m: number;
+	model: "cmyk";
+	y: number;
+}
+
+export interface CategoricalPalette {
+	colorScheme?: ColorSchemeType;
+	colors: Color[];
+	description?: string;
+	id?: string;
+	name: string;
+	semantic: string | null;
+	type: "categorical";
+	usage?: string[];
+}
+
+export type Color = HSLValue | HSVValue | HSIValue | RGBValue | RGBAValue | LABValue | LCHValue | CMYKValue;
+
+export interface ColorConfig {
+	id?: string;
+	name?: string;
+	undertone?: Undertone;
+	usage?: string[];
+	value: Color;
+}
+
+export type ColorSchema = AssetPack;
+
+export type ColorSchemeType = "monochromatic" | "complementary" | "split-complementary" | "achromatic" | "analogous" | "triadic" | "tetradic" | "polychromatic" | "customized";
+
+export interface ContinuousColor {
+	id?: string;
+	location?: number;
+	name?: string;
+	undertone?: Undertone;
+	usage?: string[];
+	value: Color;
+}
+
+export interface ContinuousScalePalette {
+	colorScheme?: ColorSchemeType;
+	colors: ContinuousColor[];
+	description?: string;
+	id?: string;
+	name: string;
+	semantic: string | null;
+	type: "continuous-scale";
+	usage?: string[];
+}
+
+export interface DiscreteScalePalette {
+	colorScheme?: ColorSchemeType;
+	colors: Color[];
+	description?: string;
+	id?: string;
+	name: string;
+	semantic: string | null;
+	type: "discrete-scale";
+	usage?: string[];
+}
+
+export interface HSIValue {
+	h: number;
+	i: number;
+	model: "hsi";
+	s: number;
+}
+
+export interface HSLValue {
+	h: number;
+	l: number;
+	model: "hsl";
+	s: number;
+}
+
+export interface HSVValue {
+	h: number;
+	model: "hsv";
+	s: number;
+	v: number;
+}
+
+export interface LABValue {
+	a: number;
+	b: number;
+	l: number;
+	model: "lab";
+}
+
+export interface LCHValue {
+	c: number;
+	h: number;
+	l: number;
+	model: "lch";
+}
+
+export interface MatrixPalette {
+	colorScheme?: ColorSchemeType;
+	colors: Color[][];
+	description?: string;
+	id?: string;
+	name: string;
+	origin: Origin;
+	semantic: string | null;
+	type: "matrix";
+	usage?: string[];
+}
+
+export interface Origin {
+	x: Color[];
+	y: Color[];
+}
+
+export type Palette = CategoricalPalette | DiscreteScalePalette | ContinuousScalePalette | MatrixPalette;
+
+export interface RGBAValue {
+	a: number;
+	b: number;
+	g: number;
+	model: "rgba";
+	r: number;
+}
+
+export interface RGBValue {
+	b: number;
+	g: number;
+	model: "rgb";
+	r: number;
+}
+
+export type Undertone = "warm" | "neutral" | "cool";
+
+/** isRecord reports whether value is a JSON object */
+function isRecord(value: unknown): value is Record<string, unknown> {
+	return typeof value === "object" && value !== null && !Array.isArray(value);
+}
+
+/** pathPrefix returns the JSON pointer an error message starts with, if there is one */
+function pathPrefix(path: string): string {
+	return path === "" ? "" : path + ": ";
+}
+
+/** checkItems runs check on every item of value, if it is an array */
+function checkItems(value: unknown, path: string, check: (item: unknown, itemPath: string) => void): void {
+	if (Array.isArray(value)) {
+		value.forEach((item, i) => check(item, path + "/" + i));
+	}
+}
+
+/** isCMYKValue reports whether value has the keys and constants of CMYKValue */
+export function isCMYKValue(value: unknown): value is CMYKValue {
+	return isRecord(value) && "c" in value && "k" in value && "m" in value && value["model"] === "cmyk" && "y" in value;
+}
+
+/** isCategoricalPalette reports whether value has the keys and constants of CategoricalPalette */
+export function isCategoricalPalette(value: unknown): value is CategoricalPalette {
+	return isRecord(value) && "colors" in value && "name" in value && "semantic" in value && value["type"] === "categorical";
+}
+
+/** isContinuousScalePalette reports whether value has the keys and constants of ContinuousScalePalette */
+export function isContinuousScalePalette(value: unknown): value is ContinuousScalePalette {
+	return isRecord(value) && "colors" in value && "name" in value && "semantic" in value && value["type"] === "continuous-scale";
+}
+
+/** isDiscreteScalePalette reports whether value has the keys and constants of DiscreteScalePalette */
+export function isDiscreteScalePalette(value: unknown): value is DiscreteScalePalette {
+	return isRecord(value) && "colors" in value && "name" in value && "semantic" in value && value["type"] === "discrete-scale";
+}
+
+/** isHSIValue reports whether value has the keys and constants of HSIValue */
+export function isHSIValue(value: unknown): value is HSIValue {
+	return isRecord(value) && "h" in value && "i" in value && value["model"] === "hsi" && "s" in value;
+}
+
+/** isHSLValue reports whether value has the keys and constants of HSLValue */
+export function isHSLValue(value: unknown): value is HSLValue {
+	return isRecord(value) && "h" in value && "l" in value && value["model"] === "hsl" && "s" in value;
+}
+
+/** isHSVValue reports whether value has the keys and constants of HSVValue */
+export function isHSVValue(value: unknown): value is HSVValue {
+	return isRecord(value) && "h" in value && value["model"] === "hsv" && "s" in value && "v" in value;
+}
+
+/** isLABValue reports whether value has the keys and constants of LABValue */
+export function isLABValue(value: unknown): value is LABValue {
+	return isRecord(value) && "a" in value && "b" in value && "l" in value && value["model"] === "lab";
+}
+
+/** isLCHValue reports whether value has the keys and constants of LCHValue */
+export function isLCHValue(value: unknown): value is LCHValue {
+	return isRecord(value) && "c" in value && "h" in value && "l" in value && value["model"] === "lch";
+}
+
+/** isMatrixPalette reports whether value has the keys and constants of MatrixPalette */
+export function isMatrixPalette(value: unknown): value is MatrixPalette {
+	return isRecord(value) && "colors" in value && "name" in value && "origin" in value && "semantic" in value && value["type"] === "matrix";
+}
+
+/** isRGBAValue reports whether value has the keys and constants of RGBAValue */
+export function isRGBAValue(value: unknown): value is RGBAValue {
+	return isRecord(value) && "a" in value && "b" in value && "g" in value && value["model"] === "rgba" && "r" in value;
+}
+
+/** isRGBValue reports whether value has the keys and constants of RGBValue */
+export function isRGBValue(value: unknown): value is RGBValue {
+	return isRecord(value) && "b" in value && "g" in value && value["model"] === "rgb" && "r" in value;
+}
+
+/** checkCategoricalPalette decodes the unions nested in value, throwing at the path of the first invalid one */
+function checkCategoricalPalette(value: unknown, path: string): void {
+	if (!isRecord(value)) {
+		throw new Error(pathPrefix(path) + "CategoricalPalette must be an object");
+	}
+	checkItems(value["colors"], path + "/colors", decodeColor);
+}
+
+/** checkContinuousColor decodes the unions nested in value, throwing at the path of the first invalid one */
+function checkContinuousColor(value: unknown, path: string): void {
+	if (!isRecord(value)) {
+		throw new Error(pathPrefix(path) + "ContinuousColor must be an object");
+	}
+	decodeColor(value["value"], path + "/value");
+}
+
+/** checkContinuousScalePalette decodes the unions nested in value, throwing at the path of the first invalid one */
+function checkContinuousScalePalette(value: unknown, path: string): void {
+	if (!isRecord(value)) {
+		throw new Error(pathPrefix(path) + "ContinuousScalePalette must be an object");
+	}
+	checkItems(value["colors"], path + "/colors", checkContinuousColor);
+}
+
+/** checkDiscreteScalePalette decodes the unions nested in value, throwing at the path of the first invalid one */
+function checkDiscreteScalePalette(value: unknown, path: string): void {
+	if (!isRecord(value)) {
+		throw new Error(pathPrefix(path) + "DiscreteScalePalette must be an object");
+	}
+	checkItems(value["colors"], path + "/colors", decodeColor);
+}
+
+/** checkMatrixPalette decodes the unions nested in value, throwing at the path of the first invalid one */
+function checkMatrixPalette(value: unknown, path: string): void {
+	if (!isRecord(value)) {
+		throw new Error(pathPrefix(path) + "MatrixPalette must be an object");
+	}
+	checkItems(value["colors"], path + "/colors", (item0: unknown, itemPath0: string) => { checkItems(item0, itemPath0, decodeColor); });
+	checkOrigin(value["origin"], path + "/origin");
+}
+
+/** checkOrigin decodes the unions nested in value, throwing at the path of the first invalid one */
+function checkOrigin(value: unknown, path: string): void {
+	if (!isRecord(value)) {
+		throw new Error(pathPrefix(path) + "Origin must be an object");
+	}
+	checkItems(value["x"], path + "/x", decodeColor);
+	checkItems(value["y"], path + "/y", decodeColor);
+}
+
+/** isColor reports whether value is one of the children of Color */
+export function isColor(value: unknown): value is Color {
+	return isHSLValue(value) || isHSVValue(value) || isHSIValue(value) || isRGBValue(value) || isRGBAValue(value) || isLABValue(value) || isLCHValue(value) || isCMYKValue(value);
+}
+
+/**
+ * decodeColor returns value as the child of Color it matches, decoding the
+ * unions nested in it, or throws at the path of the first invalid one
+ */
+export function decodeColor(value: unknown, path = ""): Color {
+	if (!isRecord(value)) {
+		throw new Error(pathPrefix(path) + "Color must be an object");
+	}
+	switch (value["model"]) {
+		case "hsl":
+			if (isHSLValue(value)) {
+				return value;
+			}
+			throw new Error(pathPrefix(path) + "invalid HSLValue");
+		case "hsv":
+			if (isHSVValue(value)) {
+				return value;
+			}
+			throw new Error(pathPrefix(path) + "invalid HSVValue");
+		case "hsi":
+			if (isHSIValue(value)) {
+				return value;
+			}
+			throw new Error(pathPrefix(path) + "invalid HSIValue");
+		case "rgb":
+			if (isRGBValue(value)) {
+				return value;
+			}
+			throw new Error(pathPrefix(path) + "invalid RGBValue");
+		case "rgba":
+			if (isRGBAValue(value)) {
+				return value;
+			}
+			throw new Error(pathPrefix(path) + "invalid RGBAValue");
+		case "lab":
+			if (isLABValue(value)) {
+				return value;
+			}
+			throw new Error(pathPrefix(path) + "invalid LABValue");
+		case "lch":
+			if (isLCHValue(value)) {
+				return value;
+			}
+			throw new Error(pathPrefix(path) + "invalid LCHValue");
+		case "cmyk":
+			if (isCMYKValue(value)) {
+				return value;
+			}
+			throw new Error(pathPrefix(path) + "invalid CMYKValue");
+		default:
+			throw new Error(pathPrefix(path) + "unknown Color model: " + JSON.stringify(value["model"]));
+	}
+}
+
+/** isPalette reports whether value is one of the children of Palette */
+export function isPalette(value: unknown): value is Palette {
+	return isCategoricalPalette(value) || isDiscreteScalePalette(value) || isContinuousScalePalette(value) || isMatrixPalette(value);
+}
+
+/**
+ * decodePalette returns value as the child of Palette it matches, decoding the
+ * unions nested in it, or throws at the path of the first invalid one
+ */
+export function decodePalette(value: unknown, path = ""): Palette {
+	if (!isRecord(value)) {
+		throw new Error(pathPrefix(path) + "Palette must be an object");
+	}
+	switch (value["type"]) {
+		case "categorical":
+			if (isCategoricalPalette(value)) {
+				checkCategoricalPalette(value, path);
+				return value;
+			}
+			throw new Error(pathPrefix(path) + "invalid CategoricalPalette");
+		case "discrete-scale":
+			if (isDiscreteScalePalette(value)) {
+				checkDiscreteScalePalette(value, path);
+				return value;
+			}
+			throw new Error(pathPrefix(path) + "invalid DiscreteScalePalette");
+		case "continuous-scale":
+			if (isContinuousScalePalette(value)) {
+				checkContinuousScalePalette(value, path);
+				return value;
+			}
+			throw new Error(pathPrefix(path) + "invalid ContinuousScalePalette");
+		case "matrix":
+			if (isMatrixPalette(value)) {
+				checkMatrixPalette(value, path);
+				return value;
+			}
+			throw new Error(pathPrefix(path) + "invalid MatrixPalette");
+		default:
+			throw new Error(pathPrefix(path) + "unknown Palette type: " + JSON.stringify(value["type"]));
+	}
+}
@@ -0,0 +1,266 @@
+package color
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+// benchColors builds n colors cycling through a few of the models
+func benchColors(tb testing.TB, n int) ColorSlice {
+	tb.Helper()
+
+	colors := make(ColorSlice, 0, n)
+	for i := 0; i < n; i++ {
+		var (
+			c   Color
+			err error
+		)
+		switch i % 4 {
+		case 0:
+			c, err = NewRGBValue(float64(i%256), 128, 64)
+		case 1:
+			c, err = NewHSLValue(float64(i%360), 0.5, 0.5)
+		case 2:
+			c, err = NewCMYKValue(0.1, 0.2, 0.3, float64(i%100)/100)
+		case 3:
+			c, err = NewLABValue(float64(i%100), -20, 20)
+		}
+		if err != nil {
+			tb.Fatalf("Failed to build color %d: %v", i, err)
+		}
+		colors = append(colors, c)
+	}
+	return colors
+}
+
+// benchAssetPack builds an asset pack of categorical palettes holding n colors in all
+func benchAssetPack(tb testing.TB, n int) []byte {
+	tb.Helper()
+
+	colors := benchColors(tb, n)
+	pack := AssetPack{BrandName: "bench"}
+	for i := 0; i < len(colors); i += 10 {
+		palette, err := NewCategoricalPalette(colors[i:min(i+10, len(colors))], fmt.Sprintf("palette-%d", i), nil)
+		if err != nil {
+			tb.Fatalf("Failed to build palette %d: %v", i, err)
+		}
+		pack.Palettes = append(pack.Palettes, palette)
+	}
+
+	b, err := json.Marshal(pack)
+	if err != nil {
+		tb.Fatalf("Failed to marshal asset pack: %v", err)
+	}
+	return b
+}
+
+// marshalColors returns the JSON array of colors the benchmarks decode
+func marshalColors(tb testing.TB, colors ColorSlice) []byte {
+	tb.Helper()
+
+	b, err := json.Marshal(colors)
+	if err != nil {
+		tb.Fatalf("Failed to marshal colors: %v", err)
+	}
+	return b
+}
+
+// legacyParseUnknownColor is a copy of the parser the generator emitted before
+// unions were parsed from raw JSON, kept as the baseline the benchmarks measure
+// against. It marshals the decoded value back to JSON and then unmarshals it
+// twice, once for the model and once into the child
+func legacyParseUnknownColor(b interface{}) (Color, error) {
+	str, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+
+	// Use the model field to determine the type
+	type Plain struct {
+		Model ColorModel `json:"model" yaml:"model" mapstructure:"model"`
+	}
+	var plain Plain
+	if err := json.Unmarshal(str, &plain); err != nil {
+		return nil, err
+	}
+
+	switch plain.Model {
+	case ColorModelHsl:
+		var hSLValue HSLValue
+		err = json.Unmarshal(str, &hSLValue)
+		return &hSLValue, err
+	case ColorModelHsv:
+		var hSVValue HSVValue
+		err = json.Unmarshal(str, &hSVValue)
+		return &hSVValue, err
+	case ColorModelHsi:
+		var hSIValue HSIValue
+		err = json.Unmarshal(str, &hSIValue)
+		return &hSIValue, err
+	case ColorModelRgb:
+		var rGBValue RGBValue
+		err = json.Unmarshal(str, &rGBValue)
+		return &rGBValue, err
+	case ColorModelRgba:
+		var rGBAValue RGBAValue
+		err = json.Unmarshal(str, &rGBAValue)
+		return &rGBAValue, err
+	case ColorModelLab:
+		var lABValue LABValue
+		err = json.Unmarshal(str, &lABValue)
+		return &lABValue, err
+	case ColorModelLch:
+		var lCHValue LCHValue
+		err = json.Unmarshal(str, &lCHValue)
+		return &lCHValue, err
+	case ColorModelCmyk:
+		var cMYKValue CMYKValue
+		err = json.Unmarshal(str, &cMYKValue)
+		return &cMYKValue, err
+	default:
+		return nil, fmt.Errorf("invalid model: %s", plain.Model)
+	}
+}
+
+// decodeColorsLegacy parses a JSON array of colors the way ColorSlice did
+// before unions were parsed from raw JSON: into interface values first, each
+// then parsed by legacyParseUnknownColor
+func decodeColorsLegacy(b []byte) (ColorSlice, error) {
+	var items []interface{}
+	if err := json.Unmarshal(b, &items); err != nil {
+		return nil, err
+	}
+	colors := make(ColorSlice, 0, len(items))
+	for _, item := range items {
+		c, err := legacyParseUnknownColor(item)
+		if err != nil {
+			return nil, err
+		}
+		colors = append(colors, c)
+	}
+	return colors, nil
+}
+
+// decodeColorsRaw parses a JSON array of colors from the raw JSON of each element
+func decodeColorsRaw(b []byte) (ColorSlice, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(b, &items); err != nil {
+		return nil, err
+	}
+	colors := make(ColorSlice, 0, len(items))
+	for _, item := range items {
+		c, err := parseColorRaw(item)
+		if err != nil {
+			return nil, err
+		}
+		colors = append(colors, c)
+	}
+	return colors, nil
+}
+
+func TestDecodeColors_PathsAgree(t *testing.T) {
+	want := benchColors(t, 100)
+	b := marshalColors(t, want)
+
+	decoders := map[string]func([]byte) (ColorSlice, error){
+		"legacy": decodeColorsLegacy,
+		"raw":    decodeColorsRaw,
+		"stream": func(b []byte) (ColorSlice, error) {
+			return DecodeColorSlice(json.NewDecoder(bytes.NewReader(b)))
+		},
+	}
+	for name, decode := range decoders {
+		got, err := decode(b)
+		if err != nil {
+			t.Fatalf("%s: failed to decode colors: %v", name, err)
+		}
+		if !want.Equal(got) {
+			t.Errorf("%s: decoded colors differ from the encoded ones", name)
+		}
+	}
+}
+
+func TestPeekJSONField(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{`{"model":"rgb"}`, `"rgb"`},
+		{` { "a" : {"model":"hsl","b":[1,{"c":"}"}]} , "d":"\"model\"" , "model" : "lab" }`, `"lab"`},
+		{`{"a":1.5e3,"b":true,"c":null,"model":"hsl"}`, `"hsl"`},
+		{`{"a":"x\\","model":1}`, `1`},
+		{`{"model":"cmyk"}`, `"cmyk"`},
+		{`{"model":"rgb","model":"hsl"}`, `"rgb"`},
+		{`[{"model":"rgb"}]`, ``},
+		{`{"a":"unterminated`, ``},
+		{`{"a":1 "model":"rgb"}`, ``},
+	}
+	for _, tt := range tests {
+		got, ok := peekJSONField([]byte(tt.data), "model")
+		if string(got) != tt.want || ok != (tt.want != "") {
+			t.Errorf("peekJSONField(%s) = %s, %v, want %s", tt.data, got, ok, tt.want)
+		}
+	}
+}
+
+func TestDecodeColorStream_Errors(t *testing.T) {
+	for _, data := range []string{`{}`, `[{"model":"unknown"}]`, `[{"model":"rgb"}`} {
+		if _, err := DecodeColorSlice(json.NewDecoder(bytes.NewReader([]byte(data)))); err == nil {
+			t.Errorf("Expected an error decoding %s", data)
+		}
+	}
+
+	colors, err := DecodeColorSlice(json.NewDecoder(bytes.NewReader([]byte(`null`))))
+	if err != nil || colors != nil {
+		t.Errorf("Expected no colors from null, got %v, %v", colors, err)
+	}
+}
+
+func BenchmarkDecodeColors(b *testing.B) {
+	data := marshalColors(b, benchColors(b, 1000))
+
+	b.Run("legacy", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := decodeColorsLegacy(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("raw", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := decodeColorsRaw(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("stream", func(b *testing.B) {
+		b.SetBytes(int64(len(data)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := DecodeColorSlice(json.NewDecoder(bytes.NewReader(data))); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkAssetPack_UnmarshalJSON(b *testing.B) {
+	data := benchAssetPack(b, 1000)
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var pack AssetPack
+		if err := json.Unmarshal(data, &pack); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+// Package color is the model of schema/color.schema.json, generated by
+// go-jsonschema and merged with the union code of json-schema-postprocess
+package color
+
+// The postprocessor regenerates every target of the repository's
+// .postprocess.yaml, the same run as the generate-jsonschema task
+//go:generate go run ../../../../cmd/json-schema-postprocess -config=../../../../.postprocess.yaml
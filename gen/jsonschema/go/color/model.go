@@ -1,40 +1,24 @@
-// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+// Code generated by json-schema-postprocess. DO NOT EDIT.
 
 package color
 
-import "encoding/json"
-import "fmt"
-import "reflect"
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
 
 type AssetPack struct {
 	// BrandName corresponds to the JSON schema field "brandName".
 	BrandName string `json:"brandName" yaml:"brandName" mapstructure:"brandName"`
 
 	// Palettes corresponds to the JSON schema field "palettes".
-	Palettes []AssetPackPalettesElem `json:"palettes" yaml:"palettes" mapstructure:"palettes"`
-}
-
-type AssetPackPalettesElem interface{}
-
-// UnmarshalJSON implements json.Unmarshaler.
-func (j *AssetPack) UnmarshalJSON(b []byte) error {
-	var raw map[string]interface{}
-	if err := json.Unmarshal(b, &raw); err != nil {
-		return err
-	}
-	if _, ok := raw["brandName"]; raw != nil && !ok {
-		return fmt.Errorf("field brandName in AssetPack: required")
-	}
-	if _, ok := raw["palettes"]; raw != nil && !ok {
-		return fmt.Errorf("field palettes in AssetPack: required")
-	}
-	type Plain AssetPack
-	var plain Plain
-	if err := json.Unmarshal(b, &plain); err != nil {
-		return err
-	}
-	*j = AssetPack(plain)
-	return nil
+	Palettes PaletteSlice `json:"palettes" yaml:"palettes" mapstructure:"palettes"`
 }
 
 type CMYKValue struct {
@@ -51,63 +35,12 @@ type CMYKValue struct {
 	Y float64 `json:"y" yaml:"y" mapstructure:"y"`
 }
 
-// UnmarshalJSON implements json.Unmarshaler.
-func (j *CMYKValue) UnmarshalJSON(b []byte) error {
-	var raw map[string]interface{}
-	if err := json.Unmarshal(b, &raw); err != nil {
-		return err
-	}
-	if _, ok := raw["c"]; raw != nil && !ok {
-		return fmt.Errorf("field c in CMYKValue: required")
-	}
-	if _, ok := raw["k"]; raw != nil && !ok {
-		return fmt.Errorf("field k in CMYKValue: required")
-	}
-	if _, ok := raw["m"]; raw != nil && !ok {
-		return fmt.Errorf("field m in CMYKValue: required")
-	}
-	if _, ok := raw["y"]; raw != nil && !ok {
-		return fmt.Errorf("field y in CMYKValue: required")
-	}
-	type Plain CMYKValue
-	var plain Plain
-	if err := json.Unmarshal(b, &plain); err != nil {
-		return err
-	}
-	if 1 < plain.C {
-		return fmt.Errorf("field %s: must be <= %v", "c", 1)
-	}
-	if 0 > plain.C {
-		return fmt.Errorf("field %s: must be >= %v", "c", 0)
-	}
-	if 1 < plain.K {
-		return fmt.Errorf("field %s: must be <= %v", "k", 1)
-	}
-	if 0 > plain.K {
-		return fmt.Errorf("field %s: must be >= %v", "k", 0)
-	}
-	if 1 < plain.M {
-		return fmt.Errorf("field %s: must be <= %v", "m", 1)
-	}
-	if 0 > plain.M {
-		return fmt.Errorf("field %s: must be >= %v", "m", 0)
-	}
-	if 1 < plain.Y {
-		return fmt.Errorf("field %s: must be <= %v", "y", 1)
-	}
-	if 0 > plain.Y {
-		return fmt.Errorf("field %s: must be >= %v", "y", 0)
-	}
-	*j = CMYKValue(plain)
-	return nil
-}
-
 type CategoricalPalette struct {
 	// ColorScheme corresponds to the JSON schema field "colorScheme".
 	ColorScheme *ColorSchemeType `json:"colorScheme,omitempty" yaml:"colorScheme,omitempty" mapstructure:"colorScheme,omitempty"`
 
 	// Colors corresponds to the JSON schema field "colors".
-	Colors []CategoricalPaletteColorsElem `json:"colors" yaml:"colors" mapstructure:"colors"`
+	Colors ColorSlice `json:"colors" yaml:"colors" mapstructure:"colors"`
 
 	// Description corresponds to the JSON schema field "description".
 	Description *string `json:"description,omitempty" yaml:"description,omitempty" mapstructure:"description,omitempty"`
@@ -121,44 +54,10 @@ type CategoricalPalette struct {
 	// Semantic corresponds to the JSON schema field "semantic".
 	Semantic *string `json:"semantic" yaml:"semantic" mapstructure:"semantic"`
 
-	// Type corresponds to the JSON schema field "type".
-	Type string `json:"type" yaml:"type" mapstructure:"type"`
-
 	// Usage corresponds to the JSON schema field "usage".
 	Usage []string `json:"usage,omitempty" yaml:"usage,omitempty" mapstructure:"usage,omitempty"`
 }
 
-type CategoricalPaletteColorsElem interface{}
-
-// UnmarshalJSON implements json.Unmarshaler.
-func (j *CategoricalPalette) UnmarshalJSON(b []byte) error {
-	var raw map[string]interface{}
-	if err := json.Unmarshal(b, &raw); err != nil {
-		return err
-	}
-	if _, ok := raw["colors"]; raw != nil && !ok {
-		return fmt.Errorf("field colors in CategoricalPalette: required")
-	}
-	if _, ok := raw["name"]; raw != nil && !ok {
-		return fmt.Errorf("field name in CategoricalPalette: required")
-	}
-	if _, ok := raw["semantic"]; raw != nil && !ok {
-		return fmt.Errorf("field semantic in CategoricalPalette: required")
-	}
-	if _, ok := raw["type"]; raw != nil && !ok {
-		return fmt.Errorf("field type in CategoricalPalette: required")
-	}
-	type Plain CategoricalPalette
-	var plain Plain
-	if err := json.Unmarshal(b, &plain); err != nil {
-		return err
-	}
-	*j = CategoricalPalette(plain)
-	return nil
-}
-
-type Color interface{}
-
 type ColorConfig struct {
 	// Id corresponds to the JSON schema field "id".
 	Id *string `json:"id,omitempty" yaml:"id,omitempty" mapstructure:"id,omitempty"`
@@ -173,39 +72,27 @@ type ColorConfig struct {
 	Usage []string `json:"usage,omitempty" yaml:"usage,omitempty" mapstructure:"usage,omitempty"`
 
 	// Value corresponds to the JSON schema field "value".
-	Value ColorConfigValue `json:"value" yaml:"value" mapstructure:"value"`
-}
-
-type ColorConfigValue interface{}
-
-// UnmarshalJSON implements json.Unmarshaler.
-func (j *ColorConfig) UnmarshalJSON(b []byte) error {
-	var raw map[string]interface{}
-	if err := json.Unmarshal(b, &raw); err != nil {
-		return err
-	}
-	if _, ok := raw["value"]; raw != nil && !ok {
-		return fmt.Errorf("field value in ColorConfig: required")
-	}
-	type Plain ColorConfig
-	var plain Plain
-	if err := json.Unmarshal(b, &plain); err != nil {
-		return err
-	}
-	*j = ColorConfig(plain)
-	return nil
+	Value Color `json:"value" yaml:"value" mapstructure:"value"`
 }
 
 type ColorSchemeType string
 
 const ColorSchemeTypeAchromatic ColorSchemeType = "achromatic"
+
 const ColorSchemeTypeAnalogous ColorSchemeType = "analogous"
+
 const ColorSchemeTypeComplementary ColorSchemeType = "complementary"
+
 const ColorSchemeTypeCustomized ColorSchemeType = "customized"
+
 const ColorSchemeTypeMonochromatic ColorSchemeType = "monochromatic"
+
 const ColorSchemeTypePolychromatic ColorSchemeType = "polychromatic"
+
 const ColorSchemeTypeSplitComplementary ColorSchemeType = "split-complementary"
+
 const ColorSchemeTypeTetradic ColorSchemeType = "tetradic"
+
 const ColorSchemeTypeTriadic ColorSchemeType = "triadic"
 
 var enumValues_ColorSchemeType = []interface{}{
@@ -257,33 +144,7 @@ type ContinuousColor struct {
 	Usage []string `json:"usage,omitempty" yaml:"usage,omitempty" mapstructure:"usage,omitempty"`
 
 	// Value corresponds to the JSON schema field "value".
-	Value ContinuousColorValue `json:"value" yaml:"value" mapstructure:"value"`
-}
-
-type ContinuousColorValue interface{}
-
-// UnmarshalJSON implements json.Unmarshaler.
-func (j *ContinuousColor) UnmarshalJSON(b []byte) error {
-	var raw map[string]interface{}
-	if err := json.Unmarshal(b, &raw); err != nil {
-		return err
-	}
-	if _, ok := raw["value"]; raw != nil && !ok {
-		return fmt.Errorf("field value in ContinuousColor: required")
-	}
-	type Plain ContinuousColor
-	var plain Plain
-	if err := json.Unmarshal(b, &plain); err != nil {
-		return err
-	}
-	if plain.Location != nil && 1 < *plain.Location {
-		return fmt.Errorf("field %s: must be <= %v", "location", 1)
-	}
-	if plain.Location != nil && 0 > *plain.Location {
-		return fmt.Errorf("field %s: must be >= %v", "location", 0)
-	}
-	*j = ContinuousColor(plain)
-	return nil
+	Value Color `json:"value" yaml:"value" mapstructure:"value"`
 }
 
 type ContinuousScalePalette struct {
@@ -305,46 +166,16 @@ type ContinuousScalePalette struct {
 	// Semantic corresponds to the JSON schema field "semantic".
 	Semantic *string `json:"semantic" yaml:"semantic" mapstructure:"semantic"`
 
-	// Type corresponds to the JSON schema field "type".
-	Type string `json:"type" yaml:"type" mapstructure:"type"`
-
 	// Usage corresponds to the JSON schema field "usage".
 	Usage []string `json:"usage,omitempty" yaml:"usage,omitempty" mapstructure:"usage,omitempty"`
 }
 
-// UnmarshalJSON implements json.Unmarshaler.
-func (j *ContinuousScalePalette) UnmarshalJSON(b []byte) error {
-	var raw map[string]interface{}
-	if err := json.Unmarshal(b, &raw); err != nil {
-		return err
-	}
-	if _, ok := raw["colors"]; raw != nil && !ok {
-		return fmt.Errorf("field colors in ContinuousScalePalette: required")
-	}
-	if _, ok := raw["name"]; raw != nil && !ok {
-		return fmt.Errorf("field name in ContinuousScalePalette: required")
-	}
-	if _, ok := raw["semantic"]; raw != nil && !ok {
-		return fmt.Errorf("field semantic in ContinuousScalePalette: required")
-	}
-	if _, ok := raw["type"]; raw != nil && !ok {
-		return fmt.Errorf("field type in ContinuousScalePalette: required")
-	}
-	type Plain ContinuousScalePalette
-	var plain Plain
-	if err := json.Unmarshal(b, &plain); err != nil {
-		return err
-	}
-	*j = ContinuousScalePalette(plain)
-	return nil
-}
-
 type DiscreteScalePalette struct {
 	// ColorScheme corresponds to the JSON schema field "colorScheme".
 	ColorScheme *ColorSchemeType `json:"colorScheme,omitempty" yaml:"colorScheme,omitempty" mapstructure:"colorScheme,omitempty"`
 
 	// Colors corresponds to the JSON schema field "colors".
-	Colors []DiscreteScalePaletteColorsElem `json:"colors" yaml:"colors" mapstructure:"colors"`
+	Colors ColorSlice `json:"colors" yaml:"colors" mapstructure:"colors"`
 
 	// Description corresponds to the JSON schema field "description".
 	Description *string `json:"description,omitempty" yaml:"description,omitempty" mapstructure:"description,omitempty"`
@@ -358,42 +189,10 @@ type DiscreteScalePalette struct {
 	// Semantic corresponds to the JSON schema field "semantic".
 	Semantic *string `json:"semantic" yaml:"semantic" mapstructure:"semantic"`
 
-	// Type corresponds to the JSON schema field "type".
-	Type string `json:"type" yaml:"type" mapstructure:"type"`
-
 	// Usage corresponds to the JSON schema field "usage".
 	Usage []string `json:"usage,omitempty" yaml:"usage,omitempty" mapstructure:"usage,omitempty"`
 }
 
-type DiscreteScalePaletteColorsElem interface{}
-
-// UnmarshalJSON implements json.Unmarshaler.
-func (j *DiscreteScalePalette) UnmarshalJSON(b []byte) error {
-	var raw map[string]interface{}
-	if err := json.Unmarshal(b, &raw); err != nil {
-		return err
-	}
-	if _, ok := raw["colors"]; raw != nil && !ok {
-		return fmt.Errorf("field colors in DiscreteScalePalette: required")
-	}
-	if _, ok := raw["name"]; raw != nil && !ok {
-		return fmt.Errorf("field name in DiscreteScalePalette: required")
-	}
-	if _, ok := raw["semantic"]; raw != nil && !ok {
-		return fmt.Errorf("field semantic in DiscreteScalePalette: required")
-	}
-	if _, ok := raw["type"]; raw != nil && !ok {
-		return fmt.Errorf("field type in DiscreteScalePalette: required")
-	}
-	type Plain DiscreteScalePalette
-	var plain Plain
-	if err := json.Unmarshal(b, &plain); err != nil {
-		return err
-	}
-	*j = DiscreteScalePalette(plain)
-	return nil
-}
-
 type HSIValue struct {
 	// H corresponds to the JSON schema field "h".
 	H float64 `json:"h" yaml:"h" mapstructure:"h"`
@@ -405,48 +204,6 @@ type HSIValue struct {
 	S float64 `json:"s" yaml:"s" mapstructure:"s"`
 }
 
-// UnmarshalJSON implements json.Unmarshaler.
-func (j *HSIValue) UnmarshalJSON(b []byte) error {
-	var raw map[string]interface{}
-	if err := json.Unmarshal(b, &raw); err != nil {
-		return err
-	}
-	if _, ok := raw["h"]; raw != nil && !ok {
-		return fmt.Errorf("field h in HSIValue: required")
-	}
-	if _, ok := raw["i"]; raw != nil && !ok {
-		return fmt.Errorf("field i in HSIValue: required")
-	}
-	if _, ok := raw["s"]; raw != nil && !ok {
-		return fmt.Errorf("field s in HSIValue: required")
-	}
-	type Plain HSIValue
-	var plain Plain
-	if err := json.Unmarshal(b, &plain); err != nil {
-		return err
-	}
-	if 360 < plain.H {
-		return fmt.Errorf("field %s: must be <= %v", "h", 360)
-	}
-	if 0 > plain.H {
-		return fmt.Errorf("field %s: must be >= %v", "h", 0)
-	}
-	if 1 < plain.I {
-		return fmt.Errorf("field %s: must be <= %v", "i", 1)
-	}
-	if 0 > plain.I {
-		return fmt.Errorf("field %s: must be >= %v", "i", 0)
-	}
-	if 1 < plain.S {
-		return fmt.Errorf("field %s: must be <= %v", "s", 1)
-	}
-	if 0 > plain.S {
-		return fmt.Errorf("field %s: must be >= %v", "s", 0)
-	}
-	*j = HSIValue(plain)
-	return nil
-}
-
 type HSLValue struct {
 	// H corresponds to the JSON schema field "h".
 	H float64 `json:"h" yaml:"h" mapstructure:"h"`
@@ -458,48 +215,6 @@ type HSLValue struct {
 	S float64 `json:"s" yaml:"s" mapstructure:"s"`
 }
 
-// UnmarshalJSON implements json.Unmarshaler.
-func (j *HSLValue) UnmarshalJSON(b []byte) error {
-	var raw map[string]interface{}
-	if err := json.Unmarshal(b, &raw); err != nil {
-		return err
-	}
-	if _, ok := raw["h"]; raw != nil && !ok {
-		return fmt.Errorf("field h in HSLValue: required")
-	}
-	if _, ok := raw["l"]; raw != nil && !ok {
-		return fmt.Errorf("field l in HSLValue: required")
-	}
-	if _, ok := raw["s"]; raw != nil && !ok {
-		return fmt.Errorf("field s in HSLValue: required")
-	}
-	type Plain HSLValue
-	var plain Plain
-	if err := json.Unmarshal(b, &plain); err != nil {
-		return err
-	}
-	if 360 < plain.H {
-		return fmt.Errorf("field %s: must be <= %v", "h", 360)
-	}
-	if 0 > plain.H {
-		return fmt.Errorf("field %s: must be >= %v", "h", 0)
-	}
-	if 1 < plain.L {
-		return fmt.Errorf("field %s: must be <= %v", "l", 1)
-	}
-	if 0 > plain.L {
-		return fmt.Errorf("field %s: must be >= %v", "l", 0)
-	}
-	if 1 < plain.S {
-		return fmt.Errorf("field %s: must be <= %v", "s", 1)
-	}
-	if 0 > plain.S {
-		return fmt.Errorf("field %s: must be >= %v", "s", 0)
-	}
-	*j = HSLValue(plain)
-	return nil
-}
-
 type HSVValue struct {
 	// H corresponds to the JSON schema field "h".
 	H float64 `json:"h" yaml:"h" mapstructure:"h"`
@@ -511,48 +226,6 @@ type HSVValue struct {
 	V float64 `json:"v" yaml:"v" mapstructure:"v"`
 }
 
-// UnmarshalJSON implements json.Unmarshaler.
-func (j *HSVValue) UnmarshalJSON(b []byte) error {
-	var raw map[string]interface{}
-	if err := json.Unmarshal(b, &raw); err != nil {
-		return err
-	}
-	if _, ok := raw["h"]; raw != nil && !ok {
-		return fmt.Errorf("field h in HSVValue: required")
-	}
-	if _, ok := raw["s"]; raw != nil && !ok {
-		return fmt.Errorf("field s in HSVValue: required")
-	}
-	if _, ok := raw["v"]; raw != nil && !ok {
-		return fmt.Errorf("field v in HSVValue: required")
-	}
-	type Plain HSVValue
-	var plain Plain
-	if err := json.Unmarshal(b, &plain); err != nil {
-		return err
-	}
-	if 360 < plain.H {
-		return fmt.Errorf("field %s: must be <= %v", "h", 360)
-	}
-	if 0 > plain.H {
-		return fmt.Errorf("field %s: must be >= %v", "h", 0)
-	}
-	if 1 < plain.S {
-		return fmt.Errorf("field %s: must be <= %v", "s", 1)
-	}
-	if 0 > plain.S {
-		return fmt.Errorf("field %s: must be >= %v", "s", 0)
-	}
-	if 1 < plain.V {
-		return fmt.Errorf("field %s: must be <= %v", "v", 1)
-	}
-	if 0 > plain.V {
-		return fmt.Errorf("field %s: must be >= %v", "v", 0)
-	}
-	*j = HSVValue(plain)
-	return nil
-}
-
 type LABValue struct {
 	// A corresponds to the JSON schema field "a".
 	A float64 `json:"a" yaml:"a" mapstructure:"a"`
@@ -564,48 +237,6 @@ type LABValue struct {
 	L float64 `json:"l" yaml:"l" mapstructure:"l"`
 }
 
-// UnmarshalJSON implements json.Unmarshaler.
-func (j *LABValue) UnmarshalJSON(b []byte) error {
-	var raw map[string]interface{}
-	if err := json.Unmarshal(b, &raw); err != nil {
-		return err
-	}
-	if _, ok := raw["a"]; raw != nil && !ok {
-		return fmt.Errorf("field a in LABValue: required")
-	}
-	if _, ok := raw["b"]; raw != nil && !ok {
-		return fmt.Errorf("field b in LABValue: required")
-	}
-	if _, ok := raw["l"]; raw != nil && !ok {
-		return fmt.Errorf("field l in LABValue: required")
-	}
-	type Plain LABValue
-	var plain Plain
-	if err := json.Unmarshal(b, &plain); err != nil {
-		return err
-	}
-	if 98.254 < plain.A {
-		return fmt.Errorf("field %s: must be <= %v", "a", 98.254)
-	}
-	if -86.185 > plain.A {
-		return fmt.Errorf("field %s: must be >= %v", "a", -86.185)
-	}
-	if 94.482 < plain.B {
-		return fmt.Errorf("field %s: must be <= %v", "b", 94.482)
-	}
-	if -107.863 > plain.B {
-		return fmt.Errorf("field %s: must be >= %v", "b", -107.863)
-	}
-	if 100 < plain.L {
-		return fmt.Errorf("field %s: must be <= %v", "l", 100)
-	}
-	if 0 > plain.L {
-		return fmt.Errorf("field %s: must be >= %v", "l", 0)
-	}
-	*j = LABValue(plain)
-	return nil
-}
-
 type LCHValue struct {
 	// C corresponds to the JSON schema field "c".
 	C float64 `json:"c" yaml:"c" mapstructure:"c"`
@@ -615,51 +246,6 @@ type LCHValue struct {
 
 	// L corresponds to the JSON schema field "l".
 	L float64 `json:"l" yaml:"l" mapstructure:"l"`
-
-	// Model corresponds to the JSON schema field "model".
-	Model *string `json:"model,omitempty" yaml:"model,omitempty" mapstructure:"model,omitempty"`
-}
-
-// UnmarshalJSON implements json.Unmarshaler.
-func (j *LCHValue) UnmarshalJSON(b []byte) error {
-	var raw map[string]interface{}
-	if err := json.Unmarshal(b, &raw); err != nil {
-		return err
-	}
-	if _, ok := raw["c"]; raw != nil && !ok {
-		return fmt.Errorf("field c in LCHValue: required")
-	}
-	if _, ok := raw["h"]; raw != nil && !ok {
-		return fmt.Errorf("field h in LCHValue: required")
-	}
-	if _, ok := raw["l"]; raw != nil && !ok {
-		return fmt.Errorf("field l in LCHValue: required")
-	}
-	type Plain LCHValue
-	var plain Plain
-	if err := json.Unmarshal(b, &plain); err != nil {
-		return err
-	}
-	if 100 < plain.C {
-		return fmt.Errorf("field %s: must be <= %v", "c", 100)
-	}
-	if 0 > plain.C {
-		return fmt.Errorf("field %s: must be >= %v", "c", 0)
-	}
-	if 360 < plain.H {
-		return fmt.Errorf("field %s: must be <= %v", "h", 360)
-	}
-	if 0 > plain.H {
-		return fmt.Errorf("field %s: must be >= %v", "h", 0)
-	}
-	if 100 < plain.L {
-		return fmt.Errorf("field %s: must be <= %v", "l", 100)
-	}
-	if 0 > plain.L {
-		return fmt.Errorf("field %s: must be >= %v", "l", 0)
-	}
-	*j = LCHValue(plain)
-	return nil
 }
 
 type MatrixPalette struct {
@@ -667,7 +253,7 @@ type MatrixPalette struct {
 	ColorScheme *ColorSchemeType `json:"colorScheme,omitempty" yaml:"colorScheme,omitempty" mapstructure:"colorScheme,omitempty"`
 
 	// Colors corresponds to the JSON schema field "colors".
-	Colors [][]MatrixPaletteColorsElemElem `json:"colors" yaml:"colors" mapstructure:"colors"`
+	Colors []ColorSlice `json:"colors" yaml:"colors" mapstructure:"colors"`
 
 	// Description corresponds to the JSON schema field "description".
 	Description *string `json:"description,omitempty" yaml:"description,omitempty" mapstructure:"description,omitempty"`
@@ -684,80 +270,18 @@ type MatrixPalette struct {
 	// Semantic corresponds to the JSON schema field "semantic".
 	Semantic *string `json:"semantic" yaml:"semantic" mapstructure:"semantic"`
 
-	// Type corresponds to the JSON schema field "type".
-	Type string `json:"type" yaml:"type" mapstructure:"type"`
-
 	// Usage corresponds to the JSON schema field "usage".
 	Usage []string `json:"usage,omitempty" yaml:"usage,omitempty" mapstructure:"usage,omitempty"`
 }
 
-type MatrixPaletteColorsElemElem interface{}
-
-// UnmarshalJSON implements json.Unmarshaler.
-func (j *MatrixPalette) UnmarshalJSON(b []byte) error {
-	var raw map[string]interface{}
-	if err := json.Unmarshal(b, &raw); err != nil {
-		return err
-	}
-	if _, ok := raw["colors"]; raw != nil && !ok {
-		return fmt.Errorf("field colors in MatrixPalette: required")
-	}
-	if _, ok := raw["name"]; raw != nil && !ok {
-		return fmt.Errorf("field name in MatrixPalette: required")
-	}
-	if _, ok := raw["origin"]; raw != nil && !ok {
-		return fmt.Errorf("field origin in MatrixPalette: required")
-	}
-	if _, ok := raw["semantic"]; raw != nil && !ok {
-		return fmt.Errorf("field semantic in MatrixPalette: required")
-	}
-	if _, ok := raw["type"]; raw != nil && !ok {
-		return fmt.Errorf("field type in MatrixPalette: required")
-	}
-	type Plain MatrixPalette
-	var plain Plain
-	if err := json.Unmarshal(b, &plain); err != nil {
-		return err
-	}
-	*j = MatrixPalette(plain)
-	return nil
-}
-
 type Origin struct {
 	// X corresponds to the JSON schema field "x".
-	X []OriginXElem `json:"x" yaml:"x" mapstructure:"x"`
+	X ColorSlice `json:"x" yaml:"x" mapstructure:"x"`
 
 	// Y corresponds to the JSON schema field "y".
-	Y []OriginYElem `json:"y" yaml:"y" mapstructure:"y"`
-}
-
-type OriginXElem interface{}
-
-type OriginYElem interface{}
-
-// UnmarshalJSON implements json.Unmarshaler.
-func (j *Origin) UnmarshalJSON(b []byte) error {
-	var raw map[string]interface{}
-	if err := json.Unmarshal(b, &raw); err != nil {
-		return err
-	}
-	if _, ok := raw["x"]; raw != nil && !ok {
-		return fmt.Errorf("field x in Origin: required")
-	}
-	if _, ok := raw["y"]; raw != nil && !ok {
-		return fmt.Errorf("field y in Origin: required")
-	}
-	type Plain Origin
-	var plain Plain
-	if err := json.Unmarshal(b, &plain); err != nil {
-		return err
-	}
-	*j = Origin(plain)
-	return nil
+	Y ColorSlice `json:"y" yaml:"y" mapstructure:"y"`
 }
 
-type Palette interface{}
-
 type RGBAValue struct {
 	// A corresponds to the JSON schema field "a".
 	A float64 `json:"a" yaml:"a" mapstructure:"a"`
@@ -772,57 +296,6 @@ type RGBAValue struct {
 	R float64 `json:"r" yaml:"r" mapstructure:"r"`
 }
 
-// UnmarshalJSON implements json.Unmarshaler.
-func (j *RGBAValue) UnmarshalJSON(b []byte) error {
-	var raw map[string]interface{}
-	if err := json.Unmarshal(b, &raw); err != nil {
-		return err
-	}
-	if _, ok := raw["a"]; raw != nil && !ok {
-		return fmt.Errorf("field a in RGBAValue: required")
-	}
-	if _, ok := raw["b"]; raw != nil && !ok {
-		return fmt.Errorf("field b in RGBAValue: required")
-	}
-	if _, ok := raw["g"]; raw != nil && !ok {
-		return fmt.Errorf("field g in RGBAValue: required")
-	}
-	if _, ok := raw["r"]; raw != nil && !ok {
-		return fmt.Errorf("field r in RGBAValue: required")
-	}
-	type Plain RGBAValue
-	var plain Plain
-	if err := json.Unmarshal(b, &plain); err != nil {
-		return err
-	}
-	if 1 < plain.A {
-		return fmt.Errorf("field %s: must be <= %v", "a", 1)
-	}
-	if 0 > plain.A {
-		return fmt.Errorf("field %s: must be >= %v", "a", 0)
-	}
-	if 255 < plain.B {
-		return fmt.Errorf("field %s: must be <= %v", "b", 255)
-	}
-	if 0 > plain.B {
-		return fmt.Errorf("field %s: must be >= %v", "b", 0)
-	}
-	if 255 < plain.G {
-		return fmt.Errorf("field %s: must be <= %v", "g", 255)
-	}
-	if 0 > plain.G {
-		return fmt.Errorf("field %s: must be >= %v", "g", 0)
-	}
-	if 255 < plain.R {
-		return fmt.Errorf("field %s: must be <= %v", "r", 255)
-	}
-	if 0 > plain.R {
-		return fmt.Errorf("field %s: must be >= %v", "r", 0)
-	}
-	*j = RGBAValue(plain)
-	return nil
-}
-
 type RGBValue struct {
 	// B corresponds to the JSON schema field "b".
 	B float64 `json:"b" yaml:"b" mapstructure:"b"`
@@ -834,52 +307,12 @@ type RGBValue struct {
 	R float64 `json:"r" yaml:"r" mapstructure:"r"`
 }
 
-// UnmarshalJSON implements json.Unmarshaler.
-func (j *RGBValue) UnmarshalJSON(b []byte) error {
-	var raw map[string]interface{}
-	if err := json.Unmarshal(b, &raw); err != nil {
-		return err
-	}
-	if _, ok := raw["b"]; raw != nil && !ok {
-		return fmt.Errorf("field b in RGBValue: required")
-	}
-	if _, ok := raw["g"]; raw != nil && !ok {
-		return fmt.Errorf("field g in RGBValue: required")
-	}
-	if _, ok := raw["r"]; raw != nil && !ok {
-		return fmt.Errorf("field r in RGBValue: required")
-	}
-	type Plain RGBValue
-	var plain Plain
-	if err := json.Unmarshal(b, &plain); err != nil {
-		return err
-	}
-	if 255 < plain.B {
-		return fmt.Errorf("field %s: must be <= %v", "b", 255)
-	}
-	if 0 > plain.B {
-		return fmt.Errorf("field %s: must be >= %v", "b", 0)
-	}
-	if 255 < plain.G {
-		return fmt.Errorf("field %s: must be <= %v", "g", 255)
-	}
-	if 0 > plain.G {
-		return fmt.Errorf("field %s: must be >= %v", "g", 0)
-	}
-	if 255 < plain.R {
-		return fmt.Errorf("field %s: must be <= %v", "r", 255)
-	}
-	if 0 > plain.R {
-		return fmt.Errorf("field %s: must be >= %v", "r", 0)
-	}
-	*j = RGBValue(plain)
-	return nil
-}
-
 type Undertone string
 
 const UndertoneCool Undertone = "cool"
+
 const UndertoneNeutral Undertone = "neutral"
+
 const UndertoneWarm Undertone = "warm"
 
 var enumValues_Undertone = []interface{}{
@@ -907,3 +340,3922 @@ func (j *Undertone) UnmarshalJSON(b []byte) error {
 	*j = Undertone(v)
 	return nil
 }
+
+// Color represents the parent type for Color types
+type Color interface {
+	isColor()
+	Model() ColorModel
+}
+
+// ColorSlice is a slice of Color interfaces
+type ColorSlice []Color
+
+// ColorMap is a map of Color interfaces
+type ColorMap map[string]Color
+
+// isColor implements the Color interface
+func (me *HSLValue) isColor() {}
+
+// Model returns the Color type constant
+func (me *HSLValue) Model() ColorModel { return ColorModelHsl }
+
+// isColor implements the Color interface
+func (me *HSVValue) isColor() {}
+
+// Model returns the Color type constant
+func (me *HSVValue) Model() ColorModel { return ColorModelHsv }
+
+// isColor implements the Color interface
+func (me *HSIValue) isColor() {}
+
+// Model returns the Color type constant
+func (me *HSIValue) Model() ColorModel { return ColorModelHsi }
+
+// isColor implements the Color interface
+func (me *RGBValue) isColor() {}
+
+// Model returns the Color type constant
+func (me *RGBValue) Model() ColorModel { return ColorModelRgb }
+
+// isColor implements the Color interface
+func (me *RGBAValue) isColor() {}
+
+// Model returns the Color type constant
+func (me *RGBAValue) Model() ColorModel { return ColorModelRgba }
+
+// isColor implements the Color interface
+func (me *LABValue) isColor() {}
+
+// Model returns the Color type constant
+func (me *LABValue) Model() ColorModel { return ColorModelLab }
+
+// isColor implements the Color interface
+func (me *LCHValue) isColor() {}
+
+// Model returns the Color type constant
+func (me *LCHValue) Model() ColorModel { return ColorModelLch }
+
+// isColor implements the Color interface
+func (me *CMYKValue) isColor() {}
+
+// Model returns the Color type constant
+func (me *CMYKValue) Model() ColorModel { return ColorModelCmyk }
+
+// ColorModel represents the model model type
+type ColorModel string
+
+// Constants for the different model types
+const (
+	ColorModelHsl  ColorModel = "hsl"
+	ColorModelHsv  ColorModel = "hsv"
+	ColorModelHsi  ColorModel = "hsi"
+	ColorModelRgb  ColorModel = "rgb"
+	ColorModelRgba ColorModel = "rgba"
+	ColorModelLab  ColorModel = "lab"
+	ColorModelLch  ColorModel = "lch"
+	ColorModelCmyk ColorModel = "cmyk"
+)
+
+// ColorVisitor has a method for each concrete Color type, so an
+// implementation handles all of them
+type ColorVisitor interface {
+	VisitHSL(*HSLValue)
+	VisitHSV(*HSVValue)
+	VisitHSI(*HSIValue)
+	VisitRGB(*RGBValue)
+	VisitRGBA(*RGBAValue)
+	VisitLAB(*LABValue)
+	VisitLCH(*LCHValue)
+	VisitCMYK(*CMYKValue)
+}
+
+// AcceptColor calls the method of visitor for the concrete type of value
+func AcceptColor(value Color, visitor ColorVisitor) error {
+	switch value := value.(type) {
+	case *HSLValue:
+		visitor.VisitHSL(value)
+	case *HSVValue:
+		visitor.VisitHSV(value)
+	case *HSIValue:
+		visitor.VisitHSI(value)
+	case *RGBValue:
+		visitor.VisitRGB(value)
+	case *RGBAValue:
+		visitor.VisitRGBA(value)
+	case *LABValue:
+		visitor.VisitLAB(value)
+	case *LCHValue:
+		visitor.VisitLCH(value)
+	case *CMYKValue:
+		visitor.VisitCMYK(value)
+	default:
+		return fmt.Errorf("unknown Color type %T", value)
+	}
+	return nil
+}
+
+// MatchColor calls the function for the concrete type of value and returns
+// its result. Adding a child to Color adds a parameter, so every call
+// has to handle it
+func MatchColor[T any](value Color, onHSL func(*HSLValue) T, onHSV func(*HSVValue) T, onHSI func(*HSIValue) T, onRGB func(*RGBValue) T, onRGBA func(*RGBAValue) T, onLAB func(*LABValue) T, onLCH func(*LCHValue) T, onCMYK func(*CMYKValue) T) (T, error) {
+	switch value := value.(type) {
+	case *HSLValue:
+		return onHSL(value), nil
+	case *HSVValue:
+		return onHSV(value), nil
+	case *HSIValue:
+		return onHSI(value), nil
+	case *RGBValue:
+		return onRGB(value), nil
+	case *RGBAValue:
+		return onRGBA(value), nil
+	case *LABValue:
+		return onLAB(value), nil
+	case *LCHValue:
+		return onLCH(value), nil
+	case *CMYKValue:
+		return onCMYK(value), nil
+	}
+	var zero T
+	return zero, fmt.Errorf("unknown Color type %T", value)
+}
+
+// Palette represents the parent type for Palette types
+type Palette interface {
+	isPalette()
+	Type() PaletteModel
+}
+
+// PaletteSlice is a slice of Palette interfaces
+type PaletteSlice []Palette
+
+// PaletteMap is a map of Palette interfaces
+type PaletteMap map[string]Palette
+
+// isPalette implements the Palette interface
+func (me *CategoricalPalette) isPalette() {}
+
+// Type returns the Palette type constant
+func (me *CategoricalPalette) Type() PaletteModel { return PaletteModelCategorical }
+
+// isPalette implements the Palette interface
+func (me *DiscreteScalePalette) isPalette() {}
+
+// Type returns the Palette type constant
+func (me *DiscreteScalePalette) Type() PaletteModel { return PaletteModelDiscrete_scale }
+
+// isPalette implements the Palette interface
+func (me *ContinuousScalePalette) isPalette() {}
+
+// Type returns the Palette type constant
+func (me *ContinuousScalePalette) Type() PaletteModel { return PaletteModelContinuous_scale }
+
+// isPalette implements the Palette interface
+func (me *MatrixPalette) isPalette() {}
+
+// Type returns the Palette type constant
+func (me *MatrixPalette) Type() PaletteModel { return PaletteModelMatrix }
+
+// PaletteModel represents the type model type
+type PaletteModel string
+
+// Constants for the different model types
+const (
+	PaletteModelCategorical      PaletteModel = "categorical"
+	PaletteModelDiscrete_scale   PaletteModel = "discrete-scale"
+	PaletteModelContinuous_scale PaletteModel = "continuous-scale"
+	PaletteModelMatrix           PaletteModel = "matrix"
+)
+
+// PaletteVisitor has a method for each concrete Palette type, so an
+// implementation handles all of them
+type PaletteVisitor interface {
+	VisitCategorical(*CategoricalPalette)
+	VisitDiscreteScale(*DiscreteScalePalette)
+	VisitContinuousScale(*ContinuousScalePalette)
+	VisitMatrix(*MatrixPalette)
+}
+
+// AcceptPalette calls the method of visitor for the concrete type of value
+func AcceptPalette(value Palette, visitor PaletteVisitor) error {
+	switch value := value.(type) {
+	case *CategoricalPalette:
+		visitor.VisitCategorical(value)
+	case *DiscreteScalePalette:
+		visitor.VisitDiscreteScale(value)
+	case *ContinuousScalePalette:
+		visitor.VisitContinuousScale(value)
+	case *MatrixPalette:
+		visitor.VisitMatrix(value)
+	default:
+		return fmt.Errorf("unknown Palette type %T", value)
+	}
+	return nil
+}
+
+// MatchPalette calls the function for the concrete type of value and returns
+// its result. Adding a child to Palette adds a parameter, so every call
+// has to handle it
+func MatchPalette[T any](value Palette, onCategorical func(*CategoricalPalette) T, onDiscreteScale func(*DiscreteScalePalette) T, onContinuousScale func(*ContinuousScalePalette) T, onMatrix func(*MatrixPalette) T) (T, error) {
+	switch value := value.(type) {
+	case *CategoricalPalette:
+		return onCategorical(value), nil
+	case *DiscreteScalePalette:
+		return onDiscreteScale(value), nil
+	case *ContinuousScalePalette:
+		return onContinuousScale(value), nil
+	case *MatrixPalette:
+		return onMatrix(value), nil
+	}
+	var zero T
+	return zero, fmt.Errorf("unknown Palette type %T", value)
+}
+
+// parseUnknownColor parses an unknown Color type from an already decoded
+// JSON value, such as one read from YAML
+func parseUnknownColor(b interface{}) (Color, error) {
+	str, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	return parseColorRaw(str)
+}
+
+// parseColorRaw parses an unknown Color type from its JSON, peeking at the
+// fields that tell the children apart without decoding the rest
+func parseColorRaw(data json.RawMessage) (Color, error) {
+	// Use the model field to determine the type
+	var constant ColorModel
+	if value, ok := peekJSONField(data, "model"); ok {
+		if err := json.Unmarshal(value, &constant); err != nil {
+			return nil, err
+		}
+	}
+
+	switch constant {
+	case ColorModelHsl:
+		var hSLValue HSLValue
+		err := json.Unmarshal(data, &hSLValue)
+		return &hSLValue, err
+	case ColorModelHsv:
+		var hSVValue HSVValue
+		err := json.Unmarshal(data, &hSVValue)
+		return &hSVValue, err
+	case ColorModelHsi:
+		var hSIValue HSIValue
+		err := json.Unmarshal(data, &hSIValue)
+		return &hSIValue, err
+	case ColorModelRgb:
+		var rGBValue RGBValue
+		err := json.Unmarshal(data, &rGBValue)
+		return &rGBValue, err
+	case ColorModelRgba:
+		var rGBAValue RGBAValue
+		err := json.Unmarshal(data, &rGBAValue)
+		return &rGBAValue, err
+	case ColorModelLab:
+		var lABValue LABValue
+		err := json.Unmarshal(data, &lABValue)
+		return &lABValue, err
+	case ColorModelLch:
+		var lCHValue LCHValue
+		err := json.Unmarshal(data, &lCHValue)
+		return &lCHValue, err
+	case ColorModelCmyk:
+		var cMYKValue CMYKValue
+		err := json.Unmarshal(data, &cMYKValue)
+		return &cMYKValue, err
+	default:
+		return nil, fmt.Errorf("invalid model: %s", constant)
+	}
+}
+
+// DecodeColorStream reads a JSON array of Color from dec and calls fn with
+// each element as soon as it's parsed, so the array is never held in memory
+func DecodeColorStream(dec *json.Decoder, fn func(Color) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected an array of Color, got %v", tok)
+	}
+
+	for dec.More() {
+		var item json.RawMessage
+		if err := dec.Decode(&item); err != nil {
+			return err
+		}
+		parsed, err := parseColorRaw(item)
+		if err != nil {
+			return err
+		}
+		if err := fn(parsed); err != nil {
+			return err
+		}
+	}
+
+	// Consume the closing bracket
+	_, err = dec.Token()
+	return err
+}
+
+// DecodeColorSlice reads a JSON array of Color from dec one element at a time
+func DecodeColorSlice(dec *json.Decoder) (ColorSlice, error) {
+	var items ColorSlice
+	err := DecodeColorStream(dec, func(item Color) error {
+		items = append(items, item)
+		return nil
+	})
+	return items, err
+}
+
+// MarshalJSON implements json.Marshaler for HSLValue
+func (j HSLValue) MarshalJSON() ([]byte, error) {
+
+	// Add the constant field to the output
+	type Plain HSLValue
+	myMarshal := struct {
+		Model ColorModel `json:"model" yaml:"model" mapstructure:"model"`
+		Plain
+	}{
+		Model: j.Model(),
+		Plain: Plain(j),
+	}
+	return json.Marshal(myMarshal)
+}
+
+// MarshalJSON implements json.Marshaler for HSVValue
+func (j HSVValue) MarshalJSON() ([]byte, error) {
+
+	// Add the constant field to the output
+	type Plain HSVValue
+	myMarshal := struct {
+		Model ColorModel `json:"model" yaml:"model" mapstructure:"model"`
+		Plain
+	}{
+		Model: j.Model(),
+		Plain: Plain(j),
+	}
+	return json.Marshal(myMarshal)
+}
+
+// MarshalJSON implements json.Marshaler for HSIValue
+func (j HSIValue) MarshalJSON() ([]byte, error) {
+
+	// Add the constant field to the output
+	type Plain HSIValue
+	myMarshal := struct {
+		Model ColorModel `json:"model" yaml:"model" mapstructure:"model"`
+		Plain
+	}{
+		Model: j.Model(),
+		Plain: Plain(j),
+	}
+	return json.Marshal(myMarshal)
+}
+
+// MarshalJSON implements json.Marshaler for RGBValue
+func (j RGBValue) MarshalJSON() ([]byte, error) {
+
+	// Add the constant field to the output
+	type Plain RGBValue
+	myMarshal := struct {
+		Model ColorModel `json:"model" yaml:"model" mapstructure:"model"`
+		Plain
+	}{
+		Model: j.Model(),
+		Plain: Plain(j),
+	}
+	return json.Marshal(myMarshal)
+}
+
+// MarshalJSON implements json.Marshaler for RGBAValue
+func (j RGBAValue) MarshalJSON() ([]byte, error) {
+
+	// Add the constant field to the output
+	type Plain RGBAValue
+	myMarshal := struct {
+		Model ColorModel `json:"model" yaml:"model" mapstructure:"model"`
+		Plain
+	}{
+		Model: j.Model(),
+		Plain: Plain(j),
+	}
+	return json.Marshal(myMarshal)
+}
+
+// MarshalJSON implements json.Marshaler for LABValue
+func (j LABValue) MarshalJSON() ([]byte, error) {
+
+	// Add the constant field to the output
+	type Plain LABValue
+	myMarshal := struct {
+		Model ColorModel `json:"model" yaml:"model" mapstructure:"model"`
+		Plain
+	}{
+		Model: j.Model(),
+		Plain: Plain(j),
+	}
+	return json.Marshal(myMarshal)
+}
+
+// MarshalJSON implements json.Marshaler for LCHValue
+func (j LCHValue) MarshalJSON() ([]byte, error) {
+
+	// Add the constant field to the output
+	type Plain LCHValue
+	myMarshal := struct {
+		Model ColorModel `json:"model" yaml:"model" mapstructure:"model"`
+		Plain
+	}{
+		Model: j.Model(),
+		Plain: Plain(j),
+	}
+	return json.Marshal(myMarshal)
+}
+
+// MarshalJSON implements json.Marshaler for CMYKValue
+func (j CMYKValue) MarshalJSON() ([]byte, error) {
+
+	// Add the constant field to the output
+	type Plain CMYKValue
+	myMarshal := struct {
+		Model ColorModel `json:"model" yaml:"model" mapstructure:"model"`
+		Plain
+	}{
+		Model: j.Model(),
+		Plain: Plain(j),
+	}
+	return json.Marshal(myMarshal)
+}
+
+// parseUnknownPalette parses an unknown Palette type from an already decoded
+// JSON value, such as one read from YAML
+func parseUnknownPalette(b interface{}) (Palette, error) {
+	str, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	return parsePaletteRaw(str)
+}
+
+// parsePaletteRaw parses an unknown Palette type from its JSON, peeking at the
+// fields that tell the children apart without decoding the rest
+func parsePaletteRaw(data json.RawMessage) (Palette, error) {
+	// Use the type field to determine the type
+	var constant PaletteModel
+	if value, ok := peekJSONField(data, "type"); ok {
+		if err := json.Unmarshal(value, &constant); err != nil {
+			return nil, err
+		}
+	}
+
+	switch constant {
+	case PaletteModelCategorical:
+		var categoricalPalette CategoricalPalette
+		err := json.Unmarshal(data, &categoricalPalette)
+		return &categoricalPalette, err
+	case PaletteModelDiscrete_scale:
+		var discreteScalePalette DiscreteScalePalette
+		err := json.Unmarshal(data, &discreteScalePalette)
+		return &discreteScalePalette, err
+	case PaletteModelContinuous_scale:
+		var continuousScalePalette ContinuousScalePalette
+		err := json.Unmarshal(data, &continuousScalePalette)
+		return &continuousScalePalette, err
+	case PaletteModelMatrix:
+		var matrixPalette MatrixPalette
+		err := json.Unmarshal(data, &matrixPalette)
+		return &matrixPalette, err
+	default:
+		return nil, fmt.Errorf("invalid type: %s", constant)
+	}
+}
+
+// DecodePaletteStream reads a JSON array of Palette from dec and calls fn with
+// each element as soon as it's parsed, so the array is never held in memory
+func DecodePaletteStream(dec *json.Decoder, fn func(Palette) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected an array of Palette, got %v", tok)
+	}
+
+	for dec.More() {
+		var item json.RawMessage
+		if err := dec.Decode(&item); err != nil {
+			return err
+		}
+		parsed, err := parsePaletteRaw(item)
+		if err != nil {
+			return err
+		}
+		if err := fn(parsed); err != nil {
+			return err
+		}
+	}
+
+	// Consume the closing bracket
+	_, err = dec.Token()
+	return err
+}
+
+// DecodePaletteSlice reads a JSON array of Palette from dec one element at a time
+func DecodePaletteSlice(dec *json.Decoder) (PaletteSlice, error) {
+	var items PaletteSlice
+	err := DecodePaletteStream(dec, func(item Palette) error {
+		items = append(items, item)
+		return nil
+	})
+	return items, err
+}
+
+// MarshalJSON implements json.Marshaler for CategoricalPalette
+func (j CategoricalPalette) MarshalJSON() ([]byte, error) {
+	for i, item := range j.Colors {
+		if item == nil {
+			return nil, fmt.Errorf("field colors in CategoricalPalette: item %d is nil", i)
+		}
+	}
+
+	// Add the constant field to the output
+	type Plain CategoricalPalette
+	myMarshal := struct {
+		Type PaletteModel `json:"type" yaml:"type" mapstructure:"type"`
+		Plain
+	}{
+		Type:  j.Type(),
+		Plain: Plain(j),
+	}
+	return json.Marshal(myMarshal)
+}
+
+// MarshalJSON implements json.Marshaler for DiscreteScalePalette
+func (j DiscreteScalePalette) MarshalJSON() ([]byte, error) {
+	for i, item := range j.Colors {
+		if item == nil {
+			return nil, fmt.Errorf("field colors in DiscreteScalePalette: item %d is nil", i)
+		}
+	}
+
+	// Add the constant field to the output
+	type Plain DiscreteScalePalette
+	myMarshal := struct {
+		Type PaletteModel `json:"type" yaml:"type" mapstructure:"type"`
+		Plain
+	}{
+		Type:  j.Type(),
+		Plain: Plain(j),
+	}
+	return json.Marshal(myMarshal)
+}
+
+// MarshalJSON implements json.Marshaler for ContinuousScalePalette
+func (j ContinuousScalePalette) MarshalJSON() ([]byte, error) {
+
+	// Add the constant field to the output
+	type Plain ContinuousScalePalette
+	myMarshal := struct {
+		Type PaletteModel `json:"type" yaml:"type" mapstructure:"type"`
+		Plain
+	}{
+		Type:  j.Type(),
+		Plain: Plain(j),
+	}
+	return json.Marshal(myMarshal)
+}
+
+// MarshalJSON implements json.Marshaler for MatrixPalette
+func (j MatrixPalette) MarshalJSON() ([]byte, error) {
+	for i, row := range j.Colors {
+		for k, item := range row {
+			if item == nil {
+				return nil, fmt.Errorf("field colors in MatrixPalette: item %d.%d is nil", i, k)
+			}
+		}
+	}
+
+	// Add the constant field to the output
+	type Plain MatrixPalette
+	myMarshal := struct {
+		Type PaletteModel `json:"type" yaml:"type" mapstructure:"type"`
+		Plain
+	}{
+		Type:  j.Type(),
+		Plain: Plain(j),
+	}
+	return json.Marshal(myMarshal)
+}
+
+// peekJSONField returns the raw value of the first top-level field named key in
+// a JSON object, scanning past the fields before it without decoding them
+func peekJSONField(data []byte, key string) (json.RawMessage, bool) {
+	var found json.RawMessage
+	scanJSONObject(data, func(k string, value []byte) bool {
+		if k != key {
+			return true
+		}
+		found = value
+		return false
+	})
+	return found, found != nil
+}
+
+// scanJSONObject calls fn with each top-level key and raw value of a JSON
+// object until fn returns false. It stops quietly at malformed JSON, which the
+// decoding that follows reports
+func scanJSONObject(data []byte, fn func(key string, value []byte) bool) {
+	i := skipJSONSpace(data, 0)
+	if i >= len(data) || data[i] != '{' {
+		return
+	}
+	i++
+
+	for {
+		i = skipJSONSpace(data, i)
+		if i >= len(data) || data[i] != '"' {
+			return
+		}
+		end := skipJSONValue(data, i)
+		if end < 0 {
+			return
+		}
+		key := string(data[i+1 : end-1])
+		if strings.IndexByte(key, '\\') >= 0 {
+			if err := json.Unmarshal(data[i:end], &key); err != nil {
+				return
+			}
+		}
+
+		i = skipJSONSpace(data, end)
+		if i >= len(data) || data[i] != ':' {
+			return
+		}
+		i = skipJSONSpace(data, i+1)
+		end = skipJSONValue(data, i)
+		if end < 0 || !fn(key, data[i:end]) {
+			return
+		}
+
+		i = skipJSONSpace(data, end)
+		if i >= len(data) || data[i] != ',' {
+			return
+		}
+		i++
+	}
+}
+
+// skipJSONSpace returns the index of the first non-space byte at or after i
+func skipJSONSpace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+// skipJSONValue returns the index just past the JSON value starting at i, or
+// -1 if it's malformed
+func skipJSONValue(data []byte, i int) int {
+	if i >= len(data) {
+		return -1
+	}
+
+	switch data[i] {
+	case '"':
+		for j := i + 1; j < len(data); j++ {
+			switch data[j] {
+			case '\\':
+				j++
+			case '"':
+				return j + 1
+			}
+		}
+		return -1
+	case '{', '[':
+		depth := 0
+		for j := i; j < len(data); j++ {
+			switch data[j] {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return j + 1
+				}
+			case '"':
+				end := skipJSONValue(data, j)
+				if end < 0 {
+					return -1
+				}
+				j = end - 1
+			}
+		}
+		return -1
+	}
+
+	// Numbers, true, false and null run until the next delimiter
+	j := i
+	for j < len(data) && !strings.ContainsRune(",:]} \t\n\r", rune(data[j])) {
+		j++
+	}
+	if j == i {
+		return -1
+	}
+	return j
+}
+
+// isJSONNull reports whether a raw field is missing or null
+func isJSONNull(value json.RawMessage) bool {
+	return len(value) == 0 || string(bytes.TrimSpace(value)) == "null"
+}
+
+// UnmarshalJSON implements json.Unmarshaler for AssetPack
+func (j *AssetPack) UnmarshalJSON(b []byte) error {
+	// The fields stay raw JSON, so the parents are decoded straight from it
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["brandName"]; raw != nil && !ok {
+		return fmt.Errorf("field brandName in AssetPack: required")
+	}
+	if _, ok := raw["palettes"]; raw != nil && !ok {
+		return fmt.Errorf("field palettes in AssetPack: required")
+	}
+	for key := range raw {
+		switch key {
+		case "brandName", "palettes":
+		default:
+			return fmt.Errorf("field %s in AssetPack: not allowed", key)
+		}
+	}
+
+	// The parent fields are shadowed here and parsed from raw below
+	type Plain AssetPack
+	var decoded struct {
+		Plain
+		Palettes json.RawMessage `json:"palettes"`
+	}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return err
+	}
+	plain := decoded.Plain
+
+	// Parse array of Palette types
+	if !isJSONNull(raw["palettes"]) {
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw["palettes"], &items); err != nil {
+			return fmt.Errorf("field palettes in AssetPack: %w", err)
+		}
+		palettes := make(PaletteSlice, 0, len(items))
+		for _, item := range items {
+			parsed, err := parsePaletteRaw(item)
+			if err != nil {
+				return err
+			}
+			palettes = append(palettes, parsed)
+		}
+		plain.Palettes = palettes
+	}
+
+	*j = AssetPack(plain)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler for AssetPack
+func (j AssetPack) MarshalJSON() ([]byte, error) {
+	for i, item := range j.Palettes {
+		if item == nil {
+			return nil, fmt.Errorf("field palettes in AssetPack: item %d is nil", i)
+		}
+	}
+
+	type Plain AssetPack
+	return json.Marshal(Plain(j))
+}
+
+// UnmarshalJSON implements json.Unmarshaler for CMYKValue
+func (j *CMYKValue) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["c"]; raw != nil && !ok {
+		return fmt.Errorf("field c in CMYKValue: required")
+	}
+	if _, ok := raw["m"]; raw != nil && !ok {
+		return fmt.Errorf("field m in CMYKValue: required")
+	}
+	if _, ok := raw["y"]; raw != nil && !ok {
+		return fmt.Errorf("field y in CMYKValue: required")
+	}
+	if _, ok := raw["k"]; raw != nil && !ok {
+		return fmt.Errorf("field k in CMYKValue: required")
+	}
+	for key := range raw {
+		switch key {
+		case "c", "k", "m", "model", "y":
+		default:
+			return fmt.Errorf("field %s in CMYKValue: not allowed", key)
+		}
+	}
+	// The constant has no field to decode into, so it's checked in the JSON
+	if value, ok := raw["model"]; ok {
+		var constant interface{}
+		if err := json.Unmarshal(value, &constant); err != nil || constant != "cmyk" {
+			return fmt.Errorf("field model in CMYKValue: must be %#v", "cmyk")
+		}
+	}
+
+	type Plain CMYKValue
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+
+	if plain.C < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "c", 0)
+	}
+	if plain.C > 1 {
+		return fmt.Errorf("field %s: must be <= %v", "c", 1)
+	}
+	if plain.K < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "k", 0)
+	}
+	if plain.K > 1 {
+		return fmt.Errorf("field %s: must be <= %v", "k", 1)
+	}
+	if plain.M < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "m", 0)
+	}
+	if plain.M > 1 {
+		return fmt.Errorf("field %s: must be <= %v", "m", 1)
+	}
+	if plain.Y < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "y", 0)
+	}
+	if plain.Y > 1 {
+		return fmt.Errorf("field %s: must be <= %v", "y", 1)
+	}
+
+	*j = CMYKValue(plain)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler for CategoricalPalette
+func (j *CategoricalPalette) UnmarshalJSON(b []byte) error {
+	// The fields stay raw JSON, so the parents are decoded straight from it
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["colors"]; raw != nil && !ok {
+		return fmt.Errorf("field colors in CategoricalPalette: required")
+	}
+	if _, ok := raw["name"]; raw != nil && !ok {
+		return fmt.Errorf("field name in CategoricalPalette: required")
+	}
+	if _, ok := raw["semantic"]; raw != nil && !ok {
+		return fmt.Errorf("field semantic in CategoricalPalette: required")
+	}
+	if _, ok := raw["type"]; raw != nil && !ok {
+		return fmt.Errorf("field type in CategoricalPalette: required")
+	}
+	for key := range raw {
+		switch key {
+		case "colorScheme", "colors", "description", "id", "name", "semantic", "type", "usage":
+		default:
+			return fmt.Errorf("field %s in CategoricalPalette: not allowed", key)
+		}
+	}
+	// The constant has no field to decode into, so it's checked in the JSON
+	if value, ok := raw["type"]; ok {
+		var constant interface{}
+		if err := json.Unmarshal(value, &constant); err != nil || constant != "categorical" {
+			return fmt.Errorf("field type in CategoricalPalette: must be %#v", "categorical")
+		}
+	}
+
+	// The parent fields are shadowed here and parsed from raw below
+	type Plain CategoricalPalette
+	var decoded struct {
+		Plain
+		Colors json.RawMessage `json:"colors"`
+	}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return err
+	}
+	plain := decoded.Plain
+
+	// Parse array of Color types
+	if !isJSONNull(raw["colors"]) {
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw["colors"], &items); err != nil {
+			return fmt.Errorf("field colors in CategoricalPalette: %w", err)
+		}
+		colors := make(ColorSlice, 0, len(items))
+		for _, item := range items {
+			parsed, err := parseColorRaw(item)
+			if err != nil {
+				return err
+			}
+			colors = append(colors, parsed)
+		}
+		plain.Colors = colors
+	}
+
+	*j = CategoricalPalette(plain)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler for ColorConfig
+func (j *ColorConfig) UnmarshalJSON(b []byte) error {
+	// The fields stay raw JSON, so the parents are decoded straight from it
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["value"]; raw != nil && !ok {
+		return fmt.Errorf("field value in ColorConfig: required")
+	}
+	for key := range raw {
+		switch key {
+		case "id", "name", "undertone", "usage", "value":
+		default:
+			return fmt.Errorf("field %s in ColorConfig: not allowed", key)
+		}
+	}
+
+	// The parent fields are shadowed here and parsed from raw below
+	type Plain ColorConfig
+	var decoded struct {
+		Plain
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return err
+	}
+	plain := decoded.Plain
+
+	// Parse Color type
+	if !isJSONNull(raw["value"]) {
+		parsed, err := parseColorRaw(raw["value"])
+		if err != nil {
+			return err
+		}
+		plain.Value = parsed
+	} else if raw != nil {
+		return fmt.Errorf("field value in ColorConfig: must not be null")
+	}
+
+	*j = ColorConfig(plain)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler for ColorConfig
+func (j ColorConfig) MarshalJSON() ([]byte, error) {
+	if j.Value == nil {
+		return nil, fmt.Errorf("field value in ColorConfig: required")
+	}
+
+	type Plain ColorConfig
+	return json.Marshal(Plain(j))
+}
+
+// UnmarshalJSON implements json.Unmarshaler for ContinuousColor
+func (j *ContinuousColor) UnmarshalJSON(b []byte) error {
+	// The fields stay raw JSON, so the parents are decoded straight from it
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["value"]; raw != nil && !ok {
+		return fmt.Errorf("field value in ContinuousColor: required")
+	}
+	for key := range raw {
+		switch key {
+		case "id", "location", "name", "undertone", "usage", "value":
+		default:
+			return fmt.Errorf("field %s in ContinuousColor: not allowed", key)
+		}
+	}
+
+	// The parent fields are shadowed here and parsed from raw below
+	type Plain ContinuousColor
+	var decoded struct {
+		Plain
+		Value json.RawMessage `json:"value"`
+	}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return err
+	}
+	plain := decoded.Plain
+
+	// Parse Color type
+	if !isJSONNull(raw["value"]) {
+		parsed, err := parseColorRaw(raw["value"])
+		if err != nil {
+			return err
+		}
+		plain.Value = parsed
+	} else if raw != nil {
+		return fmt.Errorf("field value in ContinuousColor: must not be null")
+	}
+
+	if plain.Location != nil && *plain.Location < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "location", 0)
+	}
+	if plain.Location != nil && *plain.Location > 1 {
+		return fmt.Errorf("field %s: must be <= %v", "location", 1)
+	}
+
+	*j = ContinuousColor(plain)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler for ContinuousColor
+func (j ContinuousColor) MarshalJSON() ([]byte, error) {
+	if j.Value == nil {
+		return nil, fmt.Errorf("field value in ContinuousColor: required")
+	}
+
+	type Plain ContinuousColor
+	return json.Marshal(Plain(j))
+}
+
+// UnmarshalJSON implements json.Unmarshaler for ContinuousScalePalette
+func (j *ContinuousScalePalette) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["colors"]; raw != nil && !ok {
+		return fmt.Errorf("field colors in ContinuousScalePalette: required")
+	}
+	if _, ok := raw["name"]; raw != nil && !ok {
+		return fmt.Errorf("field name in ContinuousScalePalette: required")
+	}
+	if _, ok := raw["semantic"]; raw != nil && !ok {
+		return fmt.Errorf("field semantic in ContinuousScalePalette: required")
+	}
+	if _, ok := raw["type"]; raw != nil && !ok {
+		return fmt.Errorf("field type in ContinuousScalePalette: required")
+	}
+	for key := range raw {
+		switch key {
+		case "colorScheme", "colors", "description", "id", "name", "semantic", "type", "usage":
+		default:
+			return fmt.Errorf("field %s in ContinuousScalePalette: not allowed", key)
+		}
+	}
+	// The constant has no field to decode into, so it's checked in the JSON
+	if value, ok := raw["type"]; ok {
+		var constant interface{}
+		if err := json.Unmarshal(value, &constant); err != nil || constant != "continuous-scale" {
+			return fmt.Errorf("field type in ContinuousScalePalette: must be %#v", "continuous-scale")
+		}
+	}
+
+	type Plain ContinuousScalePalette
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+
+	*j = ContinuousScalePalette(plain)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler for DiscreteScalePalette
+func (j *DiscreteScalePalette) UnmarshalJSON(b []byte) error {
+	// The fields stay raw JSON, so the parents are decoded straight from it
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["colors"]; raw != nil && !ok {
+		return fmt.Errorf("field colors in DiscreteScalePalette: required")
+	}
+	if _, ok := raw["name"]; raw != nil && !ok {
+		return fmt.Errorf("field name in DiscreteScalePalette: required")
+	}
+	if _, ok := raw["semantic"]; raw != nil && !ok {
+		return fmt.Errorf("field semantic in DiscreteScalePalette: required")
+	}
+	if _, ok := raw["type"]; raw != nil && !ok {
+		return fmt.Errorf("field type in DiscreteScalePalette: required")
+	}
+	for key := range raw {
+		switch key {
+		case "colorScheme", "colors", "description", "id", "name", "semantic", "type", "usage":
+		default:
+			return fmt.Errorf("field %s in DiscreteScalePalette: not allowed", key)
+		}
+	}
+	// The constant has no field to decode into, so it's checked in the JSON
+	if value, ok := raw["type"]; ok {
+		var constant interface{}
+		if err := json.Unmarshal(value, &constant); err != nil || constant != "discrete-scale" {
+			return fmt.Errorf("field type in DiscreteScalePalette: must be %#v", "discrete-scale")
+		}
+	}
+
+	// The parent fields are shadowed here and parsed from raw below
+	type Plain DiscreteScalePalette
+	var decoded struct {
+		Plain
+		Colors json.RawMessage `json:"colors"`
+	}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return err
+	}
+	plain := decoded.Plain
+
+	// Parse array of Color types
+	if !isJSONNull(raw["colors"]) {
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw["colors"], &items); err != nil {
+			return fmt.Errorf("field colors in DiscreteScalePalette: %w", err)
+		}
+		colors := make(ColorSlice, 0, len(items))
+		for _, item := range items {
+			parsed, err := parseColorRaw(item)
+			if err != nil {
+				return err
+			}
+			colors = append(colors, parsed)
+		}
+		plain.Colors = colors
+	}
+
+	*j = DiscreteScalePalette(plain)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler for HSIValue
+func (j *HSIValue) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["h"]; raw != nil && !ok {
+		return fmt.Errorf("field h in HSIValue: required")
+	}
+	if _, ok := raw["s"]; raw != nil && !ok {
+		return fmt.Errorf("field s in HSIValue: required")
+	}
+	if _, ok := raw["i"]; raw != nil && !ok {
+		return fmt.Errorf("field i in HSIValue: required")
+	}
+	for key := range raw {
+		switch key {
+		case "h", "i", "model", "s":
+		default:
+			return fmt.Errorf("field %s in HSIValue: not allowed", key)
+		}
+	}
+	// The constant has no field to decode into, so it's checked in the JSON
+	if value, ok := raw["model"]; ok {
+		var constant interface{}
+		if err := json.Unmarshal(value, &constant); err != nil || constant != "hsi" {
+			return fmt.Errorf("field model in HSIValue: must be %#v", "hsi")
+		}
+	}
+
+	type Plain HSIValue
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+
+	if plain.H < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "h", 0)
+	}
+	if plain.H > 360 {
+		return fmt.Errorf("field %s: must be <= %v", "h", 360)
+	}
+	if plain.I < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "i", 0)
+	}
+	if plain.I > 1 {
+		return fmt.Errorf("field %s: must be <= %v", "i", 1)
+	}
+	if plain.S < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "s", 0)
+	}
+	if plain.S > 1 {
+		return fmt.Errorf("field %s: must be <= %v", "s", 1)
+	}
+
+	*j = HSIValue(plain)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler for HSLValue
+func (j *HSLValue) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["h"]; raw != nil && !ok {
+		return fmt.Errorf("field h in HSLValue: required")
+	}
+	if _, ok := raw["s"]; raw != nil && !ok {
+		return fmt.Errorf("field s in HSLValue: required")
+	}
+	if _, ok := raw["l"]; raw != nil && !ok {
+		return fmt.Errorf("field l in HSLValue: required")
+	}
+	for key := range raw {
+		switch key {
+		case "h", "l", "model", "s":
+		default:
+			return fmt.Errorf("field %s in HSLValue: not allowed", key)
+		}
+	}
+	// The constant has no field to decode into, so it's checked in the JSON
+	if value, ok := raw["model"]; ok {
+		var constant interface{}
+		if err := json.Unmarshal(value, &constant); err != nil || constant != "hsl" {
+			return fmt.Errorf("field model in HSLValue: must be %#v", "hsl")
+		}
+	}
+
+	type Plain HSLValue
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+
+	if plain.H < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "h", 0)
+	}
+	if plain.H > 360 {
+		return fmt.Errorf("field %s: must be <= %v", "h", 360)
+	}
+	if plain.L < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "l", 0)
+	}
+	if plain.L > 1 {
+		return fmt.Errorf("field %s: must be <= %v", "l", 1)
+	}
+	if plain.S < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "s", 0)
+	}
+	if plain.S > 1 {
+		return fmt.Errorf("field %s: must be <= %v", "s", 1)
+	}
+
+	*j = HSLValue(plain)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler for HSVValue
+func (j *HSVValue) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["h"]; raw != nil && !ok {
+		return fmt.Errorf("field h in HSVValue: required")
+	}
+	if _, ok := raw["s"]; raw != nil && !ok {
+		return fmt.Errorf("field s in HSVValue: required")
+	}
+	if _, ok := raw["v"]; raw != nil && !ok {
+		return fmt.Errorf("field v in HSVValue: required")
+	}
+	for key := range raw {
+		switch key {
+		case "h", "model", "s", "v":
+		default:
+			return fmt.Errorf("field %s in HSVValue: not allowed", key)
+		}
+	}
+	// The constant has no field to decode into, so it's checked in the JSON
+	if value, ok := raw["model"]; ok {
+		var constant interface{}
+		if err := json.Unmarshal(value, &constant); err != nil || constant != "hsv" {
+			return fmt.Errorf("field model in HSVValue: must be %#v", "hsv")
+		}
+	}
+
+	type Plain HSVValue
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+
+	if plain.H < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "h", 0)
+	}
+	if plain.H > 360 {
+		return fmt.Errorf("field %s: must be <= %v", "h", 360)
+	}
+	if plain.S < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "s", 0)
+	}
+	if plain.S > 1 {
+		return fmt.Errorf("field %s: must be <= %v", "s", 1)
+	}
+	if plain.V < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "v", 0)
+	}
+	if plain.V > 1 {
+		return fmt.Errorf("field %s: must be <= %v", "v", 1)
+	}
+
+	*j = HSVValue(plain)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler for LABValue
+func (j *LABValue) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["l"]; raw != nil && !ok {
+		return fmt.Errorf("field l in LABValue: required")
+	}
+	if _, ok := raw["a"]; raw != nil && !ok {
+		return fmt.Errorf("field a in LABValue: required")
+	}
+	if _, ok := raw["b"]; raw != nil && !ok {
+		return fmt.Errorf("field b in LABValue: required")
+	}
+	for key := range raw {
+		switch key {
+		case "a", "b", "l", "model":
+		default:
+			return fmt.Errorf("field %s in LABValue: not allowed", key)
+		}
+	}
+	// The constant has no field to decode into, so it's checked in the JSON
+	if value, ok := raw["model"]; ok {
+		var constant interface{}
+		if err := json.Unmarshal(value, &constant); err != nil || constant != "lab" {
+			return fmt.Errorf("field model in LABValue: must be %#v", "lab")
+		}
+	}
+
+	type Plain LABValue
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+
+	if plain.A < -86.185 {
+		return fmt.Errorf("field %s: must be >= %v", "a", -86.185)
+	}
+	if plain.A > 98.254 {
+		return fmt.Errorf("field %s: must be <= %v", "a", 98.254)
+	}
+	if plain.B < -107.863 {
+		return fmt.Errorf("field %s: must be >= %v", "b", -107.863)
+	}
+	if plain.B > 94.482 {
+		return fmt.Errorf("field %s: must be <= %v", "b", 94.482)
+	}
+	if plain.L < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "l", 0)
+	}
+	if plain.L > 100 {
+		return fmt.Errorf("field %s: must be <= %v", "l", 100)
+	}
+
+	*j = LABValue(plain)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler for LCHValue
+func (j *LCHValue) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["l"]; raw != nil && !ok {
+		return fmt.Errorf("field l in LCHValue: required")
+	}
+	if _, ok := raw["c"]; raw != nil && !ok {
+		return fmt.Errorf("field c in LCHValue: required")
+	}
+	if _, ok := raw["h"]; raw != nil && !ok {
+		return fmt.Errorf("field h in LCHValue: required")
+	}
+	for key := range raw {
+		switch key {
+		case "c", "h", "l", "model":
+		default:
+			return fmt.Errorf("field %s in LCHValue: not allowed", key)
+		}
+	}
+	// The constant has no field to decode into, so it's checked in the JSON
+	if value, ok := raw["model"]; ok {
+		var constant interface{}
+		if err := json.Unmarshal(value, &constant); err != nil || constant != "lch" {
+			return fmt.Errorf("field model in LCHValue: must be %#v", "lch")
+		}
+	}
+
+	type Plain LCHValue
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+
+	if plain.C < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "c", 0)
+	}
+	if plain.C > 100 {
+		return fmt.Errorf("field %s: must be <= %v", "c", 100)
+	}
+	if plain.H < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "h", 0)
+	}
+	if plain.H > 360 {
+		return fmt.Errorf("field %s: must be <= %v", "h", 360)
+	}
+	if plain.L < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "l", 0)
+	}
+	if plain.L > 100 {
+		return fmt.Errorf("field %s: must be <= %v", "l", 100)
+	}
+
+	*j = LCHValue(plain)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler for MatrixPalette
+func (j *MatrixPalette) UnmarshalJSON(b []byte) error {
+	// The fields stay raw JSON, so the parents are decoded straight from it
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["colors"]; raw != nil && !ok {
+		return fmt.Errorf("field colors in MatrixPalette: required")
+	}
+	if _, ok := raw["name"]; raw != nil && !ok {
+		return fmt.Errorf("field name in MatrixPalette: required")
+	}
+	if _, ok := raw["origin"]; raw != nil && !ok {
+		return fmt.Errorf("field origin in MatrixPalette: required")
+	}
+	if _, ok := raw["semantic"]; raw != nil && !ok {
+		return fmt.Errorf("field semantic in MatrixPalette: required")
+	}
+	if _, ok := raw["type"]; raw != nil && !ok {
+		return fmt.Errorf("field type in MatrixPalette: required")
+	}
+	for key := range raw {
+		switch key {
+		case "colorScheme", "colors", "description", "id", "name", "origin", "semantic", "type", "usage":
+		default:
+			return fmt.Errorf("field %s in MatrixPalette: not allowed", key)
+		}
+	}
+	// The constant has no field to decode into, so it's checked in the JSON
+	if value, ok := raw["type"]; ok {
+		var constant interface{}
+		if err := json.Unmarshal(value, &constant); err != nil || constant != "matrix" {
+			return fmt.Errorf("field type in MatrixPalette: must be %#v", "matrix")
+		}
+	}
+
+	// The parent fields are shadowed here and parsed from raw below
+	type Plain MatrixPalette
+	var decoded struct {
+		Plain
+		Colors json.RawMessage `json:"colors"`
+	}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return err
+	}
+	plain := decoded.Plain
+
+	// Parse arrays of arrays of Color types
+	if !isJSONNull(raw["colors"]) {
+		var rows [][]json.RawMessage
+		if err := json.Unmarshal(raw["colors"], &rows); err != nil {
+			return fmt.Errorf("field colors in MatrixPalette: %w", err)
+		}
+		colors := make([]ColorSlice, 0, len(rows))
+		for _, row := range rows {
+			parsedRow := make(ColorSlice, 0, len(row))
+			for _, item := range row {
+				parsed, err := parseColorRaw(item)
+				if err != nil {
+					return err
+				}
+				parsedRow = append(parsedRow, parsed)
+			}
+			colors = append(colors, parsedRow)
+		}
+		plain.Colors = colors
+	}
+
+	*j = MatrixPalette(plain)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler for Origin
+func (j *Origin) UnmarshalJSON(b []byte) error {
+	// The fields stay raw JSON, so the parents are decoded straight from it
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["x"]; raw != nil && !ok {
+		return fmt.Errorf("field x in Origin: required")
+	}
+	if _, ok := raw["y"]; raw != nil && !ok {
+		return fmt.Errorf("field y in Origin: required")
+	}
+	for key := range raw {
+		switch key {
+		case "x", "y":
+		default:
+			return fmt.Errorf("field %s in Origin: not allowed", key)
+		}
+	}
+
+	// The parent fields are shadowed here and parsed from raw below
+	type Plain Origin
+	var decoded struct {
+		Plain
+		X json.RawMessage `json:"x"`
+		Y json.RawMessage `json:"y"`
+	}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return err
+	}
+	plain := decoded.Plain
+
+	// Parse array of Color types
+	if !isJSONNull(raw["x"]) {
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw["x"], &items); err != nil {
+			return fmt.Errorf("field x in Origin: %w", err)
+		}
+		x := make(ColorSlice, 0, len(items))
+		for _, item := range items {
+			parsed, err := parseColorRaw(item)
+			if err != nil {
+				return err
+			}
+			x = append(x, parsed)
+		}
+		plain.X = x
+	}
+
+	// Parse array of Color types
+	if !isJSONNull(raw["y"]) {
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw["y"], &items); err != nil {
+			return fmt.Errorf("field y in Origin: %w", err)
+		}
+		y := make(ColorSlice, 0, len(items))
+		for _, item := range items {
+			parsed, err := parseColorRaw(item)
+			if err != nil {
+				return err
+			}
+			y = append(y, parsed)
+		}
+		plain.Y = y
+	}
+
+	*j = Origin(plain)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler for Origin
+func (j Origin) MarshalJSON() ([]byte, error) {
+	for i, item := range j.X {
+		if item == nil {
+			return nil, fmt.Errorf("field x in Origin: item %d is nil", i)
+		}
+	}
+	for i, item := range j.Y {
+		if item == nil {
+			return nil, fmt.Errorf("field y in Origin: item %d is nil", i)
+		}
+	}
+
+	type Plain Origin
+	return json.Marshal(Plain(j))
+}
+
+// UnmarshalJSON implements json.Unmarshaler for RGBAValue
+func (j *RGBAValue) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["r"]; raw != nil && !ok {
+		return fmt.Errorf("field r in RGBAValue: required")
+	}
+	if _, ok := raw["g"]; raw != nil && !ok {
+		return fmt.Errorf("field g in RGBAValue: required")
+	}
+	if _, ok := raw["b"]; raw != nil && !ok {
+		return fmt.Errorf("field b in RGBAValue: required")
+	}
+	if _, ok := raw["a"]; raw != nil && !ok {
+		return fmt.Errorf("field a in RGBAValue: required")
+	}
+	for key := range raw {
+		switch key {
+		case "a", "b", "g", "model", "r":
+		default:
+			return fmt.Errorf("field %s in RGBAValue: not allowed", key)
+		}
+	}
+	// The constant has no field to decode into, so it's checked in the JSON
+	if value, ok := raw["model"]; ok {
+		var constant interface{}
+		if err := json.Unmarshal(value, &constant); err != nil || constant != "rgba" {
+			return fmt.Errorf("field model in RGBAValue: must be %#v", "rgba")
+		}
+	}
+
+	type Plain RGBAValue
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+
+	if plain.A < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "a", 0)
+	}
+	if plain.A > 1 {
+		return fmt.Errorf("field %s: must be <= %v", "a", 1)
+	}
+	if plain.B < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "b", 0)
+	}
+	if plain.B > 255 {
+		return fmt.Errorf("field %s: must be <= %v", "b", 255)
+	}
+	if plain.G < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "g", 0)
+	}
+	if plain.G > 255 {
+		return fmt.Errorf("field %s: must be <= %v", "g", 255)
+	}
+	if plain.R < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "r", 0)
+	}
+	if plain.R > 255 {
+		return fmt.Errorf("field %s: must be <= %v", "r", 255)
+	}
+
+	*j = RGBAValue(plain)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler for RGBValue
+func (j *RGBValue) UnmarshalJSON(b []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["r"]; raw != nil && !ok {
+		return fmt.Errorf("field r in RGBValue: required")
+	}
+	if _, ok := raw["g"]; raw != nil && !ok {
+		return fmt.Errorf("field g in RGBValue: required")
+	}
+	if _, ok := raw["b"]; raw != nil && !ok {
+		return fmt.Errorf("field b in RGBValue: required")
+	}
+	for key := range raw {
+		switch key {
+		case "b", "g", "model", "r":
+		default:
+			return fmt.Errorf("field %s in RGBValue: not allowed", key)
+		}
+	}
+	// The constant has no field to decode into, so it's checked in the JSON
+	if value, ok := raw["model"]; ok {
+		var constant interface{}
+		if err := json.Unmarshal(value, &constant); err != nil || constant != "rgb" {
+			return fmt.Errorf("field model in RGBValue: must be %#v", "rgb")
+		}
+	}
+
+	type Plain RGBValue
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+
+	if plain.B < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "b", 0)
+	}
+	if plain.B > 255 {
+		return fmt.Errorf("field %s: must be <= %v", "b", 255)
+	}
+	if plain.G < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "g", 0)
+	}
+	if plain.G > 255 {
+		return fmt.Errorf("field %s: must be <= %v", "g", 255)
+	}
+	if plain.R < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "r", 0)
+	}
+	if plain.R > 255 {
+		return fmt.Errorf("field %s: must be <= %v", "r", 255)
+	}
+
+	*j = RGBValue(plain)
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for ColorSlice
+func (s *ColorSlice) UnmarshalYAML(node *yaml.Node) error {
+	value, err := yamlValue(node)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		*s = nil
+		return nil
+	}
+
+	items, ok := value.([]interface{})
+	if !ok {
+		return fmt.Errorf("line %d: expected a sequence of Color", node.Line)
+	}
+	parsed := make(ColorSlice, 0, len(items))
+	for _, item := range items {
+		p, err := parseUnknownColor(item)
+		if err != nil {
+			return err
+		}
+		parsed = append(parsed, p)
+	}
+	*s = parsed
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for ColorMap
+func (m *ColorMap) UnmarshalYAML(node *yaml.Node) error {
+	value, err := yamlValue(node)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		*m = nil
+		return nil
+	}
+
+	items, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("line %d: expected a mapping of Color", node.Line)
+	}
+	parsed := make(ColorMap, len(items))
+	for key, item := range items {
+		p, err := parseUnknownColor(item)
+		if err != nil {
+			return err
+		}
+		parsed[key] = p
+	}
+	*m = parsed
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for PaletteSlice
+func (s *PaletteSlice) UnmarshalYAML(node *yaml.Node) error {
+	value, err := yamlValue(node)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		*s = nil
+		return nil
+	}
+
+	items, ok := value.([]interface{})
+	if !ok {
+		return fmt.Errorf("line %d: expected a sequence of Palette", node.Line)
+	}
+	parsed := make(PaletteSlice, 0, len(items))
+	for _, item := range items {
+		p, err := parseUnknownPalette(item)
+		if err != nil {
+			return err
+		}
+		parsed = append(parsed, p)
+	}
+	*s = parsed
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for PaletteMap
+func (m *PaletteMap) UnmarshalYAML(node *yaml.Node) error {
+	value, err := yamlValue(node)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		*m = nil
+		return nil
+	}
+
+	items, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("line %d: expected a mapping of Palette", node.Line)
+	}
+	parsed := make(PaletteMap, len(items))
+	for key, item := range items {
+		p, err := parseUnknownPalette(item)
+		if err != nil {
+			return err
+		}
+		parsed[key] = p
+	}
+	*m = parsed
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for AssetPack using its JSON decoding
+func (j *AssetPack) UnmarshalYAML(node *yaml.Node) error {
+	b, err := yamlToJSON(node)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, j)
+}
+
+// MarshalYAML implements yaml.Marshaler for AssetPack using its JSON encoding
+func (j AssetPack) MarshalYAML() (interface{}, error) {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(b)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for CMYKValue using its JSON decoding
+func (j *CMYKValue) UnmarshalYAML(node *yaml.Node) error {
+	b, err := yamlToJSON(node)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, j)
+}
+
+// MarshalYAML implements yaml.Marshaler for CMYKValue using its JSON encoding
+func (j CMYKValue) MarshalYAML() (interface{}, error) {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(b)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for CategoricalPalette using its JSON decoding
+func (j *CategoricalPalette) UnmarshalYAML(node *yaml.Node) error {
+	b, err := yamlToJSON(node)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, j)
+}
+
+// MarshalYAML implements yaml.Marshaler for CategoricalPalette using its JSON encoding
+func (j CategoricalPalette) MarshalYAML() (interface{}, error) {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(b)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for ColorConfig using its JSON decoding
+func (j *ColorConfig) UnmarshalYAML(node *yaml.Node) error {
+	b, err := yamlToJSON(node)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, j)
+}
+
+// MarshalYAML implements yaml.Marshaler for ColorConfig using its JSON encoding
+func (j ColorConfig) MarshalYAML() (interface{}, error) {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(b)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for ContinuousColor using its JSON decoding
+func (j *ContinuousColor) UnmarshalYAML(node *yaml.Node) error {
+	b, err := yamlToJSON(node)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, j)
+}
+
+// MarshalYAML implements yaml.Marshaler for ContinuousColor using its JSON encoding
+func (j ContinuousColor) MarshalYAML() (interface{}, error) {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(b)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for ContinuousScalePalette using its JSON decoding
+func (j *ContinuousScalePalette) UnmarshalYAML(node *yaml.Node) error {
+	b, err := yamlToJSON(node)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, j)
+}
+
+// MarshalYAML implements yaml.Marshaler for ContinuousScalePalette using its JSON encoding
+func (j ContinuousScalePalette) MarshalYAML() (interface{}, error) {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(b)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for DiscreteScalePalette using its JSON decoding
+func (j *DiscreteScalePalette) UnmarshalYAML(node *yaml.Node) error {
+	b, err := yamlToJSON(node)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, j)
+}
+
+// MarshalYAML implements yaml.Marshaler for DiscreteScalePalette using its JSON encoding
+func (j DiscreteScalePalette) MarshalYAML() (interface{}, error) {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(b)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for HSIValue using its JSON decoding
+func (j *HSIValue) UnmarshalYAML(node *yaml.Node) error {
+	b, err := yamlToJSON(node)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, j)
+}
+
+// MarshalYAML implements yaml.Marshaler for HSIValue using its JSON encoding
+func (j HSIValue) MarshalYAML() (interface{}, error) {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(b)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for HSLValue using its JSON decoding
+func (j *HSLValue) UnmarshalYAML(node *yaml.Node) error {
+	b, err := yamlToJSON(node)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, j)
+}
+
+// MarshalYAML implements yaml.Marshaler for HSLValue using its JSON encoding
+func (j HSLValue) MarshalYAML() (interface{}, error) {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(b)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for HSVValue using its JSON decoding
+func (j *HSVValue) UnmarshalYAML(node *yaml.Node) error {
+	b, err := yamlToJSON(node)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, j)
+}
+
+// MarshalYAML implements yaml.Marshaler for HSVValue using its JSON encoding
+func (j HSVValue) MarshalYAML() (interface{}, error) {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(b)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for LABValue using its JSON decoding
+func (j *LABValue) UnmarshalYAML(node *yaml.Node) error {
+	b, err := yamlToJSON(node)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, j)
+}
+
+// MarshalYAML implements yaml.Marshaler for LABValue using its JSON encoding
+func (j LABValue) MarshalYAML() (interface{}, error) {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(b)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for LCHValue using its JSON decoding
+func (j *LCHValue) UnmarshalYAML(node *yaml.Node) error {
+	b, err := yamlToJSON(node)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, j)
+}
+
+// MarshalYAML implements yaml.Marshaler for LCHValue using its JSON encoding
+func (j LCHValue) MarshalYAML() (interface{}, error) {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(b)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for MatrixPalette using its JSON decoding
+func (j *MatrixPalette) UnmarshalYAML(node *yaml.Node) error {
+	b, err := yamlToJSON(node)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, j)
+}
+
+// MarshalYAML implements yaml.Marshaler for MatrixPalette using its JSON encoding
+func (j MatrixPalette) MarshalYAML() (interface{}, error) {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(b)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for Origin using its JSON decoding
+func (j *Origin) UnmarshalYAML(node *yaml.Node) error {
+	b, err := yamlToJSON(node)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, j)
+}
+
+// MarshalYAML implements yaml.Marshaler for Origin using its JSON encoding
+func (j Origin) MarshalYAML() (interface{}, error) {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(b)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for RGBAValue using its JSON decoding
+func (j *RGBAValue) UnmarshalYAML(node *yaml.Node) error {
+	b, err := yamlToJSON(node)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, j)
+}
+
+// MarshalYAML implements yaml.Marshaler for RGBAValue using its JSON encoding
+func (j RGBAValue) MarshalYAML() (interface{}, error) {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(b)
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler for RGBValue using its JSON decoding
+func (j *RGBValue) UnmarshalYAML(node *yaml.Node) error {
+	b, err := yamlToJSON(node)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, j)
+}
+
+// MarshalYAML implements yaml.Marshaler for RGBValue using its JSON encoding
+func (j RGBValue) MarshalYAML() (interface{}, error) {
+	b, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+	return jsonToYAML(b)
+}
+
+// yamlValue decodes a YAML node into the values encoding/json decodes to, so
+// the JSON parse functions can dispatch on it
+func yamlValue(node *yaml.Node) (interface{}, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return yamlValue(node.Content[0])
+	case yaml.AliasNode:
+		return yamlValue(node.Alias)
+	case yaml.SequenceNode:
+		items := make([]interface{}, 0, len(node.Content))
+		for _, item := range node.Content {
+			value, err := yamlValue(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+		}
+		return items, nil
+	case yaml.MappingNode:
+		object := make(map[string]interface{}, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value, err := yamlValue(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			object[node.Content[i].Value] = value
+		}
+		return object, nil
+	case yaml.ScalarNode:
+		switch node.ShortTag() {
+		case "!!null":
+			return nil, nil
+		case "!!bool", "!!int", "!!float":
+			var value interface{}
+			if err := node.Decode(&value); err != nil {
+				return nil, err
+			}
+			return value, nil
+		}
+		// Timestamps and other tagged scalars stay strings, as they are in JSON
+		return node.Value, nil
+	}
+	return nil, fmt.Errorf("line %d: unsupported YAML node", node.Line)
+}
+
+// yamlToJSON converts a YAML node to JSON for the JSON unmarshalers
+func yamlToJSON(node *yaml.Node) ([]byte, error) {
+	value, err := yamlValue(node)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// jsonToYAML converts marshaled JSON to a block style YAML node, keeping the key order
+func jsonToYAML(b []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	node := doc.Content[0]
+	clearYAMLStyle(node)
+	return node, nil
+}
+
+// clearYAMLStyle drops the flow and quoted styles JSON is parsed with
+func clearYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearYAMLStyle(child)
+	}
+}
+
+// NewHSLValue returns a HSLValue with its required fields set, checked
+// against the schema's validation keywords
+func NewHSLValue(h float64, s float64, l float64) (*HSLValue, error) {
+	plain := HSLValue{
+		H: h,
+		S: s,
+		L: l,
+	}
+	if err := plain.checkConstraints(); err != nil {
+		return nil, fmt.Errorf("new HSLValue: %w", err)
+	}
+	return &plain, nil
+}
+
+// checkConstraints checks HSLValue against the schema's validation keywords
+func (plain *HSLValue) checkConstraints() error {
+	if plain.H < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "h", 0)
+	}
+	if plain.H > 360 {
+		return fmt.Errorf("field %s: must be <= %v", "h", 360)
+	}
+	if plain.L < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "l", 0)
+	}
+	if plain.L > 1 {
+		return fmt.Errorf("field %s: must be <= %v", "l", 1)
+	}
+	if plain.S < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "s", 0)
+	}
+	if plain.S > 1 {
+		return fmt.Errorf("field %s: must be <= %v", "s", 1)
+	}
+	return nil
+}
+
+// NewHSVValue returns a HSVValue with its required fields set, checked
+// against the schema's validation keywords
+func NewHSVValue(h float64, s float64, v float64) (*HSVValue, error) {
+	plain := HSVValue{
+		H: h,
+		S: s,
+		V: v,
+	}
+	if err := plain.checkConstraints(); err != nil {
+		return nil, fmt.Errorf("new HSVValue: %w", err)
+	}
+	return &plain, nil
+}
+
+// checkConstraints checks HSVValue against the schema's validation keywords
+func (plain *HSVValue) checkConstraints() error {
+	if plain.H < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "h", 0)
+	}
+	if plain.H > 360 {
+		return fmt.Errorf("field %s: must be <= %v", "h", 360)
+	}
+	if plain.S < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "s", 0)
+	}
+	if plain.S > 1 {
+		return fmt.Errorf("field %s: must be <= %v", "s", 1)
+	}
+	if plain.V < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "v", 0)
+	}
+	if plain.V > 1 {
+		return fmt.Errorf("field %s: must be <= %v", "v", 1)
+	}
+	return nil
+}
+
+// NewHSIValue returns a HSIValue with its required fields set, checked
+// against the schema's validation keywords
+func NewHSIValue(h float64, s float64, i float64) (*HSIValue, error) {
+	plain := HSIValue{
+		H: h,
+		S: s,
+		I: i,
+	}
+	if err := plain.checkConstraints(); err != nil {
+		return nil, fmt.Errorf("new HSIValue: %w", err)
+	}
+	return &plain, nil
+}
+
+// checkConstraints checks HSIValue against the schema's validation keywords
+func (plain *HSIValue) checkConstraints() error {
+	if plain.H < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "h", 0)
+	}
+	if plain.H > 360 {
+		return fmt.Errorf("field %s: must be <= %v", "h", 360)
+	}
+	if plain.I < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "i", 0)
+	}
+	if plain.I > 1 {
+		return fmt.Errorf("field %s: must be <= %v", "i", 1)
+	}
+	if plain.S < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "s", 0)
+	}
+	if plain.S > 1 {
+		return fmt.Errorf("field %s: must be <= %v", "s", 1)
+	}
+	return nil
+}
+
+// NewRGBValue returns a RGBValue with its required fields set, checked
+// against the schema's validation keywords
+func NewRGBValue(r float64, g float64, b float64) (*RGBValue, error) {
+	plain := RGBValue{
+		R: r,
+		G: g,
+		B: b,
+	}
+	if err := plain.checkConstraints(); err != nil {
+		return nil, fmt.Errorf("new RGBValue: %w", err)
+	}
+	return &plain, nil
+}
+
+// checkConstraints checks RGBValue against the schema's validation keywords
+func (plain *RGBValue) checkConstraints() error {
+	if plain.B < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "b", 0)
+	}
+	if plain.B > 255 {
+		return fmt.Errorf("field %s: must be <= %v", "b", 255)
+	}
+	if plain.G < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "g", 0)
+	}
+	if plain.G > 255 {
+		return fmt.Errorf("field %s: must be <= %v", "g", 255)
+	}
+	if plain.R < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "r", 0)
+	}
+	if plain.R > 255 {
+		return fmt.Errorf("field %s: must be <= %v", "r", 255)
+	}
+	return nil
+}
+
+// NewRGBAValue returns a RGBAValue with its required fields set, checked
+// against the schema's validation keywords
+func NewRGBAValue(r float64, g float64, b float64, a float64) (*RGBAValue, error) {
+	plain := RGBAValue{
+		R: r,
+		G: g,
+		B: b,
+		A: a,
+	}
+	if err := plain.checkConstraints(); err != nil {
+		return nil, fmt.Errorf("new RGBAValue: %w", err)
+	}
+	return &plain, nil
+}
+
+// checkConstraints checks RGBAValue against the schema's validation keywords
+func (plain *RGBAValue) checkConstraints() error {
+	if plain.A < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "a", 0)
+	}
+	if plain.A > 1 {
+		return fmt.Errorf("field %s: must be <= %v", "a", 1)
+	}
+	if plain.B < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "b", 0)
+	}
+	if plain.B > 255 {
+		return fmt.Errorf("field %s: must be <= %v", "b", 255)
+	}
+	if plain.G < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "g", 0)
+	}
+	if plain.G > 255 {
+		return fmt.Errorf("field %s: must be <= %v", "g", 255)
+	}
+	if plain.R < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "r", 0)
+	}
+	if plain.R > 255 {
+		return fmt.Errorf("field %s: must be <= %v", "r", 255)
+	}
+	return nil
+}
+
+// NewLABValue returns a LABValue with its required fields set, checked
+// against the schema's validation keywords
+func NewLABValue(l float64, a float64, b float64) (*LABValue, error) {
+	plain := LABValue{
+		L: l,
+		A: a,
+		B: b,
+	}
+	if err := plain.checkConstraints(); err != nil {
+		return nil, fmt.Errorf("new LABValue: %w", err)
+	}
+	return &plain, nil
+}
+
+// checkConstraints checks LABValue against the schema's validation keywords
+func (plain *LABValue) checkConstraints() error {
+	if plain.A < -86.185 {
+		return fmt.Errorf("field %s: must be >= %v", "a", -86.185)
+	}
+	if plain.A > 98.254 {
+		return fmt.Errorf("field %s: must be <= %v", "a", 98.254)
+	}
+	if plain.B < -107.863 {
+		return fmt.Errorf("field %s: must be >= %v", "b", -107.863)
+	}
+	if plain.B > 94.482 {
+		return fmt.Errorf("field %s: must be <= %v", "b", 94.482)
+	}
+	if plain.L < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "l", 0)
+	}
+	if plain.L > 100 {
+		return fmt.Errorf("field %s: must be <= %v", "l", 100)
+	}
+	return nil
+}
+
+// NewLCHValue returns a LCHValue with its required fields set, checked
+// against the schema's validation keywords
+func NewLCHValue(l float64, c float64, h float64) (*LCHValue, error) {
+	plain := LCHValue{
+		L: l,
+		C: c,
+		H: h,
+	}
+	if err := plain.checkConstraints(); err != nil {
+		return nil, fmt.Errorf("new LCHValue: %w", err)
+	}
+	return &plain, nil
+}
+
+// checkConstraints checks LCHValue against the schema's validation keywords
+func (plain *LCHValue) checkConstraints() error {
+	if plain.C < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "c", 0)
+	}
+	if plain.C > 100 {
+		return fmt.Errorf("field %s: must be <= %v", "c", 100)
+	}
+	if plain.H < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "h", 0)
+	}
+	if plain.H > 360 {
+		return fmt.Errorf("field %s: must be <= %v", "h", 360)
+	}
+	if plain.L < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "l", 0)
+	}
+	if plain.L > 100 {
+		return fmt.Errorf("field %s: must be <= %v", "l", 100)
+	}
+	return nil
+}
+
+// NewCMYKValue returns a CMYKValue with its required fields set, checked
+// against the schema's validation keywords
+func NewCMYKValue(c float64, m float64, y float64, k float64) (*CMYKValue, error) {
+	plain := CMYKValue{
+		C: c,
+		M: m,
+		Y: y,
+		K: k,
+	}
+	if err := plain.checkConstraints(); err != nil {
+		return nil, fmt.Errorf("new CMYKValue: %w", err)
+	}
+	return &plain, nil
+}
+
+// checkConstraints checks CMYKValue against the schema's validation keywords
+func (plain *CMYKValue) checkConstraints() error {
+	if plain.C < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "c", 0)
+	}
+	if plain.C > 1 {
+		return fmt.Errorf("field %s: must be <= %v", "c", 1)
+	}
+	if plain.K < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "k", 0)
+	}
+	if plain.K > 1 {
+		return fmt.Errorf("field %s: must be <= %v", "k", 1)
+	}
+	if plain.M < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "m", 0)
+	}
+	if plain.M > 1 {
+		return fmt.Errorf("field %s: must be <= %v", "m", 1)
+	}
+	if plain.Y < 0 {
+		return fmt.Errorf("field %s: must be >= %v", "y", 0)
+	}
+	if plain.Y > 1 {
+		return fmt.Errorf("field %s: must be <= %v", "y", 1)
+	}
+	return nil
+}
+
+// NewCategoricalPalette returns a CategoricalPalette with its required fields set, checked
+// against the schema's validation keywords
+func NewCategoricalPalette(colors ColorSlice, name string, semantic *string) (*CategoricalPalette, error) {
+	plain := CategoricalPalette{
+		Colors:   colors,
+		Name:     name,
+		Semantic: semantic,
+	}
+	return &plain, nil
+}
+
+// NewDiscreteScalePalette returns a DiscreteScalePalette with its required fields set, checked
+// against the schema's validation keywords
+func NewDiscreteScalePalette(colors ColorSlice, name string, semantic *string) (*DiscreteScalePalette, error) {
+	plain := DiscreteScalePalette{
+		Colors:   colors,
+		Name:     name,
+		Semantic: semantic,
+	}
+	return &plain, nil
+}
+
+// NewContinuousScalePalette returns a ContinuousScalePalette with its required fields set, checked
+// against the schema's validation keywords
+func NewContinuousScalePalette(colors []ContinuousColor, name string, semantic *string) (*ContinuousScalePalette, error) {
+	plain := ContinuousScalePalette{
+		Colors:   colors,
+		Name:     name,
+		Semantic: semantic,
+	}
+	return &plain, nil
+}
+
+// NewMatrixPalette returns a MatrixPalette with its required fields set, checked
+// against the schema's validation keywords
+func NewMatrixPalette(colors []ColorSlice, name string, origin Origin, semantic *string) (*MatrixPalette, error) {
+	plain := MatrixPalette{
+		Colors:   colors,
+		Name:     name,
+		Origin:   origin,
+		Semantic: semantic,
+	}
+	return &plain, nil
+}
+
+// CloneColor returns a deep copy of value
+func CloneColor(value Color) Color {
+	switch value := value.(type) {
+	case *HSLValue:
+		return value.Clone()
+	case *HSVValue:
+		return value.Clone()
+	case *HSIValue:
+		return value.Clone()
+	case *RGBValue:
+		return value.Clone()
+	case *RGBAValue:
+		return value.Clone()
+	case *LABValue:
+		return value.Clone()
+	case *LCHValue:
+		return value.Clone()
+	case *CMYKValue:
+		return value.Clone()
+	}
+	return value
+}
+
+// EqualColor reports whether a and b hold equal values of the same type
+func EqualColor(a, b Color) bool {
+	switch a := a.(type) {
+	case *HSLValue:
+		b, ok := b.(*HSLValue)
+		return ok && a.Equal(b)
+	case *HSVValue:
+		b, ok := b.(*HSVValue)
+		return ok && a.Equal(b)
+	case *HSIValue:
+		b, ok := b.(*HSIValue)
+		return ok && a.Equal(b)
+	case *RGBValue:
+		b, ok := b.(*RGBValue)
+		return ok && a.Equal(b)
+	case *RGBAValue:
+		b, ok := b.(*RGBAValue)
+		return ok && a.Equal(b)
+	case *LABValue:
+		b, ok := b.(*LABValue)
+		return ok && a.Equal(b)
+	case *LCHValue:
+		b, ok := b.(*LCHValue)
+		return ok && a.Equal(b)
+	case *CMYKValue:
+		b, ok := b.(*CMYKValue)
+		return ok && a.Equal(b)
+	}
+	return a == nil && b == nil
+}
+
+// Clone returns a deep copy of s
+func (s ColorSlice) Clone() ColorSlice {
+	if s == nil {
+		return nil
+	}
+	clone := make(ColorSlice, len(s))
+	for i, item := range s {
+		clone[i] = CloneColor(item)
+	}
+	return clone
+}
+
+// Equal reports whether s and other hold equal values in the same order,
+// treating nil as empty
+func (s ColorSlice) Equal(other ColorSlice) bool {
+	return equalSlices(s, other, EqualColor)
+}
+
+// Clone returns a deep copy of m
+func (m ColorMap) Clone() ColorMap {
+	if m == nil {
+		return nil
+	}
+	clone := make(ColorMap, len(m))
+	for key, item := range m {
+		clone[key] = CloneColor(item)
+	}
+	return clone
+}
+
+// Equal reports whether m and other hold equal values under the same keys,
+// treating nil as empty
+func (m ColorMap) Equal(other ColorMap) bool {
+	return equalMaps(m, other, EqualColor)
+}
+
+// ClonePalette returns a deep copy of value
+func ClonePalette(value Palette) Palette {
+	switch value := value.(type) {
+	case *CategoricalPalette:
+		return value.Clone()
+	case *DiscreteScalePalette:
+		return value.Clone()
+	case *ContinuousScalePalette:
+		return value.Clone()
+	case *MatrixPalette:
+		return value.Clone()
+	}
+	return value
+}
+
+// EqualPalette reports whether a and b hold equal values of the same type
+func EqualPalette(a, b Palette) bool {
+	switch a := a.(type) {
+	case *CategoricalPalette:
+		b, ok := b.(*CategoricalPalette)
+		return ok && a.Equal(b)
+	case *DiscreteScalePalette:
+		b, ok := b.(*DiscreteScalePalette)
+		return ok && a.Equal(b)
+	case *ContinuousScalePalette:
+		b, ok := b.(*ContinuousScalePalette)
+		return ok && a.Equal(b)
+	case *MatrixPalette:
+		b, ok := b.(*MatrixPalette)
+		return ok && a.Equal(b)
+	}
+	return a == nil && b == nil
+}
+
+// Clone returns a deep copy of s
+func (s PaletteSlice) Clone() PaletteSlice {
+	if s == nil {
+		return nil
+	}
+	clone := make(PaletteSlice, len(s))
+	for i, item := range s {
+		clone[i] = ClonePalette(item)
+	}
+	return clone
+}
+
+// Equal reports whether s and other hold equal values in the same order,
+// treating nil as empty
+func (s PaletteSlice) Equal(other PaletteSlice) bool {
+	return equalSlices(s, other, EqualPalette)
+}
+
+// Clone returns a deep copy of m
+func (m PaletteMap) Clone() PaletteMap {
+	if m == nil {
+		return nil
+	}
+	clone := make(PaletteMap, len(m))
+	for key, item := range m {
+		clone[key] = ClonePalette(item)
+	}
+	return clone
+}
+
+// Equal reports whether m and other hold equal values under the same keys,
+// treating nil as empty
+func (m PaletteMap) Equal(other PaletteMap) bool {
+	return equalMaps(m, other, EqualPalette)
+}
+
+// Clone returns a deep copy of j
+func (j *AssetPack) Clone() *AssetPack {
+	if j == nil {
+		return nil
+	}
+	clone := *j
+	clone.Palettes = j.Palettes.Clone()
+	return &clone
+}
+
+// Clone returns a deep copy of j
+func (j *CMYKValue) Clone() *CMYKValue {
+	if j == nil {
+		return nil
+	}
+	clone := *j
+	return &clone
+}
+
+// Clone returns a deep copy of j
+func (j *CategoricalPalette) Clone() *CategoricalPalette {
+	if j == nil {
+		return nil
+	}
+	clone := *j
+	if j.ColorScheme != nil {
+		v0 := *j.ColorScheme
+		clone.ColorScheme = &v0
+	}
+	clone.Colors = j.Colors.Clone()
+	if j.Description != nil {
+		v0 := *j.Description
+		clone.Description = &v0
+	}
+	if j.Id != nil {
+		v0 := *j.Id
+		clone.Id = &v0
+	}
+	if j.Semantic != nil {
+		v0 := *j.Semantic
+		clone.Semantic = &v0
+	}
+	if j.Usage != nil {
+		clone.Usage = make([]string, len(j.Usage))
+		copy(clone.Usage, j.Usage)
+	}
+	return &clone
+}
+
+// Clone returns a deep copy of j
+func (j *ColorConfig) Clone() *ColorConfig {
+	if j == nil {
+		return nil
+	}
+	clone := *j
+	if j.Id != nil {
+		v0 := *j.Id
+		clone.Id = &v0
+	}
+	if j.Name != nil {
+		v0 := *j.Name
+		clone.Name = &v0
+	}
+	if j.Undertone != nil {
+		v0 := *j.Undertone
+		clone.Undertone = &v0
+	}
+	if j.Usage != nil {
+		clone.Usage = make([]string, len(j.Usage))
+		copy(clone.Usage, j.Usage)
+	}
+	clone.Value = CloneColor(j.Value)
+	return &clone
+}
+
+// Clone returns a deep copy of j
+func (j *ContinuousColor) Clone() *ContinuousColor {
+	if j == nil {
+		return nil
+	}
+	clone := *j
+	if j.Id != nil {
+		v0 := *j.Id
+		clone.Id = &v0
+	}
+	if j.Location != nil {
+		v0 := *j.Location
+		clone.Location = &v0
+	}
+	if j.Name != nil {
+		v0 := *j.Name
+		clone.Name = &v0
+	}
+	if j.Undertone != nil {
+		v0 := *j.Undertone
+		clone.Undertone = &v0
+	}
+	if j.Usage != nil {
+		clone.Usage = make([]string, len(j.Usage))
+		copy(clone.Usage, j.Usage)
+	}
+	clone.Value = CloneColor(j.Value)
+	return &clone
+}
+
+// Clone returns a deep copy of j
+func (j *ContinuousScalePalette) Clone() *ContinuousScalePalette {
+	if j == nil {
+		return nil
+	}
+	clone := *j
+	if j.ColorScheme != nil {
+		v0 := *j.ColorScheme
+		clone.ColorScheme = &v0
+	}
+	if j.Colors != nil {
+		clone.Colors = make([]ContinuousColor, len(j.Colors))
+		copy(clone.Colors, j.Colors)
+		for i0 := range j.Colors {
+			clone.Colors[i0] = *j.Colors[i0].Clone()
+		}
+	}
+	if j.Description != nil {
+		v0 := *j.Description
+		clone.Description = &v0
+	}
+	if j.Id != nil {
+		v0 := *j.Id
+		clone.Id = &v0
+	}
+	if j.Semantic != nil {
+		v0 := *j.Semantic
+		clone.Semantic = &v0
+	}
+	if j.Usage != nil {
+		clone.Usage = make([]string, len(j.Usage))
+		copy(clone.Usage, j.Usage)
+	}
+	return &clone
+}
+
+// Clone returns a deep copy of j
+func (j *DiscreteScalePalette) Clone() *DiscreteScalePalette {
+	if j == nil {
+		return nil
+	}
+	clone := *j
+	if j.ColorScheme != nil {
+		v0 := *j.ColorScheme
+		clone.ColorScheme = &v0
+	}
+	clone.Colors = j.Colors.Clone()
+	if j.Description != nil {
+		v0 := *j.Description
+		clone.Description = &v0
+	}
+	if j.Id != nil {
+		v0 := *j.Id
+		clone.Id = &v0
+	}
+	if j.Semantic != nil {
+		v0 := *j.Semantic
+		clone.Semantic = &v0
+	}
+	if j.Usage != nil {
+		clone.Usage = make([]string, len(j.Usage))
+		copy(clone.Usage, j.Usage)
+	}
+	return &clone
+}
+
+// Clone returns a deep copy of j
+func (j *HSIValue) Clone() *HSIValue {
+	if j == nil {
+		return nil
+	}
+	clone := *j
+	return &clone
+}
+
+// Clone returns a deep copy of j
+func (j *HSLValue) Clone() *HSLValue {
+	if j == nil {
+		return nil
+	}
+	clone := *j
+	return &clone
+}
+
+// Clone returns a deep copy of j
+func (j *HSVValue) Clone() *HSVValue {
+	if j == nil {
+		return nil
+	}
+	clone := *j
+	return &clone
+}
+
+// Clone returns a deep copy of j
+func (j *LABValue) Clone() *LABValue {
+	if j == nil {
+		return nil
+	}
+	clone := *j
+	return &clone
+}
+
+// Clone returns a deep copy of j
+func (j *LCHValue) Clone() *LCHValue {
+	if j == nil {
+		return nil
+	}
+	clone := *j
+	return &clone
+}
+
+// Clone returns a deep copy of j
+func (j *MatrixPalette) Clone() *MatrixPalette {
+	if j == nil {
+		return nil
+	}
+	clone := *j
+	if j.ColorScheme != nil {
+		v0 := *j.ColorScheme
+		clone.ColorScheme = &v0
+	}
+	if j.Colors != nil {
+		clone.Colors = make([]ColorSlice, len(j.Colors))
+		copy(clone.Colors, j.Colors)
+		for i0 := range j.Colors {
+			clone.Colors[i0] = j.Colors[i0].Clone()
+		}
+	}
+	if j.Description != nil {
+		v0 := *j.Description
+		clone.Description = &v0
+	}
+	if j.Id != nil {
+		v0 := *j.Id
+		clone.Id = &v0
+	}
+	clone.Origin = *j.Origin.Clone()
+	if j.Semantic != nil {
+		v0 := *j.Semantic
+		clone.Semantic = &v0
+	}
+	if j.Usage != nil {
+		clone.Usage = make([]string, len(j.Usage))
+		copy(clone.Usage, j.Usage)
+	}
+	return &clone
+}
+
+// Clone returns a deep copy of j
+func (j *Origin) Clone() *Origin {
+	if j == nil {
+		return nil
+	}
+	clone := *j
+	clone.X = j.X.Clone()
+	clone.Y = j.Y.Clone()
+	return &clone
+}
+
+// Clone returns a deep copy of j
+func (j *RGBAValue) Clone() *RGBAValue {
+	if j == nil {
+		return nil
+	}
+	clone := *j
+	return &clone
+}
+
+// Clone returns a deep copy of j
+func (j *RGBValue) Clone() *RGBValue {
+	if j == nil {
+		return nil
+	}
+	clone := *j
+	return &clone
+}
+
+// Equal reports whether j and other hold equal values, treating nil and empty
+// slices and maps as equal
+func (j *AssetPack) Equal(other *AssetPack) bool {
+	if j == nil || other == nil {
+		return j == other
+	}
+	return j.BrandName == other.BrandName &&
+		j.Palettes.Equal(other.Palettes)
+}
+
+// Equal reports whether j and other hold equal values, treating nil and empty
+// slices and maps as equal
+func (j *CMYKValue) Equal(other *CMYKValue) bool {
+	if j == nil || other == nil {
+		return j == other
+	}
+	return j.C == other.C &&
+		j.K == other.K &&
+		j.M == other.M &&
+		j.Y == other.Y
+}
+
+// Equal reports whether j and other hold equal values, treating nil and empty
+// slices and maps as equal
+func (j *CategoricalPalette) Equal(other *CategoricalPalette) bool {
+	if j == nil || other == nil {
+		return j == other
+	}
+	return equalPointers(j.ColorScheme, other.ColorScheme, equalComparable[ColorSchemeType]) &&
+		j.Colors.Equal(other.Colors) &&
+		equalPointers(j.Description, other.Description, equalComparable[string]) &&
+		equalPointers(j.Id, other.Id, equalComparable[string]) &&
+		j.Name == other.Name &&
+		equalPointers(j.Semantic, other.Semantic, equalComparable[string]) &&
+		equalSlices(j.Usage, other.Usage, equalComparable[string])
+}
+
+// Equal reports whether j and other hold equal values, treating nil and empty
+// slices and maps as equal
+func (j *ColorConfig) Equal(other *ColorConfig) bool {
+	if j == nil || other == nil {
+		return j == other
+	}
+	return equalPointers(j.Id, other.Id, equalComparable[string]) &&
+		equalPointers(j.Name, other.Name, equalComparable[string]) &&
+		equalPointers(j.Undertone, other.Undertone, equalComparable[Undertone]) &&
+		equalSlices(j.Usage, other.Usage, equalComparable[string]) &&
+		EqualColor(j.Value, other.Value)
+}
+
+// Equal reports whether j and other hold equal values, treating nil and empty
+// slices and maps as equal
+func (j *ContinuousColor) Equal(other *ContinuousColor) bool {
+	if j == nil || other == nil {
+		return j == other
+	}
+	return equalPointers(j.Id, other.Id, equalComparable[string]) &&
+		equalPointers(j.Location, other.Location, equalComparable[float64]) &&
+		equalPointers(j.Name, other.Name, equalComparable[string]) &&
+		equalPointers(j.Undertone, other.Undertone, equalComparable[Undertone]) &&
+		equalSlices(j.Usage, other.Usage, equalComparable[string]) &&
+		EqualColor(j.Value, other.Value)
+}
+
+// Equal reports whether j and other hold equal values, treating nil and empty
+// slices and maps as equal
+func (j *ContinuousScalePalette) Equal(other *ContinuousScalePalette) bool {
+	if j == nil || other == nil {
+		return j == other
+	}
+	return equalPointers(j.ColorScheme, other.ColorScheme, equalComparable[ColorSchemeType]) &&
+		equalSlices(j.Colors, other.Colors, func(a1, b1 ContinuousColor) bool {
+			return a1.Equal(&b1)
+		}) &&
+		equalPointers(j.Description, other.Description, equalComparable[string]) &&
+		equalPointers(j.Id, other.Id, equalComparable[string]) &&
+		j.Name == other.Name &&
+		equalPointers(j.Semantic, other.Semantic, equalComparable[string]) &&
+		equalSlices(j.Usage, other.Usage, equalComparable[string])
+}
+
+// Equal reports whether j and other hold equal values, treating nil and empty
+// slices and maps as equal
+func (j *DiscreteScalePalette) Equal(other *DiscreteScalePalette) bool {
+	if j == nil || other == nil {
+		return j == other
+	}
+	return equalPointers(j.ColorScheme, other.ColorScheme, equalComparable[ColorSchemeType]) &&
+		j.Colors.Equal(other.Colors) &&
+		equalPointers(j.Description, other.Description, equalComparable[string]) &&
+		equalPointers(j.Id, other.Id, equalComparable[string]) &&
+		j.Name == other.Name &&
+		equalPointers(j.Semantic, other.Semantic, equalComparable[string]) &&
+		equalSlices(j.Usage, other.Usage, equalComparable[string])
+}
+
+// Equal reports whether j and other hold equal values, treating nil and empty
+// slices and maps as equal
+func (j *HSIValue) Equal(other *HSIValue) bool {
+	if j == nil || other == nil {
+		return j == other
+	}
+	return j.H == other.H &&
+		j.I == other.I &&
+		j.S == other.S
+}
+
+// Equal reports whether j and other hold equal values, treating nil and empty
+// slices and maps as equal
+func (j *HSLValue) Equal(other *HSLValue) bool {
+	if j == nil || other == nil {
+		return j == other
+	}
+	return j.H == other.H &&
+		j.L == other.L &&
+		j.S == other.S
+}
+
+// Equal reports whether j and other hold equal values, treating nil and empty
+// slices and maps as equal
+func (j *HSVValue) Equal(other *HSVValue) bool {
+	if j == nil || other == nil {
+		return j == other
+	}
+	return j.H == other.H &&
+		j.S == other.S &&
+		j.V == other.V
+}
+
+// Equal reports whether j and other hold equal values, treating nil and empty
+// slices and maps as equal
+func (j *LABValue) Equal(other *LABValue) bool {
+	if j == nil || other == nil {
+		return j == other
+	}
+	return j.A == other.A &&
+		j.B == other.B &&
+		j.L == other.L
+}
+
+// Equal reports whether j and other hold equal values, treating nil and empty
+// slices and maps as equal
+func (j *LCHValue) Equal(other *LCHValue) bool {
+	if j == nil || other == nil {
+		return j == other
+	}
+	return j.C == other.C &&
+		j.H == other.H &&
+		j.L == other.L
+}
+
+// Equal reports whether j and other hold equal values, treating nil and empty
+// slices and maps as equal
+func (j *MatrixPalette) Equal(other *MatrixPalette) bool {
+	if j == nil || other == nil {
+		return j == other
+	}
+	return equalPointers(j.ColorScheme, other.ColorScheme, equalComparable[ColorSchemeType]) &&
+		equalSlices(j.Colors, other.Colors, ColorSlice.Equal) &&
+		equalPointers(j.Description, other.Description, equalComparable[string]) &&
+		equalPointers(j.Id, other.Id, equalComparable[string]) &&
+		j.Name == other.Name &&
+		j.Origin.Equal(&other.Origin) &&
+		equalPointers(j.Semantic, other.Semantic, equalComparable[string]) &&
+		equalSlices(j.Usage, other.Usage, equalComparable[string])
+}
+
+// Equal reports whether j and other hold equal values, treating nil and empty
+// slices and maps as equal
+func (j *Origin) Equal(other *Origin) bool {
+	if j == nil || other == nil {
+		return j == other
+	}
+	return j.X.Equal(other.X) &&
+		j.Y.Equal(other.Y)
+}
+
+// Equal reports whether j and other hold equal values, treating nil and empty
+// slices and maps as equal
+func (j *RGBAValue) Equal(other *RGBAValue) bool {
+	if j == nil || other == nil {
+		return j == other
+	}
+	return j.A == other.A &&
+		j.B == other.B &&
+		j.G == other.G &&
+		j.R == other.R
+}
+
+// Equal reports whether j and other hold equal values, treating nil and empty
+// slices and maps as equal
+func (j *RGBValue) Equal(other *RGBValue) bool {
+	if j == nil || other == nil {
+		return j == other
+	}
+	return j.B == other.B &&
+		j.G == other.G &&
+		j.R == other.R
+}
+
+// equalComparable reports whether a and b are equal with ==
+func equalComparable[T comparable](a, b T) bool {
+	return a == b
+}
+
+// equalPointers reports whether a and b are both nil or point to equal values
+func equalPointers[T any](a, b *T, equal func(T, T) bool) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return equal(*a, *b)
+}
+
+// equalSlices reports whether a and b hold equal items in the same order,
+// treating nil as empty
+func equalSlices[T any](a, b []T, equal func(T, T) bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalMaps reports whether a and b hold equal values under the same keys,
+// treating nil as empty
+func equalMaps[K comparable, V any](a, b map[K]V, equal func(V, V) bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, value := range a {
+		other, ok := b[key]
+		if !ok || !equal(value, other) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateColor checks value against the schema of the child it holds
+func ValidateColor(value Color) error {
+	var errs ValidationErrors
+	validateColor(value, "", &errs)
+	return errs.err()
+}
+
+// validateColor appends the violations of value, found at path, to errs
+func validateColor(value Color, path string, errs *ValidationErrors) {
+	switch value := value.(type) {
+	case nil:
+		errs.add(path, "must not be null")
+	case *HSLValue:
+		value.validate(path, errs)
+	case *HSVValue:
+		value.validate(path, errs)
+	case *HSIValue:
+		value.validate(path, errs)
+	case *RGBValue:
+		value.validate(path, errs)
+	case *RGBAValue:
+		value.validate(path, errs)
+	case *LABValue:
+		value.validate(path, errs)
+	case *LCHValue:
+		value.validate(path, errs)
+	case *CMYKValue:
+		value.validate(path, errs)
+	}
+}
+
+// Validate checks every item of s against its schema
+func (s ColorSlice) Validate() error {
+	var errs ValidationErrors
+	s.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of the items of s, found under path, to errs
+func (s ColorSlice) validate(path string, errs *ValidationErrors) {
+	for i, item := range s {
+		validateColor(item, path+"/"+strconv.Itoa(i), errs)
+	}
+}
+
+// Validate checks every item of m against its schema
+func (m ColorMap) Validate() error {
+	var errs ValidationErrors
+	m.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of the items of m, found under path, to errs
+func (m ColorMap) validate(path string, errs *ValidationErrors) {
+	for _, key := range sortedJSONKeys(m) {
+		validateColor(m[key], path+"/"+jsonPointerToken(key), errs)
+	}
+}
+
+// ValidatePalette checks value against the schema of the child it holds
+func ValidatePalette(value Palette) error {
+	var errs ValidationErrors
+	validatePalette(value, "", &errs)
+	return errs.err()
+}
+
+// validatePalette appends the violations of value, found at path, to errs
+func validatePalette(value Palette, path string, errs *ValidationErrors) {
+	switch value := value.(type) {
+	case nil:
+		errs.add(path, "must not be null")
+	case *CategoricalPalette:
+		value.validate(path, errs)
+	case *DiscreteScalePalette:
+		value.validate(path, errs)
+	case *ContinuousScalePalette:
+		value.validate(path, errs)
+	case *MatrixPalette:
+		value.validate(path, errs)
+	}
+}
+
+// Validate checks every item of s against its schema
+func (s PaletteSlice) Validate() error {
+	var errs ValidationErrors
+	s.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of the items of s, found under path, to errs
+func (s PaletteSlice) validate(path string, errs *ValidationErrors) {
+	for i, item := range s {
+		validatePalette(item, path+"/"+strconv.Itoa(i), errs)
+	}
+}
+
+// Validate checks every item of m against its schema
+func (m PaletteMap) Validate() error {
+	var errs ValidationErrors
+	m.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of the items of m, found under path, to errs
+func (m PaletteMap) validate(path string, errs *ValidationErrors) {
+	for _, key := range sortedJSONKeys(m) {
+		validatePalette(m[key], path+"/"+jsonPointerToken(key), errs)
+	}
+}
+
+// Validate checks j against its schema, returning every violation as ValidationErrors
+func (j *AssetPack) Validate() error {
+	var errs ValidationErrors
+	j.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of j, found at path, to errs
+func (j *AssetPack) validate(path string, errs *ValidationErrors) {
+	if j == nil {
+		errs.add(path, "must not be null")
+		return
+	}
+	if j.Palettes == nil {
+		errs.add(path+"/palettes", "is required")
+	}
+	j.Palettes.validate(path+"/palettes", errs)
+}
+
+// Validate checks j against its schema, returning every violation as ValidationErrors
+func (j *CMYKValue) Validate() error {
+	var errs ValidationErrors
+	j.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of j, found at path, to errs
+func (j *CMYKValue) validate(path string, errs *ValidationErrors) {
+	if j == nil {
+		errs.add(path, "must not be null")
+		return
+	}
+	if j.C < 0 {
+		errs.add(path+"/c", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.C > 1 {
+		errs.add(path+"/c", fmt.Sprintf("must be <= %v", 1))
+	}
+	if j.K < 0 {
+		errs.add(path+"/k", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.K > 1 {
+		errs.add(path+"/k", fmt.Sprintf("must be <= %v", 1))
+	}
+	if j.M < 0 {
+		errs.add(path+"/m", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.M > 1 {
+		errs.add(path+"/m", fmt.Sprintf("must be <= %v", 1))
+	}
+	if j.Y < 0 {
+		errs.add(path+"/y", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.Y > 1 {
+		errs.add(path+"/y", fmt.Sprintf("must be <= %v", 1))
+	}
+}
+
+// Validate checks j against its schema, returning every violation as ValidationErrors
+func (j *CategoricalPalette) Validate() error {
+	var errs ValidationErrors
+	j.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of j, found at path, to errs
+func (j *CategoricalPalette) validate(path string, errs *ValidationErrors) {
+	if j == nil {
+		errs.add(path, "must not be null")
+		return
+	}
+	if j.Colors == nil {
+		errs.add(path+"/colors", "is required")
+	}
+	if j.ColorScheme != nil {
+		(*j.ColorScheme).validate(path+"/colorScheme", errs)
+	}
+	j.Colors.validate(path+"/colors", errs)
+}
+
+// Validate checks j against its schema, returning every violation as ValidationErrors
+func (j *ColorConfig) Validate() error {
+	var errs ValidationErrors
+	j.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of j, found at path, to errs
+func (j *ColorConfig) validate(path string, errs *ValidationErrors) {
+	if j == nil {
+		errs.add(path, "must not be null")
+		return
+	}
+	if j.Value == nil {
+		errs.add(path+"/value", "is required")
+	}
+	if j.Undertone != nil {
+		(*j.Undertone).validate(path+"/undertone", errs)
+	}
+	if j.Value != nil {
+		validateColor(j.Value, path+"/value", errs)
+	}
+}
+
+// Validate checks j against the values its schema enumerates
+func (j ColorSchemeType) Validate() error {
+	var errs ValidationErrors
+	j.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends a violation to errs, at path, when j isn't one of the enumerated values
+func (j ColorSchemeType) validate(path string, errs *ValidationErrors) {
+	for _, expected := range enumValues_ColorSchemeType {
+		if fmt.Sprint(expected) == fmt.Sprint(j) {
+			return
+		}
+	}
+	errs.add(path, fmt.Sprintf("must be one of %v", enumValues_ColorSchemeType))
+}
+
+// Validate checks j against its schema, returning every violation as ValidationErrors
+func (j *ContinuousColor) Validate() error {
+	var errs ValidationErrors
+	j.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of j, found at path, to errs
+func (j *ContinuousColor) validate(path string, errs *ValidationErrors) {
+	if j == nil {
+		errs.add(path, "must not be null")
+		return
+	}
+	if j.Value == nil {
+		errs.add(path+"/value", "is required")
+	}
+	if j.Location != nil && *j.Location < 0 {
+		errs.add(path+"/location", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.Location != nil && *j.Location > 1 {
+		errs.add(path+"/location", fmt.Sprintf("must be <= %v", 1))
+	}
+	if j.Undertone != nil {
+		(*j.Undertone).validate(path+"/undertone", errs)
+	}
+	if j.Value != nil {
+		validateColor(j.Value, path+"/value", errs)
+	}
+}
+
+// Validate checks j against its schema, returning every violation as ValidationErrors
+func (j *ContinuousScalePalette) Validate() error {
+	var errs ValidationErrors
+	j.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of j, found at path, to errs
+func (j *ContinuousScalePalette) validate(path string, errs *ValidationErrors) {
+	if j == nil {
+		errs.add(path, "must not be null")
+		return
+	}
+	if j.Colors == nil {
+		errs.add(path+"/colors", "is required")
+	}
+	if j.ColorScheme != nil {
+		(*j.ColorScheme).validate(path+"/colorScheme", errs)
+	}
+	for i0 := range j.Colors {
+		j.Colors[i0].validate(path+"/colors/"+strconv.Itoa(i0), errs)
+	}
+}
+
+// Validate checks j against its schema, returning every violation as ValidationErrors
+func (j *DiscreteScalePalette) Validate() error {
+	var errs ValidationErrors
+	j.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of j, found at path, to errs
+func (j *DiscreteScalePalette) validate(path string, errs *ValidationErrors) {
+	if j == nil {
+		errs.add(path, "must not be null")
+		return
+	}
+	if j.Colors == nil {
+		errs.add(path+"/colors", "is required")
+	}
+	if j.ColorScheme != nil {
+		(*j.ColorScheme).validate(path+"/colorScheme", errs)
+	}
+	j.Colors.validate(path+"/colors", errs)
+}
+
+// Validate checks j against its schema, returning every violation as ValidationErrors
+func (j *HSIValue) Validate() error {
+	var errs ValidationErrors
+	j.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of j, found at path, to errs
+func (j *HSIValue) validate(path string, errs *ValidationErrors) {
+	if j == nil {
+		errs.add(path, "must not be null")
+		return
+	}
+	if j.H < 0 {
+		errs.add(path+"/h", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.H > 360 {
+		errs.add(path+"/h", fmt.Sprintf("must be <= %v", 360))
+	}
+	if j.I < 0 {
+		errs.add(path+"/i", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.I > 1 {
+		errs.add(path+"/i", fmt.Sprintf("must be <= %v", 1))
+	}
+	if j.S < 0 {
+		errs.add(path+"/s", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.S > 1 {
+		errs.add(path+"/s", fmt.Sprintf("must be <= %v", 1))
+	}
+}
+
+// Validate checks j against its schema, returning every violation as ValidationErrors
+func (j *HSLValue) Validate() error {
+	var errs ValidationErrors
+	j.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of j, found at path, to errs
+func (j *HSLValue) validate(path string, errs *ValidationErrors) {
+	if j == nil {
+		errs.add(path, "must not be null")
+		return
+	}
+	if j.H < 0 {
+		errs.add(path+"/h", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.H > 360 {
+		errs.add(path+"/h", fmt.Sprintf("must be <= %v", 360))
+	}
+	if j.L < 0 {
+		errs.add(path+"/l", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.L > 1 {
+		errs.add(path+"/l", fmt.Sprintf("must be <= %v", 1))
+	}
+	if j.S < 0 {
+		errs.add(path+"/s", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.S > 1 {
+		errs.add(path+"/s", fmt.Sprintf("must be <= %v", 1))
+	}
+}
+
+// Validate checks j against its schema, returning every violation as ValidationErrors
+func (j *HSVValue) Validate() error {
+	var errs ValidationErrors
+	j.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of j, found at path, to errs
+func (j *HSVValue) validate(path string, errs *ValidationErrors) {
+	if j == nil {
+		errs.add(path, "must not be null")
+		return
+	}
+	if j.H < 0 {
+		errs.add(path+"/h", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.H > 360 {
+		errs.add(path+"/h", fmt.Sprintf("must be <= %v", 360))
+	}
+	if j.S < 0 {
+		errs.add(path+"/s", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.S > 1 {
+		errs.add(path+"/s", fmt.Sprintf("must be <= %v", 1))
+	}
+	if j.V < 0 {
+		errs.add(path+"/v", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.V > 1 {
+		errs.add(path+"/v", fmt.Sprintf("must be <= %v", 1))
+	}
+}
+
+// Validate checks j against its schema, returning every violation as ValidationErrors
+func (j *LABValue) Validate() error {
+	var errs ValidationErrors
+	j.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of j, found at path, to errs
+func (j *LABValue) validate(path string, errs *ValidationErrors) {
+	if j == nil {
+		errs.add(path, "must not be null")
+		return
+	}
+	if j.A < -86.185 {
+		errs.add(path+"/a", fmt.Sprintf("must be >= %v", -86.185))
+	}
+	if j.A > 98.254 {
+		errs.add(path+"/a", fmt.Sprintf("must be <= %v", 98.254))
+	}
+	if j.B < -107.863 {
+		errs.add(path+"/b", fmt.Sprintf("must be >= %v", -107.863))
+	}
+	if j.B > 94.482 {
+		errs.add(path+"/b", fmt.Sprintf("must be <= %v", 94.482))
+	}
+	if j.L < 0 {
+		errs.add(path+"/l", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.L > 100 {
+		errs.add(path+"/l", fmt.Sprintf("must be <= %v", 100))
+	}
+}
+
+// Validate checks j against its schema, returning every violation as ValidationErrors
+func (j *LCHValue) Validate() error {
+	var errs ValidationErrors
+	j.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of j, found at path, to errs
+func (j *LCHValue) validate(path string, errs *ValidationErrors) {
+	if j == nil {
+		errs.add(path, "must not be null")
+		return
+	}
+	if j.C < 0 {
+		errs.add(path+"/c", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.C > 100 {
+		errs.add(path+"/c", fmt.Sprintf("must be <= %v", 100))
+	}
+	if j.H < 0 {
+		errs.add(path+"/h", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.H > 360 {
+		errs.add(path+"/h", fmt.Sprintf("must be <= %v", 360))
+	}
+	if j.L < 0 {
+		errs.add(path+"/l", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.L > 100 {
+		errs.add(path+"/l", fmt.Sprintf("must be <= %v", 100))
+	}
+}
+
+// Validate checks j against its schema, returning every violation as ValidationErrors
+func (j *MatrixPalette) Validate() error {
+	var errs ValidationErrors
+	j.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of j, found at path, to errs
+func (j *MatrixPalette) validate(path string, errs *ValidationErrors) {
+	if j == nil {
+		errs.add(path, "must not be null")
+		return
+	}
+	if j.Colors == nil {
+		errs.add(path+"/colors", "is required")
+	}
+	if j.ColorScheme != nil {
+		(*j.ColorScheme).validate(path+"/colorScheme", errs)
+	}
+	for i0 := range j.Colors {
+		j.Colors[i0].validate(path+"/colors/"+strconv.Itoa(i0), errs)
+	}
+	j.Origin.validate(path+"/origin", errs)
+}
+
+// Validate checks j against its schema, returning every violation as ValidationErrors
+func (j *Origin) Validate() error {
+	var errs ValidationErrors
+	j.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of j, found at path, to errs
+func (j *Origin) validate(path string, errs *ValidationErrors) {
+	if j == nil {
+		errs.add(path, "must not be null")
+		return
+	}
+	if j.X == nil {
+		errs.add(path+"/x", "is required")
+	}
+	if j.Y == nil {
+		errs.add(path+"/y", "is required")
+	}
+	j.X.validate(path+"/x", errs)
+	j.Y.validate(path+"/y", errs)
+}
+
+// Validate checks j against its schema, returning every violation as ValidationErrors
+func (j *RGBAValue) Validate() error {
+	var errs ValidationErrors
+	j.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of j, found at path, to errs
+func (j *RGBAValue) validate(path string, errs *ValidationErrors) {
+	if j == nil {
+		errs.add(path, "must not be null")
+		return
+	}
+	if j.A < 0 {
+		errs.add(path+"/a", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.A > 1 {
+		errs.add(path+"/a", fmt.Sprintf("must be <= %v", 1))
+	}
+	if j.B < 0 {
+		errs.add(path+"/b", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.B > 255 {
+		errs.add(path+"/b", fmt.Sprintf("must be <= %v", 255))
+	}
+	if j.G < 0 {
+		errs.add(path+"/g", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.G > 255 {
+		errs.add(path+"/g", fmt.Sprintf("must be <= %v", 255))
+	}
+	if j.R < 0 {
+		errs.add(path+"/r", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.R > 255 {
+		errs.add(path+"/r", fmt.Sprintf("must be <= %v", 255))
+	}
+}
+
+// Validate checks j against its schema, returning every violation as ValidationErrors
+func (j *RGBValue) Validate() error {
+	var errs ValidationErrors
+	j.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends the violations of j, found at path, to errs
+func (j *RGBValue) validate(path string, errs *ValidationErrors) {
+	if j == nil {
+		errs.add(path, "must not be null")
+		return
+	}
+	if j.B < 0 {
+		errs.add(path+"/b", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.B > 255 {
+		errs.add(path+"/b", fmt.Sprintf("must be <= %v", 255))
+	}
+	if j.G < 0 {
+		errs.add(path+"/g", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.G > 255 {
+		errs.add(path+"/g", fmt.Sprintf("must be <= %v", 255))
+	}
+	if j.R < 0 {
+		errs.add(path+"/r", fmt.Sprintf("must be >= %v", 0))
+	}
+	if j.R > 255 {
+		errs.add(path+"/r", fmt.Sprintf("must be <= %v", 255))
+	}
+}
+
+// Validate checks j against the values its schema enumerates
+func (j Undertone) Validate() error {
+	var errs ValidationErrors
+	j.validate("", &errs)
+	return errs.err()
+}
+
+// validate appends a violation to errs, at path, when j isn't one of the enumerated values
+func (j Undertone) validate(path string, errs *ValidationErrors) {
+	for _, expected := range enumValues_Undertone {
+		if fmt.Sprint(expected) == fmt.Sprint(j) {
+			return
+		}
+	}
+	errs.add(path, fmt.Sprintf("must be one of %v", enumValues_Undertone))
+}
+
+// ValidationError is one value that breaks its schema
+type ValidationError struct {
+	// Path is the JSON pointer to the value, relative to the one validated
+	Path    string
+	Message string
+}
+
+// Error implements error for ValidationError
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// ValidationErrors are all the violations Validate found, in the order it found them
+type ValidationErrors []*ValidationError
+
+// Error implements error for ValidationErrors
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// add records a violation at path
+func (e *ValidationErrors) add(path, message string) {
+	*e = append(*e, &ValidationError{Path: path, Message: message})
+}
+
+// err returns the violations as an error, or nil if there are none
+func (e ValidationErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// jsonPointerToken escapes a map key for use in a JSON pointer
+func jsonPointerToken(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// sortedJSONKeys returns the keys of m in sorted order, so violations are
+// reported in the same order every time
+func sortedJSONKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
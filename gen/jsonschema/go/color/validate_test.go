@@ -0,0 +1,41 @@
+package color
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAssetPack_Validate(t *testing.T) {
+	pack := AssetPack{BrandName: "valid"}
+	for i := 0; i < 3; i++ {
+		palette, err := NewCategoricalPalette(benchColors(t, 4), "palette", nil)
+		if err != nil {
+			t.Fatalf("Failed to build palette %d: %v", i, err)
+		}
+		pack.Palettes = append(pack.Palettes, palette)
+	}
+	if err := pack.Validate(); err != nil {
+		t.Fatalf("Expected a valid asset pack, got %v", err)
+	}
+
+	// Values set in code skip the checks the constructors and decoding run
+	pack.Palettes[2].(*CategoricalPalette).Colors[0] = &HSLValue{H: 400, S: 0.5, L: 0.5}
+	pack.Palettes[1].(*CategoricalPalette).Colors[3] = nil
+
+	var errs ValidationErrors
+	if !errors.As(pack.Validate(), &errs) {
+		t.Fatalf("Expected ValidationErrors, got %v", pack.Validate())
+	}
+	expected := []string{
+		"/palettes/1/colors/3: must not be null",
+		"/palettes/2/colors/0/h: must be <= 360",
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("Expected %d violations, got %v", len(expected), errs)
+	}
+	for i, err := range errs {
+		if err.Error() != expected[i] {
+			t.Errorf("Expected violation %q, got %q", expected[i], err.Error())
+		}
+	}
+}